@@ -5,6 +5,7 @@ import (
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/config"
 	"github.com/dhanuprys/infrantery-backend-go/internal/server"
+	"github.com/dhanuprys/infrantery-backend-go/internal/version"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
 	"github.com/joho/godotenv"
 )
@@ -19,12 +20,31 @@ func main() {
 	cfg := config.Load()
 
 	// Initialize logger
-	logger.Init(cfg.LogLevel, cfg.Environment)
+	logFile := logger.FileOptions{
+		Path:       cfg.LogFile,
+		MaxSizeMB:  cfg.LogMaxSizeMB,
+		MaxBackups: cfg.LogMaxBackups,
+		MaxAge:     cfg.LogMaxAge,
+	}
+	if err := logger.Init(cfg.LogLevel, cfg.Environment, cfg.LogSampleEvery, logFile, cfg.LogStdout); err != nil {
+		log.Fatalf("Failed to open log file: %v", err)
+	}
+	defer logger.Close()
 	logger.Info().
 		Str("log_level", cfg.LogLevel).
+		Uint32("log_sample_every", cfg.LogSampleEvery).
+		Str("log_file", cfg.LogFile).
 		Str("environment", cfg.Environment).
 		Msg("Logger initialized")
 
+	build := version.Get()
+	logger.Info().
+		Str("version", build.Version).
+		Str("git_commit", build.GitCommit).
+		Str("build_time", build.BuildTime).
+		Str("go_version", build.GoVersion).
+		Msg("Starting server")
+
 	// Initialize and run server
 	srv, err := server.NewServer(cfg)
 	if err != nil {
@@ -0,0 +1,49 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+// CreateAccessTokenRequest is the request body for issuing a personal access
+// token. ExpiresInDays defaults to 90 when omitted.
+type CreateAccessTokenRequest struct {
+	Name          string   `json:"name" validate:"required,notblank,max=100"`
+	Scopes        []string `json:"scopes" validate:"required,min=1,dive,oneof=read write"`
+	ExpiresInDays int      `json:"expires_in_days,omitempty" validate:"omitempty,min=1,max=365"`
+}
+
+// AccessTokenResponse describes a personal access token. Token holds the
+// plaintext and is only present in the response to its creation.
+type AccessTokenResponse struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	ExpiresAt  *string  `json:"expires_at,omitempty"`
+	LastUsedAt *string  `json:"last_used_at,omitempty"`
+	CreatedAt  string   `json:"created_at"`
+	Token      string   `json:"token,omitempty"`
+}
+
+// ToAccessTokenResponse converts a token to response
+func ToAccessTokenResponse(token *domain.PersonalAccessToken, plaintext string) AccessTokenResponse {
+	resp := AccessTokenResponse{
+		ID:        token.ID.Hex(),
+		Name:      token.Name,
+		Prefix:    token.Prefix,
+		Scopes:    token.Scopes,
+		CreatedAt: token.CreatedAt.Format(time.RFC3339),
+		Token:     plaintext,
+	}
+	if token.ExpiresAt != nil {
+		expires := token.ExpiresAt.Format(time.RFC3339)
+		resp.ExpiresAt = &expires
+	}
+	if token.LastUsedAt != nil {
+		lastUsed := token.LastUsedAt.Format(time.RFC3339)
+		resp.LastUsedAt = &lastUsed
+	}
+	return resp
+}
@@ -0,0 +1,32 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+// ActivityItem is one entry of a project's activity feed
+type ActivityItem struct {
+	Type      string    `json:"type"` // "diagram", "note" or "vault"
+	ID        string    `json:"id"`
+	NodeID    string    `json:"node_id,omitempty"` // Set for vault items
+	Label     string    `json:"label"`
+	Action    string    `json:"action"` // "created" or "updated"
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// ToActivityItem converts a domain Activity to ActivityItem
+func ToActivityItem(activity domain.Activity) ActivityItem {
+	item := ActivityItem{
+		Type:      string(activity.Type),
+		ID:        activity.ResourceID.Hex(),
+		Label:     activity.Label,
+		Action:    string(activity.Action),
+		Timestamp: activity.Timestamp,
+	}
+	if activity.ParentID != nil {
+		item.NodeID = activity.ParentID.Hex()
+	}
+	return item
+}
@@ -0,0 +1,9 @@
+package dto
+
+type UpdateMaintenanceRequest struct {
+	Mode string `json:"mode" validate:"required,oneof=off read-only full"`
+}
+
+type MaintenanceResponse struct {
+	Mode string `json:"mode"`
+}
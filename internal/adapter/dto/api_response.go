@@ -1,22 +1,33 @@
 package dto
 
-import "time"
+import (
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+)
 
 type MetadataResponse struct {
-	RequestId string `json:"request_id"`
-	Timestamp string `json:"timestamp"`
+	RequestId  string `json:"request_id"`
+	Timestamp  string `json:"timestamp"`
+	APIVersion string `json:"api_version,omitempty"`
 }
 
 type ErrorResponse struct {
-	Code    string               `json:"code"`
-	Message string               `json:"message"`
-	Fields  *[]map[string]string `json:"fields,omitempty"`
+	Code    string                  `json:"code"`
+	Message string                  `json:"message"`
+	Fields  []validation.FieldError `json:"fields,omitempty"`
+}
+
+// CountResponse carries the size of a collection without its items
+type CountResponse struct {
+	Count int64 `json:"count"`
 }
 
 type APIResponse[T any] struct {
 	Data       T                 `json:"data"`
 	Meta       *MetadataResponse `json:"meta"`
 	Pagination *PaginationMeta   `json:"pagination,omitempty"`
+	Cursor     *CursorMeta       `json:"cursor,omitempty"`
 	Error      *ErrorResponse    `json:"error,omitempty"`
 }
 
@@ -42,3 +53,15 @@ func NewAPIResponseWithPagination[T any](data T, pagination *PaginationMeta) *AP
 		Error:      nil,
 	}
 }
+
+func NewAPIResponseWithCursor[T any](data T, cursor *CursorMeta) *APIResponse[T] {
+	return &APIResponse[T]{
+		Data: data,
+		Meta: &MetadataResponse{
+			RequestId: "",
+			Timestamp: time.Now().Format(time.RFC3339),
+		},
+		Cursor: cursor,
+		Error:  nil,
+	}
+}
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestResponsesCarryAttribution(t *testing.T) {
+	creator, editor := primitive.NewObjectID(), primitive.NewObjectID()
+
+	diagram := ToDiagramResponse(&domain.Diagram{ID: primitive.NewObjectID(), CreatedBy: creator, UpdatedBy: editor})
+	note := ToNoteResponse(&domain.Note{ID: primitive.NewObjectID(), CreatedBy: creator, UpdatedBy: editor})
+	vault := ToNodeVaultResponse(&domain.NodeVault{ID: primitive.NewObjectID(), CreatedBy: creator, UpdatedBy: editor})
+	for name, got := range map[string][2]string{
+		"diagram": {diagram.CreatedBy, diagram.UpdatedBy},
+		"note":    {note.CreatedBy, note.UpdatedBy},
+		"vault":   {vault.CreatedBy, vault.UpdatedBy},
+	} {
+		if got[0] != creator.Hex() || got[1] != editor.Hex() {
+			t.Errorf("%s attribution = %v, want %s and %s", name, got, creator.Hex(), editor.Hex())
+		}
+	}
+
+	// Records from before attribution leave the fields out
+	raw, err := json.Marshal(ToNoteResponse(&domain.Note{ID: primitive.NewObjectID()}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "created_by") || strings.Contains(string(raw), "updated_by") {
+		t.Errorf("legacy note = %s, want no attribution fields", raw)
+	}
+}
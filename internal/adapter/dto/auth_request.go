@@ -4,12 +4,14 @@ type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Username string `json:"username" validate:"required,min=3,max=50,alphanum"`
 	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required,min=8"`
+	// Length and complexity are checked against the configured password
+	// policy; max mirrors service.MaxPasswordLength
+	Password string `json:"password" validate:"required,max=1024"`
 }
 
 type LoginRequest struct {
 	EmailOrUsername string `json:"email_or_username" validate:"required"`
-	Password        string `json:"password" validate:"required"`
+	Password        string `json:"password" validate:"required,max=1024"`
 }
 
 type RefreshTokenRequest struct {
@@ -4,6 +4,9 @@ type AuthResponse struct {
 	AccessToken  string `json:"access_token"`
 	RefreshToken string `json:"refresh_token"`
 	ExpiresIn    int64  `json:"expires_in"` // seconds
+	// CookiesSet reports whether the tokens were also set as httpOnly
+	// cookies. Clients opt out with ?cookies=false.
+	CookiesSet bool `json:"cookies_set"`
 }
 
 type UserResponse struct {
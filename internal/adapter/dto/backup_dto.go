@@ -1,11 +1,138 @@
 package dto
 
-// CreateBackupRequest is the request body for creating a backup.
+import (
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+// Backup delivery modes for CreateBackupRequest.
+const (
+	BackupModeDownload = "download"
+	BackupModeStore    = "store"
+	BackupModeBoth     = "both"
+)
+
+// CreateBackupRequest is the request body for creating a backup. Mode
+// defaults to download.
 type CreateBackupRequest struct {
-	Password string `json:"password" validate:"required,min=8"`
+	Password string `json:"password" validate:"required,min=8,max=1024"`
+	Mode     string `json:"mode,omitempty" validate:"omitempty,oneof=download store both"`
+}
+
+// UpdateBackupScheduleRequest configures automatic backups for a project.
+// IntervalHours defaults to 24 when omitted.
+type UpdateBackupScheduleRequest struct {
+	Enabled       bool `json:"enabled"`
+	IntervalHours int  `json:"interval_hours,omitempty" validate:"omitempty,min=1,max=720"`
+	RotateKey     bool `json:"rotate_key"`
+}
+
+// BackupScheduleResponse describes a project's automatic backup schedule.
+// BackupKey is only present right after a key is generated; it is the
+// password needed to restore scheduled backups and is not shown again.
+type BackupScheduleResponse struct {
+	Enabled       bool    `json:"enabled"`
+	IntervalHours int     `json:"interval_hours"`
+	NextRunAt     *string `json:"next_run_at,omitempty"`
+	LastRunAt     *string `json:"last_run_at,omitempty"`
+	LastError     string  `json:"last_error,omitempty"`
+	BackupKey     string  `json:"backup_key,omitempty"`
+}
+
+// ToBackupScheduleResponse converts a schedule to response.
+func ToBackupScheduleResponse(schedule *domain.BackupSchedule, backupKey string) BackupScheduleResponse {
+	resp := BackupScheduleResponse{
+		Enabled:       schedule.Enabled,
+		IntervalHours: schedule.IntervalHours,
+		LastError:     schedule.LastError,
+		BackupKey:     backupKey,
+	}
+	if schedule.Enabled {
+		next := schedule.NextRunAt.Format(time.RFC3339)
+		resp.NextRunAt = &next
+	}
+	if schedule.LastRunAt != nil {
+		last := schedule.LastRunAt.Format(time.RFC3339)
+		resp.LastRunAt = &last
+	}
+	return resp
+}
+
+// ExportDiagramRequest is the request body for exporting a diagram.
+type ExportDiagramRequest struct {
+	Password string `json:"password" validate:"required,min=8,max=1024"`
+}
+
+// BackupArchiveResponse describes a backup stored on the server.
+type BackupArchiveResponse struct {
+	ID        string `json:"id"`
+	Filename  string `json:"filename"`
+	Size      int64  `json:"size"`
+	CreatedBy string `json:"created_by"`
+	CreatedAt string `json:"created_at"`
+}
+
+// ToBackupArchiveResponse converts a stored archive record to response.
+func ToBackupArchiveResponse(archive *domain.BackupArchive) BackupArchiveResponse {
+	return BackupArchiveResponse{
+		ID:        archive.ID.Hex(),
+		Filename:  archive.Filename,
+		Size:      archive.Size,
+		CreatedBy: archive.CreatedBy.Hex(),
+		CreatedAt: archive.CreatedAt.Format(time.RFC3339),
+	}
 }
 
 // RestoreBackupResponse is the response after a successful restore.
 type RestoreBackupResponse struct {
 	Project ProjectResponse `json:"project"`
 }
+
+// CloneProjectRequest is the optional request body for cloning a project.
+type CloneProjectRequest struct {
+	Name *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
+}
+
+// BackupManifestResponse is the unencrypted summary of a backup file.
+type BackupManifestResponse struct {
+	Version     int       `json:"version"`
+	Scope       string    `json:"scope"`
+	ProjectName string    `json:"project_name"`
+	CreatedAt   time.Time `json:"created_at"`
+	Diagrams    int       `json:"diagrams"`
+	Nodes       int       `json:"nodes"`
+	Vaults      int       `json:"vaults"`
+	Notes       int       `json:"notes"`
+}
+
+// ToBackupManifestResponse converts a domain manifest to its response form.
+func ToBackupManifestResponse(m *domain.BackupManifest) BackupManifestResponse {
+	return BackupManifestResponse{
+		Version:     m.Version,
+		Scope:       m.Scope,
+		ProjectName: m.ProjectName,
+		CreatedAt:   m.CreatedAt,
+		Diagrams:    m.Counts.Diagrams,
+		Nodes:       m.Counts.Nodes,
+		Vaults:      m.Counts.Vaults,
+		Notes:       m.Counts.Notes,
+	}
+}
+
+// BackupVerificationResponse reports which checks a backup passed.
+// ChecksumVerified is false for archives too old to carry a checksum.
+type BackupVerificationResponse struct {
+	Version          int  `json:"version"`
+	ChecksumVerified bool `json:"checksum_verified"`
+	PasswordVerified bool `json:"password_verified"`
+}
+
+// ToBackupVerificationResponse converts a verification result to its response form.
+func ToBackupVerificationResponse(v *domain.BackupVerification) BackupVerificationResponse {
+	return BackupVerificationResponse{
+		Version:          v.Version,
+		ChecksumVerified: v.ChecksumVerified,
+		PasswordVerified: v.PasswordVerified,
+	}
+}
@@ -0,0 +1,16 @@
+package dto
+
+// BatchDeleteRequest lists the IDs to delete. IDs are checked one by one so
+// a malformed or unknown ID only fails itself.
+type BatchDeleteRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100"`
+}
+
+// BatchDeleteResult reports the outcome of one ID of a batch delete, in
+// request order. Error is set when the ID was not deleted.
+type BatchDeleteResult struct {
+	Index   int            `json:"index"`
+	ID      string         `json:"id"`
+	Deleted bool           `json:"deleted"`
+	Error   *ErrorResponse `json:"error,omitempty"`
+}
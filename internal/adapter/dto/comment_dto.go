@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+// CreateCommentRequest is the request body for commenting. TargetID is
+// ignored for project comments. ParentCommentID makes the comment a reply.
+type CreateCommentRequest struct {
+	TargetType             string `json:"target_type" validate:"required,oneof=project diagram node"`
+	TargetID               string `json:"target_id,omitempty" validate:"omitempty,objectid"`
+	ParentCommentID        string `json:"parent_comment_id,omitempty" validate:"omitempty,objectid"`
+	EncryptedBody          string `json:"encrypted_body" validate:"required,max=65536,base64std"`
+	EncryptedBodySignature string `json:"encrypted_body_signature" validate:"required,base64std"`
+}
+
+// UpdateCommentRequest replaces the body of a comment
+type UpdateCommentRequest struct {
+	EncryptedBody          string `json:"encrypted_body" validate:"required,max=65536,base64std"`
+	EncryptedBodySignature string `json:"encrypted_body_signature" validate:"required,base64std"`
+}
+
+// CommentListParams selects the comments to list. Without ParentCommentID
+// the target's top-level comments are listed, otherwise that thread's replies.
+type CommentListParams struct {
+	TargetType      string `form:"target_type" validate:"required,oneof=project diagram node"`
+	TargetID        string `form:"target_id" validate:"omitempty,objectid"`
+	ParentCommentID string `form:"parent_comment_id" validate:"omitempty,objectid"`
+}
+
+// CommentResponse describes a comment
+type CommentResponse struct {
+	ID                     string `json:"id"`
+	TargetType             string `json:"target_type"`
+	TargetID               string `json:"target_id"`
+	ParentCommentID        string `json:"parent_comment_id,omitempty"`
+	AuthorID               string `json:"author_id"`
+	EncryptedBody          string `json:"encrypted_body"`
+	EncryptedBodySignature string `json:"encrypted_body_signature"`
+	CreatedAt              string `json:"created_at"`
+	UpdatedAt              string `json:"updated_at"`
+}
+
+// ToCommentResponse converts a comment to response
+func ToCommentResponse(comment *domain.Comment) CommentResponse {
+	var parentID string
+	if comment.IsReply() {
+		parentID = comment.ParentCommentID.Hex()
+	}
+	return CommentResponse{
+		ID:                     comment.ID.Hex(),
+		TargetType:             comment.TargetType,
+		TargetID:               comment.TargetID.Hex(),
+		ParentCommentID:        parentID,
+		AuthorID:               comment.AuthorID.Hex(),
+		EncryptedBody:          comment.EncryptedBody,
+		EncryptedBodySignature: comment.EncryptedBodySignature,
+		CreatedAt:              comment.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:              comment.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -4,15 +4,21 @@ package dto
 type CreateDiagramRequest struct {
 	DiagramName            string  `json:"diagram_name" validate:"required,min=1,max=255"`
 	Description            string  `json:"description" validate:"omitempty,max=1000"`
-	ParentDiagramID        *string `json:"parent_diagram_id,omitempty"`
-	EncryptedData          *string `json:"encrypted_data,omitempty"`
-	EncryptedDataSignature string  `json:"encrypted_data_signature" validate:"required"`
+	ParentDiagramID        *string `json:"parent_diagram_id,omitempty" validate:"omitempty,objectid"`
+	EncryptedData          *string `json:"encrypted_data,omitempty" validate:"omitempty,base64std"`
+	EncryptedDataSignature string  `json:"encrypted_data_signature" validate:"required,base64std"`
 }
 
 // UpdateDiagramRequest represents a request to update an existing diagram
 type UpdateDiagramRequest struct {
 	DiagramName            *string `json:"diagram_name,omitempty" validate:"omitempty,min=1,max=255"`
 	Description            *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	EncryptedData          *string `json:"encrypted_data,omitempty"`
-	EncryptedDataSignature *string `json:"encrypted_data_signature,omitempty"`
+	EncryptedData          *string `json:"encrypted_data,omitempty" validate:"omitempty,base64std"`
+	EncryptedDataSignature *string `json:"encrypted_data_signature,omitempty" validate:"omitempty,base64std"`
+}
+
+// DuplicateDiagramRequest represents a request to duplicate a diagram
+type DuplicateDiagramRequest struct {
+	DiagramName     *string `json:"diagram_name,omitempty" validate:"omitempty,min=1,max=255"`
+	IncludeChildren bool    `json:"include_children"`
 }
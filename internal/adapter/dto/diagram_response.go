@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // DiagramResponse represents a diagram in API responses
@@ -15,8 +16,31 @@ type DiagramResponse struct {
 	Description            string  `json:"description"`
 	EncryptedData          *string `json:"encrypted_data,omitempty"`
 	EncryptedDataSignature string  `json:"encrypted_data_signature"`
+	CreatedBy              string  `json:"created_by,omitempty"`
+	UpdatedBy              string  `json:"updated_by,omitempty"`
 	CreatedAt              string  `json:"created_at"`
 	UpdatedAt              string  `json:"updated_at"`
+	// Lock is set when a member holds the diagram's edit lock
+	Lock *DiagramLockResponse `json:"lock,omitempty"`
+}
+
+// DiagramLockResponse is a live edit lock on a diagram
+type DiagramLockResponse struct {
+	DiagramID string `json:"diagram_id"`
+	LockedBy  string `json:"locked_by"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// ToDiagramLockResponse converts a diagram lock to response
+func ToDiagramLockResponse(lock *domain.DiagramLock) *DiagramLockResponse {
+	if lock == nil {
+		return nil
+	}
+	return &DiagramLockResponse{
+		DiagramID: lock.DiagramID.Hex(),
+		LockedBy:  lock.HolderID.Hex(),
+		ExpiresAt: lock.ExpiresAt.Format(time.RFC3339),
+	}
 }
 
 // ToDiagramResponse converts a domain Diagram to DiagramResponse
@@ -35,7 +59,25 @@ func ToDiagramResponse(diagram *domain.Diagram) DiagramResponse {
 		Description:            diagram.Description,
 		EncryptedData:          diagram.EncryptedData,
 		EncryptedDataSignature: diagram.EncryptedDataSignature,
+		CreatedBy:              memberIDHex(diagram.CreatedBy),
+		UpdatedBy:              memberIDHex(diagram.UpdatedBy),
 		CreatedAt:              diagram.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:              diagram.UpdatedAt.Format(time.RFC3339),
 	}
 }
+
+// memberIDHex formats a CreatedBy/UpdatedBy member ID, leaving it empty on
+// records from before attribution
+func memberIDHex(id primitive.ObjectID) string {
+	if id.IsZero() {
+		return ""
+	}
+	return id.Hex()
+}
+
+// DiagramTreeNode is a diagram in the project's hierarchy, without its data
+type DiagramTreeNode struct {
+	ID       string            `json:"id"`
+	Name     string            `json:"name"`
+	Children []DiagramTreeNode `json:"children,omitempty"`
+}
@@ -1,16 +1,35 @@
 package dto
 
+import (
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/i18n"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/mongoerr"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+)
+
 // Error codes for the application
 const (
 	// Page Not Found errors
 	ErrCodePageNotFound = "PAGE_NOT_FOUND"
 
+	// Routing errors
+	ErrCodeMethodNotAllowed     = "METHOD_NOT_ALLOWED"
+	ErrCodeUnsupportedMediaType = "UNSUPPORTED_MEDIA_TYPE"
+
 	// Authentication errors
 	ErrCodeInvalidCredentials = "INVALID_CREDENTIALS"
 	ErrCodeUserAlreadyExists  = "USER_ALREADY_EXISTS"
 	ErrCodeInvalidToken       = "INVALID_TOKEN"
 	ErrCodeExpiredToken       = "EXPIRED_TOKEN"
 	ErrCodeUnauthorized       = "UNAUTHORIZED"
+
+	// Access token errors
+	ErrCodeInsufficientTokenScope = "INSUFFICIENT_TOKEN_SCOPE"
+	ErrCodeSessionRequired        = "SESSION_REQUIRED"
+	ErrCodeAccessTokenNotFound    = "ACCESS_TOKEN_NOT_FOUND"
+	ErrCodeTooManyAccessTokens    = "TOO_MANY_ACCESS_TOKENS"
+
 	// Profile errors
 	ErrCodeEmailAlreadyExists    = "EMAIL_ALREADY_EXISTS"
 	ErrCodeUsernameAlreadyExists = "USERNAME_ALREADY_EXISTS"
@@ -24,12 +43,25 @@ const (
 	ErrCodeMemberNotFound         = "MEMBER_NOT_FOUND"
 	ErrCodeMemberAlreadyExists    = "MEMBER_ALREADY_EXISTS"
 	ErrCodeCannotRemoveOwner      = "CANNOT_REMOVE_OWNER"
+	ErrCodeInvalidPermission      = "INVALID_PERMISSION"
+
+	// Custom role errors
+	ErrCodeProjectRoleNotFound = "PROJECT_ROLE_NOT_FOUND"
+	ErrCodeProjectRoleExists   = "PROJECT_ROLE_EXISTS"
+	ErrCodeProjectRoleReserved = "PROJECT_ROLE_NAME_RESERVED"
+	ErrCodeProjectRoleInUse    = "PROJECT_ROLE_IN_USE"
+
+	// Project deletion errors
+	ErrCodeProjectDeletionPending      = "PROJECT_DELETION_PENDING"
+	ErrCodeProjectDeletionNotRequested = "PROJECT_DELETION_NOT_REQUESTED"
+	ErrCodeProjectDeletionNeedsConsent = "PROJECT_DELETION_NEEDS_CONSENT"
 
 	// Invitation errors
 	ErrCodeInvitationNotFound        = "INVITATION_NOT_FOUND"
 	ErrCodeInvitationAlreadyAccepted = "INVITATION_ALREADY_ACCEPTED"
 	ErrCodeInvitationExpired         = "INVITATION_EXPIRED"
 	ErrCodeInvitationInvalidPassword = "INVITATION_INVALID_PASSWORD"
+	ErrCodeInvitationDeclined        = "INVITATION_DECLINED"
 
 	// Note errors
 	ErrCodeNoteNotFound     = "NOTE_NOT_FOUND"
@@ -40,42 +72,74 @@ const (
 	ErrCodeDiagramNotFound     = "DIAGRAM_NOT_FOUND"
 	ErrCodeDiagramAccessDenied = "DIAGRAM_ACCESS_DENIED"
 	ErrCodeInvalidDiagramData  = "INVALID_DIAGRAM_DATA"
+	ErrCodeDiagramLocked       = "DIAGRAM_LOCKED"
+
+	// Share link errors
+	ErrCodeShareLinkNotFound         = "SHARE_LINK_NOT_FOUND"
+	ErrCodeShareLinkPasswordRequired = "SHARE_LINK_PASSWORD_REQUIRED"
+	ErrCodeShareLinkInvalidPassword  = "SHARE_LINK_INVALID_PASSWORD"
+	ErrCodeTooManyShareLinks         = "TOO_MANY_SHARE_LINKS"
+
+	// Comment errors
+	ErrCodeCommentNotFound = "COMMENT_NOT_FOUND"
 
 	// Node errors
 	ErrCodeNodeNotFound     = "NODE_NOT_FOUND"
 	ErrCodeNodeAccessDenied = "NODE_ACCESS_DENIED"
 	ErrCodeInvalidNodeData  = "INVALID_NODE_DATA"
 	ErrCodeInvalidNodeID    = "INVALID_NODE_ID"
+	ErrCodeNodeRateLimited  = "NODE_RATE_LIMITED"
 
 	// Vault errors
 	ErrCodeVaultItemNotFound    = "VAULT_ITEM_NOT_FOUND"
 	ErrCodeVaultAccessDenied    = "VAULT_ACCESS_DENIED"
 	ErrCodeInvalidVaultItemData = "INVALID_VAULT_ITEM_DATA"
+	ErrCodeVaultVersionConflict = "VAULT_VERSION_CONFLICT"
 
 	// Backup errors
 	ErrCodeBackupTooLarge         = "BACKUP_TOO_LARGE"
 	ErrCodeBackupInvalidFormat    = "BACKUP_INVALID_FORMAT"
 	ErrCodeBackupVersionMismatch  = "BACKUP_VERSION_MISMATCH"
 	ErrCodeBackupDecryptionFailed = "BACKUP_DECRYPTION_FAILED"
+	ErrCodeBackupTargetRequired   = "BACKUP_TARGET_REQUIRED"
+	ErrCodeBackupManifestMissing  = "BACKUP_MANIFEST_MISSING"
+	ErrCodeBackupStorageDisabled  = "BACKUP_STORAGE_DISABLED"
+	ErrCodeBackupArchiveNotFound  = "BACKUP_ARCHIVE_NOT_FOUND"
+	ErrCodeBackupRestoreFailed    = "BACKUP_RESTORE_FAILED"
+	ErrCodeBackupPepperUnknown    = "BACKUP_PEPPER_UNKNOWN"
+	ErrCodeBackupCorrupted        = "BACKUP_CORRUPTED"
 
 	// Validation errors
 	ErrCodeValidationFailed = "VALIDATION_FAILED"
 	ErrCodeInvalidRequest   = "INVALID_REQUEST"
 
+	// Idempotency errors
+	ErrCodeIdempotencyKeyReused  = "IDEMPOTENCY_KEY_REUSED"
+	ErrCodeIdempotencyInProgress = "IDEMPOTENCY_IN_PROGRESS"
+
+	// Streaming errors
+	ErrCodeTooManyStreams = "TOO_MANY_STREAMS"
+
 	// Resource errors
 	ErrCodeNotFound      = "RESOURCE_NOT_FOUND"
 	ErrCodeAlreadyExists = "RESOURCE_ALREADY_EXISTS"
 	ErrCodeForbidden     = "FORBIDDEN"
 
 	// Server errors
-	ErrCodeInternalError = "INTERNAL_SERVER_ERROR"
-	ErrCodeDatabaseError = "DATABASE_ERROR"
+	ErrCodeInternalError       = "INTERNAL_SERVER_ERROR"
+	ErrCodeDatabaseError       = "DATABASE_ERROR"
+	ErrCodeDatabaseUnavailable = "DATABASE_UNAVAILABLE"
+	ErrCodeMaintenance         = "MAINTENANCE_MODE"
+	ErrCodeRequestTimeout      = "REQUEST_TIMEOUT"
 )
 
 // Error messages corresponding to error codes
 var ErrorMessages = map[string]string{
 	ErrCodePageNotFound: "Page not found",
 
+	ErrCodeMethodNotAllowed:     "Method not allowed for this path",
+	ErrCodeUnsupportedMediaType: "Request body must be JSON (Content-Type: application/json)",
+
 	ErrCodeInvalidCredentials:     "Invalid email/username or password",
 	ErrCodeUserAlreadyExists:      "User with this email or username already exists",
 	ErrCodeInvalidToken:           "Invalid or expired token",
@@ -91,11 +155,22 @@ var ErrorMessages = map[string]string{
 	ErrCodeMemberNotFound:         "Member not found",
 	ErrCodeMemberAlreadyExists:    "Member already exists in this project",
 	ErrCodeCannotRemoveOwner:      "Cannot remove the last owner from project",
+	ErrCodeInvalidPermission:      "Unknown permission",
+
+	ErrCodeProjectRoleNotFound: "Role not found",
+	ErrCodeProjectRoleExists:   "A role with this name already exists in the project",
+	ErrCodeProjectRoleReserved: "This name is reserved for a built-in role",
+	ErrCodeProjectRoleInUse:    "This role is still assigned to members, move them to another role first",
+
+	ErrCodeProjectDeletionPending:      "Deletion has already been requested for this project",
+	ErrCodeProjectDeletionNotRequested: "No deletion has been requested for this project",
+	ErrCodeProjectDeletionNeedsConsent: "This project has several owners, request deletion so the others can review it",
 
 	ErrCodeInvitationNotFound:        "Invitation not found",
 	ErrCodeInvitationAlreadyAccepted: "Invitation has already been accepted",
 	ErrCodeInvitationExpired:         "Invitation has expired",
 	ErrCodeInvitationInvalidPassword: "Invalid invitation password",
+	ErrCodeInvitationDeclined:        "Invitation has been declined",
 
 	ErrCodeNoteNotFound:     "Note not found",
 	ErrCodeNoteAccessDenied: "Access denied to this note",
@@ -104,20 +179,30 @@ var ErrorMessages = map[string]string{
 	ErrCodeDiagramNotFound:     "Diagram not found",
 	ErrCodeDiagramAccessDenied: "Access denied to this diagram",
 	ErrCodeInvalidDiagramData:  "Invalid diagram data provided",
+	ErrCodeDiagramLocked:       "This diagram is locked for editing by another member",
 
 	ErrCodeNodeNotFound:     "Node not found",
 	ErrCodeNodeAccessDenied: "Access denied to this node",
 	ErrCodeInvalidNodeData:  "Invalid node data provided",
 	ErrCodeInvalidNodeID:    "Invalid node ID format",
+	ErrCodeNodeRateLimited:  "Node is being saved too often, please slow down",
 
 	ErrCodeVaultItemNotFound:    "Vault item not found",
 	ErrCodeVaultAccessDenied:    "Access denied to this vault",
 	ErrCodeInvalidVaultItemData: "Invalid vault item data provided",
+	ErrCodeVaultVersionConflict: "Vault item was modified by someone else, reload and try again",
 
 	ErrCodeBackupTooLarge:         "Backup file exceeds maximum allowed size",
 	ErrCodeBackupInvalidFormat:    "Invalid backup file format",
 	ErrCodeBackupVersionMismatch:  "Unsupported backup version",
 	ErrCodeBackupDecryptionFailed: "Decryption failed: wrong password or corrupted file",
+	ErrCodeBackupTargetRequired:   "Diagram backups must be restored into an existing project",
+	ErrCodeBackupManifestMissing:  "Backup was created by an older version and has no readable summary",
+	ErrCodeBackupStorageDisabled:  "Server-side backup storage is not configured",
+	ErrCodeBackupArchiveNotFound:  "Stored backup not found",
+	ErrCodeBackupRestoreFailed:    "Restore failed and nothing was imported, please try again",
+	ErrCodeBackupPepperUnknown:    "Backup was encrypted with a key this server no longer has",
+	ErrCodeBackupCorrupted:        "Backup file is damaged or incomplete",
 
 	ErrCodeValidationFailed: "Validation failed",
 	ErrCodeInvalidRequest:   "Invalid request body",
@@ -126,11 +211,46 @@ var ErrorMessages = map[string]string{
 	ErrCodeForbidden:        "Access forbidden",
 	ErrCodeInternalError:    "Internal server error",
 	ErrCodeDatabaseError:    "Database operation failed",
+	ErrCodeMaintenance:      "Service is under maintenance, please try again later",
+
+	ErrCodeIdempotencyKeyReused:  "Idempotency key was already used for a different request",
+	ErrCodeIdempotencyInProgress: "A request with this idempotency key is still in progress",
+
+	ErrCodeTooManyStreams: "Too many open event streams, close one and try again",
+
+	ErrCodeInsufficientTokenScope: "Access token does not allow this action",
+	ErrCodeSessionRequired:        "This action requires signing in, access tokens are not accepted",
+	ErrCodeAccessTokenNotFound:    "Access token not found",
+	ErrCodeTooManyAccessTokens:    "Too many access tokens, revoke one and try again",
+
+	ErrCodeShareLinkNotFound:         "Share link not found or expired",
+	ErrCodeShareLinkPasswordRequired: "This share link is protected by a password",
+	ErrCodeShareLinkInvalidPassword:  "Share link password is incorrect",
+	ErrCodeTooManyShareLinks:         "Too many share links for this diagram, revoke one and try again",
+
+	ErrCodeCommentNotFound: "Comment not found",
+
+	ErrCodeRequestTimeout: "The request took too long, please try again",
+
+	ErrCodeDatabaseUnavailable: "Database is temporarily unavailable, please try again",
+}
+
+// errorCatalog resolves error messages per locale. ErrorMessages is the
+// English default every other locale falls back to.
+var errorCatalog = i18n.Catalog{
+	i18n.LocaleEnglish:    ErrorMessages,
+	i18n.LocaleIndonesian: errorMessagesID,
+}
+
+// ErrorMessage returns the message for code in the given locale
+func ErrorMessage(locale, code string) string {
+	message, _ := errorCatalog.Lookup(locale, code)
+	return message
 }
 
 // NewErrorResponse creates a new error response with code and message from dictionary
 func NewErrorResponse(code string, customMessage ...string) *ErrorResponse {
-	message := ErrorMessages[code]
+	message := ErrorMessage(i18n.DefaultLocale, code)
 	if len(customMessage) > 0 && customMessage[0] != "" {
 		message = customMessage[0]
 	}
@@ -140,11 +260,41 @@ func NewErrorResponse(code string, customMessage ...string) *ErrorResponse {
 	}
 }
 
+// NewServerErrorResponse picks the status and error for a failure the caller
+// has no specific code for. Database errors a retry may fix are told apart
+// from genuine server errors.
+func NewServerErrorResponse(err error) (int, *ErrorResponse) {
+	switch mongoerr.Classify(err) {
+	case mongoerr.ErrDuplicateKey:
+		return http.StatusConflict, NewErrorResponse(ErrCodeAlreadyExists)
+	case mongoerr.ErrUnavailable:
+		return http.StatusServiceUnavailable, NewErrorResponse(ErrCodeDatabaseUnavailable)
+	case mongoerr.ErrTimeout:
+		return http.StatusGatewayTimeout, NewErrorResponse(ErrCodeRequestTimeout)
+	default:
+		return http.StatusInternalServerError, NewErrorResponse(ErrCodeInternalError)
+	}
+}
+
 // NewValidationErrorResponse creates an error response for validation errors
-func NewValidationErrorResponse(fields *[]map[string]string) *ErrorResponse {
+func NewValidationErrorResponse(fields []validation.FieldError) *ErrorResponse {
 	return &ErrorResponse{
 		Code:    ErrCodeValidationFailed,
-		Message: ErrorMessages[ErrCodeValidationFailed],
+		Message: ErrorMessage(i18n.DefaultLocale, ErrCodeValidationFailed),
 		Fields:  fields,
 	}
 }
+
+// Localize translates the response into locale. The code never changes;
+// custom messages passed to NewErrorResponse are kept as they are, since only
+// the dictionary text has translations.
+func (e *ErrorResponse) Localize(locale string) {
+	if e.Message == ErrorMessage(i18n.DefaultLocale, e.Code) {
+		e.Message = ErrorMessage(locale, e.Code)
+	}
+	for i := range e.Fields {
+		if message, ok := validation.Message(locale, e.Fields[i].Tag, e.Fields[i].Param); ok {
+			e.Fields[i].Message = message
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/i18n"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestValidationErrorResponseKeepsEveryFailure(t *testing.T) {
+	// Two failures on the same field must both reach the client
+	resp := NewValidationErrorResponse([]validation.FieldError{
+		{Field: "password", Tag: "min", Param: "12", Message: "Minimum length is 12"},
+		{Field: "password", Tag: "password_digit", Message: "Must contain a digit"},
+	})
+	resp.Localize(i18n.LocaleIndonesian)
+
+	raw, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body struct {
+		Code   string `json:"code"`
+		Fields []struct {
+			Field   string `json:"field"`
+			Tag     string `json:"tag"`
+			Param   string `json:"param"`
+			Message string `json:"message"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		t.Fatal(err)
+	}
+
+	if body.Code != ErrCodeValidationFailed || len(body.Fields) != 2 {
+		t.Fatalf("body = %s, want both password failures", raw)
+	}
+	if f := body.Fields[0]; f.Field != "password" || f.Tag != "min" || f.Param != "12" || f.Message != "Panjang minimal 12" {
+		t.Errorf("first failure = %+v", f)
+	}
+	if f := body.Fields[1]; f.Field != "password" || f.Tag != "password_digit" || f.Message != "Harus berisi angka" {
+		t.Errorf("second failure = %+v", f)
+	}
+}
+
+func TestNewServerErrorResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "duplicate key", err: mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, wantStatus: http.StatusConflict, wantCode: ErrCodeAlreadyExists},
+		{name: "failover", err: mongo.CommandError{Code: 10107}, wantStatus: http.StatusServiceUnavailable, wantCode: ErrCodeDatabaseUnavailable},
+		{name: "timeout", err: context.DeadlineExceeded, wantStatus: http.StatusGatewayTimeout, wantCode: ErrCodeRequestTimeout},
+		{name: "anything else", err: errors.New("boom"), wantStatus: http.StatusInternalServerError, wantCode: ErrCodeInternalError},
+	}
+	for _, tt := range tests {
+		status, resp := NewServerErrorResponse(tt.err)
+		if status != tt.wantStatus || resp.Code != tt.wantCode {
+			t.Errorf("%s: got %d %s, want %d %s", tt.name, status, resp.Code, tt.wantStatus, tt.wantCode)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package dto
+
+// errorMessagesID holds the Indonesian error messages. Codes missing here
+// fall back to the English text in ErrorMessages.
+var errorMessagesID = map[string]string{
+	ErrCodePageNotFound: "Halaman tidak ditemukan",
+
+	ErrCodeMethodNotAllowed:     "Metode tidak diizinkan untuk path ini",
+	ErrCodeUnsupportedMediaType: "Isi permintaan harus berupa JSON (Content-Type: application/json)",
+
+	ErrCodeInvalidCredentials:     "Email/username atau kata sandi salah",
+	ErrCodeUserAlreadyExists:      "Pengguna dengan email atau username ini sudah ada",
+	ErrCodeInvalidToken:           "Token tidak valid atau sudah kedaluwarsa",
+	ErrCodeExpiredToken:           "Token sudah kedaluwarsa",
+	ErrCodeUnauthorized:           "Diperlukan otorisasi",
+	ErrCodeEmailAlreadyExists:     "Alamat email sudah digunakan",
+	ErrCodeUsernameAlreadyExists:  "Username sudah dipakai",
+	ErrCodeCurrentPasswordWrong:   "Kata sandi saat ini salah",
+	ErrCodeSamePassword:           "Kata sandi baru harus berbeda dari kata sandi saat ini",
+	ErrCodeProjectNotFound:        "Proyek tidak ditemukan",
+	ErrCodeProjectAccessDenied:    "Akses ke proyek ini ditolak",
+	ErrCodeInsufficientPermission: "Izin tidak cukup untuk melakukan tindakan ini",
+	ErrCodeMemberNotFound:         "Anggota tidak ditemukan",
+	ErrCodeMemberAlreadyExists:    "Anggota sudah ada di proyek ini",
+	ErrCodeCannotRemoveOwner:      "Tidak dapat menghapus pemilik terakhir dari proyek",
+	ErrCodeInvalidPermission:      "Izin tidak dikenal",
+
+	ErrCodeProjectRoleNotFound: "Peran tidak ditemukan",
+	ErrCodeProjectRoleExists:   "Peran dengan nama ini sudah ada di proyek",
+	ErrCodeProjectRoleReserved: "Nama ini dipakai oleh peran bawaan",
+	ErrCodeProjectRoleInUse:    "Peran ini masih dipakai anggota, pindahkan mereka ke peran lain terlebih dahulu",
+
+	ErrCodeProjectDeletionPending:      "Penghapusan proyek ini sudah diajukan",
+	ErrCodeProjectDeletionNotRequested: "Tidak ada pengajuan penghapusan untuk proyek ini",
+	ErrCodeProjectDeletionNeedsConsent: "Proyek ini memiliki beberapa pemilik, ajukan penghapusan agar pemilik lain dapat meninjaunya",
+
+	ErrCodeInvitationNotFound:        "Undangan tidak ditemukan",
+	ErrCodeInvitationAlreadyAccepted: "Undangan sudah diterima",
+	ErrCodeInvitationExpired:         "Undangan sudah kedaluwarsa",
+	ErrCodeInvitationInvalidPassword: "Kata sandi undangan salah",
+	ErrCodeInvitationDeclined:        "Undangan sudah ditolak",
+
+	ErrCodeNoteNotFound:     "Catatan tidak ditemukan",
+	ErrCodeNoteAccessDenied: "Akses ke catatan ini ditolak",
+	ErrCodeInvalidNoteData:  "Data catatan tidak valid",
+
+	ErrCodeDiagramNotFound:     "Diagram tidak ditemukan",
+	ErrCodeDiagramAccessDenied: "Akses ke diagram ini ditolak",
+	ErrCodeInvalidDiagramData:  "Data diagram tidak valid",
+	ErrCodeDiagramLocked:       "Diagram ini sedang dikunci untuk diedit oleh anggota lain",
+
+	ErrCodeNodeNotFound:     "Node tidak ditemukan",
+	ErrCodeNodeAccessDenied: "Akses ke node ini ditolak",
+	ErrCodeInvalidNodeData:  "Data node tidak valid",
+	ErrCodeInvalidNodeID:    "Format ID node tidak valid",
+	ErrCodeNodeRateLimited:  "Node disimpan terlalu sering, mohon perlambat",
+
+	ErrCodeVaultItemNotFound:    "Item vault tidak ditemukan",
+	ErrCodeVaultAccessDenied:    "Akses ke vault ini ditolak",
+	ErrCodeInvalidVaultItemData: "Data item vault tidak valid",
+	ErrCodeVaultVersionConflict: "Item vault telah diubah oleh orang lain, muat ulang lalu coba lagi",
+
+	ErrCodeBackupTooLarge:         "Ukuran file cadangan melebihi batas yang diizinkan",
+	ErrCodeBackupInvalidFormat:    "Format file cadangan tidak valid",
+	ErrCodeBackupVersionMismatch:  "Versi cadangan tidak didukung",
+	ErrCodeBackupDecryptionFailed: "Dekripsi gagal: kata sandi salah atau file rusak",
+	ErrCodeBackupTargetRequired:   "Cadangan diagram harus dipulihkan ke proyek yang sudah ada",
+	ErrCodeBackupManifestMissing:  "Cadangan dibuat oleh versi lama dan tidak memiliki ringkasan yang dapat dibaca",
+	ErrCodeBackupStorageDisabled:  "Penyimpanan cadangan di server belum dikonfigurasi",
+	ErrCodeBackupArchiveNotFound:  "Cadangan tersimpan tidak ditemukan",
+	ErrCodeBackupRestoreFailed:    "Pemulihan gagal dan tidak ada data yang diimpor, silakan coba lagi",
+	ErrCodeBackupPepperUnknown:    "Cadangan dienkripsi dengan kunci yang tidak lagi dimiliki server ini",
+	ErrCodeBackupCorrupted:        "Berkas cadangan rusak atau tidak lengkap",
+
+	ErrCodeValidationFailed: "Validasi gagal",
+	ErrCodeInvalidRequest:   "Isi permintaan tidak valid",
+	ErrCodeNotFound:         "Sumber daya tidak ditemukan",
+	ErrCodeAlreadyExists:    "Sumber daya sudah ada",
+	ErrCodeForbidden:        "Akses dilarang",
+	ErrCodeInternalError:    "Terjadi kesalahan pada server",
+	ErrCodeDatabaseError:    "Operasi basis data gagal",
+	ErrCodeMaintenance:      "Layanan sedang dalam pemeliharaan, silakan coba lagi nanti",
+
+	ErrCodeIdempotencyKeyReused:  "Kunci idempotensi sudah dipakai untuk permintaan lain",
+	ErrCodeIdempotencyInProgress: "Permintaan dengan kunci idempotensi ini masih diproses",
+
+	ErrCodeTooManyStreams: "Terlalu banyak aliran event yang terbuka, tutup salah satu lalu coba lagi",
+
+	ErrCodeInsufficientTokenScope: "Token akses tidak mengizinkan tindakan ini",
+	ErrCodeSessionRequired:        "Tindakan ini memerlukan login, token akses tidak diterima",
+	ErrCodeAccessTokenNotFound:    "Token akses tidak ditemukan",
+	ErrCodeTooManyAccessTokens:    "Terlalu banyak token akses, cabut salah satu lalu coba lagi",
+
+	ErrCodeShareLinkNotFound:         "Tautan berbagi tidak ditemukan atau sudah kedaluwarsa",
+	ErrCodeShareLinkPasswordRequired: "Tautan berbagi ini dilindungi kata sandi",
+	ErrCodeShareLinkInvalidPassword:  "Kata sandi tautan berbagi salah",
+	ErrCodeTooManyShareLinks:         "Terlalu banyak tautan berbagi untuk diagram ini, cabut salah satu lalu coba lagi",
+
+	ErrCodeCommentNotFound: "Komentar tidak ditemukan",
+
+	ErrCodeRequestTimeout: "Permintaan terlalu lama diproses, silakan coba lagi",
+
+	ErrCodeDatabaseUnavailable: "Basis data sedang tidak tersedia, silakan coba lagi",
+}
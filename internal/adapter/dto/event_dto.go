@@ -0,0 +1,30 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+)
+
+// ProjectEventResponse is the data of one project event stream message
+type ProjectEventResponse struct {
+	Type       string    `json:"type"`
+	ProjectID  string    `json:"project_id"`
+	ActorID    string    `json:"actor_id"`
+	ResourceID string    `json:"resource_id,omitempty"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+// ToProjectEventResponse converts a bus event to ProjectEventResponse
+func ToProjectEventResponse(e event.Event) ProjectEventResponse {
+	response := ProjectEventResponse{
+		Type:       string(e.Type),
+		ProjectID:  e.ProjectID.Hex(),
+		ActorID:    e.ActorID.Hex(),
+		OccurredAt: e.OccurredAt,
+	}
+	if !e.ResourceID.IsZero() {
+		response.ResourceID = e.ResourceID.Hex()
+	}
+	return response
+}
@@ -0,0 +1,14 @@
+package dto
+
+// PermissionInfo describes one project permission
+type PermissionInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// RoleMetadataResponse lists every permission and the permissions each role
+// preset grants, so clients do not have to hardcode them
+type RoleMetadataResponse struct {
+	Permissions []PermissionInfo    `json:"permissions"`
+	Roles       map[string][]string `json:"roles"`
+}
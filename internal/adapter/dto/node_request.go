@@ -1,12 +1,15 @@
 package dto
 
 type GetOrCreateNodeRequest struct {
-	NodeID string `json:"node_id" validate:"required,mongo_id"` // Must be valid ObjectID hex
+	NodeID string `json:"node_id" validate:"required,objectid"`
 }
 
+// UpdateNodeRequest changes the fields that are sent. Label is stored in
+// plaintext and shown in breadcrumbs; an empty label clears it.
 type UpdateNodeRequest struct {
-	EncryptedReadme          *string `json:"encrypted_readme,omitempty"`
-	EncryptedReadmeSignature *string `json:"encrypted_readme_signature,omitempty"`
-	EncryptedDict            *string `json:"encrypted_dict,omitempty"`
-	EncryptedDictSignature   *string `json:"encrypted_dict_signature,omitempty"`
+	Label                    *string `json:"label,omitempty" validate:"omitempty,max=100"`
+	EncryptedReadme          *string `json:"encrypted_readme,omitempty" validate:"omitempty,base64std"`
+	EncryptedReadmeSignature *string `json:"encrypted_readme_signature,omitempty" validate:"omitempty,base64std"`
+	EncryptedDict            *string `json:"encrypted_dict,omitempty" validate:"omitempty,base64std"`
+	EncryptedDictSignature   *string `json:"encrypted_dict_signature,omitempty" validate:"omitempty,base64std"`
 }
@@ -9,6 +9,7 @@ import (
 type NodeResponse struct {
 	ID                       string `json:"id"`
 	DiagramID                string `json:"diagram_id"`
+	Label                    string `json:"label,omitempty"`
 	EncryptedReadme          string `json:"encrypted_readme,omitempty"`
 	EncryptedReadmeSignature string `json:"encrypted_readme_signature,omitempty"`
 	EncryptedDict            string `json:"encrypted_dict,omitempty"`
@@ -21,6 +22,7 @@ func ToNodeResponse(node *domain.Node) NodeResponse {
 	return NodeResponse{
 		ID:                       node.ID.Hex(),
 		DiagramID:                node.DiagramID.Hex(),
+		Label:                    node.Label,
 		EncryptedReadme:          node.EncryptedReadme,
 		EncryptedReadmeSignature: node.EncryptedReadmeSignature,
 		EncryptedDict:            node.EncryptedDict,
@@ -2,19 +2,19 @@ package dto
 
 // CreateNoteRequest represents a request to create a new note
 type CreateNoteRequest struct {
-	ParentID                  *string `json:"parent_id,omitempty" validate:"omitempty,len=24"`
+	ParentID                  *string `json:"parent_id,omitempty" validate:"omitempty,objectid"`
 	Type                      string  `json:"type" validate:"required,oneof=note folder"`
 	FileName                  string  `json:"file_name" validate:"required,min=1,max=255"`
 	Icon                      string  `json:"icon" validate:"omitempty,max=50"`
-	EncryptedContent          *string `json:"encrypted_content,omitempty"`
-	EncryptedContentSignature string  `json:"encrypted_content_signature" validate:"required_if=Type note"`
+	EncryptedContent          *string `json:"encrypted_content,omitempty" validate:"omitempty,base64std"`
+	EncryptedContentSignature string  `json:"encrypted_content_signature" validate:"required_if=Type note,base64std"`
 }
 
 // UpdateNoteRequest represents a request to update an existing note
 type UpdateNoteRequest struct {
 	FileName                  *string `json:"file_name,omitempty" validate:"omitempty,min=1,max=255"`
-	ParentID                  *string `json:"parent_id,omitempty" validate:"omitempty,len=24"`
+	ParentID                  *string `json:"parent_id,omitempty" validate:"omitempty,objectid"`
 	Icon                      *string `json:"icon,omitempty" validate:"omitempty,max=50"`
-	EncryptedContent          *string `json:"encrypted_content,omitempty"`
-	EncryptedContentSignature *string `json:"encrypted_content_signature,omitempty"`
+	EncryptedContent          *string `json:"encrypted_content,omitempty" validate:"omitempty,base64std"`
+	EncryptedContentSignature *string `json:"encrypted_content_signature,omitempty" validate:"omitempty,base64std"`
 }
@@ -0,0 +1,38 @@
+package dto
+
+import (
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+)
+
+func TestCreateNoteRequestType(t *testing.T) {
+	engine := validation.NewValidationEngine()
+	signature := "c2ln"
+
+	tests := []struct {
+		noteType string
+		valid    bool
+	}{
+		{noteType: domain.NoteTypeFolder, valid: true},
+		{noteType: domain.NoteTypeNote, valid: true},
+		{noteType: "Folder"},
+		{noteType: "markdown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.noteType, func(t *testing.T) {
+			errs := engine.ValidateStruct(CreateNoteRequest{
+				Type:                      tt.noteType,
+				FileName:                  "Runbooks",
+				EncryptedContentSignature: signature,
+			})
+			if tt.valid && len(errs) > 0 {
+				t.Errorf("errors = %+v, want none", errs)
+			}
+			if !tt.valid && (len(errs) != 1 || errs[0].Tag != "oneof") {
+				t.Errorf("errors = %+v, want one oneof failure", errs)
+			}
+		})
+	}
+}
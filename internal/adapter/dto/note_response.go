@@ -16,6 +16,8 @@ type NoteResponse struct {
 	Icon                      string  `json:"icon"`
 	EncryptedContent          *string `json:"encrypted_content,omitempty"`
 	EncryptedContentSignature *string `json:"encrypted_content_signature,omitempty"`
+	CreatedBy                 string  `json:"created_by,omitempty"`
+	UpdatedBy                 string  `json:"updated_by,omitempty"`
 	CreatedAt                 string  `json:"created_at"`
 	UpdatedAt                 string  `json:"updated_at"`
 }
@@ -30,6 +32,8 @@ func ToNoteResponse(note *domain.Note) NoteResponse {
 		Icon:                      note.Icon,
 		EncryptedContent:          note.EncryptedContent,
 		EncryptedContentSignature: note.EncryptedContentSignature,
+		CreatedBy:                 memberIDHex(note.CreatedBy),
+		UpdatedBy:                 memberIDHex(note.UpdatedBy),
 		CreatedAt:                 note.CreatedAt.Format(time.RFC3339),
 		UpdatedAt:                 note.UpdatedAt.Format(time.RFC3339),
 	}
@@ -41,3 +45,12 @@ func ToNoteResponse(note *domain.Note) NoteResponse {
 
 	return response
 }
+
+// NoteTreeNode is a note in the project's folder tree, without its content
+type NoteTreeNode struct {
+	ID       string         `json:"id"`
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	Icon     string         `json:"icon"`
+	Children []NoteTreeNode `json:"children,omitempty"`
+}
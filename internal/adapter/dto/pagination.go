@@ -1,5 +1,25 @@
 package dto
 
+import (
+	"encoding/base64"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrInvalidPagination is returned when pagination parameters are out of range
+var ErrInvalidPagination = errors.New("invalid pagination parameters")
+
+// ErrInvalidCursor is returned when a pagination cursor cannot be decoded
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
+var (
+	// DefaultPageSize is used when the client does not request a page size
+	DefaultPageSize = 20
+	// MaxPageSize caps the page size a client may request
+	MaxPageSize = 100
+)
+
 // PaginationParams represents common pagination parameters
 type PaginationParams struct {
 	Page     int `form:"page" json:"page"`           // Page number (1-indexed)
@@ -10,18 +30,27 @@ type PaginationParams struct {
 func DefaultPaginationParams() PaginationParams {
 	return PaginationParams{
 		Page:     1,
-		PageSize: 20,
+		PageSize: DefaultPageSize,
 	}
 }
 
-// Validate validates and normalizes pagination parameters
-func (p *PaginationParams) Validate() {
-	if p.Page < 1 {
+// Validate validates and normalizes pagination parameters.
+// Negative values are rejected; zero values fall back to defaults and
+// oversized pages are capped at MaxPageSize.
+func (p *PaginationParams) Validate() error {
+	if p.Page < 0 || p.PageSize < 0 {
+		return ErrInvalidPagination
+	}
+	if p.Page == 0 {
 		p.Page = 1
 	}
-	if p.PageSize < 1 || p.PageSize > 100 {
-		p.PageSize = 20
+	if p.PageSize == 0 {
+		p.PageSize = DefaultPageSize
+	}
+	if p.PageSize > MaxPageSize {
+		p.PageSize = MaxPageSize
 	}
+	return nil
 }
 
 // GetOffset calculates the MongoDB skip value
@@ -60,3 +89,64 @@ func NewPaginationMeta(params PaginationParams, totalItems int64) PaginationMeta
 		HasPrevPage: params.Page > 1,
 	}
 }
+
+// CursorParams represents cursor-based pagination parameters
+type CursorParams struct {
+	Cursor string `form:"cursor" json:"cursor"` // Opaque cursor from a previous page
+	Limit  int    `form:"limit" json:"limit"`   // Items per page
+}
+
+// Validate normalizes the limit and decodes the cursor.
+// An empty cursor starts from the beginning and yields primitive.NilObjectID.
+func (p *CursorParams) Validate() (primitive.ObjectID, error) {
+	if p.Limit < 0 {
+		return primitive.NilObjectID, ErrInvalidPagination
+	}
+	if p.Limit == 0 {
+		p.Limit = DefaultPageSize
+	}
+	if p.Limit > MaxPageSize {
+		p.Limit = MaxPageSize
+	}
+	if p.Cursor == "" {
+		return primitive.NilObjectID, nil
+	}
+	return DecodeCursor(p.Cursor)
+}
+
+// EncodeCursor encodes an ObjectID as an opaque, URL-safe cursor
+func EncodeCursor(id primitive.ObjectID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+// DecodeCursor decodes a cursor produced by EncodeCursor
+func DecodeCursor(cursor string) (primitive.ObjectID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(raw) != len(primitive.ObjectID{}) {
+		return primitive.NilObjectID, ErrInvalidCursor
+	}
+
+	var id primitive.ObjectID
+	copy(id[:], raw)
+	return id, nil
+}
+
+// CursorMeta represents cursor pagination metadata in responses
+type CursorMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	Limit      int    `json:"limit"`
+	HasMore    bool   `json:"has_more"`
+}
+
+// NewCursorMeta creates cursor metadata. lastID is the ID of the last item
+// returned and is only encoded when more items are available.
+func NewCursorMeta(limit int, lastID primitive.ObjectID, hasMore bool) CursorMeta {
+	meta := CursorMeta{
+		Limit:   limit,
+		HasMore: hasMore,
+	}
+	if hasMore {
+		meta.NextCursor = EncodeCursor(lastID)
+	}
+	return meta
+}
@@ -0,0 +1,129 @@
+package dto
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestPaginationParamsValidate(t *testing.T) {
+	tests := []struct {
+		name       string
+		params     PaginationParams
+		want       PaginationParams
+		wantOffset int
+		wantErr    error
+	}{
+		{name: "defaults", params: PaginationParams{}, want: PaginationParams{Page: 1, PageSize: DefaultPageSize}},
+		{name: "within range", params: PaginationParams{Page: 3, PageSize: 10}, want: PaginationParams{Page: 3, PageSize: 10}, wantOffset: 20},
+		{name: "at the cap", params: PaginationParams{Page: 1, PageSize: MaxPageSize}, want: PaginationParams{Page: 1, PageSize: MaxPageSize}},
+		{name: "over the cap", params: PaginationParams{Page: 2, PageSize: 1000000}, want: PaginationParams{Page: 2, PageSize: MaxPageSize}, wantOffset: MaxPageSize},
+		{name: "negative page", params: PaginationParams{Page: -1, PageSize: 10}, wantErr: ErrInvalidPagination},
+		{name: "negative page size", params: PaginationParams{Page: 1, PageSize: -10}, wantErr: ErrInvalidPagination},
+	}
+	for _, tt := range tests {
+		params := tt.params
+		err := params.Validate()
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if params != tt.want {
+			t.Errorf("%s: params = %+v, want %+v", tt.name, params, tt.want)
+		}
+		if got := params.GetOffset(); got != tt.wantOffset {
+			t.Errorf("%s: offset = %d, want %d", tt.name, got, tt.wantOffset)
+		}
+	}
+}
+
+func TestNewPaginationMeta(t *testing.T) {
+	tests := []struct {
+		name  string
+		page  int
+		total int64
+		want  PaginationMeta
+	}{
+		{name: "empty", page: 1, total: 0, want: PaginationMeta{CurrentPage: 1, PageSize: 10, TotalPages: 1}},
+		{name: "first of several", page: 1, total: 25, want: PaginationMeta{CurrentPage: 1, PageSize: 10, TotalItems: 25, TotalPages: 3, HasNextPage: true}},
+		{name: "last page", page: 3, total: 25, want: PaginationMeta{CurrentPage: 3, PageSize: 10, TotalItems: 25, TotalPages: 3, HasPrevPage: true}},
+		{name: "exact multiple", page: 2, total: 20, want: PaginationMeta{CurrentPage: 2, PageSize: 10, TotalItems: 20, TotalPages: 2, HasPrevPage: true}},
+		{name: "past the end", page: 5, total: 20, want: PaginationMeta{CurrentPage: 5, PageSize: 10, TotalItems: 20, TotalPages: 2, HasPrevPage: true}},
+	}
+	for _, tt := range tests {
+		if got := NewPaginationMeta(PaginationParams{Page: tt.page, PageSize: 10}, tt.total); got != tt.want {
+			t.Errorf("%s: meta = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	cursor := EncodeCursor(id)
+	if strings.ContainsAny(cursor, "+/=") {
+		t.Errorf("cursor %q is not URL-safe", cursor)
+	}
+	decoded, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decoded != id {
+		t.Errorf("decoded %s, want %s", decoded.Hex(), id.Hex())
+	}
+
+	for _, bad := range []string{"not a cursor!", EncodeCursor(id)[:10], id.Hex()} {
+		if _, err := DecodeCursor(bad); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) = %v, want %v", bad, err, ErrInvalidCursor)
+		}
+	}
+}
+
+func TestCursorParamsValidate(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	tests := []struct {
+		name      string
+		params    CursorParams
+		wantAfter primitive.ObjectID
+		wantLimit int
+		wantErr   error
+	}{
+		{name: "first page", params: CursorParams{}, wantLimit: DefaultPageSize},
+		{name: "next page", params: CursorParams{Cursor: EncodeCursor(id), Limit: 5}, wantAfter: id, wantLimit: 5},
+		{name: "limit capped", params: CursorParams{Limit: MaxPageSize + 1}, wantLimit: MaxPageSize},
+		{name: "negative limit", params: CursorParams{Limit: -1}, wantErr: ErrInvalidPagination},
+		{name: "garbage cursor", params: CursorParams{Cursor: "???"}, wantErr: ErrInvalidCursor},
+	}
+	for _, tt := range tests {
+		params := tt.params
+		after, err := params.Validate()
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if after != tt.wantAfter || params.Limit != tt.wantLimit {
+			t.Errorf("%s: after %s limit %d, want %s limit %d", tt.name, after.Hex(), params.Limit, tt.wantAfter.Hex(), tt.wantLimit)
+		}
+	}
+}
+
+func TestNewCursorMeta(t *testing.T) {
+	id := primitive.NewObjectID()
+
+	meta := NewCursorMeta(10, id, true)
+	if after, err := DecodeCursor(meta.NextCursor); err != nil || after != id || !meta.HasMore {
+		t.Errorf("meta = %+v, want a next cursor pointing at %s", meta, id.Hex())
+	}
+	if meta := NewCursorMeta(10, id, false); meta.NextCursor != "" || meta.HasMore {
+		t.Errorf("last page meta = %+v, want no next cursor", meta)
+	}
+}
@@ -7,8 +7,9 @@ type UpdateProfileRequest struct {
 	Email    *string `json:"email,omitempty" validate:"omitempty,email"`
 }
 
-// ChangePasswordRequest represents a request to change user password
+// ChangePasswordRequest represents a request to change user password.
+// NewPassword is checked against the configured password policy.
 type ChangePasswordRequest struct {
-	CurrentPassword string `json:"current_password" validate:"required,min=8"`
-	NewPassword     string `json:"new_password" validate:"required,min=8"`
+	CurrentPassword string `json:"current_password" validate:"required,max=1024"`
+	NewPassword     string `json:"new_password" validate:"required,max=1024"`
 }
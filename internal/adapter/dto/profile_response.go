@@ -27,3 +27,35 @@ func ToUserProfileResponse(user *domain.User) *UserProfileResponse {
 		UpdatedAt: user.UpdatedAt.Format(time.RFC3339),
 	}
 }
+
+// ProjectCountsResponse breaks down the projects a user belongs to
+type ProjectCountsResponse struct {
+	Total  int64            `json:"total"`
+	Owned  int64            `json:"owned"`
+	Shared int64            `json:"shared"`  // Projects the user belongs to without owning them
+	ByRole map[string]int64 `json:"by_role"` // Membership count per role
+}
+
+// UserDashboardResponse is the profile together with dashboard counters
+type UserDashboardResponse struct {
+	Profile            *UserProfileResponse  `json:"profile"`
+	Projects           ProjectCountsResponse `json:"projects"`
+	PendingInvitations int64                 `json:"pending_invitations"`
+}
+
+// ToUserDashboardResponse converts domain.UserDashboard to UserDashboardResponse
+func ToUserDashboardResponse(dashboard *domain.UserDashboard) *UserDashboardResponse {
+	total := dashboard.TotalProjects()
+	owned := dashboard.OwnedProjects()
+
+	return &UserDashboardResponse{
+		Profile: ToUserProfileResponse(dashboard.User),
+		Projects: ProjectCountsResponse{
+			Total:  total,
+			Owned:  owned,
+			Shared: total - owned,
+			ByRole: dashboard.ProjectsByRole,
+		},
+		PendingInvitations: dashboard.PendingInvitations,
+	}
+}
@@ -2,31 +2,45 @@ package dto
 
 // CreateProjectRequest represents the request to create a new project
 type CreateProjectRequest struct {
-	Name                    string `json:"name" validate:"required,min=1,max=100"`
-	Description             string `json:"description" validate:"max=500"`
-	SecretPassphrase        string `json:"secret_passphrase" validate:"required"`
-	SecretSigningPrivateKey string `json:"secret_signing_private_key" validate:"required"`
-	SigningPublicKey        string `json:"signing_public_key" validate:"required"`
-	UserPublicKey           string `json:"user_public_key" validate:"required"`
-	UserEncryptedPrivateKey string `json:"user_encrypted_private_key" validate:"required"`
+	Name                    string   `json:"name" validate:"required,notblank,max=100"`
+	Description             string   `json:"description" validate:"max=500"`
+	Tags                    []string `json:"tags,omitempty" validate:"omitempty,max=10,dive,notblank,max=30"`
+	SecretPassphrase        string   `json:"secret_passphrase" validate:"required"`
+	SecretSigningPrivateKey string   `json:"secret_signing_private_key" validate:"required"`
+	SigningPublicKey        string   `json:"signing_public_key" validate:"required"`
+	UserPublicKey           string   `json:"user_public_key" validate:"required"`
+	UserEncryptedPrivateKey string   `json:"user_encrypted_private_key" validate:"required"`
 }
 
-// UpdateProjectRequest represents the request to update a project
+// UpdateProjectRequest represents the request to update a project.
+// Omitted and null fields are left unchanged. Description may be set to ""
+// and tags to [] to clear them; name can never be blank.
 type UpdateProjectRequest struct {
-	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
-	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	Name        *string  `json:"name,omitempty" validate:"omitempty,notblank,max=100"`
+	Description *string  `json:"description,omitempty" validate:"omitempty,max=500"`
+	Tags        []string `json:"tags,omitempty" validate:"omitempty,max=10,dive,notblank,max=30"`
 }
 
 // AddMemberRequest represents the request to add a member to a project
+// A CustomRole names one of the project's custom roles and replaces Role and
+// Permissions, which are then resolved from it.
 type AddMemberRequest struct {
-	UserID      string   `json:"user_id" validate:"required"`
-	Role        string   `json:"role" validate:"required,oneof=owner editor viewer custom"`
-	Permissions []string `json:"permissions" validate:"required,min=1,dive,oneof=view_diagram edit_diagram view_note edit_note view_vault edit_vault manage_project"`
+	UserID      string   `json:"user_id" validate:"required,objectid"`
+	Role        string   `json:"role" validate:"required_without=CustomRole,omitempty,oneof=owner editor viewer custom"`
+	Permissions []string `json:"permissions" validate:"required_without=CustomRole,omitempty,min=1,dive,oneof=view_diagram edit_diagram view_note edit_note view_vault edit_vault manage_project"`
+	CustomRole  string   `json:"custom_role,omitempty" validate:"omitempty,max=50"`
 }
 
 // UpdateMemberRequest represents the request to update member permissions
 type UpdateMemberRequest struct {
-	Role        string   `json:"role" validate:"required,oneof=owner editor viewer custom"`
+	Role        string   `json:"role" validate:"required_without=CustomRole,omitempty,oneof=owner editor viewer custom"`
+	Permissions []string `json:"permissions" validate:"required_without=CustomRole,omitempty,min=1,dive,oneof=view_diagram edit_diagram view_note edit_note view_vault edit_vault manage_project"`
+	CustomRole  string   `json:"custom_role,omitempty" validate:"omitempty,max=50"`
+}
+
+// ProjectRoleRequest creates or replaces a project's custom role
+type ProjectRoleRequest struct {
+	Name        string   `json:"name" validate:"required,notblank,max=50"`
 	Permissions []string `json:"permissions" validate:"required,min=1,dive,oneof=view_diagram edit_diagram view_note edit_note view_vault edit_vault manage_project"`
 }
 
@@ -34,13 +48,33 @@ type UpdateMemberRequest struct {
 type CreateInvitationRequest struct {
 	Role              string   `json:"role" validate:"required,oneof=owner editor viewer custom"`
 	Permissions       []string `json:"permissions" validate:"required,min=1,dive,oneof=view_diagram edit_diagram view_note edit_note view_vault edit_vault manage_project"`
-	InviteeUserID     string   `json:"invitee_user_id,omitempty" validate:"omitempty"`
+	InviteeUserID     string   `json:"invitee_user_id,omitempty" validate:"omitempty,objectid"`
+	EncryptedKeyrings string   `json:"encrypted_keyrings" validate:"required"`
+}
+
+// BulkCreateInvitationRequest invites several users at once. Entries are
+// validated one by one so a bad entry only fails itself.
+type BulkCreateInvitationRequest struct {
+	Invitations []BulkInvitationEntry `json:"invitations" validate:"required,min=1,max=50"`
+}
+
+// BulkInvitationEntry is one invitation of a bulk request
+type BulkInvitationEntry struct {
+	InviteeUserID     string   `json:"invitee_user_id" validate:"required,objectid"`
+	Role              string   `json:"role" validate:"required,oneof=owner editor viewer custom"`
+	Permissions       []string `json:"permissions" validate:"required,min=1,dive,oneof=view_diagram edit_diagram view_note edit_note view_vault edit_vault manage_project"`
 	EncryptedKeyrings string   `json:"encrypted_keyrings" validate:"required"`
 }
 
+// ResendInvitationRequest carries the keyrings re-encrypted for the project's
+// current key epoch
+type ResendInvitationRequest struct {
+	EncryptedKeyrings string `json:"encrypted_keyrings" validate:"required"`
+}
+
 // AcceptInvitationRequest represents the request to accept an invitation
 type AcceptInvitationRequest struct {
-	Keyrings            []AcceptInvitationKeyring `json:"keyrings" validate:"required,min=1"`
+	Keyrings            []AcceptInvitationKeyring `json:"keyrings" validate:"required,min=1,dive"`
 	PublicKey           string                    `json:"public_key" validate:"required"`
 	EncryptedPrivateKey string                    `json:"encrypted_private_key" validate:"required"`
 }
@@ -56,12 +90,48 @@ type AcceptInvitationKeyring struct {
 // RotateProjectKeyRequest represents the request to rotate project keys
 type RotateProjectKeyRequest struct {
 	NewKeyEpoch string                `json:"new_key_epoch" validate:"required"`
-	Updates     []MemberKeyringUpdate `json:"updates" validate:"required,min=1"`
+	Updates     []MemberKeyringUpdate `json:"updates" validate:"required,min=1,dive"`
+}
+
+// RekeyMemberRequest represents the new keyring for a single member at the
+// current project epoch
+type RekeyMemberRequest struct {
+	EncryptedPassphrase string `json:"encrypted_passphrase" validate:"required"`
+	EncryptedSigningKey string `json:"encrypted_signing_key" validate:"required"`
+	SigningPublicKey    string `json:"signing_public_key" validate:"required"`
+}
+
+// User search limits
+const (
+	DefaultUserSearchLimit = 10
+	MaxUserSearchLimit     = 20
+)
+
+// UserSearchParams represents the user search query parameters.
+// ExcludeProject leaves out the members of that project.
+type UserSearchParams struct {
+	Query          string `form:"q"`
+	Limit          int    `form:"limit"`
+	ExcludeProject string `form:"exclude_project"`
+}
+
+// Validate normalizes the limit, capping it at MaxUserSearchLimit
+func (p *UserSearchParams) Validate() error {
+	if p.Limit < 0 {
+		return ErrInvalidPagination
+	}
+	if p.Limit == 0 {
+		p.Limit = DefaultUserSearchLimit
+	}
+	if p.Limit > MaxUserSearchLimit {
+		p.Limit = MaxUserSearchLimit
+	}
+	return nil
 }
 
 // MemberKeyringUpdate represents the new keyring for a member
 type MemberKeyringUpdate struct {
-	UserID              string `json:"user_id" validate:"required"`
+	UserID              string `json:"user_id" validate:"required,objectid"`
 	EncryptedPassphrase string `json:"encrypted_passphrase" validate:"required"`
 	EncryptedSigningKey string `json:"encrypted_signing_key" validate:"required"`
 	SigningPublicKey    string `json:"signing_public_key" validate:"required"`
@@ -0,0 +1,46 @@
+package dto
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+)
+
+func TestProjectTagLimits(t *testing.T) {
+	engine := validation.NewValidationEngine()
+	name := "infra"
+
+	tests := []struct {
+		name    string
+		tags    []string
+		wantTag string // the failing constraint; "" when valid
+	}{
+		{name: "none", tags: nil},
+		{name: "at the limits", tags: append(slices.Repeat([]string{"prod"}, 9), strings.Repeat("t", 30))},
+		{name: "too many", tags: slices.Repeat([]string{"prod"}, 11), wantTag: "max"},
+		{name: "too long", tags: []string{strings.Repeat("t", 31)}, wantTag: "max"},
+		{name: "blank", tags: []string{"prod", "  "}, wantTag: "notblank"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := map[string]any{
+				"create": CreateProjectRequest{
+					Name: name, Tags: tt.tags, SecretPassphrase: "p", SecretSigningPrivateKey: "k",
+					SigningPublicKey: "k", UserPublicKey: "k", UserEncryptedPrivateKey: "k",
+				},
+				"update": UpdateProjectRequest{Name: &name, Tags: tt.tags},
+			}
+			for kind, req := range requests {
+				errs := engine.ValidateStruct(req)
+				if tt.wantTag == "" && len(errs) > 0 {
+					t.Errorf("%s: errors = %+v, want none", kind, errs)
+				}
+				if tt.wantTag != "" && (len(errs) == 0 || errs[0].Tag != tt.wantTag) {
+					t.Errorf("%s: errors = %+v, want %s", kind, errs, tt.wantTag)
+				}
+			}
+		})
+	}
+}
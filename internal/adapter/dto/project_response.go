@@ -8,12 +8,20 @@ import (
 
 // ProjectResponse represents a basic project response
 type ProjectResponse struct {
-	ID          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	KeyEpoch    string `json:"key_epoch"`
-	CreatedAt   string `json:"created_at"`
-	UpdatedAt   string `json:"updated_at"`
+	ID                  string   `json:"id"`
+	Name                string   `json:"name"`
+	Description         string   `json:"description"`
+	Tags                []string `json:"tags,omitempty"`
+	KeyEpoch            string   `json:"key_epoch"`
+	Role                string   `json:"role,omitempty"`
+	Favorite            bool     `json:"favorite"`
+	DeletionScheduledAt string   `json:"deletion_scheduled_at,omitempty"`
+	DeletedAt           string   `json:"deleted_at,omitempty"`
+	PurgeAt             string   `json:"purge_at,omitempty"`
+	Archived            bool     `json:"archived"`
+	ArchivedAt          string   `json:"archived_at,omitempty"`
+	CreatedAt           string   `json:"created_at"`
+	UpdatedAt           string   `json:"updated_at"`
 }
 
 // ProjectDetailResponse includes user's permissions
@@ -21,15 +29,28 @@ type ProjectDetailResponse struct {
 	ID                      string                        `json:"id"`
 	Name                    string                        `json:"name"`
 	Description             string                        `json:"description"`
+	Tags                    []string                      `json:"tags,omitempty"`
 	KeyEpoch                string                        `json:"key_epoch"` // Changed from int64 to string
 	Role                    string                        `json:"role"`
+	Favorite                bool                          `json:"favorite"`
 	Permissions             []string                      `json:"permissions"`
 	UserEncryptedPrivateKey string                        `json:"user_encrypted_private_key"`
 	Keyrings                []domain.ProjectMemberKeyring `json:"keyrings"`
+	DeletionScheduledAt     string                        `json:"deletion_scheduled_at,omitempty"`
+	Archived                bool                          `json:"archived"`
+	ArchivedAt              string                        `json:"archived_at,omitempty"`
 	CreatedAt               string                        `json:"created_at"`
 	UpdatedAt               string                        `json:"updated_at"`
 }
 
+// ProjectDeletionResponse describes a pending deletion request
+type ProjectDeletionResponse struct {
+	ProjectID   string `json:"project_id"`
+	RequestedBy string `json:"requested_by"`
+	RequestedAt string `json:"requested_at"`
+	ScheduledAt string `json:"scheduled_at"`
+}
+
 // ProjectChunkResponse represents a project chunk
 type ProjectChunkResponse struct {
 	ID       string `json:"id"`
@@ -42,6 +63,7 @@ type ProjectMemberResponse struct {
 	UserName    string                        `json:"user_name"`
 	UserEmail   string                        `json:"user_email"`
 	Role        string                        `json:"role"`
+	CustomRole  string                        `json:"custom_role,omitempty"`
 	Permissions []string                      `json:"permissions"`
 	PublicKey   string                        `json:"public_key"`
 	Keyrings    []domain.ProjectMemberKeyring `json:"keyrings"`
@@ -52,29 +74,67 @@ type ProjectMemberResponse struct {
 // ToProjectResponse converts a project to basic response
 func ToProjectResponse(project *domain.Project) ProjectResponse {
 	return ProjectResponse{
-		ID:          project.ID.Hex(),
-		Name:        project.Name,
-		Description: project.Description,
-		KeyEpoch:    project.KeyEpoch,
-		CreatedAt:   project.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   project.UpdatedAt.Format(time.RFC3339),
+		ID:                  project.ID.Hex(),
+		Name:                project.Name,
+		Description:         project.Description,
+		Tags:                project.Tags,
+		KeyEpoch:            project.KeyEpoch,
+		DeletionScheduledAt: formatDeletionTime(project.DeletionScheduledAt),
+		DeletedAt:           formatDeletionTime(project.DeletedAt),
+		PurgeAt:             formatDeletionTime(project.PurgeAt),
+		Archived:            project.IsArchived(),
+		ArchivedAt:          formatDeletionTime(project.ArchivedAt),
+		CreatedAt:           project.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           project.UpdatedAt.Format(time.RFC3339),
 	}
 }
 
+// ToMemberProjectResponse converts a project with the caller's role to
+// basic response
+func ToMemberProjectResponse(memberProject *domain.MemberProject) ProjectResponse {
+	response := ToProjectResponse(memberProject.Project)
+	response.Role = memberProject.Role
+	response.Favorite = memberProject.Favorite
+	return response
+}
+
 // ToProjectDetailResponse converts a project and member to detailed response
 func ToProjectDetailResponse(project *domain.Project, member *domain.ProjectMember) ProjectDetailResponse {
 	return ProjectDetailResponse{
-		ID:          project.ID.Hex(),
-		Name:        project.Name,
-		Description: project.Description,
-		KeyEpoch:    project.KeyEpoch,
-		Role:        member.Role,
-		Permissions: member.Permissions,
-		CreatedAt:   project.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:   project.UpdatedAt.Format(time.RFC3339),
+		ID:                  project.ID.Hex(),
+		Name:                project.Name,
+		Description:         project.Description,
+		Tags:                project.Tags,
+		KeyEpoch:            project.KeyEpoch,
+		Role:                member.Role,
+		Favorite:            member.Favorite,
+		Permissions:         member.EffectivePermissions(),
+		DeletionScheduledAt: formatDeletionTime(project.DeletionScheduledAt),
+		Archived:            project.IsArchived(),
+		ArchivedAt:          formatDeletionTime(project.ArchivedAt),
+		CreatedAt:           project.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:           project.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ToProjectDeletionResponse converts a project with a pending deletion
+// request to response
+func ToProjectDeletionResponse(project *domain.Project) ProjectDeletionResponse {
+	return ProjectDeletionResponse{
+		ProjectID:   project.ID.Hex(),
+		RequestedBy: project.DeletionRequestedBy.Hex(),
+		RequestedAt: formatDeletionTime(project.DeletionRequestedAt),
+		ScheduledAt: formatDeletionTime(project.DeletionScheduledAt),
 	}
 }
 
+func formatDeletionTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
 func ToProjectChunkResponse(project *domain.Project) ProjectChunkResponse {
 	return ProjectChunkResponse{
 		ID:       project.ID.Hex(),
@@ -89,6 +149,7 @@ func ToProjectMemberResponse(member *domain.ProjectMember, user *domain.User) Pr
 		UserName:    user.Name,
 		UserEmail:   user.Email,
 		Role:        member.Role,
+		CustomRole:  member.CustomRole,
 		Permissions: member.Permissions,
 		PublicKey:   member.PublicKey,
 		Keyrings:    member.Keyrings,
@@ -98,6 +159,32 @@ func ToProjectMemberResponse(member *domain.ProjectMember, user *domain.User) Pr
 }
 
 // InvitationResponse represents an invitation
+// ProjectRoleResponse is a project's custom role
+type ProjectRoleResponse struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	CreatedAt   string   `json:"created_at"`
+	UpdatedAt   string   `json:"updated_at"`
+}
+
+// ToProjectRoleResponse converts a custom role to response
+func ToProjectRoleResponse(role *domain.ProjectRole) ProjectRoleResponse {
+	return ProjectRoleResponse{
+		ID:          role.ID.Hex(),
+		Name:        role.Name,
+		Permissions: role.Permissions,
+		CreatedAt:   role.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   role.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// ProjectPermissionsResponse is the caller's access to a project
+type ProjectPermissionsResponse struct {
+	Role        string   `json:"role"`
+	Permissions []string `json:"permissions"`
+}
+
 type InvitationResponse struct {
 	ID                string   `json:"id"`
 	ProjectID         string   `json:"project_id"`
@@ -109,6 +196,7 @@ type InvitationResponse struct {
 	EncryptedKeyrings string   `json:"encrypted_keyrings"`
 	Status            string   `json:"status"`
 	CreatedAt         string   `json:"created_at"`
+	UpdatedAt         string   `json:"updated_at"`
 }
 
 // ToInvitationResponse converts an invitation to response
@@ -124,6 +212,29 @@ func ToInvitationResponse(invitation *domain.Invitation, projectName, inviterNam
 		EncryptedKeyrings: invitation.EncryptedKeyrings,
 		Status:            invitation.Status,
 		CreatedAt:         invitation.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:         invitation.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// KeyRotationResponse represents a key rotation history entry
+type KeyRotationResponse struct {
+	ID            string `json:"id"`
+	Epoch         string `json:"epoch"`
+	RotatedBy     string `json:"rotated_by"`
+	RotatedByName string `json:"rotated_by_name"`
+	RotatedAt     string `json:"rotated_at"`
+	MemberCount   int    `json:"member_count"`
+}
+
+// ToKeyRotationResponse converts a key rotation to response
+func ToKeyRotationResponse(rotation *domain.KeyRotation, rotatedByName string) KeyRotationResponse {
+	return KeyRotationResponse{
+		ID:            rotation.ID.Hex(),
+		Epoch:         rotation.Epoch,
+		RotatedBy:     rotation.RotatedBy.Hex(),
+		RotatedByName: rotatedByName,
+		RotatedAt:     rotation.RotatedAt.Format(time.RFC3339),
+		MemberCount:   rotation.MemberCount,
 	}
 }
 
@@ -144,3 +255,17 @@ func ToUserSearchResponse(user *domain.User) UserSearchResponse {
 		Username: user.Username,
 	}
 }
+
+// InvitationCountResponse represents the number of pending invitations for a user
+type InvitationCountResponse struct {
+	Pending int64 `json:"pending"`
+}
+
+// BulkInvitationResult reports the outcome of one bulk invitation entry, in
+// request order. Exactly one of InvitationID and Error is set.
+type BulkInvitationResult struct {
+	Index         int            `json:"index"`
+	InviteeUserID string         `json:"invitee_user_id"`
+	InvitationID  string         `json:"invitation_id,omitempty"`
+	Error         *ErrorResponse `json:"error,omitempty"`
+}
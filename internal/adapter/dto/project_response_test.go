@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestToMemberProjectResponseIncludesRole(t *testing.T) {
+	project := &domain.Project{ID: primitive.NewObjectID(), Name: "infra"}
+
+	raw, err := json.Marshal(ToMemberProjectResponse(&domain.MemberProject{Project: project, Role: domain.RoleEditor, Favorite: true}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]any
+	if err := json.Unmarshal(raw, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["role"] != domain.RoleEditor || body["favorite"] != true || body["id"] != project.ID.Hex() {
+		t.Errorf("response = %s, want the project with role %q", raw, domain.RoleEditor)
+	}
+}
@@ -0,0 +1,53 @@
+package dto
+
+// Search limits. The limit applies to each kind of result separately.
+const (
+	DefaultSearchLimit   = 20
+	MaxSearchLimit       = 50
+	MaxSearchQueryLength = 100
+)
+
+// SearchParams are the query parameters of a search across the caller's
+// projects
+type SearchParams struct {
+	Query string `form:"q"`
+	Limit int    `form:"limit"`
+}
+
+// Validate normalizes the limit, capping it at MaxSearchLimit
+func (p *SearchParams) Validate() error {
+	if p.Limit < 0 {
+		return ErrInvalidPagination
+	}
+	if p.Limit == 0 {
+		p.Limit = DefaultSearchLimit
+	}
+	if p.Limit > MaxSearchLimit {
+		p.Limit = MaxSearchLimit
+	}
+	return nil
+}
+
+// SearchResponse holds the matches of a search, grouped by project.
+// Truncated is set when some kind of result had more matches than the limit.
+type SearchResponse struct {
+	Query     string               `json:"query"`
+	Groups    []SearchProjectGroup `json:"groups"`
+	Truncated bool                 `json:"truncated"`
+}
+
+// SearchProjectGroup is the matches found in one project
+type SearchProjectGroup struct {
+	ProjectID   string         `json:"project_id"`
+	ProjectName string         `json:"project_name"`
+	Results     []SearchResult `json:"results"`
+}
+
+// SearchResult is one matching project, diagram or note. Path leads from the
+// project through the parent diagrams or folders to the match itself.
+type SearchResult struct {
+	Type string           `json:"type"` // "project", "diagram" or "note"
+	ID   string           `json:"id"`
+	Name string           `json:"name"`
+	Path []BreadcrumbItem `json:"path"`
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+// CreateShareLinkRequest is the request body for sharing a diagram.
+// ExpiresInHours defaults to a week when omitted. A password, when set, must
+// be sent in the X-Share-Password header to open the link.
+type CreateShareLinkRequest struct {
+	Password       string `json:"password,omitempty" validate:"omitempty,min=8,max=1024"`
+	ExpiresInHours int    `json:"expires_in_hours,omitempty" validate:"omitempty,min=1,max=2160"`
+}
+
+// ShareLinkResponse describes a share link. Token holds the plaintext and is
+// only present in the response to its creation.
+type ShareLinkResponse struct {
+	ID          string `json:"id"`
+	DiagramID   string `json:"diagram_id"`
+	Prefix      string `json:"prefix"`
+	HasPassword bool   `json:"has_password"`
+	ExpiresAt   string `json:"expires_at"`
+	CreatedBy   string `json:"created_by"`
+	CreatedAt   string `json:"created_at"`
+	Token       string `json:"token,omitempty"`
+}
+
+// ToShareLinkResponse converts a share link to response
+func ToShareLinkResponse(link *domain.ShareLink, plaintext string) ShareLinkResponse {
+	return ShareLinkResponse{
+		ID:          link.ID.Hex(),
+		DiagramID:   link.DiagramID.Hex(),
+		Prefix:      link.Prefix,
+		HasPassword: link.HasPassword(),
+		ExpiresAt:   link.ExpiresAt.Format(time.RFC3339),
+		CreatedBy:   link.CreatedBy.Hex(),
+		CreatedAt:   link.CreatedAt.Format(time.RFC3339),
+		Token:       plaintext,
+	}
+}
+
+// SharedDiagramResponse is what a share link exposes: the diagram's encrypted
+// payload without any project details
+type SharedDiagramResponse struct {
+	DiagramName            string  `json:"diagram_name"`
+	Description            string  `json:"description"`
+	EncryptedData          *string `json:"encrypted_data,omitempty"`
+	EncryptedDataSignature string  `json:"encrypted_data_signature"`
+	UpdatedAt              string  `json:"updated_at"`
+}
+
+// ToSharedDiagramResponse converts a shared diagram to response
+func ToSharedDiagramResponse(diagram *domain.Diagram) SharedDiagramResponse {
+	return SharedDiagramResponse{
+		DiagramName:            diagram.DiagramName,
+		Description:            diagram.Description,
+		EncryptedData:          diagram.EncryptedData,
+		EncryptedDataSignature: diagram.EncryptedDataSignature,
+		UpdatedAt:              diagram.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -9,14 +9,20 @@ import (
 type CreateNodeVaultRequest struct {
 	Label                   string `json:"label" validate:"required"`
 	Type                    string `json:"type" validate:"required"`
-	EncryptedValue          string `json:"encrypted_value" validate:"required"`
-	EncryptedValueSignature string `json:"encrypted_value_signature" validate:"required"`
+	EncryptedValue          string `json:"encrypted_value" validate:"required,base64std"`
+	EncryptedValueSignature string `json:"encrypted_value_signature" validate:"required,base64std"`
 }
 
+// MaxBulkVaultItems caps how many vault items one bulk create may carry.
+const MaxBulkVaultItems = 100
+
+// UpdateNodeVaultRequest optionally carries the version the client last read;
+// when set, the update is rejected with a conflict if the item has changed since.
 type UpdateNodeVaultRequest struct {
+	Version                 *int    `json:"version" validate:"omitempty,min=0"`
 	Label                   *string `json:"label"`
-	EncryptedValue          *string `json:"encrypted_value"`
-	EncryptedValueSignature *string `json:"encrypted_value_signature"`
+	EncryptedValue          *string `json:"encrypted_value" validate:"omitempty,base64std"`
+	EncryptedValueSignature *string `json:"encrypted_value_signature" validate:"omitempty,base64std"`
 }
 
 type NodeVaultResponse struct {
@@ -27,6 +33,9 @@ type NodeVaultResponse struct {
 	Type                    string `json:"type"`
 	EncryptedValue          string `json:"encrypted_value,omitempty"`
 	EncryptedValueSignature string `json:"encrypted_value_signature,omitempty"`
+	Version                 int    `json:"version"`
+	CreatedBy               string `json:"created_by,omitempty"`
+	UpdatedBy               string `json:"updated_by,omitempty"`
 	CreatedAt               string `json:"created_at"`
 	UpdatedAt               string `json:"updated_at"`
 }
@@ -50,6 +59,9 @@ func ToNodeVaultResponse(vault *domain.NodeVault) NodeVaultResponse {
 			}
 			return ""
 		}(),
+		Version:   vault.Version,
+		CreatedBy: memberIDHex(vault.CreatedBy),
+		UpdatedBy: memberIDHex(vault.UpdatedBy),
 		CreatedAt: vault.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: vault.UpdatedAt.Format(time.RFC3339),
 	}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type AccessTokenHandler struct {
+	accessTokens *service.AccessTokenService
+	validator    *validation.ValidationEngine
+}
+
+func NewAccessTokenHandler(accessTokens *service.AccessTokenService, validator *validation.ValidationEngine) *AccessTokenHandler {
+	return &AccessTokenHandler{
+		accessTokens: accessTokens,
+		validator:    validator,
+	}
+}
+
+// CreateToken godoc
+// @Summary Create a personal access token
+// @Description The plaintext token is returned once and cannot be retrieved again.
+// @Tags profile
+// @Accept json
+// @Produce json
+// @Param request body dto.CreateAccessTokenRequest true "Create Access Token Request"
+// @Success 201 {object} dto.APIResponse[dto.AccessTokenResponse]
+// @Router /api/v1/profile/tokens [post]
+func (h *AccessTokenHandler) CreateToken(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var req dto.CreateAccessTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, plaintext, err := h.accessTokens.CreateToken(c.Request.Context(), userID, req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		if errors.Is(err, service.ErrTooManyAccessTokens) {
+			c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeTooManyAccessTokens)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to create access token")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	logger.Info().
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Str("token_id", token.ID.Hex()).
+		Msg("Access token created")
+
+	c.JSON(http.StatusCreated, dto.NewAPIResponse(dto.ToAccessTokenResponse(token, plaintext), nil))
+}
+
+// ListTokens godoc
+// @Summary List personal access tokens
+// @Tags profile
+// @Produce json
+// @Success 200 {object} dto.APIResponse[[]dto.AccessTokenResponse]
+// @Router /api/v1/profile/tokens [get]
+func (h *AccessTokenHandler) ListTokens(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	tokens, err := h.accessTokens.ListTokens(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to list access tokens")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	responses := make([]dto.AccessTokenResponse, 0, len(tokens))
+	for _, token := range tokens {
+		responses = append(responses, dto.ToAccessTokenResponse(token, ""))
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(responses, nil))
+}
+
+// RevokeToken godoc
+// @Summary Revoke a personal access token
+// @Tags profile
+// @Produce json
+// @Param token_id path string true "Token ID"
+// @Success 200 {object} dto.APIResponse[any]
+// @Router /api/v1/profile/tokens/{token_id} [delete]
+func (h *AccessTokenHandler) RevokeToken(c *gin.Context) {
+	tokenID, err := primitive.ObjectIDFromHex(c.Param("token_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	if err := h.accessTokens.RevokeToken(c.Request.Context(), userID, tokenID); err != nil {
+		if errors.Is(err, service.ErrAccessTokenNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeAccessTokenNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to revoke access token")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	logger.Info().
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Str("token_id", tokenID.Hex()).
+		Msg("Access token revoked")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+		"message": "Access token revoked",
+	}, nil))
+}
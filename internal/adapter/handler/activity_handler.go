@@ -0,0 +1,87 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type ActivityHandler struct {
+	activityService *service.ActivityService
+}
+
+func NewActivityHandler(activityService *service.ActivityService) *ActivityHandler {
+	return &ActivityHandler{activityService: activityService}
+}
+
+// ListProjectActivity godoc
+// @Summary List recent project activity
+// @Description Recently created or updated diagrams, notes and vault items, newest first
+// @Tags projects
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param page query int false "Page number"
+// @Param page_size query int false "Items per page"
+// @Success 200 {object} dto.APIResponse[[]dto.ActivityItem]
+// @Router /api/v1/projects/{project_id}/activity [get]
+func (h *ActivityHandler) ListProjectActivity(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var params dto.PaginationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		params = dto.DefaultPaginationParams()
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+
+	activities, totalCount, err := h.activityService.ListProjectActivity(
+		c.Request.Context(),
+		projectID,
+		userID,
+		params.GetOffset(),
+		params.GetLimit(),
+	)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to list project activity")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	items := make([]dto.ActivityItem, 0, len(activities))
+	for _, activity := range activities {
+		items = append(items, dto.ToActivityItem(activity))
+	}
+
+	paginationMeta := dto.NewPaginationMeta(params, totalCount)
+	c.JSON(http.StatusOK, dto.NewAPIResponseWithPagination(items, &paginationMeta))
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/middleware"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves operator endpoints guarded by the admin token
+type AdminHandler struct {
+	maintenance *middleware.Maintenance
+	validator   *validation.ValidationEngine
+}
+
+func NewAdminHandler(maintenance *middleware.Maintenance, validator *validation.ValidationEngine) *AdminHandler {
+	return &AdminHandler{
+		maintenance: maintenance,
+		validator:   validator,
+	}
+}
+
+// GetMaintenance reports the current maintenance mode
+func (h *AdminHandler) GetMaintenance(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.MaintenanceResponse{
+		Mode: h.maintenance.Mode().String(),
+	}, nil))
+}
+
+// SetMaintenance switches the maintenance mode at runtime
+func (h *AdminHandler) SetMaintenance(c *gin.Context) {
+	var req dto.UpdateMaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err, err.Error())
+		return
+	}
+
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	mode, err := middleware.ParseMaintenanceMode(req.Mode)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, err.Error())))
+		return
+	}
+
+	previous := h.maintenance.Mode()
+	h.maintenance.SetMode(mode)
+
+	logger.Warn().
+		Str("previous_mode", previous.String()).
+		Str("mode", mode.String()).
+		Str("client_ip", c.ClientIP()).
+		Msg("Maintenance mode changed")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.MaintenanceResponse{
+		Mode: mode.String(),
+	}, nil))
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
@@ -31,13 +32,13 @@ func NewAuthHandler(authService *service.AuthService, validator *validation.Vali
 // @Accept json
 // @Produce json
 // @Param request body dto.RegisterRequest true "Register Request"
+// @Param cookies query bool false "Set to false to receive tokens without auth cookies"
 // @Success 201 {object} dto.APIResponse[dto.AuthResponse]
 // @Router /api/v1/auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -51,6 +52,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 	// Register user
 	authResp, err := h.authService.Register(c.Request.Context(), req)
 	if err != nil {
+		var policyErr *service.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewValidationErrorResponse(policyErr.Violations)))
+			return
+		}
 		if err == service.ErrUserExists {
 			logger.Warn().
 				Str("email", logger.MaskEmail(req.Email)).
@@ -61,8 +68,8 @@ func (h *AuthHandler) Register(c *gin.Context) {
 			return
 		}
 		logger.Error().Err(err).Msg("Failed to register user")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -70,7 +77,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		Str("email", logger.MaskEmail(req.Email)).
 		Msg("User registered successfully")
 
-	h.setCookies(c, authResp.AccessToken, authResp.RefreshToken)
+	h.issueCookies(c, authResp)
 	c.JSON(http.StatusCreated, dto.NewAPIResponse(authResp, nil))
 }
 
@@ -80,13 +87,13 @@ func (h *AuthHandler) Register(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "Login Request"
+// @Param cookies query bool false "Set to false to receive tokens without auth cookies"
 // @Success 200 {object} dto.APIResponse[dto.AuthResponse]
 // @Router /api/v1/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -109,8 +116,8 @@ func (h *AuthHandler) Login(c *gin.Context) {
 			return
 		}
 		logger.Error().Err(err).Msg("Login error")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -118,7 +125,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		Str("identifier", logger.MaskEmail(req.EmailOrUsername)).
 		Msg("User logged in successfully")
 
-	h.setCookies(c, authResp.AccessToken, authResp.RefreshToken)
+	h.issueCookies(c, authResp)
 	c.JSON(http.StatusOK, dto.NewAPIResponse(authResp, nil))
 }
 
@@ -128,6 +135,7 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body dto.RefreshTokenRequest true "Refresh Token Request"
+// @Param cookies query bool false "Set to false to receive tokens without auth cookies"
 // @Success 200 {object} dto.APIResponse[dto.AuthResponse]
 // @Router /api/v1/auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
@@ -140,8 +148,12 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	} else {
 		// Fallback to body
 		var req dto.RefreshTokenRequest
-		if err := c.ShouldBindJSON(&req); err == nil {
+		err := c.ShouldBindJSON(&req)
+		if err == nil {
 			refreshToken = req.RefreshToken
+		} else if isBodyTooLarge(err) {
+			writeBindError(c, err)
+			return
 		}
 	}
 
@@ -161,14 +173,14 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 			return
 		}
 		logger.Error().Err(err).Msg("Failed to refresh token")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
 	logger.Info().Msg("Token refreshed successfully")
 
-	h.setCookies(c, authResp.AccessToken, authResp.RefreshToken)
+	h.issueCookies(c, authResp)
 	c.JSON(http.StatusOK, dto.NewAPIResponse(authResp, nil))
 }
 
@@ -204,6 +216,16 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.NewAPIResponse[any](nil, nil))
 }
 
+// issueCookies sets the auth cookies unless the client asked for tokens only
+// with ?cookies=false, and records the outcome on the response
+func (h *AuthHandler) issueCookies(c *gin.Context, authResp *dto.AuthResponse) {
+	if c.Query("cookies") == "false" {
+		return
+	}
+	h.setCookies(c, authResp.AccessToken, authResp.RefreshToken)
+	authResp.CookiesSet = true
+}
+
 func (h *AuthHandler) setCookies(c *gin.Context, accessToken, refreshToken string) {
 	domain := h.config.CookieDomain
 	path := "/"
@@ -2,7 +2,12 @@ package handler
 
 import (
 	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"path/filepath"
+	"strings"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
@@ -34,8 +39,7 @@ func NewBackupHandler(
 func (h *BackupHandler) CreateBackup(c *gin.Context) {
 	var req dto.CreateBackupRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -53,10 +57,16 @@ func (h *BackupHandler) CreateBackup(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	store := req.Mode == dto.BackupModeStore || req.Mode == dto.BackupModeBoth
 
-	reader, filename, err := h.backupService.CreateBackup(c.Request.Context(), projectID, userID, req.Password)
+	reader, filename, archive, err := h.backupService.CreateBackup(c.Request.Context(), projectID, userID, req.Password, store)
 	if err != nil {
 		logger.Error().
 			Err(err).
@@ -69,60 +79,484 @@ func (h *BackupHandler) CreateBackup(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeBackupTooLarge)))
 			return
 		}
+		if errors.Is(err, service.ErrBackupStorageDisabled) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeBackupStorageDisabled)))
+			return
+		}
 		if errors.Is(err, service.ErrInsufficientPermission) {
 			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrProjectAccessDenied) || errors.Is(err, mongo.ErrNoDocuments) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeProjectAccessDenied)))
+		if errors.Is(err, service.ErrProjectNotFound) || errors.Is(err, mongo.ErrNoDocuments) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
 			return
 		}
 
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	if req.Mode == dto.BackupModeStore {
+		c.JSON(http.StatusCreated, dto.NewAPIResponse(dto.ToBackupArchiveResponse(archive), nil))
 		return
 	}
+	if archive != nil {
+		c.Header("X-Backup-Archive-Id", archive.ID.Hex())
+	}
 
 	c.Header("Content-Disposition", "attachment; filename="+filename)
 	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", reader, nil)
 }
 
-// RestoreBackup handles POST /projects/restore
-func (h *BackupHandler) RestoreBackup(c *gin.Context) {
-	fileHeader, err := c.FormFile("file")
+// ListStoredBackups handles GET /projects/:project_id/backups
+func (h *BackupHandler) ListStoredBackups(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Backup file is required")))
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
 		return
 	}
 
-	password := c.PostForm("password")
-	if len(password) < 8 {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var params dto.PaginationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		params = dto.DefaultPaginationParams()
+	}
+	if err := params.Validate(); err != nil {
 		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Password must be at least 8 characters")))
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
 		return
 	}
 
-	file, err := fileHeader.Open()
+	archives, totalCount, err := h.backupService.ListStoredBackups(
+		c.Request.Context(), projectID, userID, params.GetOffset(), params.GetLimit())
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInsufficientPermission):
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+		case errors.Is(err, service.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+		default:
+			logger.Error().
+				Err(err).
+				Str("project_id", projectIDStr).
+				Msg("Failed to list stored backups")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		}
+		return
+	}
+
+	responses := make([]dto.BackupArchiveResponse, 0, len(archives))
+	for _, archive := range archives {
+		responses = append(responses, dto.ToBackupArchiveResponse(archive))
+	}
+
+	paginationMeta := dto.NewPaginationMeta(params, totalCount)
+	c.JSON(http.StatusOK, dto.NewAPIResponseWithPagination(responses, &paginationMeta))
+}
+
+// DownloadStoredBackup handles GET /projects/:project_id/backups/:backup_id/download
+func (h *BackupHandler) DownloadStoredBackup(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Cannot read backup file")))
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
+
+	archiveIDStr := c.Param("backup_id")
+	archiveID, err := primitive.ObjectIDFromHex(archiveIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid backup ID")))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	reader, archive, err := h.backupService.OpenStoredBackup(c.Request.Context(), projectID, archiveID, userID)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrBackupArchiveNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeBackupArchiveNotFound)))
+		case errors.Is(err, service.ErrBackupStorageDisabled):
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeBackupStorageDisabled)))
+		case errors.Is(err, service.ErrInsufficientPermission):
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+		case errors.Is(err, service.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+		default:
+			logger.Error().
+				Err(err).
+				Str("project_id", projectIDStr).
+				Str("backup_id", archiveIDStr).
+				Msg("Failed to open stored backup")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		}
+		return
+	}
+	defer reader.Close()
+
+	c.Header("Content-Disposition", "attachment; filename="+archive.Filename)
+	c.DataFromReader(http.StatusOK, archive.Size, "application/octet-stream", reader, nil)
+}
+
+// GetBackupSchedule handles GET /projects/:project_id/backup-schedule
+func (h *BackupHandler) GetBackupSchedule(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	schedule, err := h.backupService.GetBackupSchedule(c.Request.Context(), projectID, userID)
+	if err != nil {
+		h.respondScheduleError(c, err, projectIDStr, "Failed to get backup schedule")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToBackupScheduleResponse(schedule, ""), nil))
+}
+
+// UpdateBackupSchedule handles PUT /projects/:project_id/backup-schedule
+func (h *BackupHandler) UpdateBackupSchedule(c *gin.Context) {
+	var req dto.UpdateBackupScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	schedule, backupKey, err := h.backupService.UpdateBackupSchedule(
+		c.Request.Context(), projectID, userID, req.Enabled, req.IntervalHours, req.RotateKey)
+	if err != nil {
+		h.respondScheduleError(c, err, projectIDStr, "Failed to update backup schedule")
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectIDStr).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Bool("enabled", schedule.Enabled).
+		Int("interval_hours", schedule.IntervalHours).
+		Msg("Backup schedule updated")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToBackupScheduleResponse(schedule, backupKey), nil))
+}
+
+func (h *BackupHandler) respondScheduleError(c *gin.Context, err error, projectIDStr, msg string) {
+	switch {
+	case errors.Is(err, service.ErrBackupStorageDisabled):
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupStorageDisabled)))
+	case errors.Is(err, service.ErrInsufficientPermission):
+		c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+	case errors.Is(err, service.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+	default:
+		logger.Error().
+			Err(err).
+			Str("project_id", projectIDStr).
+			Msg(msg)
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+	}
+}
+
+// ExportDiagram handles POST /projects/:project_id/diagrams/:diagram_id/export
+func (h *BackupHandler) ExportDiagram(c *gin.Context) {
+	var req dto.ExportDiagramRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
+
+	diagramIDStr := c.Param("diagram_id")
+	diagramID, err := primitive.ObjectIDFromHex(diagramIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid diagram ID")))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	reader, filename, err := h.backupService.ExportDiagram(c.Request.Context(), projectID, diagramID, userID, req.Password)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrDiagramNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeDiagramNotFound)))
+		case errors.Is(err, service.ErrInsufficientPermission):
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+		case errors.Is(err, service.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+		default:
+			logger.Error().
+				Err(err).
+				Str("project_id", projectIDStr).
+				Str("diagram_id", diagramIDStr).
+				Str("user_id", logger.SanitizeUserID(userID.Hex())).
+				Msg("Failed to export diagram")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		}
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+filename)
+	c.DataFromReader(http.StatusOK, -1, "application/octet-stream", reader, nil)
+}
+
+// RestoreBackup handles POST /projects/restore
+//
+// Diagram exports are imported into the project given by the optional
+// project_id form field rather than creating a new project.
+func (h *BackupHandler) RestoreBackup(c *gin.Context) {
+	file, ok := openBackupUpload(c)
+	if !ok {
 		return
 	}
 	defer file.Close()
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	password, ok := restorePassword(c)
+	if !ok {
+		return
+	}
+
+	var targetProjectID *primitive.ObjectID
+	if raw := c.PostForm("project_id"); raw != "" {
+		id, err := primitive.ObjectIDFromHex(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+			return
+		}
+		targetProjectID = &id
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
-	project, err := h.backupService.RestoreBackup(c.Request.Context(), userID, password, file)
+	project, err := h.backupService.RestoreBackup(c.Request.Context(), userID, password, file, targetProjectID)
 	if err != nil {
 		logger.Error().
 			Err(err).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to restore backup")
+		respondRestoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(
+		&dto.RestoreBackupResponse{
+			Project: dto.ToProjectResponse(project),
+		},
+		nil,
+	))
+}
+
+// MergeBackup handles POST /projects/:project_id/backup/merge
+//
+// The backup's diagrams, notes and vaults are imported into the existing
+// project instead of creating a new one. Needs manage_project permission.
+func (h *BackupHandler) MergeBackup(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
+
+	file, ok := openBackupUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	password, ok := restorePassword(c)
+	if !ok {
+		return
+	}
 
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	project, err := h.backupService.MergeBackup(c.Request.Context(), projectID, userID, password, file)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to merge backup")
+		respondRestoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(
+		&dto.RestoreBackupResponse{
+			Project: dto.ToProjectResponse(project),
+		},
+		nil,
+	))
+}
+
+// restorePassword reads the archive password from a restore form, writing a
+// 400 response when its length is out of bounds
+func restorePassword(c *gin.Context) (string, bool) {
+	password := c.PostForm("password")
+	if len(password) < 8 {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Password must be at least 8 characters")))
+		return "", false
+	}
+	if len(password) > service.MaxPasswordLength {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest,
+				fmt.Sprintf("Password must be at most %d characters", service.MaxPasswordLength))))
+		return "", false
+	}
+	return password, true
+}
+
+// respondRestoreError maps errors from restoring or merging a backup
+func respondRestoreError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrBackupTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupTooLarge)))
+	case errors.Is(err, service.ErrBackupInvalidFormat):
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupInvalidFormat)))
+	case errors.Is(err, service.ErrBackupVersionMismatch):
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupVersionMismatch)))
+	case errors.Is(err, service.ErrBackupDecryptionFailed):
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupDecryptionFailed)))
+	case errors.Is(err, service.ErrBackupTargetRequired):
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupTargetRequired)))
+	case errors.Is(err, service.ErrBackupPepperUnknown):
+		c.JSON(http.StatusUnprocessableEntity, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupPepperUnknown)))
+	case errors.Is(err, service.ErrBackupCorrupted):
+		c.JSON(http.StatusUnprocessableEntity, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupCorrupted)))
+	case errors.Is(err, service.ErrBackupRestoreFailed):
+		status, _ := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupRestoreFailed)))
+	case errors.Is(err, service.ErrPasswordTooLong):
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+	case errors.Is(err, service.ErrInsufficientPermission):
+		c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+	case errors.Is(err, service.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+	default:
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+	}
+}
+
+// InspectBackup handles POST /projects/restore/inspect
+func (h *BackupHandler) InspectBackup(c *gin.Context) {
+	file, ok := openBackupUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	manifest, err := h.backupService.InspectBackup(file)
+	if err != nil {
 		switch {
 		case errors.Is(err, service.ErrBackupTooLarge):
 			c.JSON(http.StatusRequestEntityTooLarge, dto.NewAPIResponse[any](nil,
@@ -133,20 +567,161 @@ func (h *BackupHandler) RestoreBackup(c *gin.Context) {
 		case errors.Is(err, service.ErrBackupVersionMismatch):
 			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeBackupVersionMismatch)))
-		case errors.Is(err, service.ErrBackupDecryptionFailed):
-			c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeBackupDecryptionFailed)))
+		case errors.Is(err, service.ErrBackupManifestMissing):
+			c.JSON(http.StatusUnprocessableEntity, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeBackupManifestMissing)))
+		case errors.Is(err, service.ErrBackupCorrupted):
+			c.JSON(http.StatusUnprocessableEntity, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeBackupCorrupted)))
 		default:
-			c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeInternalError)))
+			logger.Error().Err(err).Msg("Failed to inspect backup")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		}
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.NewAPIResponse(
-		&dto.RestoreBackupResponse{
-			Project: dto.ToProjectResponse(project),
-		},
-		nil,
-	))
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToBackupManifestResponse(manifest), nil))
+}
+
+// VerifyBackup handles POST /backups/verify
+//
+// Checks the archive's checksum and, when the optional password form field is
+// set, that it decrypts. Nothing is restored.
+func (h *BackupHandler) VerifyBackup(c *gin.Context) {
+	file, ok := openBackupUpload(c)
+	if !ok {
+		return
+	}
+	defer file.Close()
+
+	var password string
+	if c.PostForm("password") != "" {
+		if password, ok = restorePassword(c); !ok {
+			return
+		}
+	}
+
+	result, err := h.backupService.VerifyBackup(file, password)
+	if err != nil {
+		respondRestoreError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToBackupVerificationResponse(result), nil))
+}
+
+// CloneProject handles POST /projects/:project_id/clone
+func (h *BackupHandler) CloneProject(c *gin.Context) {
+	// Body is optional
+	var req dto.CloneProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeBindError(c, err)
+		return
+	}
+
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	project, err := h.backupService.CloneProject(c.Request.Context(), projectID, userID, req.Name)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectIDStr).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to clone project")
+		status, errResp := dto.NewServerErrorResponse(err)
+		if errors.Is(err, service.ErrBackupRestoreFailed) {
+			errResp = dto.NewErrorResponse(dto.ErrCodeBackupRestoreFailed)
+		}
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectIDStr).
+		Str("new_project_id", project.ID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Msg("Project cloned")
+
+	c.JSON(http.StatusCreated, dto.NewAPIResponse(dto.ToProjectResponse(project), nil))
+}
+
+// backupUploadOverhead leaves room in a restore request for the multipart
+// framing and the small form fields sent next to the archive
+const backupUploadOverhead = 1 << 20
+
+// openBackupUpload opens the uploaded archive in the "file" form field. The
+// body is capped before the multipart form is parsed so an oversized upload
+// is refused with 413 instead of being spooled to memory or disk first. It
+// writes the error response and returns false when the upload is unusable.
+func openBackupUpload(c *gin.Context) (multipart.File, bool) {
+	const maxUpload = service.MaxBackupSize + backupUploadOverhead
+
+	if c.Request.ContentLength > maxUpload {
+		c.JSON(http.StatusRequestEntityTooLarge, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupTooLarge)))
+		return nil, false
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUpload)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeBackupTooLarge)))
+			return nil, false
+		}
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Backup file is required")))
+		return nil, false
+	}
+
+	if fileHeader.Size > service.MaxBackupSize {
+		c.JSON(http.StatusRequestEntityTooLarge, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupTooLarge)))
+		return nil, false
+	}
+	if !strings.EqualFold(filepath.Ext(fileHeader.Filename), service.BackupFileExtension) {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeBackupInvalidFormat)))
+		return nil, false
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Cannot read backup file")))
+		return nil, false
+	}
+	return file, true
 }
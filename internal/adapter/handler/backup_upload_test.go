@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+// zeros is an endless stream of zero bytes
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// multipartUpload streams a form with a "file" part of size bytes
+func multipartUpload(filename string, size int64) (io.Reader, string) {
+	pr, pw := io.Pipe()
+	form := multipart.NewWriter(pw)
+	go func() {
+		part, err := form.CreateFormFile("file", filename)
+		if err == nil {
+			_, err = io.Copy(part, io.LimitReader(zeros{}, size))
+		}
+		if err == nil {
+			err = form.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	return pr, form.FormDataContentType()
+}
+
+func TestOpenBackupUpload(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/restore", func(c *gin.Context) {
+		file, ok := openBackupUpload(c)
+		if !ok {
+			return
+		}
+		defer file.Close()
+		c.Status(http.StatusNoContent)
+	})
+
+	tests := []struct {
+		name       string
+		filename   string
+		size       int64
+		declared   int64 // Content-Length; -1 streams the body
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "small archive", filename: "infra.infbk", size: 1024, declared: -1, wantStatus: http.StatusNoContent},
+		{name: "declared length over the limit", filename: "infra.infbk", size: 1024, declared: service.MaxBackupSize + backupUploadOverhead + 1,
+			wantStatus: http.StatusRequestEntityTooLarge, wantCode: dto.ErrCodeBackupTooLarge},
+		{name: "streamed past the limit", filename: "infra.infbk", size: service.MaxBackupSize + backupUploadOverhead, declared: -1,
+			wantStatus: http.StatusRequestEntityTooLarge, wantCode: dto.ErrCodeBackupTooLarge},
+		{name: "file over the archive limit", filename: "infra.infbk", size: service.MaxBackupSize + 1, declared: -1,
+			wantStatus: http.StatusRequestEntityTooLarge, wantCode: dto.ErrCodeBackupTooLarge},
+		{name: "wrong extension", filename: "infra.zip", size: 1024, declared: -1,
+			wantStatus: http.StatusBadRequest, wantCode: dto.ErrCodeBackupInvalidFormat},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, contentType := multipartUpload(tt.filename, tt.size)
+			req := httptest.NewRequest(http.MethodPost, "/restore", body)
+			req.Header.Set("Content-Type", contentType)
+			req.ContentLength = tt.declared
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if tt.wantCode == "" {
+				return
+			}
+			var resp dto.APIResponse[any]
+			if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Error == nil || resp.Error.Code != tt.wantCode {
+				t.Errorf("error = %+v, want %s", resp.Error, tt.wantCode)
+			}
+		})
+	}
+
+	t.Run("no file", func(t *testing.T) {
+		var buf bytes.Buffer
+		form := multipart.NewWriter(&buf)
+		_ = form.WriteField("password", "secret")
+		_ = form.Close()
+		req := httptest.NewRequest(http.MethodPost, "/restore", strings.NewReader(buf.String()))
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("status = %d, want %d", recorder.Code, http.StatusBadRequest)
+		}
+	})
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// batchDelete is a parsed batch delete request. Malformed IDs already carry
+// their error in results; ids holds the rest, with indexes pointing back at
+// their result.
+type batchDelete struct {
+	results []dto.BatchDeleteResult
+	ids     []primitive.ObjectID
+	indexes []int
+}
+
+// bindBatchDelete reads a batch delete request, writing a 400 response when
+// the body itself is invalid
+func bindBatchDelete(c *gin.Context, validator *validation.ValidationEngine) (batchDelete, bool) {
+	var req dto.BatchDeleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return batchDelete{}, false
+	}
+
+	// Validate request
+	if validationErrors := validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return batchDelete{}, false
+	}
+
+	batch := batchDelete{
+		results: make([]dto.BatchDeleteResult, len(req.IDs)),
+		ids:     make([]primitive.ObjectID, 0, len(req.IDs)),
+		indexes: make([]int, 0, len(req.IDs)),
+	}
+	for i, rawID := range req.IDs {
+		batch.results[i] = dto.BatchDeleteResult{Index: i, ID: rawID}
+		id, err := primitive.ObjectIDFromHex(rawID)
+		if err != nil {
+			batch.results[i].Error = dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid ID")
+			continue
+		}
+		batch.ids = append(batch.ids, id)
+		batch.indexes = append(batch.indexes, i)
+	}
+	return batch, true
+}
+
+// apply records the service's per-ID outcomes, naming each failure with
+// errorCode
+func (b batchDelete) apply(errs []error, errorCode func(error) string) []dto.BatchDeleteResult {
+	for j, i := range b.indexes {
+		if errs[j] != nil {
+			b.results[i].Error = dto.NewErrorResponse(errorCode(errs[j]))
+			continue
+		}
+		b.results[i].Deleted = true
+	}
+	return b.results
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type stubNoteRepo struct {
+	port.NoteRepository
+	notes   []*domain.Note
+	deleted []primitive.ObjectID
+}
+
+func (r *stubNoteRepo) FindByIDs(_ context.Context, ids []primitive.ObjectID) ([]*domain.Note, error) {
+	var found []*domain.Note
+	for _, n := range r.notes {
+		if slices.Contains(ids, n.ID) {
+			found = append(found, n)
+		}
+	}
+	return found, nil
+}
+
+func (r *stubNoteRepo) DeleteMany(_ context.Context, ids []primitive.ObjectID) error {
+	r.deleted = append(r.deleted, ids...)
+	return nil
+}
+
+func TestBatchDeleteNotesReportsEachID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	note := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: domain.NoteTypeNote}
+	foreign := &domain.Note{ID: primitive.NewObjectID(), ProjectID: primitive.NewObjectID(), Type: domain.NoteTypeNote}
+	repo := &stubNoteRepo{notes: []*domain.Note{note, foreign}}
+	authz := service.NewAuthorizationService(&stubMemberRepo{
+		member: &domain.ProjectMember{ProjectID: projectID, UserID: userID, Role: domain.RoleOwner},
+	})
+	h := NewNoteHandler(service.NewNoteService(repo, authz, nil, service.PayloadLimits{}, nopPublisher{}), validation.NewValidationEngine())
+
+	router := gin.New()
+	router.POST("/projects/:project_id/notes/batch-delete", func(c *gin.Context) {
+		c.Set("user_id", userID.Hex())
+		h.BatchDeleteNotes(c)
+	})
+	post := func(body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost,
+			"/projects/"+projectID.Hex()+"/notes/batch-delete", strings.NewReader(body)))
+		return recorder
+	}
+
+	ids := []string{note.ID.Hex(), "not-an-id", foreign.ID.Hex(), primitive.NewObjectID().Hex()}
+	body, _ := json.Marshal(dto.BatchDeleteRequest{IDs: ids})
+	recorder := post(string(body))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", recorder.Code, recorder.Body)
+	}
+	var resp dto.APIResponse[[]dto.BatchDeleteResult]
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	wantCodes := []string{"", dto.ErrCodeInvalidRequest, dto.ErrCodeNoteNotFound, dto.ErrCodeNoteNotFound}
+	if len(resp.Data) != len(ids) {
+		t.Fatalf("got %d results, want %d", len(resp.Data), len(ids))
+	}
+	for i, result := range resp.Data {
+		code := ""
+		if result.Error != nil {
+			code = result.Error.Code
+		}
+		if result.Index != i || result.ID != ids[i] || result.Deleted != (wantCodes[i] == "") || code != wantCodes[i] {
+			t.Errorf("result %d = %+v, want error %q", i, result, wantCodes[i])
+		}
+	}
+	if !slices.Equal(repo.deleted, []primitive.ObjectID{note.ID}) {
+		t.Errorf("deleted %v, want only the project's note", repo.deleted)
+	}
+
+	for _, body := range []string{`{"ids":[]}`, `{}`} {
+		if recorder := post(body); recorder.Code != http.StatusBadRequest {
+			t.Errorf("%s: status = %d, want 400", body, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/middleware"
+	"github.com/gin-gonic/gin"
+)
+
+// writeBindError answers a request whose JSON body failed to bind: 413 when
+// the body limit cut the body off, otherwise 400 with the optional message
+func writeBindError(c *gin.Context, err error, message ...string) {
+	if isBodyTooLarge(err) {
+		middleware.RespondBodyTooLarge(c)
+		return
+	}
+	c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+		dto.NewErrorResponse(dto.ErrCodeInvalidRequest, message...)))
+}
+
+// isBodyTooLarge reports whether err comes from reading past the limit
+// BodyLimitMiddleware put on the body
+func isBodyTooLarge(err error) bool {
+	var tooLarge *http.MaxBytesError
+	return errors.As(err, &tooLarge)
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/middleware"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestBodyCutOffByLimitIs413(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := NewNodeVaultHandler(nil, validation.NewValidationEngine())
+	router := gin.New()
+	router.Use(middleware.BodyLimitMiddleware(64))
+	router.POST("/projects/:project_id/nodes/:node_id/vault", h.CreateVaultItem)
+	path := "/projects/" + primitive.NewObjectID().Hex() + "/nodes/" + primitive.NewObjectID().Hex() + "/vault"
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "over the limit", body: `{"label":"` + strings.Repeat("x", 128) + `"}`, wantStatus: http.StatusRequestEntityTooLarge},
+		{name: "malformed within the limit", body: `{"label":`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Hide the length, as a chunked upload would, so the body is
+			// only cut off while binding
+			req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(tt.body))
+			req.ContentLength = -1
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+		})
+	}
+}
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"net/http"
+	"strings"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
@@ -25,7 +29,9 @@ func NewBreadcrumbHandler(service *service.BreadcrumbService) *BreadcrumbHandler
 // @Param project_id path string true "Project ID"
 // @Param type query string true "Resource Type (project, note, diagram, node, vault)"
 // @Param id query string false "Resource ID"
+// @Param If-None-Match header string false "ETag of a previous response"
 // @Success 200 {object} dto.APIResponse[dto.BreadcrumbResponse]
+// @Success 304 "Breadcrumbs unchanged"
 // @Router /api/v1/projects/{project_id}/breadcrumbs [get]
 func (h *BreadcrumbHandler) GetBreadcrumbs(c *gin.Context) {
 	projectID := c.Param("project_id")
@@ -44,7 +50,14 @@ func (h *BreadcrumbHandler) GetBreadcrumbs(c *gin.Context) {
 		return
 	}
 
-	breadcrumbs, err := h.service.GetBreadcrumbs(c.Request.Context(), projectID, resourceType, resourceID)
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	breadcrumbs, err := h.service.GetBreadcrumbs(c.Request.Context(), projectID, userID, resourceType, resourceID)
 	if err != nil {
 		if errors.Is(err, service.ErrInvalidID) {
 			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
@@ -61,11 +74,48 @@ func (h *BreadcrumbHandler) GetBreadcrumbs(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid resource type")))
 		} else {
 			logger.Error().Err(err).Msg("Failed to get breadcrumbs")
-			c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeInternalError)))
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		}
 		return
 	}
 
+	// The response envelope carries a timestamp, so the tag covers only the
+	// breadcrumbs themselves
+	body, err := json.Marshal(breadcrumbs)
+	if err != nil {
+		logger.Error().Err(err).Msg("Failed to encode breadcrumbs")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+	sum := sha256.Sum256(body)
+	etag := `W/"` + hex.EncodeToString(sum[:16]) + `"`
+
+	// Breadcrumbs depend on the caller's access, so only private caches may
+	// keep them, and only after checking back
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "private, no-cache")
+	if etagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
 	c.JSON(http.StatusOK, dto.NewAPIResponse(breadcrumbs, nil))
 }
+
+// etagMatches reports whether an If-None-Match header lists etag, comparing
+// weakly as RFC 9110 requires for that header
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
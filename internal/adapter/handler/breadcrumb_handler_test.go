@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type stubProjectRepo struct {
+	port.ProjectRepository
+	project *domain.Project
+}
+
+func (r *stubProjectRepo) FindByID(context.Context, primitive.ObjectID) (*domain.Project, error) {
+	project := *r.project
+	return &project, nil
+}
+
+func TestBreadcrumbsETag(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	projects := &stubProjectRepo{project: &domain.Project{ID: projectID, Name: "Infra"}}
+	authz := service.NewAuthorizationService(&stubMemberRepo{member: &domain.ProjectMember{ProjectID: projectID, UserID: userID}})
+	handler := NewBreadcrumbHandler(service.NewBreadcrumbService(projects, nil, nil, nil, nil, authz, service.NewDiagramPathCache(0)))
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", userID.Hex()) })
+	router.GET("/projects/:project_id/breadcrumbs", handler.GetBreadcrumbs)
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/projects/"+projectID.Hex()+"/breadcrumbs?type=project", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, req)
+		return recorder
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || !strings.HasPrefix(etag, `W/"`) {
+		t.Fatalf("status = %d, ETag = %q; want 200 with a weak tag", first.Code, etag)
+	}
+	if got := first.Header().Get("Cache-Control"); got != "private, no-cache" {
+		t.Errorf("Cache-Control = %q", got)
+	}
+
+	for _, header := range []string{etag, strings.TrimPrefix(etag, "W/"), `"other", ` + etag, "*"} {
+		resp := get(header)
+		if resp.Code != http.StatusNotModified || resp.Body.Len() != 0 {
+			t.Errorf("If-None-Match %s: status = %d with %d body bytes, want an empty 304", header, resp.Code, resp.Body.Len())
+		}
+	}
+	if resp := get(`W/"stale"`); resp.Code != http.StatusOK {
+		t.Errorf("stale tag: status = %d, want 200", resp.Code)
+	}
+
+	// Renaming the project changes the breadcrumbs and so the tag
+	projects.project.Name = "Infra (prod)"
+	resp := get(etag)
+	if resp.Code != http.StatusOK || resp.Header().Get("ETag") == etag {
+		t.Errorf("after rename: status = %d, ETag = %q; want 200 with a new tag", resp.Code, resp.Header().Get("ETag"))
+	}
+}
@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type CommentHandler struct {
+	comments  *service.CommentService
+	validator *validation.ValidationEngine
+}
+
+func NewCommentHandler(comments *service.CommentService, validator *validation.ValidationEngine) *CommentHandler {
+	return &CommentHandler{
+		comments:  comments,
+		validator: validator,
+	}
+}
+
+// CreateComment godoc
+// @Summary Comment on a project, diagram or node
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param request body dto.CreateCommentRequest true "Create Comment Request"
+// @Success 201 {object} dto.APIResponse[dto.CommentResponse]
+// @Router /api/v1/projects/{project_id}/comments [post]
+func (h *CommentHandler) CreateComment(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var req dto.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	// Both IDs were validated above; empty ones stay zero
+	targetID, _ := primitive.ObjectIDFromHex(req.TargetID)
+	parentID, _ := primitive.ObjectIDFromHex(req.ParentCommentID)
+	target := domain.CommentTarget{Type: req.TargetType, ID: targetID}
+
+	comment, err := h.comments.CreateComment(c.Request.Context(), projectID, userID, target, parentID,
+		req.EncryptedBody, req.EncryptedBodySignature)
+	if err != nil {
+		h.respondError(c, err, projectID, userID, "Failed to create comment")
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewAPIResponse(dto.ToCommentResponse(comment), nil))
+}
+
+// ListComments godoc
+// @Summary List the comments on a project, diagram or node
+// @Description Lists top-level comments, or the replies of one thread when parent_comment_id is set, oldest first.
+// @Tags comments
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param target_type query string true "project, diagram or node"
+// @Param target_id query string false "Diagram or node ID"
+// @Param parent_comment_id query string false "List the replies to this comment"
+// @Param cursor query string false "Cursor from the previous page"
+// @Param limit query int false "Items per page"
+// @Success 200 {object} dto.APIResponse[[]dto.CommentResponse]
+// @Router /api/v1/projects/{project_id}/comments [get]
+func (h *CommentHandler) ListComments(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var query dto.CommentListParams
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+	if validationErrors := h.validator.ValidateStruct(query); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	var params dto.CursorParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+	afterID, err := params.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+
+	targetID, _ := primitive.ObjectIDFromHex(query.TargetID)
+	parentID, _ := primitive.ObjectIDFromHex(query.ParentCommentID)
+	target := domain.CommentTarget{Type: query.TargetType, ID: targetID}
+
+	// Fetch one extra item to detect whether another page exists
+	comments, err := h.comments.ListComments(c.Request.Context(), projectID, userID, target, parentID, afterID, params.Limit+1)
+	if err != nil {
+		h.respondError(c, err, projectID, userID, "Failed to list comments")
+		return
+	}
+
+	hasMore := len(comments) > params.Limit
+	if hasMore {
+		comments = comments[:params.Limit]
+	}
+
+	responses := make([]dto.CommentResponse, 0, len(comments))
+	var lastID primitive.ObjectID
+	for _, comment := range comments {
+		responses = append(responses, dto.ToCommentResponse(comment))
+		lastID = comment.ID
+	}
+
+	cursorMeta := dto.NewCursorMeta(params.Limit, lastID, hasMore)
+	c.JSON(http.StatusOK, dto.NewAPIResponseWithCursor(responses, &cursorMeta))
+}
+
+// UpdateComment godoc
+// @Summary Edit your own comment
+// @Tags comments
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param comment_id path string true "Comment ID"
+// @Param request body dto.UpdateCommentRequest true "Update Comment Request"
+// @Success 200 {object} dto.APIResponse[dto.CommentResponse]
+// @Router /api/v1/projects/{project_id}/comments/{comment_id} [put]
+func (h *CommentHandler) UpdateComment(c *gin.Context) {
+	projectID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var req dto.UpdateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	comment, err := h.comments.UpdateComment(c.Request.Context(), projectID, commentID, userID,
+		req.EncryptedBody, req.EncryptedBodySignature)
+	if err != nil {
+		h.respondError(c, err, projectID, userID, "Failed to update comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToCommentResponse(comment), nil))
+}
+
+// DeleteComment godoc
+// @Summary Delete a comment and its replies
+// @Tags comments
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param comment_id path string true "Comment ID"
+// @Success 200 {object} dto.APIResponse[any]
+// @Router /api/v1/projects/{project_id}/comments/{comment_id} [delete]
+func (h *CommentHandler) DeleteComment(c *gin.Context) {
+	projectID, commentID, ok := parseCommentPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	if err := h.comments.DeleteComment(c.Request.Context(), projectID, commentID, userID); err != nil {
+		h.respondError(c, err, projectID, userID, "Failed to delete comment")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+		"message": "Comment deleted",
+	}, nil))
+}
+
+func (h *CommentHandler) respondError(c *gin.Context, err error, projectID, userID primitive.ObjectID, msg string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+	case errors.Is(err, service.ErrDiagramNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeDiagramNotFound)))
+	case errors.Is(err, service.ErrNodeNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeNodeNotFound)))
+	case errors.Is(err, service.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeCommentNotFound)))
+	case errors.Is(err, service.ErrInvalidCommentTarget):
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "A target_id is required for diagram and node comments")))
+	case errors.Is(err, service.ErrInsufficientPermission):
+		c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+	default:
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg(msg)
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+	}
+}
+
+// parseCommentPath reads the project and comment IDs from the route, writing
+// a 400 response when either is malformed
+func parseCommentPath(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	commentID, err := primitive.ObjectIDFromHex(c.Param("comment_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	return projectID, commentID, true
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// currentUserID extracts the authenticated user's ID set by the auth middleware.
+// It returns false if the value is missing or is not a valid ObjectID.
+func currentUserID(c *gin.Context) (primitive.ObjectID, bool) {
+	value, exists := c.Get("user_id")
+	if !exists {
+		return primitive.NilObjectID, false
+	}
+
+	userIDStr, ok := value.(string)
+	if !ok {
+		return primitive.NilObjectID, false
+	}
+
+	userID, err := primitive.ObjectIDFromHex(userIDStr)
+	if err != nil {
+		return primitive.NilObjectID, false
+	}
+
+	return userID, true
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"errors"
+	"io"
 	"net/http"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
@@ -40,8 +41,7 @@ func (h *DiagramHandler) CreateDiagram(c *gin.Context) {
 
 	var req dto.CreateDiagramRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -53,8 +53,12 @@ func (h *DiagramHandler) CreateDiagram(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Parse parent diagram ID if provided
 	var parentDiagramID *primitive.ObjectID
@@ -80,6 +84,11 @@ func (h *DiagramHandler) CreateDiagram(c *gin.Context) {
 		req.EncryptedDataSignature,
 	)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidDiagramData) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidDiagramData)))
+			return
+		}
 		if errors.Is(err, service.ErrInsufficientPermission) {
 			logger.Warn().
 				Str("project_id", projectID.Hex()).
@@ -89,9 +98,9 @@ func (h *DiagramHandler) CreateDiagram(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrDiagramAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeDiagramAccessDenied)))
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
 			return
 		}
 		logger.Error().
@@ -99,8 +108,8 @@ func (h *DiagramHandler) CreateDiagram(c *gin.Context) {
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to create diagram")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -126,17 +135,31 @@ func (h *DiagramHandler) ListDiagrams(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	rootOnly := c.Query("root_only") == "true"
+
+	// Switch to cursor mode when a cursor is supplied
+	if _, useCursor := c.GetQuery("cursor"); useCursor {
+		h.listDiagramsByCursor(c, projectID, userID, rootOnly)
+		return
+	}
 
 	// Get pagination params
 	var params dto.PaginationParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		params = dto.DefaultPaginationParams()
 	}
-	params.Validate()
-
-	rootOnly := c.Query("root_only") == "true"
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
 
 	diagrams, totalCount, err := h.diagramService.ListDiagrams(
 		c.Request.Context(),
@@ -152,9 +175,9 @@ func (h *DiagramHandler) ListDiagrams(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrDiagramAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeDiagramAccessDenied)))
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
 			return
 		}
 		logger.Error().
@@ -162,8 +185,8 @@ func (h *DiagramHandler) ListDiagrams(c *gin.Context) {
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to list diagrams")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -178,6 +201,101 @@ func (h *DiagramHandler) ListDiagrams(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.NewAPIResponseWithPagination(responses, &paginationMeta))
 }
 
+// GetDiagramTree handles GET /projects/:project_id/diagrams/tree
+func (h *DiagramHandler) GetDiagramTree(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	tree, err := h.diagramService.GetDiagramTree(c.Request.Context(), projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to build diagram tree")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(tree, nil))
+}
+
+// listDiagramsByCursor lists diagrams using cursor-based pagination ordered by ID
+func (h *DiagramHandler) listDiagramsByCursor(c *gin.Context, projectID, userID primitive.ObjectID, rootOnly bool) {
+	var params dto.CursorParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+	afterID, err := params.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+
+	// Fetch one extra item to detect whether another page exists
+	diagrams, err := h.diagramService.ListDiagramsAfter(c.Request.Context(), projectID, userID, afterID, rootOnly, params.Limit+1)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to list diagrams")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	hasMore := len(diagrams) > params.Limit
+	if hasMore {
+		diagrams = diagrams[:params.Limit]
+	}
+
+	responses := make([]dto.DiagramResponse, 0, len(diagrams))
+	var lastID primitive.ObjectID
+	for _, diagram := range diagrams {
+		responses = append(responses, dto.ToDiagramResponse(diagram))
+		lastID = diagram.ID
+	}
+
+	cursorMeta := dto.NewCursorMeta(params.Limit, lastID, hasMore)
+	c.JSON(http.StatusOK, dto.NewAPIResponseWithCursor(responses, &cursorMeta))
+}
+
 // GetDiagram gets a specific diagram
 func (h *DiagramHandler) GetDiagram(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
@@ -197,8 +315,12 @@ func (h *DiagramHandler) GetDiagram(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	diagram, err := h.diagramService.GetDiagram(c.Request.Context(), diagramID, userID)
 	if err != nil {
@@ -212,23 +334,19 @@ func (h *DiagramHandler) GetDiagram(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrDiagramAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeDiagramAccessDenied)))
-			return
-		}
 		logger.Error().
 			Err(err).
 			Str("diagram_id", diagramID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to get diagram")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
 	// TODO: Get actual timestamps from mgod
 	response := dto.ToDiagramResponse(diagram)
+	response.Lock = dto.ToDiagramLockResponse(h.diagramService.DiagramLock(diagram.ID))
 	c.JSON(http.StatusOK, dto.NewAPIResponse(response, nil))
 }
 
@@ -252,8 +370,7 @@ func (h *DiagramHandler) UpdateDiagram(c *gin.Context) {
 
 	var req dto.UpdateDiagramRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -265,8 +382,12 @@ func (h *DiagramHandler) UpdateDiagram(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Update diagram
 	diagram, err := h.diagramService.UpdateDiagram(
@@ -279,6 +400,11 @@ func (h *DiagramHandler) UpdateDiagram(c *gin.Context) {
 		req.EncryptedDataSignature,
 	)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidDiagramData) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidDiagramData)))
+			return
+		}
 		if errors.Is(err, service.ErrDiagramNotFound) || errors.Is(err, mongo.ErrNoDocuments) {
 			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeDiagramNotFound)))
@@ -293,9 +419,9 @@ func (h *DiagramHandler) UpdateDiagram(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrDiagramAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeDiagramAccessDenied)))
+		if errors.Is(err, service.ErrDiagramLocked) {
+			c.JSON(http.StatusLocked, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeDiagramLocked)))
 			return
 		}
 		logger.Error().
@@ -303,8 +429,8 @@ func (h *DiagramHandler) UpdateDiagram(c *gin.Context) {
 			Str("diagram_id", diagramID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to update diagram")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -337,8 +463,12 @@ func (h *DiagramHandler) DeleteDiagram(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	err = h.diagramService.DeleteDiagram(c.Request.Context(), diagramID, userID)
 	if err != nil {
@@ -356,9 +486,9 @@ func (h *DiagramHandler) DeleteDiagram(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrDiagramAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeDiagramAccessDenied)))
+		if errors.Is(err, service.ErrDiagramLocked) {
+			c.JSON(http.StatusLocked, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeDiagramLocked)))
 			return
 		}
 		logger.Error().
@@ -366,8 +496,8 @@ func (h *DiagramHandler) DeleteDiagram(c *gin.Context) {
 			Str("diagram_id", diagramID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to delete diagram")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -380,3 +510,217 @@ func (h *DiagramHandler) DeleteDiagram(c *gin.Context) {
 		"message": "Diagram deleted successfully",
 	}, nil))
 }
+
+// BatchDeleteDiagrams deletes several diagrams of a project in one request
+// and reports each ID's outcome
+func (h *DiagramHandler) BatchDeleteDiagrams(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	batch, ok := bindBatchDelete(c, h.validator)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	errs, err := h.diagramService.DeleteDiagrams(c.Request.Context(), projectID, userID, batch.ids)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to delete diagrams")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	results := batch.apply(errs, func(err error) string {
+		if errors.Is(err, service.ErrDiagramLocked) {
+			return dto.ErrCodeDiagramLocked
+		}
+		return dto.ErrCodeDiagramNotFound
+	})
+
+	logger.Info().
+		Str("project_id", projectID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Int("requested", len(results)).
+		Msg("Diagrams batch deleted")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(results, nil))
+}
+
+// DuplicateDiagram copies a diagram (and optionally its child diagrams) with all nodes
+func (h *DiagramHandler) DuplicateDiagram(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	diagramIDStr := c.Param("diagram_id")
+	diagramID, err := primitive.ObjectIDFromHex(diagramIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	// Body is optional
+	var req dto.DuplicateDiagramRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeBindError(c, err)
+		return
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	diagram, err := h.diagramService.DuplicateDiagram(
+		c.Request.Context(),
+		projectID,
+		diagramID,
+		userID,
+		req.DiagramName,
+		req.IncludeChildren,
+	)
+	if err != nil {
+		if errors.Is(err, service.ErrDiagramNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeDiagramNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			logger.Warn().
+				Str("diagram_id", diagramID.Hex()).
+				Str("user_id", logger.SanitizeUserID(userID.Hex())).
+				Msg("Insufficient permission to duplicate diagram")
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("diagram_id", diagramID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to duplicate diagram")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	logger.Info().
+		Str("diagram_id", diagramID.Hex()).
+		Str("new_diagram_id", diagram.ID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Bool("include_children", req.IncludeChildren).
+		Msg("Diagram duplicated")
+
+	c.JSON(http.StatusCreated, dto.NewAPIResponse(dto.ToDiagramResponse(diagram), nil))
+}
+
+// LockDiagram takes the caller's edit lock on a diagram, or renews it. While
+// the lock is held other members cannot change the diagram or its nodes.
+func (h *DiagramHandler) LockDiagram(c *gin.Context) {
+	projectID, diagramID, ok := parseDiagramPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	lock, err := h.diagramService.LockDiagram(c.Request.Context(), projectID, diagramID, userID)
+	if err != nil {
+		h.respondLockError(c, err, diagramID, userID, "Failed to lock diagram")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToDiagramLockResponse(lock), nil))
+}
+
+// UnlockDiagram releases the edit lock on a diagram. Members who can manage
+// the project may break a lock someone else holds.
+func (h *DiagramHandler) UnlockDiagram(c *gin.Context) {
+	projectID, diagramID, ok := parseDiagramPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	if err := h.diagramService.UnlockDiagram(c.Request.Context(), projectID, diagramID, userID); err != nil {
+		h.respondLockError(c, err, diagramID, userID, "Failed to unlock diagram")
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+		"message": "Diagram unlocked",
+	}, nil))
+}
+
+func (h *DiagramHandler) respondLockError(c *gin.Context, err error, diagramID, userID primitive.ObjectID, msg string) {
+	switch {
+	case errors.Is(err, service.ErrDiagramNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeDiagramNotFound)))
+	case errors.Is(err, service.ErrInsufficientPermission):
+		c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+	case errors.Is(err, service.ErrDiagramLocked):
+		c.JSON(http.StatusLocked, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeDiagramLocked)))
+	default:
+		logger.Error().
+			Err(err).
+			Str("diagram_id", diagramID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg(msg)
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type stubDiagramRepo struct {
+	port.DiagramRepository
+	diagram *domain.Diagram
+}
+
+func (r *stubDiagramRepo) FindByID(context.Context, primitive.ObjectID) (*domain.Diagram, error) {
+	diagram := *r.diagram
+	return &diagram, nil
+}
+
+type stubNodeRepo struct {
+	port.NodeRepository
+	node *domain.Node
+}
+
+func (r *stubNodeRepo) FindByID(context.Context, primitive.ObjectID) (*domain.Node, error) {
+	node := *r.node
+	return &node, nil
+}
+
+func TestLockedDiagramWritesAre423(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	projectID := primitive.NewObjectID()
+	holder, other := primitive.NewObjectID(), primitive.NewObjectID()
+	diagram := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID}
+	node := &domain.Node{ID: primitive.NewObjectID(), DiagramID: diagram.ID}
+
+	// Every caller is an editor of the project
+	authz := service.NewAuthorizationService(&stubMemberRepo{member: &domain.ProjectMember{
+		ProjectID:   projectID,
+		Permissions: []string{string(domain.PermissionViewDiagram), string(domain.PermissionEditDiagram)},
+	}})
+	diagrams := &stubDiagramRepo{diagram: diagram}
+	locks := service.NewDiagramLocks(time.Minute)
+	diagramService := service.NewDiagramService(diagrams, authz, nil, nil, nil, nil, nil,
+		locks, service.NewDiagramPathCache(0), service.PayloadLimits{}, nopPublisher{})
+	nodeService := service.NewNodeService(&stubNodeRepo{node: node}, diagrams, authz,
+		service.PayloadLimits{}, nopPublisher{}, 0, locks)
+
+	if _, err := diagramService.LockDiagram(context.Background(), projectID, diagram.ID, holder); err != nil {
+		t.Fatalf("LockDiagram: %v", err)
+	}
+
+	validator := validation.NewValidationEngine()
+	diagramHandler := NewDiagramHandler(diagramService, validator)
+	nodeHandler := NewNodeHandler(nodeService, validator)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("user_id", other.Hex()) })
+	router.PUT("/projects/:project_id/diagrams/:diagram_id", diagramHandler.UpdateDiagram)
+	router.PUT("/projects/:project_id/diagrams/:diagram_id/nodes/:node_id", nodeHandler.UpdateNode)
+
+	base := "/projects/" + projectID.Hex() + "/diagrams/" + diagram.ID.Hex()
+	for _, path := range []string{base, base + "/nodes/" + node.ID.Hex()} {
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, path, strings.NewReader(`{"label":"x","diagram_name":"x"}`)))
+		if recorder.Code != http.StatusLocked {
+			t.Errorf("PUT %s: status = %d, want 423: %s", path, recorder.Code, recorder.Body)
+			continue
+		}
+		if !strings.Contains(recorder.Body.String(), dto.ErrCodeDiagramLocked) {
+			t.Errorf("PUT %s: body %s lacks %s", path, recorder.Body, dto.ErrCodeDiagramLocked)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// eventStreamBuffer is how many events may queue for a slow stream
+	// before the bus starts dropping them
+	eventStreamBuffer = 32
+	// eventStreamHeartbeat keeps idle streams from being cut by proxies
+	eventStreamHeartbeat = 25 * time.Second
+)
+
+// eventPermissions lists the view permission a member needs to be told
+// about each resource-scoped event. Project and member events go to every
+// member.
+var eventPermissions = map[event.Type]domain.Permission{
+	event.DiagramCreated:   domain.PermissionViewDiagram,
+	event.DiagramUpdated:   domain.PermissionViewDiagram,
+	event.DiagramDeleted:   domain.PermissionViewDiagram,
+	event.DiagramLocked:    domain.PermissionViewDiagram,
+	event.DiagramUnlocked:  domain.PermissionViewDiagram,
+	event.NodeUpdated:      domain.PermissionViewDiagram,
+	event.NodeDeleted:      domain.PermissionViewDiagram,
+	event.NoteCreated:      domain.PermissionViewNote,
+	event.NoteUpdated:      domain.PermissionViewNote,
+	event.NoteDeleted:      domain.PermissionViewNote,
+	event.VaultItemCreated: domain.PermissionViewVault,
+	event.VaultItemUpdated: domain.PermissionViewVault,
+	event.VaultItemDeleted: domain.PermissionViewVault,
+}
+
+type EventStreamHandler struct {
+	projectService *service.ProjectService
+	authz          *service.AuthorizationService
+	bus            *event.Bus
+	maxPerUser     int
+
+	mu      sync.Mutex
+	streams map[primitive.ObjectID]int
+}
+
+func NewEventStreamHandler(
+	projectService *service.ProjectService,
+	authz *service.AuthorizationService,
+	bus *event.Bus,
+	maxPerUser int,
+) *EventStreamHandler {
+	return &EventStreamHandler{
+		projectService: projectService,
+		authz:          authz,
+		bus:            bus,
+		maxPerUser:     maxPerUser,
+		streams:        make(map[primitive.ObjectID]int),
+	}
+}
+
+// StreamProjectEvents godoc
+// @Summary Stream project change notifications
+// @Description Server-Sent Events stream of changes to the project's
+// @Description resources. Events carry IDs only; clients refetch what they need.
+// @Tags projects
+// @Produce text/event-stream
+// @Param project_id path string true "Project ID"
+// @Success 200 {object} dto.ProjectEventResponse
+// @Failure 429 {object} dto.APIResponse[any]
+// @Router /api/v1/projects/{project_id}/events [get]
+func (h *EventStreamHandler) StreamProjectEvents(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	_, member, err := h.projectService.GetProjectDetails(c.Request.Context(), projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to open project event stream")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	if !h.acquire(userID) {
+		c.JSON(http.StatusTooManyRequests, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeTooManyStreams)))
+		return
+	}
+	defer h.release(userID)
+
+	sub := h.bus.Subscribe(eventStreamBuffer, func(e event.Event) bool {
+		if e.ProjectID != projectID {
+			return false
+		}
+		permission, scoped := eventPermissions[e.Type]
+		return !scoped || h.authz.Can(member, permission)
+	})
+	defer sub.Close()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(eventStreamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+			c.Writer.Flush()
+		case e, ok := <-sub.Events():
+			if !ok {
+				return
+			}
+			c.SSEvent(string(e.Type), dto.ToProjectEventResponse(e))
+			c.Writer.Flush()
+
+			// End the stream when the user loses access or their permissions
+			// change; EventSource reconnects and picks up the new permissions
+			if e.Type == event.ProjectDeleted || (isMemberChange(e.Type) && e.ResourceID == userID) {
+				return
+			}
+		}
+	}
+}
+
+func isMemberChange(eventType event.Type) bool {
+	return eventType == event.MemberRemoved || eventType == event.MemberUpdated
+}
+
+func (h *EventStreamHandler) acquire(userID primitive.ObjectID) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.maxPerUser > 0 && h.streams[userID] >= h.maxPerUser {
+		return false
+	}
+	h.streams[userID]++
+	return true
+}
+
+func (h *EventStreamHandler) release(userID primitive.ObjectID) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.streams[userID]--
+	if h.streams[userID] <= 0 {
+		delete(h.streams, userID)
+	}
+}
@@ -53,8 +53,8 @@ func (h *InvitationHandler) GetInvitation(c *gin.Context) {
 			return
 		}
 		logger.Error().Err(err).Str("invitation_id", invitationIDStr).Msg("Failed to get invitation")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -95,8 +95,7 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 
 	var req dto.AcceptInvitationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -107,8 +106,12 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Convert DTO keyrings to domain keyrings
 	keyrings := make([]domain.ProjectMemberKeyring, len(req.Keyrings))
@@ -145,6 +148,11 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInvitationExpired)))
 			return
 		}
+		if errors.Is(err, service.ErrInvitationDeclined) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvitationDeclined)))
+			return
+		}
 		if errors.Is(err, service.ErrInvitationInvalidPassword) {
 			c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeInvitationInvalidPassword)))
@@ -159,8 +167,8 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 			Str("invitation_id", invitationIDStr).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to accept invitation")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -175,32 +183,125 @@ func (h *InvitationHandler) AcceptInvitation(c *gin.Context) {
 	}, nil))
 }
 
-// SearchUsers searches for users by name, email, or username
+// DeclineInvitation turns down an invitation addressed to the current user
+func (h *InvitationHandler) DeclineInvitation(c *gin.Context) {
+	invitationIDStr := c.Param("invitation_id")
+	invitationID, err := primitive.ObjectIDFromHex(invitationIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	if err := h.projectService.DeclineInvitation(c.Request.Context(), invitationID, userID); err != nil {
+		switch {
+		case errors.Is(err, service.ErrInvitationNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvitationNotFound)))
+		case errors.Is(err, service.ErrInvitationAlreadyAccepted):
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvitationAlreadyAccepted)))
+		case errors.Is(err, service.ErrInvitationExpired):
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvitationExpired)))
+		case errors.Is(err, service.ErrInvitationDeclined):
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvitationDeclined)))
+		default:
+			logger.Error().Err(err).
+				Str("invitation_id", invitationIDStr).
+				Str("user_id", logger.SanitizeUserID(userID.Hex())).
+				Msg("Failed to decline invitation")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+		"message": "Invitation declined",
+	}, nil))
+}
+
+// SearchUsers searches for users by name, email, or username. The caller is
+// never listed; exclude_project also leaves out that project's members.
 func (h *InvitationHandler) SearchUsers(c *gin.Context) {
-	query := c.Query("q")
-	if query == "" {
+	var params dto.UserSearchParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid limit")))
+		return
+	}
+	if params.Query == "" {
 		c.JSON(http.StatusOK, dto.NewAPIResponse([]dto.UserSearchResponse{}, nil))
 		return
 	}
 
 	// Get current user ID to exclude from results
-	userIDStr, _ := c.Get("user_id")
-	currentUserID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+	excludeIDs := []primitive.ObjectID{userID}
+
+	if params.ExcludeProject != "" {
+		projectID, err := primitive.ObjectIDFromHex(params.ExcludeProject)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+			return
+		}
+
+		memberIDs, err := h.projectService.GetMemberUserIDs(c.Request.Context(), projectID, userID)
+		if err != nil {
+			if errors.Is(err, service.ErrInsufficientPermission) {
+				c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+					dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+				return
+			}
+			if errors.Is(err, service.ErrProjectNotFound) {
+				c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+					dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+				return
+			}
+			logger.Error().
+				Err(err).
+				Str("project_id", projectID.Hex()).
+				Str("user_id", logger.SanitizeUserID(userID.Hex())).
+				Msg("Failed to list project members for user search")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+			return
+		}
+		excludeIDs = append(excludeIDs, memberIDs...)
+	}
 
-	users, err := h.userRepo.SearchUsers(c.Request.Context(), query, 10)
+	users, err := h.userRepo.SearchUsers(c.Request.Context(), params.Query, excludeIDs, params.Limit)
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to search users")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
-	// Filter out current user
 	responses := make([]dto.UserSearchResponse, 0, len(users))
 	for _, user := range users {
-		if user.ID != currentUserID {
-			responses = append(responses, dto.ToUserSearchResponse(user))
-		}
+		responses = append(responses, dto.ToUserSearchResponse(user))
 	}
 
 	c.JSON(http.StatusOK, dto.NewAPIResponse(responses, nil))
@@ -209,21 +310,29 @@ func (h *InvitationHandler) SearchUsers(c *gin.Context) {
 // ListUserInvitations lists invitations for the current user
 func (h *InvitationHandler) ListUserInvitations(c *gin.Context) {
 	// Get current user ID
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Parse query params for pagination
 	params := dto.DefaultPaginationParams()
 	if err := c.ShouldBindQuery(&params); err != nil {
 		// Ignore error, use defaults
 	}
-	params.Validate()
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
 
 	invitations, total, err := h.projectService.GetUserInvitations(c.Request.Context(), userID, params.GetOffset(), params.GetLimit())
 	if err != nil {
 		logger.Error().Err(err).Msg("Failed to list user invitations")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -252,3 +361,26 @@ func (h *InvitationHandler) ListUserInvitations(c *gin.Context) {
 	metadata := dto.NewPaginationMeta(params, total)
 	c.JSON(http.StatusOK, dto.NewAPIResponseWithPagination(responses, &metadata))
 }
+
+// CountPendingInvitations returns the number of pending invitations for the current user
+func (h *InvitationHandler) CountPendingInvitations(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	count, err := h.projectService.CountPendingInvitations(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to count pending invitations")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.InvitationCountResponse{Pending: count}, nil))
+}
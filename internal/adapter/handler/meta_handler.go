@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+// MetaHandler serves static definitions clients build their UI from
+type MetaHandler struct {
+	roles dto.RoleMetadataResponse
+}
+
+func NewMetaHandler() *MetaHandler {
+	roles := dto.RoleMetadataResponse{
+		Permissions: make([]dto.PermissionInfo, 0, len(domain.AllPermissions)),
+		Roles:       make(map[string][]string, len(service.RolePresets)),
+	}
+	for _, permission := range domain.AllPermissions {
+		roles.Permissions = append(roles.Permissions, dto.PermissionInfo{
+			Name:        string(permission),
+			Description: domain.PermissionDescriptions[permission],
+		})
+	}
+	for role, permissions := range service.RolePresets {
+		roles.Roles[role] = domain.PermissionStrings(permissions)
+	}
+
+	return &MetaHandler{roles: roles}
+}
+
+// GetRoles godoc
+// @Summary List permissions and role presets
+// @Description Every project permission with a description, and the permissions each role preset grants. Custom roles pick permissions individually.
+// @Tags meta
+// @Produce json
+// @Success 200 {object} dto.APIResponse[dto.RoleMetadataResponse]
+// @Router /api/v1/meta/roles [get]
+func (h *MetaHandler) GetRoles(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.NewAPIResponse(h.roles, nil))
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetRolesMatchesPresets(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/meta/roles", NewMetaHandler().GetRoles)
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/meta/roles", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", recorder.Code)
+	}
+	var resp dto.APIResponse[dto.RoleMetadataResponse]
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp.Data.Roles) != len(service.RolePresets) {
+		t.Errorf("roles = %v, want one entry per preset", resp.Data.Roles)
+	}
+	for role, permissions := range service.RolePresets {
+		if got := resp.Data.Roles[role]; !slices.Equal(got, domain.PermissionStrings(permissions)) {
+			t.Errorf("%s grants %v, want %v", role, got, permissions)
+		}
+	}
+
+	if len(resp.Data.Permissions) != len(domain.AllPermissions) {
+		t.Fatalf("permissions = %v, want all %d", resp.Data.Permissions, len(domain.AllPermissions))
+	}
+	for i, permission := range domain.AllPermissions {
+		info := resp.Data.Permissions[i]
+		if info.Name != string(permission) || info.Description == "" || info.Description != domain.PermissionDescriptions[permission] {
+			t.Errorf("permission %d = %+v, want %s with its description", i, info, permission)
+		}
+	}
+}
+
+func TestPermissionDefinitionsAreComplete(t *testing.T) {
+	if len(domain.ValidPermissions) != len(domain.AllPermissions) {
+		t.Errorf("%d valid permissions, %d listed", len(domain.ValidPermissions), len(domain.AllPermissions))
+	}
+	for _, permission := range domain.AllPermissions {
+		if !domain.IsValidPermission(string(permission)) {
+			t.Errorf("%s is listed but not valid", permission)
+		}
+	}
+	for _, permissions := range service.RolePresets {
+		for _, permission := range permissions {
+			if !slices.Contains(domain.AllPermissions, permission) {
+				t.Errorf("preset grants unlisted permission %s", permission)
+			}
+		}
+	}
+}
@@ -45,8 +45,12 @@ func (h *NodeHandler) GetOrCreateNode(c *gin.Context) {
 	// Service checks it too.
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	node, err := h.nodeService.GetOrCreateNode(c.Request.Context(), nodeIDStr, diagramID, userID)
 	if err != nil {
@@ -66,8 +70,8 @@ func (h *NodeHandler) GetOrCreateNode(c *gin.Context) {
 		// If diagram not found, service returns ErrCodeDiagramNotFound error (wrapped/new)
 
 		logger.Error().Err(err).Str("node_id", nodeIDStr).Msg("Failed to get/create node")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -79,19 +83,34 @@ func (h *NodeHandler) GetOrCreateNode(c *gin.Context) {
 func (h *NodeHandler) UpdateNode(c *gin.Context) {
 	var req dto.UpdateNodeRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
 		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+			dto.NewValidationErrorResponse(validationErrors)))
 		return
 	}
 
 	nodeIDStr := c.Param("node_id")
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	node, err := h.nodeService.UpdateNode(c.Request.Context(), nodeIDStr, userID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidNodeData) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidNodeData)))
+			return
+		}
 		if errors.Is(err, service.ErrNodeAccessDenied) {
 			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeNodeAccessDenied)))
@@ -102,9 +121,19 @@ func (h *NodeHandler) UpdateNode(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeNodeNotFound)))
 			return
 		}
+		if errors.Is(err, service.ErrNodeRateLimited) {
+			c.JSON(http.StatusTooManyRequests, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeNodeRateLimited)))
+			return
+		}
+		if errors.Is(err, service.ErrDiagramLocked) {
+			c.JSON(http.StatusLocked, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeDiagramLocked)))
+			return
+		}
 		logger.Error().Err(err).Str("node_id", nodeIDStr).Msg("Failed to update node")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -117,8 +146,12 @@ func (h *NodeHandler) DeleteNode(c *gin.Context) {
 	nodeIDStr := c.Param("node_id")
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	err := h.nodeService.DeleteNode(c.Request.Context(), nodeIDStr, userID)
 	if err != nil {
@@ -132,9 +165,14 @@ func (h *NodeHandler) DeleteNode(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeNodeNotFound)))
 			return
 		}
+		if errors.Is(err, service.ErrDiagramLocked) {
+			c.JSON(http.StatusLocked, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeDiagramLocked)))
+			return
+		}
 		logger.Error().Err(err).Str("node_id", nodeIDStr).Msg("Failed to delete node")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
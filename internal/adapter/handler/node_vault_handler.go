@@ -2,9 +2,11 @@ package handler
 
 import (
 	"errors"
+	"fmt"
 	"net/http"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
@@ -39,26 +41,46 @@ func (h *NodeVaultHandler) CreateVaultItem(c *gin.Context) {
 	var req dto.CreateNodeVaultRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		logger.Error().Err(err).Msg("Failed to bind JSON in CreateVaultItem")
+		writeBindError(c, err, err.Error())
+		return
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
 		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, err.Error())))
+			dto.NewValidationErrorResponse(validationErrors)))
 		return
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 	projectID, _ := primitive.ObjectIDFromHex(projectIDStr)
 
 	vaultItem, err := h.service.CreateVaultItem(c.Request.Context(), nodeID, projectID, userID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidVaultData) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidVaultItemData)))
+			return
+		}
 		if errors.Is(err, service.ErrVaultAccessDenied) {
 			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeVaultAccessDenied)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
 		logger.Error().Err(err).Msg("Failed to create vault item")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -66,6 +88,140 @@ func (h *NodeVaultHandler) CreateVaultItem(c *gin.Context) {
 	c.JSON(http.StatusCreated, dto.NewAPIResponse(response, nil))
 }
 
+// CreateVaultItems handles POST .../nodes/:node_id/vault/bulk. The body is a
+// JSON array of vault items; if any of them is invalid nothing is created.
+func (h *NodeVaultHandler) CreateVaultItems(c *gin.Context) {
+	nodeID := c.Param("node_id")
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if nodeID == "" || err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Node ID and Project ID are required")))
+		return
+	}
+
+	var reqs []dto.CreateNodeVaultRequest
+	if err := c.ShouldBindJSON(&reqs); err != nil {
+		writeBindError(c, err, err.Error())
+		return
+	}
+	if len(reqs) == 0 || len(reqs) > dto.MaxBulkVaultItems {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest,
+				fmt.Sprintf("Between 1 and %d vault items are required", dto.MaxBulkVaultItems))))
+		return
+	}
+
+	// Validate every item before creating any, prefixing field names with
+	// the item's index so clients can point at the offending entry
+	var validationErrors []validation.FieldError
+	for i, req := range reqs {
+		for _, fieldError := range h.validator.ValidateStruct(req) {
+			fieldError.Field = fmt.Sprintf("[%d].%s", i, fieldError.Field)
+			validationErrors = append(validationErrors, fieldError)
+		}
+	}
+	if validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	vaultItems, err := h.service.CreateVaultItems(c.Request.Context(), nodeID, projectID, userID, reqs)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidVaultData) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidVaultItemData)))
+			return
+		}
+		if errors.Is(err, service.ErrInvalidNodeID) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidNodeID)))
+			return
+		}
+		if errors.Is(err, service.ErrVaultAccessDenied) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeVaultAccessDenied)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectIDStr).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Int("count", len(reqs)).
+			Msg("Failed to create vault items")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	response := make([]dto.NodeVaultResponse, len(vaultItems))
+	for i, vaultItem := range vaultItems {
+		response[i] = dto.ToNodeVaultResponse(vaultItem)
+	}
+	c.JSON(http.StatusCreated, dto.NewAPIResponse(response, nil))
+}
+
+// CountVaultItems handles GET .../nodes/:node_id/vault/count
+func (h *NodeVaultHandler) CountVaultItems(c *gin.Context) {
+	nodeID := c.Param("node_id")
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if nodeID == "" || err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Node ID and Project ID are required")))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	count, err := h.service.CountVaultItems(c.Request.Context(), nodeID, projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidNodeID) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidNodeID)))
+			return
+		}
+		if errors.Is(err, service.ErrVaultAccessDenied) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeVaultAccessDenied)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectIDStr).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to count vault items")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.CountResponse{Count: count}, nil))
+}
+
 func (h *NodeVaultHandler) ListVaultItems(c *gin.Context) {
 	// Parse params
 	nodeID := c.Param("node_id")
@@ -76,20 +232,36 @@ func (h *NodeVaultHandler) ListVaultItems(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 	projectID, _ := primitive.ObjectIDFromHex(projectIDStr)
 
-	items, err := h.service.ListVaultItems(c.Request.Context(), nodeID, projectID, userID)
+	vaultType := c.Query("type")
+	if vaultType != "" && !domain.IsValidVaultType(vaultType) {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Unknown vault type")))
+		return
+	}
+
+	items, err := h.service.ListVaultItems(c.Request.Context(), nodeID, projectID, userID, vaultType)
 	if err != nil {
 		if errors.Is(err, service.ErrVaultAccessDenied) {
 			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeVaultAccessDenied)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
 		logger.Error().Err(err).Msg("Failed to list vault items")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -103,11 +275,22 @@ func (h *NodeVaultHandler) ListVaultItems(c *gin.Context) {
 
 func (h *NodeVaultHandler) GetVaultItem(c *gin.Context) {
 	vaultID := c.Param("vault_id")
+	nodeID := c.Param("node_id")
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
-	item, err := h.service.GetVaultItem(c.Request.Context(), vaultID, userID)
+	item, err := h.service.GetVaultItem(c.Request.Context(), vaultID, nodeID, projectID, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrVaultAccessDenied) {
 			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
@@ -119,8 +302,13 @@ func (h *NodeVaultHandler) GetVaultItem(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeVaultItemNotFound)))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		if errors.Is(err, service.ErrInvalidRequest) || errors.Is(err, service.ErrInvalidNodeID) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+			return
+		}
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -130,19 +318,46 @@ func (h *NodeVaultHandler) GetVaultItem(c *gin.Context) {
 
 func (h *NodeVaultHandler) UpdateVaultItem(c *gin.Context) {
 	vaultID := c.Param("vault_id")
+	nodeID := c.Param("node_id")
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
 
 	var req dto.UpdateNodeVaultRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
 		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+			dto.NewValidationErrorResponse(validationErrors)))
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
-	item, err := h.service.UpdateVaultItem(c.Request.Context(), vaultID, userID, req)
+	item, err := h.service.UpdateVaultItem(c.Request.Context(), vaultID, nodeID, projectID, userID, req)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidVaultData) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidVaultItemData)))
+			return
+		}
+		if errors.Is(err, service.ErrVaultConflict) {
+			c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeVaultVersionConflict)))
+			return
+		}
 		if errors.Is(err, service.ErrVaultAccessDenied) {
 			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeVaultAccessDenied)))
@@ -153,9 +368,14 @@ func (h *NodeVaultHandler) UpdateVaultItem(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeVaultItemNotFound)))
 			return
 		}
+		if errors.Is(err, service.ErrInvalidRequest) || errors.Is(err, service.ErrInvalidNodeID) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+			return
+		}
 		logger.Error().Err(err).Msg("Failed to update vault item")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -165,11 +385,22 @@ func (h *NodeVaultHandler) UpdateVaultItem(c *gin.Context) {
 
 func (h *NodeVaultHandler) DeleteVaultItem(c *gin.Context) {
 	vaultID := c.Param("vault_id")
+	nodeID := c.Param("node_id")
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid project ID")))
+		return
+	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
-	err := h.service.DeleteVaultItem(c.Request.Context(), vaultID, userID)
+	err = h.service.DeleteVaultItem(c.Request.Context(), vaultID, nodeID, projectID, userID)
 	if err != nil {
 		if errors.Is(err, service.ErrVaultAccessDenied) {
 			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
@@ -181,9 +412,14 @@ func (h *NodeVaultHandler) DeleteVaultItem(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeVaultItemNotFound)))
 			return
 		}
+		if errors.Is(err, service.ErrInvalidRequest) || errors.Is(err, service.ErrInvalidNodeID) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+			return
+		}
 		logger.Error().Err(err).Msg("Failed to delete vault item")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type stubVaultRepo struct {
+	port.NodeVaultRepository
+	item *domain.NodeVault
+}
+
+func (r *stubVaultRepo) FindByID(context.Context, primitive.ObjectID) (*domain.NodeVault, error) {
+	item := *r.item
+	return &item, nil
+}
+
+func (r *stubVaultRepo) Update(context.Context, *domain.NodeVault) error {
+	// Someone else saved in between the read and this write
+	return port.ErrVersionConflict
+}
+
+type stubMemberRepo struct {
+	port.ProjectMemberRepository
+	member *domain.ProjectMember
+}
+
+func (r *stubMemberRepo) FindByProjectAndUser(context.Context, primitive.ObjectID, primitive.ObjectID) (*domain.ProjectMember, error) {
+	return r.member, nil
+}
+
+type nopPublisher struct{}
+
+func (nopPublisher) Publish(event.Event) {}
+
+func TestUpdateVaultItemConflictIs409(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID, projectID := primitive.NewObjectID(), primitive.NewObjectID()
+	item := &domain.NodeVault{ID: primitive.NewObjectID(), NodeId: primitive.NewObjectID(), ProjectId: projectID, Version: 2}
+	authz := service.NewAuthorizationService(&stubMemberRepo{
+		member: &domain.ProjectMember{ProjectID: projectID, UserID: userID, Role: domain.RoleOwner},
+	})
+	vaultService := service.NewNodeVaultService(&stubVaultRepo{item: item}, nil, nil, authz, service.PayloadLimits{}, nopPublisher{})
+	h := NewNodeVaultHandler(vaultService, validation.NewValidationEngine())
+
+	router := gin.New()
+	router.PUT("/projects/:project_id/nodes/:node_id/vault/:vault_id", func(c *gin.Context) {
+		c.Set("user_id", userID.Hex())
+		h.UpdateVaultItem(c)
+	})
+
+	for name, version := range map[string]int{"stale version": 1, "lost race": 2} {
+		t.Run(name, func(t *testing.T) {
+			path := "/projects/" + projectID.Hex() + "/nodes/" + item.NodeId.Hex() + "/vault/" + item.ID.Hex()
+			body := `{"version":` + strconv.Itoa(version) + `,"label":"x"}`
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodPut, path, strings.NewReader(body)))
+
+			if recorder.Code != http.StatusConflict {
+				t.Fatalf("status = %d, want 409: %s", recorder.Code, recorder.Body)
+			}
+			var response dto.APIResponse[json.RawMessage]
+			if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+				t.Fatal(err)
+			}
+			if response.Error == nil || response.Error.Code != dto.ErrCodeVaultVersionConflict {
+				t.Errorf("error = %+v, want %s", response.Error, dto.ErrCodeVaultVersionConflict)
+			}
+		})
+	}
+}
@@ -40,8 +40,7 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 
 	var req dto.CreateNoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -53,8 +52,12 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Parse ParentID if present
 	var parentID *primitive.ObjectID
@@ -81,6 +84,11 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 		&req.EncryptedContentSignature,
 	)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidNoteData) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidNoteData)))
+			return
+		}
 		if errors.Is(err, service.ErrInsufficientPermission) {
 			logger.Warn().
 				Str("project_id", projectID.Hex()).
@@ -90,9 +98,9 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrNoteAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeNoteAccessDenied)))
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
 			return
 		}
 		logger.Error().
@@ -100,8 +108,8 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to create note")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -117,6 +125,88 @@ func (h *NoteHandler) CreateNote(c *gin.Context) {
 }
 
 // ListNotes gets all notes for a project with pagination
+// CountNotes handles GET /projects/:project_id/notes/count
+func (h *NoteHandler) CountNotes(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	count, err := h.noteService.CountNotes(c.Request.Context(), projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to count notes")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.CountResponse{Count: count}, nil))
+}
+
+// GetNoteTree handles GET /projects/:project_id/notes/tree
+func (h *NoteHandler) GetNoteTree(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	tree, err := h.noteService.GetNoteTree(c.Request.Context(), projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to build note tree")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(tree, nil))
+}
+
 func (h *NoteHandler) ListNotes(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
 	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
@@ -127,8 +217,18 @@ func (h *NoteHandler) ListNotes(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	// Switch to cursor mode when a cursor is supplied
+	if _, useCursor := c.GetQuery("cursor"); useCursor {
+		h.listNotesByCursor(c, projectID, userID)
+		return
+	}
 
 	notes, err := h.noteService.ListNotes(
 		c.Request.Context(),
@@ -141,9 +241,9 @@ func (h *NoteHandler) ListNotes(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrNoteAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeNoteAccessDenied)))
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
 			return
 		}
 		logger.Error().
@@ -151,8 +251,8 @@ func (h *NoteHandler) ListNotes(c *gin.Context) {
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to list notes")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -169,6 +269,63 @@ func (h *NoteHandler) ListNotes(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.NewAPIResponse(responses, nil))
 }
 
+// listNotesByCursor lists notes using cursor-based pagination ordered by ID
+func (h *NoteHandler) listNotesByCursor(c *gin.Context, projectID, userID primitive.ObjectID) {
+	var params dto.CursorParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+	afterID, err := params.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+
+	// Fetch one extra item to detect whether another page exists
+	notes, err := h.noteService.ListNotesAfter(c.Request.Context(), projectID, userID, afterID, params.Limit+1)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to list notes")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	hasMore := len(notes) > params.Limit
+	if hasMore {
+		notes = notes[:params.Limit]
+	}
+
+	responses := make([]dto.NoteResponse, 0, len(notes))
+	var lastID primitive.ObjectID
+	for _, note := range notes {
+		response := dto.ToNoteResponse(note)
+		response.EncryptedContent = nil // Don't send content in list view
+		response.EncryptedContentSignature = nil
+		responses = append(responses, response)
+		lastID = note.ID
+	}
+
+	cursorMeta := dto.NewCursorMeta(params.Limit, lastID, hasMore)
+	c.JSON(http.StatusOK, dto.NewAPIResponseWithCursor(responses, &cursorMeta))
+}
+
 // GetNote gets a specific note
 func (h *NoteHandler) GetNote(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
@@ -188,8 +345,12 @@ func (h *NoteHandler) GetNote(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	note, err := h.noteService.GetNote(c.Request.Context(), noteID, userID)
 	if err != nil {
@@ -203,18 +364,13 @@ func (h *NoteHandler) GetNote(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrNoteAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeNoteAccessDenied)))
-			return
-		}
 		logger.Error().
 			Err(err).
 			Str("note_id", noteID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to get note")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -243,8 +399,7 @@ func (h *NoteHandler) UpdateNote(c *gin.Context) {
 
 	var req dto.UpdateNoteRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -256,8 +411,12 @@ func (h *NoteHandler) UpdateNote(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Update note
 	note, err := h.noteService.UpdateNote(
@@ -271,6 +430,11 @@ func (h *NoteHandler) UpdateNote(c *gin.Context) {
 		req.EncryptedContentSignature,
 	)
 	if err != nil {
+		if errors.Is(err, service.ErrInvalidNoteData) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidNoteData)))
+			return
+		}
 		if errors.Is(err, service.ErrNoteNotFound) || errors.Is(err, mongo.ErrNoDocuments) {
 			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeNoteNotFound)))
@@ -285,18 +449,13 @@ func (h *NoteHandler) UpdateNote(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrNoteAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeNoteAccessDenied)))
-			return
-		}
 		logger.Error().
 			Err(err).
 			Str("note_id", noteID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to update note")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -310,6 +469,63 @@ func (h *NoteHandler) UpdateNote(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.NewAPIResponse(response, nil))
 }
 
+// BatchDeleteNotes deletes several notes of a project in one request and
+// reports each ID's outcome
+func (h *NoteHandler) BatchDeleteNotes(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	batch, ok := bindBatchDelete(c, h.validator)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	errs, err := h.noteService.DeleteNotes(c.Request.Context(), projectID, userID, batch.ids)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to delete notes")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	results := batch.apply(errs, func(error) string {
+		return dto.ErrCodeNoteNotFound
+	})
+
+	logger.Info().
+		Str("project_id", projectID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Int("requested", len(results)).
+		Msg("Notes batch deleted")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(results, nil))
+}
+
 // DeleteNote deletes a note
 func (h *NoteHandler) DeleteNote(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
@@ -329,8 +545,12 @@ func (h *NoteHandler) DeleteNote(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	err = h.noteService.DeleteNote(c.Request.Context(), noteID, userID)
 	if err != nil {
@@ -348,18 +568,13 @@ func (h *NoteHandler) DeleteNote(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
-		if errors.Is(err, service.ErrNoteAccessDenied) {
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeNoteAccessDenied)))
-			return
-		}
 		logger.Error().
 			Err(err).
 			Str("note_id", noteID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to delete note")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
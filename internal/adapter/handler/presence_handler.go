@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/middleware"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/realtime"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// presenceMaxMessageBytes bounds a single client message; cursor and
+	// selection payloads are tiny
+	presenceMaxMessageBytes = 4 << 10
+	// presenceIdleTimeout closes sockets that send nothing, not even a ping
+	presenceIdleTimeout = 90 * time.Second
+	// presenceWriteTimeout bounds a single write to a client
+	presenceWriteTimeout = 10 * time.Second
+)
+
+type PresenceHandler struct {
+	diagramService *service.DiagramService
+	userService    *service.UserService
+	hub            *realtime.Hub
+}
+
+func NewPresenceHandler(
+	diagramService *service.DiagramService,
+	userService *service.UserService,
+	hub *realtime.Hub,
+) *PresenceHandler {
+	return &PresenceHandler{
+		diagramService: diagramService,
+		userService:    userService,
+		hub:            hub,
+	}
+}
+
+// DiagramPresence godoc
+// @Summary Join a diagram's presence channel
+// @Description Upgrades to a WebSocket that broadcasts presence join/leave and
+// @Description cursor/selection events among the diagram's viewers. Browsers
+// @Description pass the access token as the access_token query parameter or
+// @Description via the subprotocols ["access_token", "<jwt>"].
+// @Tags diagrams
+// @Param project_id path string true "Project ID"
+// @Param diagram_id path string true "Diagram ID"
+// @Success 101
+// @Router /api/v1/projects/{project_id}/diagrams/{diagram_id}/ws [get]
+func (h *PresenceHandler) DiagramPresence(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	diagramID, err := primitive.ObjectIDFromHex(c.Param("diagram_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	// Enforce view permission before upgrading so failures are plain HTTP
+	diagram, err := h.diagramService.GetDiagram(c.Request.Context(), diagramID, userID)
+	if err == nil && diagram.ProjectID != projectID {
+		err = service.ErrDiagramNotFound
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrDiagramNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeDiagramNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("diagram_id", diagramID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to authorize diagram presence")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	user, err := h.userService.GetUserProfile(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to load user for diagram presence")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	client := realtime.NewClient(realtime.Participant{
+		SessionID: uuid.NewString(),
+		UserID:    userID.Hex(),
+		Username:  user.Username,
+		Name:      user.Name,
+	})
+	room := diagramID.Hex()
+
+	server := websocket.Server{
+		Handshake: acceptSocketTokenProtocol,
+		Handler: func(conn *websocket.Conn) {
+			h.serveClient(conn, room, client)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// serveClient pumps events between the socket and the hub until either side
+// goes away
+func (h *PresenceHandler) serveClient(conn *websocket.Conn, room string, client *realtime.Client) {
+	defer conn.Close()
+	conn.MaxPayloadBytes = presenceMaxMessageBytes
+
+	h.hub.Join(room, client)
+	defer h.hub.Leave(room, client)
+
+	go func() {
+		defer conn.Close()
+		for {
+			select {
+			case <-client.Done():
+				return
+			case message := <-client.Outbox():
+				_ = conn.SetWriteDeadline(time.Now().Add(presenceWriteTimeout))
+				if err := websocket.Message.Send(conn, string(message)); err != nil {
+					return
+				}
+			}
+		}
+	}()
+
+	for {
+		_ = conn.SetReadDeadline(time.Now().Add(presenceIdleTimeout))
+
+		var message []byte
+		if err := websocket.Message.Receive(conn, &message); err != nil {
+			return
+		}
+
+		var event realtime.Event
+		if err := json.Unmarshal(message, &event); err != nil {
+			continue
+		}
+
+		switch event.Type {
+		case realtime.EventCursor, realtime.EventSelection:
+			h.hub.Relay(room, client, event.Type, event.Data)
+		case realtime.EventPing:
+			client.Send(realtime.Event{Type: realtime.EventPong})
+		}
+	}
+}
+
+// acceptSocketTokenProtocol echoes the token marker when the client passed
+// its token as a subprotocol; the token itself must never be echoed back
+func acceptSocketTokenProtocol(config *websocket.Config, _ *http.Request) error {
+	for _, protocol := range config.Protocol {
+		if protocol == middleware.SocketTokenProtocol {
+			config.Protocol = []string{middleware.SocketTokenProtocol}
+			return nil
+		}
+	}
+	config.Protocol = nil
+	return nil
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
@@ -8,7 +9,6 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
 	"github.com/gin-gonic/gin"
-	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ProfileHandler struct {
@@ -31,20 +31,13 @@ func NewProfileHandler(userService *service.UserService, validator *validation.V
 // @Router /api/v1/profile [get]
 func (h *ProfileHandler) GetProfile(c *gin.Context) {
 	// Get user ID from auth middleware context
-	userIDStr, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := currentUserID(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
 			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
 		return
 	}
 
-	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid user ID")))
-		return
-	}
-
 	// Get user profile
 	user, err := h.userService.GetUserProfile(c.Request.Context(), userID)
 	if err != nil {
@@ -53,8 +46,8 @@ func (h *ProfileHandler) GetProfile(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeNotFound, "User not found")))
 			return
 		}
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -62,6 +55,39 @@ func (h *ProfileHandler) GetProfile(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.NewAPIResponse(response, nil))
 }
 
+// GetDashboard godoc
+// @Summary Get current user profile with project and invitation counts
+// @Tags profile
+// @Produce json
+// @Success 200 {object} dto.APIResponse[dto.UserDashboardResponse]
+// @Router /api/v1/profile/dashboard [get]
+func (h *ProfileHandler) GetDashboard(c *gin.Context) {
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	dashboard, err := h.userService.GetUserDashboard(c.Request.Context(), userID)
+	if err != nil {
+		if err == service.ErrUserNotFound {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeNotFound, "User not found")))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to get user dashboard")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToUserDashboardResponse(dashboard), nil))
+}
+
 // UpdateProfile godoc
 // @Summary Update user profile
 // @Tags profile
@@ -72,24 +98,16 @@ func (h *ProfileHandler) GetProfile(c *gin.Context) {
 // @Router /api/v1/profile [put]
 func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 	// Get user ID from auth middleware context
-	userIDStr, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := currentUserID(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
 			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
 		return
 	}
 
-	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid user ID")))
-		return
-	}
-
 	var req dto.UpdateProfileRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -123,8 +141,8 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 			Err(err).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to update profile")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -146,24 +164,16 @@ func (h *ProfileHandler) UpdateProfile(c *gin.Context) {
 // @Router /api/v1/profile/password [put]
 func (h *ProfileHandler) ChangePassword(c *gin.Context) {
 	// Get user ID from auth middleware context
-	userIDStr, exists := c.Get("user_id")
-	if !exists {
+	userID, ok := currentUserID(c)
+	if !ok {
 		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
 			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
 		return
 	}
 
-	userID, err := primitive.ObjectIDFromHex(userIDStr.(string))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid user ID")))
-		return
-	}
-
 	var req dto.ChangePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -175,8 +185,14 @@ func (h *ProfileHandler) ChangePassword(c *gin.Context) {
 	}
 
 	// Change password
-	err = h.userService.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
+	err := h.userService.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
 	if err != nil {
+		var policyErr *service.PasswordPolicyError
+		if errors.As(err, &policyErr) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewValidationErrorResponse(policyErr.Violations)))
+			return
+		}
 		if err == service.ErrCurrentPasswordWrong {
 			logger.Warn().
 				Str("user_id", logger.SanitizeUserID(userID.Hex())).
@@ -197,8 +213,8 @@ func (h *ProfileHandler) ChangePassword(c *gin.Context) {
 			Err(err).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to change password")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -37,8 +37,7 @@ func NewProjectHandler(
 func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	var req dto.CreateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -50,8 +49,12 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Create project
 	project, err := h.projectService.CreateProject(
@@ -59,6 +62,7 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 		userID,
 		req.Name,
 		req.Description,
+		req.Tags,
 		req.SecretPassphrase,
 		req.SecretSigningPrivateKey,
 		req.SigningPublicKey,
@@ -70,8 +74,8 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 			Err(err).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to create project")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -87,19 +91,50 @@ func (h *ProjectHandler) CreateProject(c *gin.Context) {
 // GetUserProjects gets all projects for the current user with pagination
 func (h *ProjectHandler) GetUserProjects(c *gin.Context) {
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Get pagination params
 	var params dto.PaginationParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		params = dto.DefaultPaginationParams()
 	}
-	params.Validate()
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+
+	roleFilter := c.Query("role")
+	if !domain.IsValidProjectRoleFilter(roleFilter) {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Unknown role filter")))
+		return
+	}
+
+	sortOrder := c.Query("sort")
+	if !domain.IsValidProjectSort(sortOrder) {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Unknown sort order")))
+		return
+	}
+
+	query := domain.ProjectListQuery{
+		Role:            roleFilter,
+		Sort:            sortOrder,
+		Tag:             c.Query("tag"),
+		IncludeArchived: c.Query("include_archived") == "true",
+		FavoritesOnly:   c.Query("favorites") == "true",
+	}
 
 	projects, totalCount, err := h.projectService.GetUserProjects(
 		c.Request.Context(),
 		userID,
+		query,
 		params.GetOffset(),
 		params.GetLimit(),
 	)
@@ -109,21 +144,50 @@ func (h *ProjectHandler) GetUserProjects(c *gin.Context) {
 			Err(err).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to get user projects")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
 	// Convert to responses
 	responses := make([]dto.ProjectResponse, 0, len(projects))
 	for _, project := range projects {
-		responses = append(responses, dto.ToProjectResponse(project))
+		responses = append(responses, dto.ToMemberProjectResponse(project))
 	}
 
 	paginationMeta := dto.NewPaginationMeta(params, totalCount)
 	c.JSON(http.StatusOK, dto.NewAPIResponseWithPagination(responses, &paginationMeta))
 }
 
+// GetDeletedProjects lists the user's projects that are in the recycle bin
+func (h *ProjectHandler) GetDeletedProjects(c *gin.Context) {
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	projects, err := h.projectService.GetDeletedProjects(c.Request.Context(), userID)
+	if err != nil {
+		logger.Error().
+			Err(err).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to get deleted projects")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	responses := make([]dto.ProjectResponse, 0, len(projects))
+	for _, project := range projects {
+		responses = append(responses, dto.ToMemberProjectResponse(project))
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(responses, nil))
+}
+
 // GetProjectDetails gets project details with user permissions
 func (h *ProjectHandler) GetProjectDetails(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
@@ -135,20 +199,15 @@ func (h *ProjectHandler) GetProjectDetails(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	project, member, err := h.projectService.GetProjectDetails(c.Request.Context(), projectID, userID)
 	if err != nil {
-		if errors.Is(err, service.ErrProjectAccessDenied) {
-			logger.Warn().
-				Str("project_id", projectID.Hex()).
-				Str("user_id", logger.SanitizeUserID(userID.Hex())).
-				Msg("Project access denied")
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeProjectAccessDenied)))
-			return
-		}
 		if errors.Is(err, service.ErrProjectNotFound) || errors.Is(err, mongo.ErrNoDocuments) {
 			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
@@ -159,8 +218,8 @@ func (h *ProjectHandler) GetProjectDetails(c *gin.Context) {
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to get project details")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -181,6 +240,47 @@ func (h *ProjectHandler) GetProjectDetails(c *gin.Context) {
 	c.JSON(http.StatusOK, dto.NewAPIResponse(response, nil))
 }
 
+// GetPermissions returns the caller's role and effective permissions in a
+// project, without the rest of the project details
+func (h *ProjectHandler) GetPermissions(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	role, permissions, err := h.projectService.GetUserPermissions(c.Request.Context(), projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to get project permissions")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ProjectPermissionsResponse{
+		Role:        role,
+		Permissions: permissions,
+	}, nil))
+}
+
 // UpdateProject updates a project
 func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
@@ -193,8 +293,7 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 
 	var req dto.UpdateProjectRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -206,11 +305,15 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Update project
-	project, err := h.projectService.UpdateProject(c.Request.Context(), projectID, userID, req.Name, req.Description)
+	project, err := h.projectService.UpdateProject(c.Request.Context(), projectID, userID, req.Name, req.Description, req.Tags)
 	if err != nil {
 		if errors.Is(err, service.ErrInsufficientPermission) {
 			logger.Warn().
@@ -231,8 +334,8 @@ func (h *ProjectHandler) UpdateProject(c *gin.Context) {
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to update project")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -256,8 +359,12 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	err = h.projectService.DeleteProject(c.Request.Context(), projectID, userID)
 	if err != nil {
@@ -270,26 +377,310 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectDeletionNeedsConsent) {
+			c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectDeletionNeedsConsent)))
+			return
+		}
 		logger.Error().
 			Err(err).
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to delete project")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Msg("Project moved to recycle bin")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+		"message": "Project moved to recycle bin",
+	}, nil))
+}
+
+// RequestProjectDeletion schedules the project for deletion after the grace
+// period so the other owners can cancel it
+func (h *ProjectHandler) RequestProjectDeletion(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	project, err := h.projectService.RequestProjectDeletion(c.Request.Context(), projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			logger.Warn().
+				Str("project_id", projectID.Hex()).
+				Str("user_id", logger.SanitizeUserID(userID.Hex())).
+				Msg("Insufficient permission to request project deletion")
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectDeletionPending) {
+			c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectDeletionPending)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to request project deletion")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Msg("Project deletion requested")
+
+	c.JSON(http.StatusAccepted, dto.NewAPIResponse(dto.ToProjectDeletionResponse(project), nil))
+}
+
+// CancelProjectDeletion withdraws a pending deletion request
+func (h *ProjectHandler) CancelProjectDeletion(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	err = h.projectService.CancelProjectDeletion(c.Request.Context(), projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			logger.Warn().
+				Str("project_id", projectID.Hex()).
+				Str("user_id", logger.SanitizeUserID(userID.Hex())).
+				Msg("Insufficient permission to cancel project deletion")
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectDeletionNotRequested) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectDeletionNotRequested)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to cancel project deletion")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
 	logger.Info().
 		Str("project_id", projectID.Hex()).
 		Str("user_id", logger.SanitizeUserID(userID.Hex())).
-		Msg("Project deleted")
+		Msg("Project deletion cancelled")
 
 	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
-		"message": "Project deleted successfully",
+		"message": "Project deletion cancelled",
 	}, nil))
 }
 
+// FavoriteProject pins a project to the top of the caller's project list
+func (h *ProjectHandler) FavoriteProject(c *gin.Context) {
+	h.setFavorite(c, true)
+}
+
+// UnfavoriteProject unpins a project from the caller's project list
+func (h *ProjectHandler) UnfavoriteProject(c *gin.Context) {
+	h.setFavorite(c, false)
+}
+
+func (h *ProjectHandler) setFavorite(c *gin.Context, favorite bool) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	if err := h.projectService.SetFavorite(c.Request.Context(), projectID, userID, favorite); err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to change project favorite")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]bool{
+		"favorite": favorite,
+	}, nil))
+}
+
+// ArchiveProject archives a project (manage_project)
+func (h *ProjectHandler) ArchiveProject(c *gin.Context) {
+	h.setArchived(c, true)
+}
+
+// UnarchiveProject takes a project out of the archive (manage_project)
+func (h *ProjectHandler) UnarchiveProject(c *gin.Context) {
+	h.setArchived(c, false)
+}
+
+func (h *ProjectHandler) setArchived(c *gin.Context, archive bool) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var project *domain.Project
+	if archive {
+		project, err = h.projectService.ArchiveProject(c.Request.Context(), projectID, userID)
+	} else {
+		project, err = h.projectService.UnarchiveProject(c.Request.Context(), projectID, userID)
+	}
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Bool("archive", archive).
+			Msg("Failed to change project archive state")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToProjectResponse(project), nil))
+}
+
+// RestoreProject takes a project out of the recycle bin
+func (h *ProjectHandler) RestoreProject(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	// Get user ID from context
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	project, err := h.projectService.RestoreProject(c.Request.Context(), projectID, userID)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			logger.Warn().
+				Str("project_id", projectID.Hex()).
+				Str("user_id", logger.SanitizeUserID(userID.Hex())).
+				Msg("Insufficient permission to restore project")
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to restore project")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Msg("Project restored")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToProjectResponse(project), nil))
+}
+
 // AddMember adds a member to the project
 func (h *ProjectHandler) AddMember(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
@@ -302,8 +693,7 @@ func (h *ProjectHandler) AddMember(c *gin.Context) {
 
 	var req dto.AddMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -315,8 +705,12 @@ func (h *ProjectHandler) AddMember(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	targetUserID, err := primitive.ObjectIDFromHex(req.UserID)
 	if err != nil {
@@ -325,7 +719,7 @@ func (h *ProjectHandler) AddMember(c *gin.Context) {
 		return
 	}
 
-	err = h.projectService.AddMember(c.Request.Context(), projectID, userID, targetUserID, req.Role, req.Permissions)
+	err = h.projectService.AddMember(c.Request.Context(), projectID, userID, targetUserID, req.Role, req.Permissions, req.CustomRole)
 	if err != nil {
 		if errors.Is(err, service.ErrInsufficientPermission) {
 			logger.Warn().
@@ -336,19 +730,27 @@ func (h *ProjectHandler) AddMember(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
 		if errors.Is(err, service.ErrMemberAlreadyExists) {
 			c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeMemberAlreadyExists)))
 			return
 		}
+		if writeMemberRoleError(c, err) {
+			return
+		}
 		logger.Error().
 			Err(err).
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Str("target_user_id", logger.SanitizeUserID(targetUserID.Hex())).
 			Msg("Failed to add member")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -375,15 +777,29 @@ func (h *ProjectHandler) GetMembers(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	// Switch to cursor mode when a cursor is supplied
+	if _, useCursor := c.GetQuery("cursor"); useCursor {
+		h.getMembersByCursor(c, projectID, userID)
+		return
+	}
 
 	// Get pagination params
 	var params dto.PaginationParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		params = dto.DefaultPaginationParams()
 	}
-	params.Validate()
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
 
 	members, totalCount, err := h.projectService.GetMembers(
 		c.Request.Context(),
@@ -393,13 +809,64 @@ func (h *ProjectHandler) GetMembers(c *gin.Context) {
 		params.GetLimit(),
 	)
 	if err != nil {
-		if errors.Is(err, service.ErrProjectAccessDenied) {
+		if errors.Is(err, service.ErrProjectNotFound) {
 			logger.Warn().
 				Str("project_id", projectID.Hex()).
 				Str("user_id", logger.SanitizeUserID(userID.Hex())).
 				Msg("Access denied to view members")
-			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeProjectAccessDenied)))
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg("Failed to get members")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	// Convert to responses with user details
+	responses := make([]dto.ProjectMemberResponse, 0, len(members))
+	for _, member := range members {
+		user, err := h.userRepo.FindByID(c.Request.Context(), member.UserID)
+		if err != nil {
+			continue
+		}
+		responses = append(responses, dto.ToProjectMemberResponse(member, user))
+	}
+
+	paginationMeta := dto.NewPaginationMeta(params, totalCount)
+	c.JSON(http.StatusOK, dto.NewAPIResponseWithPagination(responses, &paginationMeta))
+}
+
+// getMembersByCursor gets project members using cursor-based pagination ordered by ID
+func (h *ProjectHandler) getMembersByCursor(c *gin.Context, projectID, userID primitive.ObjectID) {
+	var params dto.CursorParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+	afterID, err := params.Validate()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+
+	// Fetch one extra item to detect whether another page exists
+	members, err := h.projectService.GetMembersAfter(c.Request.Context(), projectID, userID, afterID, params.Limit+1)
+	if err != nil {
+		if errors.Is(err, service.ErrProjectNotFound) {
+			logger.Warn().
+				Str("project_id", projectID.Hex()).
+				Str("user_id", logger.SanitizeUserID(userID.Hex())).
+				Msg("Access denied to view members")
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
 			return
 		}
 		logger.Error().
@@ -407,14 +874,21 @@ func (h *ProjectHandler) GetMembers(c *gin.Context) {
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Msg("Failed to get members")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
+	hasMore := len(members) > params.Limit
+	if hasMore {
+		members = members[:params.Limit]
+	}
+
 	// Convert to responses with user details
 	responses := make([]dto.ProjectMemberResponse, 0, len(members))
+	var lastID primitive.ObjectID
 	for _, member := range members {
+		lastID = member.ID
 		user, err := h.userRepo.FindByID(c.Request.Context(), member.UserID)
 		if err != nil {
 			continue
@@ -422,8 +896,8 @@ func (h *ProjectHandler) GetMembers(c *gin.Context) {
 		responses = append(responses, dto.ToProjectMemberResponse(member, user))
 	}
 
-	paginationMeta := dto.NewPaginationMeta(params, totalCount)
-	c.JSON(http.StatusOK, dto.NewAPIResponseWithPagination(responses, &paginationMeta))
+	cursorMeta := dto.NewCursorMeta(params.Limit, lastID, hasMore)
+	c.JSON(http.StatusOK, dto.NewAPIResponseWithCursor(responses, &cursorMeta))
 }
 
 // UpdateMember updates member permissions
@@ -446,8 +920,7 @@ func (h *ProjectHandler) UpdateMember(c *gin.Context) {
 
 	var req dto.UpdateMemberRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -459,10 +932,14 @@ func (h *ProjectHandler) UpdateMember(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
-	err = h.projectService.UpdateMember(c.Request.Context(), projectID, userID, targetUserID, req.Role, req.Permissions)
+	err = h.projectService.UpdateMember(c.Request.Context(), projectID, userID, targetUserID, req.Role, req.Permissions, req.CustomRole)
 	if err != nil {
 		if errors.Is(err, service.ErrInsufficientPermission) {
 			logger.Warn().
@@ -473,19 +950,27 @@ func (h *ProjectHandler) UpdateMember(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
 		if errors.Is(err, service.ErrMemberNotFound) || errors.Is(err, mongo.ErrNoDocuments) {
 			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeMemberNotFound)))
 			return
 		}
+		if writeMemberRoleError(c, err) {
+			return
+		}
 		logger.Error().
 			Err(err).
 			Str("project_id", projectID.Hex()).
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Str("target_user_id", logger.SanitizeUserID(targetUserID.Hex())).
 			Msg("Failed to update member")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -520,8 +1005,12 @@ func (h *ProjectHandler) RemoveMember(c *gin.Context) {
 	}
 
 	// Get user ID from context
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	err = h.projectService.RemoveMember(c.Request.Context(), projectID, userID, targetUserID)
 	if err != nil {
@@ -534,6 +1023,11 @@ func (h *ProjectHandler) RemoveMember(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
 		if errors.Is(err, service.ErrCannotRemoveOwner) {
 			logger.Warn().
 				Str("project_id", projectID.Hex()).
@@ -550,8 +1044,8 @@ func (h *ProjectHandler) RemoveMember(c *gin.Context) {
 			Str("user_id", logger.SanitizeUserID(userID.Hex())).
 			Str("target_user_id", logger.SanitizeUserID(targetUserID.Hex())).
 			Msg("Failed to remove member")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -578,8 +1072,7 @@ func (h *ProjectHandler) CreateInvitation(c *gin.Context) {
 
 	var req dto.CreateInvitationRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -589,8 +1082,12 @@ func (h *ProjectHandler) CreateInvitation(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	var inviteeUserID primitive.ObjectID
 	if req.InviteeUserID != "" {
@@ -617,11 +1114,26 @@ func (h *ProjectHandler) CreateInvitation(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrCannotInviteSelf) {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "You cannot invite yourself")))
+			return
+		}
+		if errors.Is(err, service.ErrMemberAlreadyExists) {
+			c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeMemberAlreadyExists)))
+			return
+		}
 		logger.Error().Err(err).
 			Str("project_id", projectIDStr).
 			Msg("Failed to create invitation")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -631,6 +1143,94 @@ func (h *ProjectHandler) CreateInvitation(c *gin.Context) {
 	}, nil))
 }
 
+// BulkCreateInvitations invites several users at once. Entries that fail
+// validation or are rejected, such as existing members, are reported in their
+// result while the others are still created.
+func (h *ProjectHandler) BulkCreateInvitations(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	var req dto.BulkCreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	results := make([]dto.BulkInvitationResult, len(req.Invitations))
+	drafts := make([]domain.InvitationDraft, 0, len(req.Invitations))
+	draftIndexes := make([]int, 0, len(req.Invitations))
+	for i, entry := range req.Invitations {
+		results[i] = dto.BulkInvitationResult{Index: i, InviteeUserID: entry.InviteeUserID}
+		if validationErrors := h.validator.ValidateStruct(entry); validationErrors != nil {
+			results[i].Error = dto.NewValidationErrorResponse(validationErrors)
+			continue
+		}
+		inviteeUserID, _ := primitive.ObjectIDFromHex(entry.InviteeUserID)
+		drafts = append(drafts, domain.InvitationDraft{
+			InviteeUserID:     inviteeUserID,
+			Role:              entry.Role,
+			Permissions:       entry.Permissions,
+			EncryptedKeyrings: entry.EncryptedKeyrings,
+		})
+		draftIndexes = append(draftIndexes, i)
+	}
+
+	if len(drafts) > 0 {
+		invitations, errs, err := h.projectService.CreateInvitations(c.Request.Context(), projectID, userID, drafts)
+		if err != nil {
+			if errors.Is(err, service.ErrInsufficientPermission) {
+				c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+					dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+				return
+			}
+			if errors.Is(err, service.ErrProjectNotFound) {
+				c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+					dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+				return
+			}
+			logger.Error().Err(err).
+				Str("project_id", projectIDStr).
+				Msg("Failed to create invitations")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+			return
+		}
+
+		for j, i := range draftIndexes {
+			switch {
+			case errors.Is(errs[j], service.ErrCannotInviteSelf):
+				results[i].Error = dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "You cannot invite yourself")
+			case errors.Is(errs[j], service.ErrMemberAlreadyExists):
+				results[i].Error = dto.NewErrorResponse(dto.ErrCodeMemberAlreadyExists)
+			case errors.Is(errs[j], service.ErrDuplicateInvitee):
+				results[i].Error = dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "User is listed more than once")
+			default:
+				results[i].InvitationID = invitations[j].ID.Hex()
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(results, nil))
+}
+
 // GetProjectInvitations lists invitations for a project
 func (h *ProjectHandler) GetProjectInvitations(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
@@ -641,14 +1241,22 @@ func (h *ProjectHandler) GetProjectInvitations(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	var params dto.PaginationParams
 	if err := c.ShouldBindQuery(&params); err != nil {
 		params = dto.DefaultPaginationParams()
 	}
-	params.Validate()
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
 
 	invitations, totalCount, err := h.projectService.GetProjectInvitations(
 		c.Request.Context(),
@@ -663,11 +1271,16 @@ func (h *ProjectHandler) GetProjectInvitations(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
 		logger.Error().Err(err).
 			Str("project_id", projectIDStr).
 			Msg("Failed to get project invitations")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -711,8 +1324,12 @@ func (h *ProjectHandler) RevokeInvitation(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	err = h.projectService.RevokeInvitation(
 		c.Request.Context(),
@@ -726,6 +1343,11 @@ func (h *ProjectHandler) RevokeInvitation(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
 		if errors.Is(err, service.ErrInvitationNotFound) {
 			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
 				dto.NewErrorResponse(dto.ErrCodeInvitationNotFound)))
@@ -740,8 +1362,8 @@ func (h *ProjectHandler) RevokeInvitation(c *gin.Context) {
 			Str("project_id", projectIDStr).
 			Str("invitation_id", invitationIDStr).
 			Msg("Failed to revoke invitation")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
@@ -750,6 +1372,234 @@ func (h *ProjectHandler) RevokeInvitation(c *gin.Context) {
 	}, nil))
 }
 
+// ResendInvitation replaces an invitation with one at the current key epoch
+func (h *ProjectHandler) ResendInvitation(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	invitationIDStr := c.Param("invitation_id")
+	invitationID, err := primitive.ObjectIDFromHex(invitationIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	var req dto.ResendInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	invitation, err := h.projectService.ResendInvitation(
+		c.Request.Context(),
+		projectID,
+		userID,
+		invitationID,
+		req.EncryptedKeyrings,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrInsufficientPermission):
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+		case errors.Is(err, service.ErrProjectNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+		case errors.Is(err, service.ErrInvitationNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvitationNotFound)))
+		case errors.Is(err, service.ErrInvitationAlreadyAccepted):
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvitationAlreadyAccepted)))
+		case errors.Is(err, service.ErrInvitationDeclined):
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvitationDeclined)))
+		case errors.Is(err, service.ErrMemberAlreadyExists):
+			c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeMemberAlreadyExists)))
+		default:
+			logger.Error().Err(err).
+				Str("project_id", projectIDStr).
+				Str("invitation_id", invitationIDStr).
+				Msg("Failed to resend invitation")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.NewAPIResponse(map[string]string{
+		"invitation_id": invitation.ID.Hex(),
+	}, nil))
+}
+
+// GetKeyRotations lists the project's key rotation history
+func (h *ProjectHandler) GetKeyRotations(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var params dto.PaginationParams
+	if err := c.ShouldBindQuery(&params); err != nil {
+		params = dto.DefaultPaginationParams()
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Invalid pagination parameters")))
+		return
+	}
+
+	rotations, totalCount, err := h.projectService.GetKeyRotations(
+		c.Request.Context(),
+		projectID,
+		userID,
+		params.GetOffset(),
+		params.GetLimit(),
+	)
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().Err(err).
+			Str("project_id", projectIDStr).
+			Msg("Failed to get key rotations")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	names := make(map[primitive.ObjectID]string)
+	responses := make([]dto.KeyRotationResponse, 0, len(rotations))
+	for _, rotation := range rotations {
+		name, seen := names[rotation.RotatedBy]
+		if !seen {
+			if user, _ := h.userRepo.FindByID(c.Request.Context(), rotation.RotatedBy); user != nil {
+				name = user.Name
+			}
+			names[rotation.RotatedBy] = name
+		}
+		responses = append(responses, dto.ToKeyRotationResponse(rotation, name))
+	}
+
+	paginationMeta := dto.NewPaginationMeta(params, totalCount)
+	c.JSON(http.StatusOK, dto.NewAPIResponseWithPagination(responses, &paginationMeta))
+}
+
+// RekeyMember replaces a single member's keyring for the current epoch
+func (h *ProjectHandler) RekeyMember(c *gin.Context) {
+	projectIDStr := c.Param("project_id")
+	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	targetUserIDStr := c.Param("user_id")
+	targetUserID, err := primitive.ObjectIDFromHex(targetUserIDStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	var req dto.RekeyMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	err = h.projectService.RekeyMember(c.Request.Context(), projectID, userID, targetUserID, domain.ProjectMemberKeyring{
+		SecretPassphrase:        req.EncryptedPassphrase,
+		SecretSigningPrivateKey: req.EncryptedSigningKey,
+		SigningPublicKey:        req.SigningPublicKey,
+	})
+	if err != nil {
+		if errors.Is(err, service.ErrInsufficientPermission) {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+			return
+		}
+		if errors.Is(err, service.ErrMemberNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeMemberNotFound)))
+			return
+		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
+		logger.Error().Err(err).
+			Str("project_id", projectIDStr).
+			Str("target_user_id", logger.SanitizeUserID(targetUserIDStr)).
+			Msg("Failed to rekey member")
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectIDStr).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Str("target_user_id", logger.SanitizeUserID(targetUserIDStr)).
+		Msg("Member keyring replaced")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+		"message": "Member keyring updated successfully",
+	}, nil))
+}
+
 // RotateProjectKeys rotates the project keys
 func (h *ProjectHandler) RotateProjectKeys(c *gin.Context) {
 	projectIDStr := c.Param("project_id")
@@ -762,8 +1612,7 @@ func (h *ProjectHandler) RotateProjectKeys(c *gin.Context) {
 
 	var req dto.RotateProjectKeyRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		writeBindError(c, err)
 		return
 	}
 
@@ -773,8 +1622,12 @@ func (h *ProjectHandler) RotateProjectKeys(c *gin.Context) {
 		return
 	}
 
-	userIDStr, _ := c.Get("user_id")
-	userID, _ := primitive.ObjectIDFromHex(userIDStr.(string))
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
 
 	// Map DTO to Domain
 	domainUpdates := make([]domain.MemberKeyringUpdate, len(req.Updates))
@@ -800,11 +1653,16 @@ func (h *ProjectHandler) RotateProjectKeys(c *gin.Context) {
 				dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
 			return
 		}
+		if errors.Is(err, service.ErrProjectNotFound) {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+			return
+		}
 		logger.Error().Err(err).
 			Str("project_id", projectIDStr).
 			Msg("Failed to rotate project keys")
-		c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
-			dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		return
 	}
 
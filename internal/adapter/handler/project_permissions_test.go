@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// membersByUser answers membership lookups for several users of one project
+type membersByUser struct {
+	port.ProjectMemberRepository
+	members map[primitive.ObjectID]*domain.ProjectMember
+}
+
+func (r *membersByUser) FindByProjectAndUser(_ context.Context, _ primitive.ObjectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
+	if member, ok := r.members[userID]; ok {
+		return member, nil
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func TestGetPermissions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	projectID := primitive.NewObjectID()
+	ownerID, viewerID, outsiderID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	viewerPermissions := domain.PermissionStrings(service.RolePresets[domain.RoleViewer])
+	members := &membersByUser{members: map[primitive.ObjectID]*domain.ProjectMember{
+		ownerID:  {ProjectID: projectID, UserID: ownerID, Role: domain.RoleOwner},
+		viewerID: {ProjectID: projectID, UserID: viewerID, Role: domain.RoleViewer, Permissions: viewerPermissions},
+	}}
+	projects := service.NewProjectService(nil, members, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		service.NewAuthorizationService(members), nil, nopPublisher{}, 0, 0)
+	h := NewProjectHandler(projects, nil, validation.NewValidationEngine())
+
+	get := func(userID primitive.ObjectID) *httptest.ResponseRecorder {
+		router := gin.New()
+		router.GET("/projects/:project_id/permissions", func(c *gin.Context) {
+			c.Set("user_id", userID.Hex())
+			h.GetPermissions(c)
+		})
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/projects/"+projectID.Hex()+"/permissions", nil))
+		return recorder
+	}
+
+	tests := []struct {
+		name            string
+		userID          primitive.ObjectID
+		wantRole        string
+		wantPermissions []string
+	}{
+		{"owner", ownerID, domain.RoleOwner, domain.PermissionStrings(domain.AllPermissions)},
+		{"viewer", viewerID, domain.RoleViewer, viewerPermissions},
+	}
+	for _, tt := range tests {
+		recorder := get(tt.userID)
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s: status = %d, want 200: %s", tt.name, recorder.Code, recorder.Body)
+		}
+		var resp dto.APIResponse[dto.ProjectPermissionsResponse]
+		if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if resp.Data.Role != tt.wantRole || !slices.Equal(resp.Data.Permissions, tt.wantPermissions) {
+			t.Errorf("%s: got %s %v, want %s %v", tt.name, resp.Data.Role, resp.Data.Permissions, tt.wantRole, tt.wantPermissions)
+		}
+	}
+	if slices.Contains(viewerPermissions, string(domain.PermissionManageProject)) {
+		t.Error("viewer preset grants manage_project")
+	}
+
+	// Non-members are told the project does not exist rather than denied
+	recorder := get(outsiderID)
+	var resp dto.APIResponse[any]
+	if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if recorder.Code != http.StatusNotFound || resp.Error == nil || resp.Error.Code != dto.ErrCodeProjectNotFound {
+		t.Errorf("outsider: status = %d, error = %+v; want 404 %s", recorder.Code, resp.Error, dto.ErrCodeProjectNotFound)
+	}
+}
@@ -0,0 +1,241 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProjectRoleHandler manages a project's custom roles
+type ProjectRoleHandler struct {
+	projectService *service.ProjectService
+	validator      *validation.ValidationEngine
+}
+
+func NewProjectRoleHandler(projectService *service.ProjectService, validator *validation.ValidationEngine) *ProjectRoleHandler {
+	return &ProjectRoleHandler{
+		projectService: projectService,
+		validator:      validator,
+	}
+}
+
+// ListRoles lists the project's custom roles
+func (h *ProjectRoleHandler) ListRoles(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	roles, err := h.projectService.ListRoles(c.Request.Context(), projectID, userID)
+	if err != nil {
+		h.respondError(c, err, projectID, userID, "Failed to list project roles")
+		return
+	}
+
+	responses := make([]dto.ProjectRoleResponse, 0, len(roles))
+	for _, role := range roles {
+		responses = append(responses, dto.ToProjectRoleResponse(role))
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(responses, nil))
+}
+
+// CreateRole defines a custom role in the project (manage_project)
+func (h *ProjectRoleHandler) CreateRole(c *gin.Context) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	role, err := h.projectService.CreateRole(c.Request.Context(), projectID, userID, req.Name, req.Permissions)
+	if err != nil {
+		h.respondError(c, err, projectID, userID, "Failed to create project role")
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectID.Hex()).
+		Str("role_id", role.ID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Msg("Project role created")
+
+	c.JSON(http.StatusCreated, dto.NewAPIResponse(dto.ToProjectRoleResponse(role), nil))
+}
+
+// UpdateRole renames a custom role or changes its permissions
+// (manage_project); members holding it follow the change
+func (h *ProjectRoleHandler) UpdateRole(c *gin.Context) {
+	projectID, roleID, ok := parseRolePath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	req, ok := h.bindRequest(c)
+	if !ok {
+		return
+	}
+
+	role, err := h.projectService.UpdateRole(c.Request.Context(), projectID, userID, roleID, req.Name, req.Permissions)
+	if err != nil {
+		h.respondError(c, err, projectID, userID, "Failed to update project role")
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectID.Hex()).
+		Str("role_id", role.ID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Strs("permissions", role.Permissions).
+		Msg("Project role updated")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToProjectRoleResponse(role), nil))
+}
+
+// DeleteRole removes a custom role no member holds anymore (manage_project)
+func (h *ProjectRoleHandler) DeleteRole(c *gin.Context) {
+	projectID, roleID, ok := parseRolePath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	if err := h.projectService.DeleteRole(c.Request.Context(), projectID, userID, roleID); err != nil {
+		h.respondError(c, err, projectID, userID, "Failed to delete project role")
+		return
+	}
+
+	logger.Info().
+		Str("project_id", projectID.Hex()).
+		Str("role_id", roleID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Msg("Project role deleted")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+		"message": "Role deleted",
+	}, nil))
+}
+
+func (h *ProjectRoleHandler) bindRequest(c *gin.Context) (dto.ProjectRoleRequest, bool) {
+	var req dto.ProjectRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return req, false
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return req, false
+	}
+	return req, true
+}
+
+func (h *ProjectRoleHandler) respondError(c *gin.Context, err error, projectID, userID primitive.ObjectID, msg string) {
+	switch {
+	case errors.Is(err, service.ErrProjectNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeProjectNotFound)))
+	case errors.Is(err, service.ErrInsufficientPermission):
+		c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+	case errors.Is(err, service.ErrProjectRoleExists):
+		c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeProjectRoleExists)))
+	case errors.Is(err, service.ErrProjectRoleReserved):
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeProjectRoleReserved)))
+	case errors.Is(err, service.ErrProjectRoleInUse):
+		c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeProjectRoleInUse)))
+	default:
+		if writeMemberRoleError(c, err) {
+			return
+		}
+		logger.Error().
+			Err(err).
+			Str("project_id", projectID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg(msg)
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+	}
+}
+
+// writeMemberRoleError answers the errors shared by role management and
+// assigning roles to members, reporting whether it wrote a response
+func writeMemberRoleError(c *gin.Context, err error) bool {
+	switch {
+	case errors.Is(err, service.ErrProjectRoleNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeProjectRoleNotFound)))
+	case errors.Is(err, service.ErrInvalidPermission):
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidPermission)))
+	default:
+		return false
+	}
+	return true
+}
+
+// parseRolePath reads the project and role IDs from the route, writing a 400
+// response when either is malformed
+func parseRolePath(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	roleID, err := primitive.ObjectIDFromHex(c.Param("role_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	return projectID, roleID, true
+}
@@ -0,0 +1,243 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShareLinkPasswordHeader carries the password of a protected share link.
+// A header keeps the password out of URLs and access logs.
+const ShareLinkPasswordHeader = "X-Share-Password"
+
+type ShareLinkHandler struct {
+	shareLinks *service.ShareLinkService
+	validator  *validation.ValidationEngine
+}
+
+func NewShareLinkHandler(shareLinks *service.ShareLinkService, validator *validation.ValidationEngine) *ShareLinkHandler {
+	return &ShareLinkHandler{
+		shareLinks: shareLinks,
+		validator:  validator,
+	}
+}
+
+// CreateShareLink godoc
+// @Summary Create a read-only share link for a diagram
+// @Description The plaintext token is returned once and cannot be retrieved again.
+// @Tags diagrams
+// @Accept json
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param diagram_id path string true "Diagram ID"
+// @Param request body dto.CreateShareLinkRequest true "Create Share Link Request"
+// @Success 201 {object} dto.APIResponse[dto.ShareLinkResponse]
+// @Router /api/v1/projects/{project_id}/diagrams/{diagram_id}/share-links [post]
+func (h *ShareLinkHandler) CreateShareLink(c *gin.Context) {
+	projectID, diagramID, ok := parseDiagramPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	var req dto.CreateShareLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		writeBindError(c, err)
+		return
+	}
+
+	// Validate request
+	if validationErrors := h.validator.ValidateStruct(req); validationErrors != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewValidationErrorResponse(validationErrors)))
+		return
+	}
+
+	expiresIn := time.Duration(req.ExpiresInHours) * time.Hour
+	link, plaintext, err := h.shareLinks.CreateShareLink(c.Request.Context(), projectID, diagramID, userID, req.Password, expiresIn)
+	if err != nil {
+		h.respondError(c, err, diagramID, userID, "Failed to create share link")
+		return
+	}
+
+	logger.Info().
+		Str("diagram_id", diagramID.Hex()).
+		Str("share_link_id", link.ID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Msg("Share link created")
+
+	c.JSON(http.StatusCreated, dto.NewAPIResponse(dto.ToShareLinkResponse(link, plaintext), nil))
+}
+
+// ListShareLinks godoc
+// @Summary List a diagram's share links
+// @Tags diagrams
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param diagram_id path string true "Diagram ID"
+// @Success 200 {object} dto.APIResponse[[]dto.ShareLinkResponse]
+// @Router /api/v1/projects/{project_id}/diagrams/{diagram_id}/share-links [get]
+func (h *ShareLinkHandler) ListShareLinks(c *gin.Context) {
+	projectID, diagramID, ok := parseDiagramPath(c)
+	if !ok {
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	links, err := h.shareLinks.ListShareLinks(c.Request.Context(), projectID, diagramID, userID)
+	if err != nil {
+		h.respondError(c, err, diagramID, userID, "Failed to list share links")
+		return
+	}
+
+	responses := make([]dto.ShareLinkResponse, 0, len(links))
+	for _, link := range links {
+		responses = append(responses, dto.ToShareLinkResponse(link, ""))
+	}
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(responses, nil))
+}
+
+// RevokeShareLink godoc
+// @Summary Revoke a diagram share link
+// @Tags diagrams
+// @Produce json
+// @Param project_id path string true "Project ID"
+// @Param diagram_id path string true "Diagram ID"
+// @Param link_id path string true "Share Link ID"
+// @Success 200 {object} dto.APIResponse[any]
+// @Router /api/v1/projects/{project_id}/diagrams/{diagram_id}/share-links/{link_id} [delete]
+func (h *ShareLinkHandler) RevokeShareLink(c *gin.Context) {
+	projectID, diagramID, ok := parseDiagramPath(c)
+	if !ok {
+		return
+	}
+
+	linkID, err := primitive.ObjectIDFromHex(c.Param("link_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return
+	}
+
+	userID, ok := currentUserID(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+		return
+	}
+
+	if err := h.shareLinks.RevokeShareLink(c.Request.Context(), projectID, diagramID, linkID, userID); err != nil {
+		h.respondError(c, err, diagramID, userID, "Failed to revoke share link")
+		return
+	}
+
+	logger.Info().
+		Str("diagram_id", diagramID.Hex()).
+		Str("share_link_id", linkID.Hex()).
+		Str("user_id", logger.SanitizeUserID(userID.Hex())).
+		Msg("Share link revoked")
+
+	c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+		"message": "Share link revoked",
+	}, nil))
+}
+
+// OpenShareLink godoc
+// @Summary Open a diagram share link
+// @Description Public endpoint. Returns the encrypted diagram; the key to decrypt it travels with the link on the client side.
+// @Tags share
+// @Produce json
+// @Param token path string true "Share link token"
+// @Param X-Share-Password header string false "Password of a protected link"
+// @Success 200 {object} dto.APIResponse[dto.SharedDiagramResponse]
+// @Router /api/v1/share/{token} [get]
+func (h *ShareLinkHandler) OpenShareLink(c *gin.Context) {
+	diagram, err := h.shareLinks.OpenShareLink(c.Request.Context(), c.Param("token"), c.GetHeader(ShareLinkPasswordHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, service.ErrShareLinkNotFound):
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeShareLinkNotFound)))
+		case errors.Is(err, service.ErrShareLinkPasswordRequired):
+			c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeShareLinkPasswordRequired)))
+		case errors.Is(err, service.ErrShareLinkInvalidPassword):
+			c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeShareLinkInvalidPassword)))
+		default:
+			logger.Error().Err(err).Msg("Failed to open share link")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+		}
+		return
+	}
+
+	// Shared content must not linger in shared caches after a link is revoked
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, dto.NewAPIResponse(dto.ToSharedDiagramResponse(diagram), nil))
+}
+
+func (h *ShareLinkHandler) respondError(c *gin.Context, err error, diagramID, userID primitive.ObjectID, msg string) {
+	switch {
+	case errors.Is(err, service.ErrDiagramNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeDiagramNotFound)))
+	case errors.Is(err, service.ErrShareLinkNotFound):
+		c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeShareLinkNotFound)))
+	case errors.Is(err, service.ErrTooManyShareLinks):
+		c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeTooManyShareLinks)))
+	case errors.Is(err, service.ErrInsufficientPermission):
+		c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInsufficientPermission)))
+	default:
+		logger.Error().
+			Err(err).
+			Str("diagram_id", diagramID.Hex()).
+			Str("user_id", logger.SanitizeUserID(userID.Hex())).
+			Msg(msg)
+		status, errResp := dto.NewServerErrorResponse(err)
+		c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+	}
+}
+
+// parseDiagramPath reads the project and diagram IDs from the route, writing
+// a 400 response when either is malformed
+func parseDiagramPath(c *gin.Context) (primitive.ObjectID, primitive.ObjectID, bool) {
+	projectID, err := primitive.ObjectIDFromHex(c.Param("project_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	diagramID, err := primitive.ObjectIDFromHex(c.Param("diagram_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInvalidRequest)))
+		return primitive.NilObjectID, primitive.NilObjectID, false
+	}
+
+	return projectID, diagramID, true
+}
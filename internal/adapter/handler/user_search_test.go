@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// searchUserRepo matches every user and applies the exclusions and limit
+// the way the query does
+type searchUserRepo struct {
+	port.UserRepository
+	users    []*domain.User
+	excluded []primitive.ObjectID
+	limit    int
+	calls    int
+}
+
+func (r *searchUserRepo) SearchUsers(_ context.Context, _ string, excludeIDs []primitive.ObjectID, limit int) ([]*domain.User, error) {
+	r.calls++
+	r.excluded, r.limit = excludeIDs, limit
+	var found []*domain.User
+	for _, user := range r.users {
+		if !slices.Contains(excludeIDs, user.ID) && len(found) < limit {
+			found = append(found, user)
+		}
+	}
+	return found, nil
+}
+
+// searchMemberRepo makes the caller an owner of any project whose members
+// are memberIDs
+type searchMemberRepo struct {
+	stubMemberRepo
+	memberIDs []primitive.ObjectID
+}
+
+func (r *searchMemberRepo) FindUserIDsByProjectID(context.Context, primitive.ObjectID) ([]primitive.ObjectID, error) {
+	return r.memberIDs, nil
+}
+
+func TestSearchUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	callerID, projectID := primitive.NewObjectID(), primitive.NewObjectID()
+
+	users := []*domain.User{{ID: callerID, Name: "me"}}
+	for range 30 {
+		users = append(users, &domain.User{ID: primitive.NewObjectID(), Name: "alice"})
+	}
+	memberIDs := []primitive.ObjectID{callerID, users[1].ID, users[2].ID}
+
+	members := &searchMemberRepo{
+		stubMemberRepo: stubMemberRepo{member: &domain.ProjectMember{ProjectID: projectID, UserID: callerID, Role: domain.RoleOwner}},
+		memberIDs:      memberIDs,
+	}
+	projects := service.NewProjectService(nil, members, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		service.NewAuthorizationService(members), nil, nopPublisher{}, 0, 0)
+
+	tests := []struct {
+		name        string
+		query       string
+		wantStatus  int
+		wantLimit   int
+		wantCount   int
+		wantExclude []primitive.ObjectID
+	}{
+		{name: "default limit", query: "q=ali", wantStatus: http.StatusOK, wantLimit: dto.DefaultUserSearchLimit, wantCount: 10, wantExclude: []primitive.ObjectID{callerID}},
+		{name: "limit honoured", query: "q=ali&limit=3", wantStatus: http.StatusOK, wantLimit: 3, wantCount: 3, wantExclude: []primitive.ObjectID{callerID}},
+		{name: "limit capped", query: "q=ali&limit=500", wantStatus: http.StatusOK, wantLimit: dto.MaxUserSearchLimit, wantCount: 20, wantExclude: []primitive.ObjectID{callerID}},
+		{name: "project members left out", query: "q=ali&limit=20&exclude_project=" + projectID.Hex(), wantStatus: http.StatusOK, wantLimit: 20, wantCount: 20,
+			wantExclude: append([]primitive.ObjectID{callerID}, memberIDs...)},
+		{name: "negative limit", query: "q=ali&limit=-1", wantStatus: http.StatusBadRequest},
+		{name: "bad project", query: "q=ali&exclude_project=nope", wantStatus: http.StatusBadRequest},
+		{name: "empty query", query: "q=", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userRepo := &searchUserRepo{users: users}
+			handler := NewInvitationHandler(projects, userRepo, nil, nil)
+			router := gin.New()
+			router.Use(func(c *gin.Context) { c.Set("user_id", callerID.Hex()) })
+			router.GET("/users/search", handler.SearchUsers)
+
+			recorder := httptest.NewRecorder()
+			router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/users/search?"+tt.query, nil))
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if tt.wantLimit == 0 {
+				if userRepo.calls != 0 {
+					t.Error("searched the repository")
+				}
+				return
+			}
+
+			var resp dto.APIResponse[[]dto.UserSearchResponse]
+			if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if userRepo.limit != tt.wantLimit || len(resp.Data) != tt.wantCount {
+				t.Errorf("limit = %d with %d results, want %d with %d", userRepo.limit, len(resp.Data), tt.wantLimit, tt.wantCount)
+			}
+			if !slices.Equal(userRepo.excluded, tt.wantExclude) {
+				t.Errorf("excluded %v, want %v", userRepo.excluded, tt.wantExclude)
+			}
+			for _, user := range resp.Data {
+				if slices.Contains(tt.wantExclude, mustObjectID(t, user.ID)) {
+					t.Errorf("result %s should have been excluded", user.ID)
+				}
+			}
+		})
+	}
+}
+
+func mustObjectID(t *testing.T, hex string) primitive.ObjectID {
+	t.Helper()
+	id, err := primitive.ObjectIDFromHex(hex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return id
+}
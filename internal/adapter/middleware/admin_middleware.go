@@ -0,0 +1,36 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader carries the operator token for admin endpoints.
+const AdminTokenHeader = "X-Admin-Token"
+
+// RequireAdminToken guards operator endpoints with a static shared token.
+// When token is empty the endpoints are disabled and answer 404, as if they
+// did not exist.
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.JSON(http.StatusNotFound, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodePageNotFound)))
+			c.Abort()
+			return
+		}
+
+		provided := c.GetHeader(AdminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeUnauthorized)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,95 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionContextKey is the gin context key holding the API version a
+// request came in on
+const APIVersionContextKey = "api_version"
+
+// ResponseShaper rewrites a JSON envelope, written by a handler in the v1
+// shape, into the shape of another API version
+type ResponseShaper func(c *gin.Context, envelope *dto.APIResponse[json.RawMessage])
+
+// APIVersion records the API version of the request. With a shaper, JSON
+// envelopes are buffered and reshaped before they are written; downloads
+// and streams pass through untouched. Must be registered inside the locale
+// and compression middleware so it sees the plain JSON body.
+func APIVersion(version string, shaper ResponseShaper) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(APIVersionContextKey, version)
+		if shaper == nil {
+			c.Next()
+			return
+		}
+
+		writer := &shapingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+		writer.flush(c, shaper)
+	}
+}
+
+// ShapeV2Response shapes responses for API v2. So far v2 only fills in the
+// metadata v1 leaves empty: the request ID and the API version. Only
+// envelopes that carry metadata are passed in.
+func ShapeV2Response(c *gin.Context, envelope *dto.APIResponse[json.RawMessage]) {
+	envelope.Meta.RequestId = c.GetString("request_id")
+	envelope.Meta.APIVersion = c.GetString(APIVersionContextKey)
+}
+
+// shapingWriter holds back JSON bodies so they can be reshaped once the
+// handler has finished writing them
+type shapingWriter struct {
+	gin.ResponseWriter
+	decided   bool
+	buffering bool
+	body      bytes.Buffer
+}
+
+func (w *shapingWriter) shouldBuffer() bool {
+	if !w.decided {
+		w.decided = true
+		header := w.Header()
+		w.buffering = strings.HasPrefix(header.Get("Content-Type"), "application/json") &&
+			header.Get("Content-Disposition") == ""
+	}
+	return w.buffering
+}
+
+func (w *shapingWriter) Write(data []byte) (int, error) {
+	if w.shouldBuffer() {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *shapingWriter) WriteString(s string) (int, error) {
+	if w.shouldBuffer() {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *shapingWriter) flush(c *gin.Context, shaper ResponseShaper) {
+	if !w.buffering {
+		return
+	}
+
+	body := w.body.Bytes()
+	var envelope dto.APIResponse[json.RawMessage]
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Meta != nil {
+		shaper(c, &envelope)
+		if shaped, err := json.Marshal(envelope); err == nil {
+			body = shaped
+		}
+	}
+	_, _ = w.ResponseWriter.Write(body)
+}
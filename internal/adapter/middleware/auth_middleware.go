@@ -6,68 +6,180 @@ import (
 	"strings"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type AuthMiddleware struct {
-	jwtService *service.JWTService
+	jwtService   *service.JWTService
+	accessTokens *service.AccessTokenService
 }
 
-func NewAuthMiddleware(jwtService *service.JWTService) *AuthMiddleware {
+func NewAuthMiddleware(jwtService *service.JWTService, accessTokens *service.AccessTokenService) *AuthMiddleware {
 	return &AuthMiddleware{
-		jwtService: jwtService,
+		jwtService:   jwtService,
+		accessTokens: accessTokens,
 	}
 }
 
+// accessTokenIDKey is the context key set when a request was authenticated
+// with a personal access token instead of a session
+const accessTokenIDKey = "access_token_id"
+
+// SocketTokenProtocol is the WebSocket subprotocol marker for passing the
+// access token. Browsers cannot set headers on a WebSocket handshake, so
+// clients offer the protocols ["access_token", "<jwt>"] instead.
+const SocketTokenProtocol = "access_token"
+
 // RequireAuth is a middleware that validates JWT tokens
 func (m *AuthMiddleware) RequireAuth() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tokenString := ""
+		m.authenticate(c, requestToken(c))
+	}
+}
 
-		// 1. Try getting from cookie
-		cookieToken, err := c.Cookie("access_token")
-		if err == nil && cookieToken != "" {
-			tokenString = cookieToken
+// RequireSocketAuth validates JWT tokens on WebSocket handshakes. Besides the
+// Authorization header it accepts the token as the access_token query
+// parameter or through the SocketTokenProtocol subprotocol. The cookie is
+// ignored: browsers attach it to handshakes from any site and WebSockets are
+// not covered by CORS, so it would let other sites open sockets as the user.
+func (m *AuthMiddleware) RequireSocketAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tokenString := bearerToken(c)
+		if tokenString == "" {
+			tokenString = socketProtocolToken(c.GetHeader("Sec-WebSocket-Protocol"))
 		}
-
-		// 2. Fallback to Authorization header
 		if tokenString == "" {
-			authHeader := c.GetHeader("Authorization")
-			if authHeader != "" {
-				parts := strings.SplitN(authHeader, " ", 2)
-				if len(parts) == 2 && parts[0] == "Bearer" {
-					tokenString = parts[1]
-				}
-			}
+			tokenString = c.Query("access_token")
 		}
+		m.authenticate(c, tokenString)
+	}
+}
 
-		if tokenString == "" {
-			c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(dto.ErrCodeUnauthorized, "Authentication required")))
-			c.Abort()
-			return
+// requestToken reads the token from a Bearer Authorization header, falling
+// back to the access_token cookie. An explicit header wins so CLI and
+// server-to-server clients are not overridden by a stale browser cookie.
+func requestToken(c *gin.Context) string {
+	if token := bearerToken(c); token != "" {
+		return token
+	}
+
+	if cookieToken, err := c.Cookie("access_token"); err == nil && cookieToken != "" {
+		return cookieToken
+	}
+	return ""
+}
+
+// bearerToken reads the token from a Bearer Authorization header
+func bearerToken(c *gin.Context) string {
+	authHeader := c.GetHeader("Authorization")
+	if authHeader != "" {
+		parts := strings.SplitN(authHeader, " ", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
+
+// socketProtocolToken returns the protocol following SocketTokenProtocol in a
+// Sec-WebSocket-Protocol header
+func socketProtocolToken(header string) string {
+	protocols := strings.Split(header, ",")
+	for i := 0; i+1 < len(protocols); i++ {
+		if strings.TrimSpace(protocols[i]) == SocketTokenProtocol {
+			return strings.TrimSpace(protocols[i+1])
+		}
+	}
+	return ""
+}
+
+func (m *AuthMiddleware) authenticate(c *gin.Context, tokenString string) {
+	if tokenString == "" {
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeUnauthorized, "Authentication required")))
+		c.Abort()
+		return
+	}
+
+	if service.IsAccessToken(tokenString) {
+		m.authenticateAccessToken(c, tokenString)
+		return
+	}
+
+	// Validate token
+	claims, err := m.jwtService.ValidateToken(tokenString)
+	if err != nil {
+		errCode := dto.ErrCodeInvalidToken
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			errCode = dto.ErrCodeExpiredToken
 		}
 
-		// Validate token
-		claims, err := m.jwtService.ValidateToken(tokenString)
-		if err != nil {
-			errCode := dto.ErrCodeInvalidToken
-			if errors.Is(err, jwt.ErrTokenExpired) {
-				errCode = dto.ErrCodeExpiredToken
-			}
+		c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(errCode)))
+		c.Abort()
+		return
+	}
+
+	// Set user information in context
+	c.Set("user_id", claims.UserID)
+	c.Set("user_email", claims.Email)
+
+	c.Next()
+}
 
+// authenticateAccessToken accepts a personal access token. Read-only tokens
+// are limited to safe methods.
+func (m *AuthMiddleware) authenticateAccessToken(c *gin.Context, tokenString string) {
+	token, err := m.accessTokens.Authenticate(c.Request.Context(), tokenString)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidToken) {
 			c.JSON(http.StatusUnauthorized, dto.NewAPIResponse[any](nil,
-				dto.NewErrorResponse(errCode)))
-			c.Abort()
-			return
+				dto.NewErrorResponse(dto.ErrCodeInvalidToken)))
+		} else {
+			logger.Error().Err(err).Msg("Failed to verify access token")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
 		}
+		c.Abort()
+		return
+	}
+
+	if !isSafeMethod(c.Request.Method) && !token.HasScope(domain.TokenScopeWrite) {
+		c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+			dto.NewErrorResponse(dto.ErrCodeInsufficientTokenScope)))
+		c.Abort()
+		return
+	}
 
-		// Set user information in context
-		c.Set("user_id", claims.UserID)
-		c.Set("user_email", claims.Email)
+	c.Set("user_id", token.UserID.Hex())
+	c.Set(accessTokenIDKey, token.ID.Hex())
 
+	c.Next()
+}
+
+// RequireSession rejects requests authenticated with a personal access
+// token. It guards routes that manage credentials, so a leaked token cannot
+// mint new tokens or change the password.
+func (m *AuthMiddleware) RequireSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, viaToken := c.Get(accessTokenIDKey); viaToken {
+			c.JSON(http.StatusForbidden, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeSessionRequired)))
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
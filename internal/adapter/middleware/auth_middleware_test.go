@@ -0,0 +1,232 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryTokenRepo struct {
+	port.PersonalAccessTokenRepository
+	tokens []*domain.PersonalAccessToken
+}
+
+func (r *memoryTokenRepo) Create(_ context.Context, token *domain.PersonalAccessToken) error {
+	token.ID = primitive.NewObjectID()
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+func (r *memoryTokenRepo) FindByUserID(context.Context, primitive.ObjectID) ([]*domain.PersonalAccessToken, error) {
+	return nil, nil
+}
+
+func (r *memoryTokenRepo) FindByHash(_ context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			return token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryTokenRepo) TouchLastUsed(context.Context, primitive.ObjectID, time.Time) error {
+	return nil
+}
+
+type authTestEnv struct {
+	router       *gin.Engine
+	jwt          *service.JWTService
+	accessTokens *service.AccessTokenService
+}
+
+func newAuthTestEnv(t *testing.T) *authTestEnv {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	env := &authTestEnv{
+		jwt:          service.NewJWTService("test-secret", time.Minute, time.Hour),
+		accessTokens: service.NewAccessTokenService(&memoryTokenRepo{}),
+	}
+	auth := NewAuthMiddleware(env.jwt, env.accessTokens)
+
+	whoami := func(c *gin.Context) { c.String(http.StatusOK, c.GetString("user_id")) }
+	env.router = gin.New()
+	protected := env.router.Group("/", auth.RequireAuth())
+	protected.GET("/items", whoami)
+	protected.POST("/items", whoami)
+	protected.PUT("/items", whoami)
+	protected.DELETE("/items", whoami)
+	protected.POST("/tokens", auth.RequireSession(), whoami)
+	env.router.GET("/ws", auth.RequireSocketAuth(), whoami)
+	return env
+}
+
+func (env *authTestEnv) session(t *testing.T, userID primitive.ObjectID) string {
+	t.Helper()
+	token, err := env.jwt.GenerateAccessToken(userID, "user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func (env *authTestEnv) accessToken(t *testing.T, userID primitive.ObjectID, scopes ...string) string {
+	t.Helper()
+	_, plaintext, err := env.accessTokens.CreateToken(context.Background(), userID, "ci", scopes, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return plaintext
+}
+
+func (env *authTestEnv) do(method, path, bearer, cookie string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: "access_token", Value: cookie})
+	}
+	recorder := httptest.NewRecorder()
+	env.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func errorCode(t *testing.T, recorder *httptest.ResponseRecorder) string {
+	t.Helper()
+	var response dto.APIResponse[json.RawMessage]
+	if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil || response.Error == nil {
+		t.Fatalf("expected an error envelope, got %d %s", recorder.Code, recorder.Body)
+	}
+	return response.Error.Code
+}
+
+func TestRequireAuthTokenSources(t *testing.T) {
+	env := newAuthTestEnv(t)
+	headerUser, cookieUser := primitive.NewObjectID(), primitive.NewObjectID()
+	headerToken, cookieToken := env.session(t, headerUser), env.session(t, cookieUser)
+
+	tests := []struct {
+		name           string
+		bearer, cookie string
+		wantStatus     int
+		wantUser       primitive.ObjectID
+	}{
+		{name: "bearer wins over cookie", bearer: headerToken, cookie: cookieToken, wantStatus: http.StatusOK, wantUser: headerUser},
+		{name: "cookie fallback", cookie: cookieToken, wantStatus: http.StatusOK, wantUser: cookieUser},
+		{name: "invalid bearer is not rescued by cookie", bearer: "garbage", cookie: cookieToken, wantStatus: http.StatusUnauthorized},
+		{name: "no token", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := env.do(http.MethodGet, "/items", tt.bearer, tt.cookie)
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", recorder.Code, tt.wantStatus, recorder.Body)
+			}
+			if tt.wantStatus == http.StatusOK && recorder.Body.String() != tt.wantUser.Hex() {
+				t.Errorf("user = %s, want %s", recorder.Body, tt.wantUser.Hex())
+			}
+		})
+	}
+}
+
+func TestRequireAuthAccessTokenScopes(t *testing.T) {
+	env := newAuthTestEnv(t)
+	userID := primitive.NewObjectID()
+	readOnly := env.accessToken(t, userID, domain.TokenScopeRead)
+	readWrite := env.accessToken(t, userID, domain.TokenScopeWrite)
+
+	if recorder := env.do(http.MethodGet, "/items", readOnly, ""); recorder.Code != http.StatusOK {
+		t.Errorf("read-only GET: status = %d, want 200", recorder.Code)
+	}
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodDelete} {
+		recorder := env.do(method, "/items", readOnly, "")
+		if recorder.Code != http.StatusForbidden {
+			t.Errorf("read-only %s: status = %d, want 403", method, recorder.Code)
+			continue
+		}
+		if code := errorCode(t, recorder); code != dto.ErrCodeInsufficientTokenScope {
+			t.Errorf("read-only %s: code = %s, want %s", method, code, dto.ErrCodeInsufficientTokenScope)
+		}
+
+		if recorder := env.do(method, "/items", readWrite, ""); recorder.Code != http.StatusOK {
+			t.Errorf("write %s: status = %d, want 200", method, recorder.Code)
+		}
+	}
+}
+
+func TestRequireSessionRejectsAccessTokens(t *testing.T) {
+	env := newAuthTestEnv(t)
+	userID := primitive.NewObjectID()
+
+	recorder := env.do(http.MethodPost, "/tokens", env.accessToken(t, userID, domain.TokenScopeWrite), "")
+	if recorder.Code != http.StatusForbidden {
+		t.Fatalf("access token: status = %d, want 403", recorder.Code)
+	}
+	if code := errorCode(t, recorder); code != dto.ErrCodeSessionRequired {
+		t.Errorf("access token: code = %s, want %s", code, dto.ErrCodeSessionRequired)
+	}
+
+	if recorder := env.do(http.MethodPost, "/tokens", env.session(t, userID), ""); recorder.Code != http.StatusOK {
+		t.Errorf("session: status = %d, want 200", recorder.Code)
+	}
+}
+
+func TestRequireSocketAuthIgnoresCookie(t *testing.T) {
+	env := newAuthTestEnv(t)
+	userID := primitive.NewObjectID()
+	token := env.session(t, userID)
+
+	tests := []struct {
+		name       string
+		prepare    func(req *http.Request)
+		wantStatus int
+	}{
+		{
+			name:       "cookie",
+			prepare:    func(req *http.Request) { req.AddCookie(&http.Cookie{Name: "access_token", Value: token}) },
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "bearer header",
+			prepare:    func(req *http.Request) { req.Header.Set("Authorization", "Bearer "+token) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "subprotocol",
+			prepare:    func(req *http.Request) { req.Header.Set("Sec-WebSocket-Protocol", SocketTokenProtocol+", "+token) },
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "query parameter",
+			prepare:    func(req *http.Request) { req.URL.RawQuery = "access_token=" + token },
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ws", nil)
+			tt.prepare(req)
+			recorder := httptest.NewRecorder()
+			env.router.ServeHTTP(recorder, req)
+
+			if recorder.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", recorder.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusOK && recorder.Body.String() != userID.Hex() {
+				t.Errorf("user = %s, want %s", recorder.Body, userID.Hex())
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// BodyLimitMiddleware caps the request body size to protect the server from
+// oversized payloads. Routes listed in skipRoutes (matched against the gin
+// route pattern) are left untouched so they can apply their own limits.
+func BodyLimitMiddleware(limit int64, skipRoutes ...string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(skipRoutes))
+	for _, route := range skipRoutes {
+		skip[route] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if limit <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		if _, ok := skip[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+
+		// Reject early when the client announces an oversized body
+		if c.Request.ContentLength > limit {
+			RespondBodyTooLarge(c)
+			return
+		}
+
+		// Guard chunked or misreported bodies while they are being read
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit)
+
+		c.Next()
+	}
+}
+
+// RespondBodyTooLarge aborts with 413. Handlers call it when binding fails
+// with an *http.MaxBytesError, i.e. the body was cut off while being read.
+func RespondBodyTooLarge(c *gin.Context) {
+	c.JSON(http.StatusRequestEntityTooLarge, dto.NewAPIResponse[any](nil,
+		dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Request body too large")))
+	c.Abort()
+}
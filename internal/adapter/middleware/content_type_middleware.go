@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/gin-gonic/gin"
+)
+
+// RequireJSON rejects POST, PUT and PATCH requests whose body is not declared
+// as JSON with 415, instead of letting binding fail with a generic error.
+// Requests without a body pass, as do routes listed in skipRoutes (matched
+// against the gin route pattern), such as multipart uploads.
+func RequireJSON(skipRoutes ...string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(skipRoutes))
+	for _, route := range skipRoutes {
+		skip[route] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			c.Next()
+			return
+		}
+
+		if _, ok := skip[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+
+		// Actions such as archive or accept carry no body at all
+		if c.Request.ContentLength == 0 && len(c.Request.TransferEncoding) == 0 {
+			c.Next()
+			return
+		}
+
+		if !isJSONMediaType(c.GetHeader("Content-Type")) {
+			c.JSON(http.StatusUnsupportedMediaType, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeUnsupportedMediaType)))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// isJSONMediaType accepts application/json and structured +json types, with
+// any parameters such as charset
+func isJSONMediaType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,93 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/gin-gonic/gin"
+)
+
+func newContentTypeRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequireJSON("/projects/:project_id/restore"))
+	ok := func(c *gin.Context) { c.Status(http.StatusNoContent) }
+	router.POST("/projects", ok)
+	router.PUT("/projects/:project_id", ok)
+	router.PATCH("/projects/:project_id", ok)
+	router.DELETE("/projects/:project_id", ok)
+	router.POST("/projects/:project_id/archive", ok)
+	router.POST("/projects/:project_id/restore", ok)
+	return router
+}
+
+func sendBody(router *gin.Engine, method, path, contentType string, body io.Reader) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, body)
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestRequireJSONAcceptsJSON(t *testing.T) {
+	router := newContentTypeRouter()
+
+	cases := []struct {
+		method, path, contentType string
+	}{
+		{http.MethodPost, "/projects", "application/json"},
+		{http.MethodPut, "/projects/p1", "application/json; charset=utf-8"},
+		{http.MethodPatch, "/projects/p1", "application/merge-patch+json"},
+		{http.MethodPost, "/projects", "Application/JSON"},
+	}
+	for _, tc := range cases {
+		recorder := sendBody(router, tc.method, tc.path, tc.contentType, strings.NewReader(`{"name":"infra"}`))
+		if recorder.Code != http.StatusNoContent {
+			t.Errorf("%s %s as %q: status = %d, want 204", tc.method, tc.path, tc.contentType, recorder.Code)
+		}
+	}
+}
+
+func TestRequireJSONRejectsOtherTypes(t *testing.T) {
+	router := newContentTypeRouter()
+
+	for _, contentType := range []string{"", "text/plain", "application/x-www-form-urlencoded", "multipart/form-data; boundary=x", "application/json;;"} {
+		recorder := sendBody(router, http.MethodPost, "/projects", contentType, strings.NewReader(`name=infra`))
+		if recorder.Code != http.StatusUnsupportedMediaType {
+			t.Fatalf("%q: status = %d, want 415", contentType, recorder.Code)
+		}
+		var resp dto.APIResponse[any]
+		if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%q: body is not the envelope: %s", contentType, recorder.Body)
+		}
+		if resp.Error == nil || resp.Error.Code != dto.ErrCodeUnsupportedMediaType {
+			t.Errorf("%q: response = %+v, want %s", contentType, resp, dto.ErrCodeUnsupportedMediaType)
+		}
+	}
+}
+
+func TestRequireJSONPassesExemptRequests(t *testing.T) {
+	router := newContentTypeRouter()
+
+	cases := []struct {
+		name, method, path, contentType string
+		body                            io.Reader
+	}{
+		{"skipped route", http.MethodPost, "/projects/p1/restore", "multipart/form-data; boundary=x", strings.NewReader("--x--")},
+		{"no body", http.MethodPost, "/projects/p1/archive", "", nil},
+		{"delete", http.MethodDelete, "/projects/p1", "text/plain", strings.NewReader("ignored")},
+	}
+	for _, tc := range cases {
+		recorder := sendBody(router, tc.method, tc.path, tc.contentType, tc.body)
+		if recorder.Code != http.StatusNoContent {
+			t.Errorf("%s: status = %d, want 204", tc.name, recorder.Code)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// IdempotentReplayHeader marks responses replayed from a stored record.
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+)
+
+type IdempotencyMiddleware struct {
+	repo     port.IdempotencyRepository
+	ttl      time.Duration
+	inFlight sync.Map
+}
+
+func NewIdempotencyMiddleware(repo port.IdempotencyRepository, ttl time.Duration) *IdempotencyMiddleware {
+	if ttl <= 0 {
+		ttl = 24 * time.Hour
+	}
+	return &IdempotencyMiddleware{
+		repo: repo,
+		ttl:  ttl,
+	}
+}
+
+// Handle replays the stored response when an authenticated user repeats a
+// request with the same Idempotency-Key. Requests without the header pass
+// through untouched. Only successful responses are stored, so failed
+// attempts can be retried with the same key. Must run after RequireAuth.
+func (m *IdempotencyMiddleware) Handle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInvalidRequest, "Idempotency-Key is too long")))
+			c.Abort()
+			return
+		}
+
+		userIDStr := c.GetString("user_id")
+		userID, err := primitive.ObjectIDFromHex(userIDStr)
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		// Serialize concurrent retries of the same key on this instance
+		lockKey := userIDStr + ":" + key
+		if _, busy := m.inFlight.LoadOrStore(lockKey, struct{}{}); busy {
+			c.JSON(http.StatusConflict, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeIdempotencyInProgress)))
+			c.Abort()
+			return
+		}
+		defer m.inFlight.Delete(lockKey)
+
+		ctx := c.Request.Context()
+		path := c.Request.URL.Path
+
+		record, err := m.repo.FindActive(ctx, userID, key)
+		if err != nil {
+			logger.Error().Err(err).Msg("Failed to look up idempotency key")
+			status, errResp := dto.NewServerErrorResponse(err)
+			c.JSON(status, dto.NewAPIResponse[any](nil, errResp))
+			c.Abort()
+			return
+		}
+
+		if record != nil {
+			if record.Method != c.Request.Method || record.Path != path {
+				c.JSON(http.StatusUnprocessableEntity, dto.NewAPIResponse[any](nil,
+					dto.NewErrorResponse(dto.ErrCodeIdempotencyKeyReused)))
+				c.Abort()
+				return
+			}
+
+			c.Header(IdempotentReplayHeader, "true")
+			c.Data(record.StatusCode, record.ContentType, record.Body)
+			c.Abort()
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+
+		c.Next()
+
+		status := recorder.Status()
+		if status < 200 || status >= 300 {
+			return
+		}
+
+		err = m.repo.Create(ctx, &domain.IdempotencyRecord{
+			UserID:      userID,
+			Key:         key,
+			Method:      c.Request.Method,
+			Path:        path,
+			StatusCode:  status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+			ExpiresAt:   time.Now().Add(m.ttl),
+		})
+		if err != nil {
+			logger.Error().Err(err).Str("path", path).Msg("Failed to store idempotency record")
+		}
+	}
+}
+
+// responseRecorder copies the response body while it is written
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(s string) (int, error) {
+	r.body.WriteString(s)
+	return r.ResponseWriter.WriteString(s)
+}
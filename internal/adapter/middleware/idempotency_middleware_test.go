@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/gin-gonic/gin"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type memoryIdempotencyRepo struct {
+	port.IdempotencyRepository
+	mu      sync.Mutex
+	records []*domain.IdempotencyRecord
+}
+
+func (r *memoryIdempotencyRepo) Create(_ context.Context, record *domain.IdempotencyRecord) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.records = append(r.records, record)
+	return nil
+}
+
+func (r *memoryIdempotencyRepo) FindActive(_ context.Context, userID primitive.ObjectID, key string) (*domain.IdempotencyRecord, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, record := range r.records {
+		if record.UserID == userID && record.Key == key && record.ExpiresAt.After(time.Now()) {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+// idempotencyTestEnv counts the calls that reach the handlers. POST /fail
+// answers 500 until fail is cleared; POST /slow waits for release.
+type idempotencyTestEnv struct {
+	router  *gin.Engine
+	repo    *memoryIdempotencyRepo
+	userID  primitive.ObjectID
+	mu      sync.Mutex
+	calls   int
+	fail    bool
+	entered chan struct{}
+	release chan struct{}
+}
+
+func newIdempotencyTestEnv() *idempotencyTestEnv {
+	gin.SetMode(gin.TestMode)
+	env := &idempotencyTestEnv{
+		repo:    &memoryIdempotencyRepo{},
+		userID:  primitive.NewObjectID(),
+		fail:    true,
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	idempotency := NewIdempotencyMiddleware(env.repo, time.Hour)
+
+	count := func() int {
+		env.mu.Lock()
+		defer env.mu.Unlock()
+		env.calls++
+		return env.calls
+	}
+	env.router = gin.New()
+	group := env.router.Group("/", func(c *gin.Context) { c.Set("user_id", env.userID.Hex()) }, idempotency.Handle())
+	group.POST("/items", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, dto.NewAPIResponse(gin.H{"call": count()}, nil))
+	})
+	group.POST("/other", func(c *gin.Context) {
+		c.JSON(http.StatusCreated, dto.NewAPIResponse(gin.H{"call": count()}, nil))
+	})
+	group.POST("/fail", func(c *gin.Context) {
+		count()
+		if env.fail {
+			c.JSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil, dto.NewErrorResponse(dto.ErrCodeInternalError)))
+			return
+		}
+		c.JSON(http.StatusCreated, dto.NewAPIResponse(gin.H{"ok": true}, nil))
+	})
+	group.POST("/slow", func(c *gin.Context) {
+		close(env.entered)
+		<-env.release
+		c.JSON(http.StatusCreated, dto.NewAPIResponse(gin.H{"call": count()}, nil))
+	})
+	return env
+}
+
+func (env *idempotencyTestEnv) post(path, key string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	req.Header.Set(IdempotencyKeyHeader, key)
+	recorder := httptest.NewRecorder()
+	env.router.ServeHTTP(recorder, req)
+	return recorder
+}
+
+func TestIdempotencyReplaysStoredResponse(t *testing.T) {
+	env := newIdempotencyTestEnv()
+
+	first := env.post("/items", "key-1")
+	second := env.post("/items", "key-1")
+
+	if env.calls != 1 {
+		t.Fatalf("handler ran %d times, want once", env.calls)
+	}
+	if second.Code != first.Code || second.Body.String() != first.Body.String() {
+		t.Errorf("replay = %d %s, want %d %s", second.Code, second.Body, first.Code, first.Body)
+	}
+	if second.Header().Get(IdempotentReplayHeader) != "true" {
+		t.Errorf("replay is missing the %s header", IdempotentReplayHeader)
+	}
+
+	if third := env.post("/items", "key-2"); third.Header().Get(IdempotentReplayHeader) != "" || env.calls != 2 {
+		t.Errorf("a new key was replayed; handler ran %d times", env.calls)
+	}
+}
+
+func TestIdempotencyKeyReusedOnAnotherPath(t *testing.T) {
+	env := newIdempotencyTestEnv()
+	env.post("/items", "key-1")
+
+	recorder := env.post("/other", "key-1")
+	if recorder.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("status = %d, want 422", recorder.Code)
+	}
+	if code := errorCode(t, recorder); code != dto.ErrCodeIdempotencyKeyReused {
+		t.Errorf("code = %s, want %s", code, dto.ErrCodeIdempotencyKeyReused)
+	}
+	if env.calls != 1 {
+		t.Errorf("handler ran %d times, want once", env.calls)
+	}
+}
+
+func TestIdempotencyConcurrentReuseIs409(t *testing.T) {
+	env := newIdempotencyTestEnv()
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- env.post("/slow", "key-1") }()
+	<-env.entered
+
+	recorder := env.post("/slow", "key-1")
+	close(env.release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Fatalf("first request status = %d, want 201", first.Code)
+	}
+
+	if recorder.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409", recorder.Code)
+	}
+	if code := errorCode(t, recorder); code != dto.ErrCodeIdempotencyInProgress {
+		t.Errorf("code = %s, want %s", code, dto.ErrCodeIdempotencyInProgress)
+	}
+}
+
+func TestIdempotencyDoesNotStoreFailures(t *testing.T) {
+	env := newIdempotencyTestEnv()
+
+	if recorder := env.post("/fail", "key-1"); recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("status = %d, want 500", recorder.Code)
+	}
+	if len(env.repo.records) != 0 {
+		t.Fatalf("stored %d records for a failed response", len(env.repo.records))
+	}
+
+	env.fail = false
+	recorder := env.post("/fail", "key-1")
+	if recorder.Code != http.StatusCreated || recorder.Header().Get(IdempotentReplayHeader) != "" {
+		t.Errorf("retry = %d replayed=%q, want a fresh 201", recorder.Code, recorder.Header().Get(IdempotentReplayHeader))
+	}
+	if env.calls != 2 {
+		t.Errorf("handler ran %d times, want twice", env.calls)
+	}
+}
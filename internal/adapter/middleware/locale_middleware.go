@@ -0,0 +1,86 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// LocaleContextKey is the gin context key holding the negotiated locale.
+const LocaleContextKey = "locale"
+
+// LocaleMiddleware negotiates the response locale from Accept-Language and
+// translates error envelopes into it. Error codes are never translated, so
+// clients can keep switching on them. Default-locale responses pass through
+// untouched; for other locales JSON error bodies are buffered, localized and
+// then written. Must be registered inside any compression middleware so it
+// sees the plain JSON body.
+func LocaleMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Negotiate(c.GetHeader("Accept-Language"))
+		c.Set(LocaleContextKey, locale)
+		c.Header("Content-Language", locale)
+
+		if locale == i18n.DefaultLocale {
+			c.Next()
+			return
+		}
+
+		writer := &localizingWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		writer.flush(locale)
+	}
+}
+
+// localizingWriter holds back JSON error bodies so they can be translated
+// once the handler has finished writing them
+type localizingWriter struct {
+	gin.ResponseWriter
+	decided   bool
+	buffering bool
+	body      bytes.Buffer
+}
+
+func (w *localizingWriter) shouldBuffer() bool {
+	if !w.decided {
+		w.decided = true
+		w.buffering = w.Status() >= 400 &&
+			strings.HasPrefix(w.Header().Get("Content-Type"), "application/json")
+	}
+	return w.buffering
+}
+
+func (w *localizingWriter) Write(data []byte) (int, error) {
+	if w.shouldBuffer() {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *localizingWriter) WriteString(s string) (int, error) {
+	if w.shouldBuffer() {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *localizingWriter) flush(locale string) {
+	if !w.buffering {
+		return
+	}
+
+	body := w.body.Bytes()
+	var envelope dto.APIResponse[json.RawMessage]
+	if err := json.Unmarshal(body, &envelope); err == nil && envelope.Error != nil {
+		envelope.Error.Localize(locale)
+		if localized, err := json.Marshal(envelope); err == nil {
+			body = localized
+		}
+	}
+	_, _ = w.ResponseWriter.Write(body)
+}
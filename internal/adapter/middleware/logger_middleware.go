@@ -17,7 +17,7 @@ func LoggerMiddleware() gin.HandlerFunc {
 
 		// Start timer
 		start := time.Now()
-		path := c.Request.URL.Path
+		path := logger.RedactPath(c.Request.URL.Path, c.Param("token"))
 		method := c.Request.Method
 
 		// Process request
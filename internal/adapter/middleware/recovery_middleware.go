@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// RecoveryMiddleware turns a panicking handler into a 500 with the usual
+// error envelope and logs the panic with its stack. It must be registered
+// first so it covers every other middleware.
+func RecoveryMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			recovered := recover()
+			if recovered == nil {
+				return
+			}
+			// net/http uses this panic to abort a response on purpose
+			if err, ok := recovered.(error); ok && errors.Is(err, http.ErrAbortHandler) {
+				panic(recovered)
+			}
+
+			logger.Error().
+				Interface("panic", recovered).
+				Str("method", c.Request.Method).
+				Str("path", logger.RedactPath(c.Request.URL.Path, c.Param("token"))).
+				Bytes("stack", debug.Stack()).
+				Msg("Recovered from panic")
+
+			// Part of a response may already be on the wire; all that is left
+			// is to stop the chain
+			if c.Writer.Written() {
+				c.Abort()
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewAPIResponse[any](nil,
+				dto.NewErrorResponse(dto.ErrCodeInternalError)))
+		}()
+
+		c.Next()
+	}
+}
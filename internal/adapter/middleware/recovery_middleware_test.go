@@ -0,0 +1,65 @@
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/gin-gonic/gin"
+)
+
+func newRecoveryRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RecoveryMiddleware())
+	router.GET("/panic", func(c *gin.Context) {
+		panic("nil map write")
+	})
+	router.GET("/panic-error", func(c *gin.Context) {
+		panic(errors.New("index out of range"))
+	})
+	router.GET("/partial", func(c *gin.Context) {
+		c.String(http.StatusOK, "half a response")
+		panic("after writing")
+	})
+	router.GET("/abort", func(c *gin.Context) {
+		panic(http.ErrAbortHandler)
+	})
+	return router
+}
+
+func TestRecoveryReturnsEnvelope(t *testing.T) {
+	router := newRecoveryRouter()
+
+	for _, path := range []string{"/panic", "/panic-error"} {
+		recorder := serve(router, path)
+		if recorder.Code != http.StatusInternalServerError {
+			t.Fatalf("%s: status = %d, want 500", path, recorder.Code)
+		}
+		var resp dto.APIResponse[any]
+		if err := json.Unmarshal(recorder.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s: body is not the envelope: %s", path, recorder.Body)
+		}
+		if resp.Data != nil || resp.Error == nil || resp.Error.Code != dto.ErrCodeInternalError {
+			t.Errorf("%s: response = %+v, want %s", path, resp, dto.ErrCodeInternalError)
+		}
+	}
+}
+
+func TestRecoveryKeepsStartedResponse(t *testing.T) {
+	recorder := serve(newRecoveryRouter(), "/partial")
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "half a response" {
+		t.Errorf("status = %d, body = %q; want the response already written", recorder.Code, recorder.Body)
+	}
+}
+
+func TestRecoveryRepanicsAbortHandler(t *testing.T) {
+	defer func() {
+		if recovered := recover(); recovered != http.ErrAbortHandler {
+			t.Errorf("recovered %v, want http.ErrAbortHandler passed on", recovered)
+		}
+	}()
+	serve(newRecoveryRouter(), "/abort")
+}
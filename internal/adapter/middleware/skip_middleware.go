@@ -0,0 +1,21 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// SkipRoutes wraps a middleware so it is not applied to the listed routes
+// (matched against the gin route pattern). Used to keep response-wrapping
+// middleware such as compression away from streaming endpoints.
+func SkipRoutes(handler gin.HandlerFunc, routes ...string) gin.HandlerFunc {
+	skip := make(map[string]struct{}, len(routes))
+	for _, route := range routes {
+		skip[route] = struct{}{}
+	}
+
+	return func(c *gin.Context) {
+		if _, ok := skip[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+		handler(c)
+	}
+}
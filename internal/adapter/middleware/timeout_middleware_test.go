@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/gin-gonic/gin"
+)
+
+func newTimeoutRouter(overrides map[string]time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestTimeout(20*time.Millisecond, overrides))
+
+	// slow fails the way a handler does when its Mongo call hits the
+	// deadline: with a 500 of its own
+	router.GET("/slow", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "context deadline exceeded"})
+	})
+	router.GET("/silent", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+	})
+	router.GET("/late-ok", func(c *gin.Context) {
+		<-c.Request.Context().Done()
+		c.JSON(http.StatusOK, gin.H{"ok": true})
+	})
+	router.GET("/stream", func(c *gin.Context) {
+		if _, ok := c.Request.Context().Deadline(); ok {
+			c.Status(http.StatusInternalServerError)
+			return
+		}
+		time.Sleep(40 * time.Millisecond)
+		c.String(http.StatusOK, "done")
+	})
+	return router
+}
+
+func serve(router *gin.Engine, path string) *httptest.ResponseRecorder {
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, path, nil))
+	return recorder
+}
+
+func TestRequestTimeoutReplacesLateServerError(t *testing.T) {
+	router := newTimeoutRouter(nil)
+
+	for _, path := range []string{"/slow", "/silent"} {
+		recorder := serve(router, path)
+		if recorder.Code != http.StatusGatewayTimeout {
+			t.Fatalf("%s: status = %d, want 504", path, recorder.Code)
+		}
+		if strings.Contains(recorder.Body.String(), "context deadline exceeded") {
+			t.Errorf("%s: the handler's late 500 body leaked: %s", path, recorder.Body)
+		}
+		var response dto.APIResponse[json.RawMessage]
+		if err := json.Unmarshal(recorder.Body.Bytes(), &response); err != nil {
+			t.Fatalf("%s: body is not one JSON envelope: %v: %s", path, err, recorder.Body)
+		}
+		if response.Error == nil || response.Error.Code != dto.ErrCodeRequestTimeout {
+			t.Errorf("%s: error = %+v, want %s", path, response.Error, dto.ErrCodeRequestTimeout)
+		}
+	}
+}
+
+func TestRequestTimeoutKeepsLateSuccess(t *testing.T) {
+	recorder := serve(newTimeoutRouter(nil), "/late-ok")
+	if recorder.Code != http.StatusOK {
+		t.Errorf("status = %d, want the handler's 200", recorder.Code)
+	}
+}
+
+func TestRequestTimeoutOverrideDisablesDeadline(t *testing.T) {
+	recorder := serve(newTimeoutRouter(map[string]time.Duration{"/stream": 0}), "/stream")
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "done" {
+		t.Errorf("status = %d body = %q, want 200 done without a deadline", recorder.Code, recorder.Body)
+	}
+}
@@ -0,0 +1,18 @@
+package notifier
+
+import (
+	"context"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+)
+
+type noopNotifier struct{}
+
+// NewNoopNotifier returns a Notifier that drops every notification
+func NewNoopNotifier() port.Notifier {
+	return noopNotifier{}
+}
+
+func (noopNotifier) Notify(context.Context, port.Notification) error {
+	return nil
+}
@@ -0,0 +1,179 @@
+// Package realtime keeps ephemeral, in-memory collaboration state such as
+// who is currently viewing a diagram. Nothing here is persisted; a restart
+// simply drops every room and clients reconnect.
+package realtime
+
+import (
+	"encoding/json"
+	"sync"
+)
+
+// Event types exchanged over a presence socket
+const (
+	// EventSnapshot is sent to a client right after it joins and lists the
+	// participants already in the room
+	EventSnapshot = "presence.snapshot"
+	EventJoin     = "presence.join"
+	EventLeave    = "presence.leave"
+
+	// EventCursor and EventSelection are relayed verbatim to the other
+	// participants, stamped with the sender
+	EventCursor    = "cursor"
+	EventSelection = "selection"
+
+	// EventPing is answered with EventPong and keeps an idle socket alive
+	EventPing = "ping"
+	EventPong = "pong"
+)
+
+// sendBuffer is how many events may queue up for a client before it is
+// considered too slow and disconnected
+const sendBuffer = 64
+
+// Participant identifies one connection in a room. A user with several tabs
+// open appears once per tab, each with its own session ID.
+type Participant struct {
+	SessionID string `json:"session_id"`
+	UserID    string `json:"user_id"`
+	Username  string `json:"username"`
+	Name      string `json:"name"`
+}
+
+// Event is the JSON envelope of every socket message
+type Event struct {
+	Type         string          `json:"type"`
+	Participant  *Participant    `json:"participant,omitempty"`
+	Participants []Participant   `json:"participants,omitempty"`
+	Data         json.RawMessage `json:"data,omitempty"`
+}
+
+// Client is a hub member. The transport drains Outbox and stops once Done is
+// closed.
+type Client struct {
+	Participant Participant
+
+	outbox    chan []byte
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewClient creates a client for the participant
+func NewClient(participant Participant) *Client {
+	return &Client{
+		Participant: participant,
+		outbox:      make(chan []byte, sendBuffer),
+		done:        make(chan struct{}),
+	}
+}
+
+// Outbox returns the encoded events waiting to be written to the socket
+func (c *Client) Outbox() <-chan []byte {
+	return c.outbox
+}
+
+// Done is closed when the client has left or was dropped for being slow
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Send queues an event for this client only
+func (c *Client) Send(event Event) {
+	if message, err := json.Marshal(event); err == nil {
+		c.enqueue(message)
+	}
+}
+
+func (c *Client) enqueue(message []byte) {
+	select {
+	case <-c.done:
+	case c.outbox <- message:
+	default:
+		// The socket cannot keep up; drop it rather than block the room
+		c.close()
+	}
+}
+
+func (c *Client) close() {
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// Hub fans presence events out to the clients of each room. Rooms are keyed
+// by an opaque string (the diagram ID) and disappear when the last client
+// leaves.
+type Hub struct {
+	mu    sync.Mutex
+	rooms map[string]map[*Client]struct{}
+}
+
+// NewHub creates an empty hub
+func NewHub() *Hub {
+	return &Hub{rooms: make(map[string]map[*Client]struct{})}
+}
+
+// Join adds the client to the room, sends it a snapshot of the current
+// participants and announces it to everyone else.
+func (h *Hub) Join(room string, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	clients, ok := h.rooms[room]
+	if !ok {
+		clients = make(map[*Client]struct{})
+		h.rooms[room] = clients
+	}
+
+	participants := make([]Participant, 0, len(clients))
+	for other := range clients {
+		participants = append(participants, other.Participant)
+	}
+	client.Send(Event{Type: EventSnapshot, Participants: participants})
+
+	clients[client] = struct{}{}
+	h.broadcastLocked(room, client, Event{Type: EventJoin, Participant: &client.Participant})
+}
+
+// Leave removes the client from the room and announces its departure. It is
+// safe to call more than once.
+func (h *Hub) Leave(room string, client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	client.close()
+
+	clients, ok := h.rooms[room]
+	if !ok {
+		return
+	}
+	if _, ok := clients[client]; !ok {
+		return
+	}
+
+	delete(clients, client)
+	if len(clients) == 0 {
+		delete(h.rooms, room)
+		return
+	}
+	h.broadcastLocked(room, client, Event{Type: EventLeave, Participant: &client.Participant})
+}
+
+// Relay forwards a client event to the other participants of the room
+func (h *Hub) Relay(room string, from *Client, eventType string, data json.RawMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.broadcastLocked(room, from, Event{Type: eventType, Participant: &from.Participant, Data: data})
+}
+
+// broadcastLocked sends the event to every client in the room except the
+// sender. The caller must hold h.mu.
+func (h *Hub) broadcastLocked(room string, from *Client, event Event) {
+	message, err := json.Marshal(event)
+	if err != nil {
+		return
+	}
+	for client := range h.rooms[room] {
+		if client != from {
+			client.enqueue(message)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Lyearn/mgod"
+	"github.com/Lyearn/mgod/schema/schemaopt"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type backupArchiveRepository struct {
+	model mgod.EntityMongoModel[domain.BackupArchive]
+}
+
+func NewBackupArchiveRepository(collectionName string) (port.BackupArchiveRepository, error) {
+	opts := schemaopt.SchemaOptions{
+		Collection: collectionName,
+		Timestamps: true,
+	}
+	model, err := mgod.NewEntityMongoModel(domain.BackupArchive{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backupArchiveRepository{model: model}, nil
+}
+
+func (r *backupArchiveRepository) Create(ctx context.Context, archive *domain.BackupArchive) (*domain.BackupArchive, error) {
+	result, err := r.model.InsertOne(ctx, *archive)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r *backupArchiveRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.BackupArchive, error) {
+	return r.model.FindOne(ctx, bson.M{"_id": id})
+}
+
+// FindByProjectID returns a page of archives, newest first
+func (r *backupArchiveRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.BackupArchive, int64, error) {
+	filter := bson.M{"project_id": projectID}
+
+	totalCount, err := r.model.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if int64(offset) >= totalCount {
+		return []*domain.BackupArchive{}, totalCount, nil
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	archives, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*domain.BackupArchive, 0, len(archives))
+	for i := range archives {
+		result = append(result, &archives[i])
+	}
+
+	return result, totalCount, nil
+}
+
+func (r *backupArchiveRepository) DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
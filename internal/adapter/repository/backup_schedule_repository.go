@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Lyearn/mgod"
+	"github.com/Lyearn/mgod/schema/schemaopt"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type backupScheduleRepository struct {
+	model mgod.EntityMongoModel[domain.BackupSchedule]
+}
+
+func NewBackupScheduleRepository(collectionName string) (port.BackupScheduleRepository, error) {
+	opts := schemaopt.SchemaOptions{
+		Collection: collectionName,
+		Timestamps: true,
+	}
+	model, err := mgod.NewEntityMongoModel(domain.BackupSchedule{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &backupScheduleRepository{model: model}, nil
+}
+
+func (r *backupScheduleRepository) Create(ctx context.Context, schedule *domain.BackupSchedule) error {
+	result, err := r.model.InsertOne(ctx, *schedule)
+	if err != nil {
+		return err
+	}
+	schedule.ID = result.ID
+	return nil
+}
+
+// FindByProjectID returns nil when the project has no schedule
+func (r *backupScheduleRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID) (*domain.BackupSchedule, error) {
+	return r.model.FindOne(ctx, bson.M{"project_id": projectID})
+}
+
+func (r *backupScheduleRepository) Update(ctx context.Context, schedule *domain.BackupSchedule) error {
+	filter := bson.M{"_id": schedule.ID}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "enabled", Value: schedule.Enabled},
+			{Key: "interval_hours", Value: schedule.IntervalHours},
+			{Key: "backup_key", Value: schedule.BackupKey},
+			{Key: "configured_by", Value: schedule.ConfiguredBy},
+			{Key: "next_run_at", Value: schedule.NextRunAt},
+			{Key: "last_run_at", Value: schedule.LastRunAt},
+			{Key: "last_error", Value: schedule.LastError},
+		}},
+	}
+	_, err := r.model.UpdateMany(ctx, filter, update)
+	return err
+}
+
+// ClaimDue atomically picks one enabled schedule whose next run is due and
+// pushes its next run out by lease, so concurrent schedulers do not run the
+// same backup. It returns nil when nothing is due.
+func (r *backupScheduleRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.BackupSchedule, error) {
+	filter := bson.M{
+		"enabled":     true,
+		"next_run_at": bson.M{"$lte": now},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "next_run_at", Value: now.Add(lease)},
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "next_run_at", Value: 1}})
+
+	schedule, err := r.model.FindOneAndUpdate(ctx, filter, update, opts)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *backupScheduleRepository) DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Lyearn/mgod"
+	"github.com/Lyearn/mgod/schema/schemaopt"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type commentRepository struct {
+	model mgod.EntityMongoModel[domain.Comment]
+}
+
+func NewCommentRepository(collectionName string) (port.CommentRepository, error) {
+	opts := schemaopt.SchemaOptions{
+		Collection: collectionName,
+		Timestamps: true,
+	}
+	model, err := mgod.NewEntityMongoModel(domain.Comment{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &commentRepository{model: model}, nil
+}
+
+func (r *commentRepository) Create(ctx context.Context, comment *domain.Comment) error {
+	result, err := r.model.InsertOne(ctx, *comment)
+	if err != nil {
+		return err
+	}
+	comment.ID = result.ID
+	comment.CreatedAt = result.CreatedAt
+	comment.UpdatedAt = result.UpdatedAt
+	return nil
+}
+
+func (r *commentRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Comment, error) {
+	return r.model.FindOne(ctx, bson.M{"_id": id})
+}
+
+// FindByTargetAfter lists the target's comments oldest first. A zero
+// parentID lists the top-level comments, otherwise the replies to parentID.
+func (r *commentRepository) FindByTargetAfter(
+	ctx context.Context,
+	projectID primitive.ObjectID,
+	target domain.CommentTarget,
+	parentID, afterID primitive.ObjectID,
+	limit int,
+) ([]*domain.Comment, error) {
+	filter := bson.M{
+		"project_id":  projectID,
+		"target_type": target.Type,
+		"target_id":   target.ID,
+	}
+	if parentID.IsZero() {
+		filter["parent_comment_id"] = bson.M{"$exists": false}
+	} else {
+		filter["parent_comment_id"] = parentID
+	}
+	if !afterID.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	// Sort by _id so the cursor stays stable under concurrent inserts
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+
+	comments, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Comment, 0, len(comments))
+	for i := range comments {
+		result = append(result, &comments[i])
+	}
+	return result, nil
+}
+
+func (r *commentRepository) UpdateBody(ctx context.Context, id primitive.ObjectID, encryptedBody, signature string) (*domain.Comment, error) {
+	update := bson.D{{Key: "$set", Value: bson.M{
+		"encrypted_body":           encryptedBody,
+		"encrypted_body_signature": signature,
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	comment, err := r.model.FindOneAndUpdate(ctx, bson.M{"_id": id}, update, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Delete removes the comment together with its replies
+func (r *commentRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"$or": bson.A{
+		bson.M{"_id": id},
+		bson.M{"parent_comment_id": id},
+	}})
+	return err
+}
+
+func (r *commentRepository) DeleteByDiagramID(ctx context.Context, diagramID primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"diagram_id": diagramID})
+	return err
+}
+
+func (r *commentRepository) DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
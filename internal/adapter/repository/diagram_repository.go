@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"regexp"
 
 	"github.com/Lyearn/mgod"
 	"github.com/Lyearn/mgod/schema/schemaopt"
@@ -9,6 +10,7 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type diagramRepository struct {
@@ -41,6 +43,50 @@ func (r *diagramRepository) FindByID(ctx context.Context, id primitive.ObjectID)
 	return r.model.FindOne(ctx, bson.M{"_id": id})
 }
 
+func (r *diagramRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Diagram, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	diagrams, err := r.model.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Diagram, 0, len(diagrams))
+	for i := range diagrams {
+		result = append(result, &diagrams[i])
+	}
+	return result, nil
+}
+
+// SearchByName finds up to limit diagrams of the given projects whose name
+// contains query, sorted by name. The query is matched literally. A $text
+// index only matches whole words, so a substring search needs the
+// unanchored regex, which scans the diagrams of the given projects.
+func (r *diagramRepository) SearchByName(ctx context.Context, projectIDs []primitive.ObjectID, query string, limit int) ([]*domain.Diagram, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+	filter := bson.M{
+		"project_id":   bson.M{"$in": projectIDs},
+		"diagram_name": bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "diagram_name", Value: 1}}).
+		SetCollation(&options.Collation{Locale: "en", Strength: 1}).
+		SetLimit(int64(limit))
+	diagrams, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Diagram, 0, len(diagrams))
+	for i := range diagrams {
+		result = append(result, &diagrams[i])
+	}
+	return result, nil
+}
+
 func (r *diagramRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID, rootOnly bool, offset, limit int) ([]*domain.Diagram, int64, error) {
 	filter := bson.M{"project_id": projectID}
 	if rootOnly {
@@ -74,6 +120,30 @@ func (r *diagramRepository) FindByProjectID(ctx context.Context, projectID primi
 	return result, totalCount, nil
 }
 
+func (r *diagramRepository) FindByProjectIDAfter(ctx context.Context, projectID, afterID primitive.ObjectID, rootOnly bool, limit int) ([]*domain.Diagram, error) {
+	filter := bson.M{"project_id": projectID}
+	if rootOnly {
+		filter["parent_diagram_id"] = nil
+	}
+	if !afterID.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	// Sort by _id so the cursor stays stable under concurrent inserts
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+
+	diagrams, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Diagram, 0, len(diagrams))
+	for i := range diagrams {
+		result = append(result, &diagrams[i])
+	}
+	return result, nil
+}
+
 func (r *diagramRepository) FindAllByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]*domain.Diagram, error) {
 	diagrams, err := r.model.Find(ctx, bson.M{"project_id": projectID})
 	if err != nil {
@@ -87,6 +157,32 @@ func (r *diagramRepository) FindAllByProjectID(ctx context.Context, projectID pr
 	return result, nil
 }
 
+// FindRecentByProjectID returns the most recently updated diagrams of a project,
+// newest first, together with the project's total diagrams count
+func (r *diagramRepository) FindRecentByProjectID(ctx context.Context, projectID primitive.ObjectID, limit int) ([]*domain.Diagram, int64, error) {
+	filter := bson.M{"project_id": projectID}
+
+	total, err := r.model.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	diagrams, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*domain.Diagram, 0, len(diagrams))
+	for i := range diagrams {
+		result = append(result, &diagrams[i])
+	}
+	return result, total, nil
+}
+
 func (r *diagramRepository) Update(ctx context.Context, diagram *domain.Diagram) error {
 	filter := bson.M{"_id": diagram.ID}
 	update := bson.D{
@@ -96,6 +192,7 @@ func (r *diagramRepository) Update(ctx context.Context, diagram *domain.Diagram)
 			{Key: "parent_diagram_id", Value: diagram.ParentDiagramID},
 			{Key: "encrypted_data", Value: diagram.EncryptedData},
 			{Key: "encrypted_data_signature", Value: diagram.EncryptedDataSignature},
+			{Key: "updated_by", Value: diagram.UpdatedBy},
 		}},
 	}
 	_, err := r.model.UpdateMany(ctx, filter, update)
@@ -107,6 +204,14 @@ func (r *diagramRepository) Delete(ctx context.Context, id primitive.ObjectID) e
 	return err
 }
 
+func (r *diagramRepository) DeleteMany(ctx context.Context, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.model.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
 func (r *diagramRepository) DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error {
 	_, err := r.model.DeleteMany(ctx, bson.M{"project_id": projectID})
 	return err
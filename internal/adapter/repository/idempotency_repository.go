@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/Lyearn/mgod"
+	"github.com/Lyearn/mgod/schema/schemaopt"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type idempotencyRepository struct {
+	model mgod.EntityMongoModel[domain.IdempotencyRecord]
+}
+
+func NewIdempotencyRepository(collectionName string) (port.IdempotencyRepository, error) {
+	opts := schemaopt.SchemaOptions{
+		Collection: collectionName,
+		Timestamps: true,
+	}
+	model, err := mgod.NewEntityMongoModel(domain.IdempotencyRecord{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &idempotencyRepository{model: model}, nil
+}
+
+func (r *idempotencyRepository) Create(ctx context.Context, record *domain.IdempotencyRecord) error {
+	// Drop the user's expired records while we are here
+	if _, err := r.model.DeleteMany(ctx, bson.M{
+		"user_id":    record.UserID,
+		"expires_at": bson.M{"$lte": time.Now()},
+	}); err != nil {
+		return err
+	}
+
+	_, err := r.model.InsertOne(ctx, *record)
+	return err
+}
+
+// FindActive returns the unexpired record for the user's key, or nil
+func (r *idempotencyRepository) FindActive(ctx context.Context, userID primitive.ObjectID, key string) (*domain.IdempotencyRecord, error) {
+	return r.model.FindOne(ctx, bson.M{
+		"user_id":    userID,
+		"key":        key,
+		"expires_at": bson.M{"$gt": time.Now()},
+	})
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Lyearn/mgod"
 	"github.com/Lyearn/mgod/schema/schemaopt"
@@ -9,6 +10,8 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type invitationRepository struct {
@@ -36,61 +39,68 @@ func (r *invitationRepository) Create(ctx context.Context, invitation *domain.In
 	return &result, nil
 }
 
-func (r *invitationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Invitation, error) {
-	return r.model.FindOne(ctx, bson.M{"_id": id})
-}
-
-func (r *invitationRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.Invitation, int64, error) {
-	filter := bson.M{"project_id": projectID}
+// CreateMany inserts all invitations or none of them. IDs are assigned up
+// front so a partially applied insert can be rolled back.
+func (r *invitationRepository) CreateMany(ctx context.Context, invitations []*domain.Invitation) error {
+	docs := make([]domain.Invitation, len(invitations))
+	ids := make([]primitive.ObjectID, len(invitations))
+	for i, invitation := range invitations {
+		if invitation.ID.IsZero() {
+			invitation.ID = primitive.NewObjectID()
+		}
+		docs[i] = *invitation
+		ids[i] = invitation.ID
+	}
 
-	allInvitations, err := r.model.Find(ctx, filter)
+	inserted, err := r.model.InsertMany(ctx, docs)
 	if err != nil {
-		return nil, 0, err
+		_, _ = r.model.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		return err
 	}
-	totalCount := int64(len(allInvitations))
 
-	// Apply pagination manually
-	startIdx := offset
-	endIdx := offset + limit
-	if startIdx >= len(allInvitations) {
-		return []*domain.Invitation{}, totalCount, nil
-	}
-	if endIdx > len(allInvitations) {
-		endIdx = len(allInvitations)
+	for i := range inserted {
+		*invitations[i] = inserted[i]
 	}
-	paginated := allInvitations[startIdx:endIdx]
+	return nil
+}
 
-	result := make([]*domain.Invitation, 0, len(paginated))
-	for i := range paginated {
-		result = append(result, &paginated[i])
-	}
+func (r *invitationRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Invitation, error) {
+	return r.model.FindOne(ctx, bson.M{"_id": id})
+}
 
-	return result, totalCount, nil
+func (r *invitationRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.Invitation, int64, error) {
+	filter := bson.M{"project_id": projectID}
+	return r.findPaginated(ctx, filter, offset, limit)
 }
 
 func (r *invitationRepository) FindByInviteeID(ctx context.Context, inviteeUserID primitive.ObjectID, offset, limit int) ([]*domain.Invitation, int64, error) {
 	filter := bson.M{"invitee_user_id": inviteeUserID, "status": domain.InvitationStatusPending}
+	return r.findPaginated(ctx, filter, offset, limit)
+}
 
-	allInvitations, err := r.model.Find(ctx, filter)
+// findPaginated counts all matches and fetches a single page in insertion order
+func (r *invitationRepository) findPaginated(ctx context.Context, filter bson.M, offset, limit int) ([]*domain.Invitation, int64, error) {
+	totalCount, err := r.model.CountDocuments(ctx, filter)
 	if err != nil {
 		return nil, 0, err
 	}
-	totalCount := int64(len(allInvitations))
-
-	// Apply pagination manually
-	startIdx := offset
-	endIdx := offset + limit
-	if startIdx >= len(allInvitations) {
+	if int64(offset) >= totalCount {
 		return []*domain.Invitation{}, totalCount, nil
 	}
-	if endIdx > len(allInvitations) {
-		endIdx = len(allInvitations)
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "_id", Value: 1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	invitations, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
 	}
-	paginated := allInvitations[startIdx:endIdx]
 
-	result := make([]*domain.Invitation, 0, len(paginated))
-	for i := range paginated {
-		result = append(result, &paginated[i])
+	result := make([]*domain.Invitation, 0, len(invitations))
+	for i := range invitations {
+		result = append(result, &invitations[i])
 	}
 
 	return result, totalCount, nil
@@ -105,13 +115,28 @@ func (r *invitationRepository) FindByProjectAndInvitee(ctx context.Context, proj
 	return r.model.FindOne(ctx, filter)
 }
 
-func (r *invitationRepository) Update(ctx context.Context, invitation *domain.Invitation) error {
-	_, err := r.model.UpdateMany(ctx, bson.M{"_id": invitation.ID}, bson.M{
-		"$set": bson.M{
-			"status": invitation.Status,
-		},
+func (r *invitationRepository) CountByInviteeAndStatus(ctx context.Context, inviteeUserID primitive.ObjectID, status string) (int64, error) {
+	return r.model.CountDocuments(ctx, bson.M{
+		"invitee_user_id": inviteeUserID,
+		"status":          status,
 	})
-	return err
+}
+
+// Update writes the invitation's status and refreshes its UpdatedAt, which
+// then records when the status last changed
+func (r *invitationRepository) Update(ctx context.Context, invitation *domain.Invitation) error {
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	updated, err := r.model.FindOneAndUpdate(ctx, bson.M{"_id": invitation.ID}, bson.D{
+		{Key: "$set", Value: bson.D{{Key: "status", Value: invitation.Status}}},
+	}, opts)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	}
+	*invitation = updated
+	return nil
 }
 
 func (r *invitationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
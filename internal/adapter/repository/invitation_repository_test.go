@@ -0,0 +1,212 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Lyearn/mgod"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// newMockInvitationRepository builds the repository on mt's mocked
+// deployment, which answers commands with the responses a test queues
+func newMockInvitationRepository(mt *mtest.T) *invitationRepository {
+	mgod.SetDefaultConnection(mt.DB)
+	repo, err := NewInvitationRepository(mt.Coll.Name())
+	if err != nil {
+		mt.Fatal(err)
+	}
+	return repo.(*invitationRepository)
+}
+
+func testInvitation() *domain.Invitation {
+	return &domain.Invitation{
+		ID:                primitive.NewObjectID(),
+		ProjectID:         primitive.NewObjectID(),
+		InviterUserID:     primitive.NewObjectID(),
+		InviteeUserID:     primitive.NewObjectID(),
+		Role:              "viewer",
+		Permissions:       []string{},
+		EncryptedKeyrings: "keyrings",
+		KeyEpoch:          "epoch-1",
+		Status:            domain.InvitationStatusPending,
+	}
+}
+
+func TestInvitationRepositoryTimestamps(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("create sets both timestamps", func(mt *mtest.T) {
+		repo := newMockInvitationRepository(mt)
+		mt.AddMockResponses(mtest.CreateSuccessResponse())
+
+		created, err := repo.Create(context.Background(), testInvitation())
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if created.CreatedAt.IsZero() || created.UpdatedAt.IsZero() {
+			mt.Errorf("timestamps = %v, %v, want both set", created.CreatedAt, created.UpdatedAt)
+		}
+	})
+
+	mt.Run("update refreshes updatedAt", func(mt *mtest.T) {
+		repo := newMockInvitationRepository(mt)
+		invitation := testInvitation()
+		invitation.CreatedAt = time.Now().Add(-time.Hour).Truncate(time.Millisecond)
+		invitation.UpdatedAt = invitation.CreatedAt
+		invitation.Status = domain.InvitationStatusAccepted
+
+		// The stored document after the update, as findAndModify returns it
+		raw, err := bson.Marshal(invitation)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		var doc bson.D
+		if err := bson.Unmarshal(raw, &doc); err != nil {
+			mt.Fatal(err)
+		}
+		accepted := time.Now().Truncate(time.Millisecond)
+		for i := range doc {
+			if doc[i].Key == "updatedAt" {
+				doc[i].Value = accepted
+			}
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: doc}))
+
+		if err := repo.Update(context.Background(), invitation); err != nil {
+			mt.Fatal(err)
+		}
+		if !invitation.UpdatedAt.Equal(accepted) {
+			mt.Errorf("UpdatedAt = %v, want %v", invitation.UpdatedAt, accepted)
+		}
+		if invitation.Status != domain.InvitationStatusAccepted {
+			mt.Errorf("Status = %q, want %q", invitation.Status, domain.InvitationStatusAccepted)
+		}
+
+		update := mt.GetStartedEvent().Command.Lookup("update").Document()
+		if got := update.Lookup("$set", "status").StringValue(); got != domain.InvitationStatusAccepted {
+			mt.Errorf("$set.status = %q, want %q", got, domain.InvitationStatusAccepted)
+		}
+		if _, err := update.LookupErr("$currentDate", "updatedAt"); err != nil {
+			mt.Errorf("update does not refresh updatedAt: %v", update)
+		}
+	})
+}
+
+func TestInvitationRepositoryCountByInviteeAndStatus(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("counts in the database", func(mt *mtest.T) {
+		repo := newMockInvitationRepository(mt)
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(3)}}))
+
+		invitee := primitive.NewObjectID()
+		count, err := repo.CountByInviteeAndStatus(context.Background(), invitee, domain.InvitationStatusPending)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if count != 3 {
+			mt.Errorf("count = %d, want 3", count)
+		}
+
+		// CountDocuments runs as an aggregate matching invitee and status
+		started := mt.GetStartedEvent()
+		if started.CommandName != "aggregate" {
+			mt.Fatalf("command = %s, want aggregate", started.CommandName)
+		}
+		match := started.Command.Lookup("pipeline", "0", "$match").Document()
+		if got := match.Lookup("invitee_user_id").ObjectID(); got != invitee {
+			mt.Errorf("invitee_user_id = %s, want %s", got.Hex(), invitee.Hex())
+		}
+		if got := match.Lookup("status").StringValue(); got != domain.InvitationStatusPending {
+			mt.Errorf("status = %q, want %q", got, domain.InvitationStatusPending)
+		}
+	})
+
+	mt.Run("no matches", func(mt *mtest.T) {
+		repo := newMockInvitationRepository(mt)
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		count, err := repo.CountByInviteeAndStatus(context.Background(), primitive.NewObjectID(), domain.InvitationStatusPending)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if count != 0 {
+			mt.Errorf("count = %d, want 0", count)
+		}
+	})
+}
+
+func TestInvitationRepositoryFindByInviteeIDPaginates(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("counts every match and fetches one page", func(mt *mtest.T) {
+		repo := newMockInvitationRepository(mt)
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+
+		page := make([]bson.D, 2)
+		for i := range page {
+			invitation := testInvitation()
+			invitation.CreatedAt = time.Now().Truncate(time.Millisecond)
+			invitation.UpdatedAt = invitation.CreatedAt
+			raw, err := bson.Marshal(invitation)
+			if err != nil {
+				mt.Fatal(err)
+			}
+			if err := bson.Unmarshal(raw, &page[i]); err != nil {
+				mt.Fatal(err)
+			}
+		}
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(5)}}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, page...),
+		)
+
+		invitations, total, err := repo.FindByInviteeID(context.Background(), primitive.NewObjectID(), 2, 2)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if total != 5 || len(invitations) != 2 {
+			mt.Errorf("got %d invitations of %d, want 2 of 5", len(invitations), total)
+		}
+
+		started := mt.GetAllStartedEvents()
+		if len(started) != 2 || started[0].CommandName != "aggregate" || started[1].CommandName != "find" {
+			mt.Fatalf("commands = %v, want aggregate then find", started)
+		}
+		find := started[1].Command
+		if got := find.Lookup("skip").AsInt64(); got != 2 {
+			mt.Errorf("skip = %d, want 2", got)
+		}
+		if got := find.Lookup("limit").AsInt64(); got != 2 {
+			mt.Errorf("limit = %d, want 2", got)
+		}
+		// Only pending invitations are listed for the invitee
+		if got := find.Lookup("filter", "status").StringValue(); got != domain.InvitationStatusPending {
+			mt.Errorf("filter status = %q, want %q", got, domain.InvitationStatusPending)
+		}
+	})
+
+	mt.Run("an offset past the total skips the find", func(mt *mtest.T) {
+		repo := newMockInvitationRepository(mt)
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(3)}}))
+
+		invitations, total, err := repo.FindByInviteeID(context.Background(), primitive.NewObjectID(), 10, 5)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if total != 3 || len(invitations) != 0 {
+			mt.Errorf("got %d invitations of %d, want 0 of 3", len(invitations), total)
+		}
+		if started := mt.GetAllStartedEvents(); len(started) != 1 {
+			mt.Errorf("sent %d commands, want only the count", len(started))
+		}
+	})
+}
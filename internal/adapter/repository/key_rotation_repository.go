@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Lyearn/mgod"
+	"github.com/Lyearn/mgod/schema/schemaopt"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type keyRotationRepository struct {
+	model mgod.EntityMongoModel[domain.KeyRotation]
+}
+
+func NewKeyRotationRepository(collectionName string) (port.KeyRotationRepository, error) {
+	opts := schemaopt.SchemaOptions{
+		Collection: collectionName,
+		Timestamps: true,
+	}
+	model, err := mgod.NewEntityMongoModel(domain.KeyRotation{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &keyRotationRepository{model: model}, nil
+}
+
+func (r *keyRotationRepository) Create(ctx context.Context, rotation *domain.KeyRotation) error {
+	_, err := r.model.InsertOne(ctx, *rotation)
+	return err
+}
+
+// FindByProjectID returns a page of rotations, newest first
+func (r *keyRotationRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.KeyRotation, int64, error) {
+	filter := bson.M{"project_id": projectID}
+
+	totalCount, err := r.model.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	if int64(offset) >= totalCount {
+		return []*domain.KeyRotation{}, totalCount, nil
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "rotated_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(int64(offset)).
+		SetLimit(int64(limit))
+
+	rotations, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*domain.KeyRotation, 0, len(rotations))
+	for i := range rotations {
+		result = append(result, &rotations[i])
+	}
+
+	return result, totalCount, nil
+}
+
+func (r *keyRotationRepository) DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
@@ -9,6 +9,8 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type nodeRepository struct {
@@ -91,18 +93,46 @@ func (r *nodeRepository) FindByDiagramIDs(ctx context.Context, diagramIDs []prim
 	return result, nil
 }
 
-func (r *nodeRepository) Update(ctx context.Context, node *domain.Node) error {
-	filter := bson.M{"_id": node.ID}
-	update := bson.D{
-		{Key: "$set", Value: bson.D{
-			{Key: "encrypted_readme", Value: node.EncryptedReadme},
-			{Key: "encrypted_readme_signature", Value: node.EncryptedReadmeSignature},
-			{Key: "encrypted_dict", Value: node.EncryptedDict},
-			{Key: "encrypted_dict_signature", Value: node.EncryptedDictSignature},
-		}},
-	}
-	_, err := r.model.UpdateMany(ctx, filter, update)
-	return err
+// UpdateContent sets only the non-nil fields in a single atomic update and
+// returns the updated node, so saving the dict cannot write back a stale
+// readme over a concurrent edit. Returns mongo.ErrNoDocuments if the node
+// does not exist.
+func (r *nodeRepository) UpdateContent(ctx context.Context, nodeID primitive.ObjectID, update domain.NodeContentUpdate) (*domain.Node, error) {
+	set := bson.D{}
+	if update.Label != nil {
+		set = append(set, bson.E{Key: "label", Value: *update.Label})
+	}
+	if update.EncryptedReadme != nil {
+		set = append(set, bson.E{Key: "encrypted_readme", Value: *update.EncryptedReadme})
+	}
+	if update.EncryptedReadmeSignature != nil {
+		set = append(set, bson.E{Key: "encrypted_readme_signature", Value: *update.EncryptedReadmeSignature})
+	}
+	if update.EncryptedDict != nil {
+		set = append(set, bson.E{Key: "encrypted_dict", Value: *update.EncryptedDict})
+	}
+	if update.EncryptedDictSignature != nil {
+		set = append(set, bson.E{Key: "encrypted_dict_signature", Value: *update.EncryptedDictSignature})
+	}
+
+	filter := bson.M{"_id": nodeID}
+	if len(set) == 0 {
+		node, err := r.model.FindOne(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if node == nil {
+			return nil, mongo.ErrNoDocuments
+		}
+		return node, nil
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	node, err := r.model.FindOneAndUpdate(ctx, filter, bson.D{{Key: "$set", Value: set}}, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &node, nil
 }
 
 func (r *nodeRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
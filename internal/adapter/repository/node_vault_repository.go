@@ -9,6 +9,7 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type nodeVaultRepository struct {
@@ -37,6 +38,31 @@ func (r *nodeVaultRepository) Create(ctx context.Context, vault *domain.NodeVaul
 	return nil
 }
 
+// CreateMany inserts all vault items or none of them. IDs are assigned up
+// front so a partially applied insert can be rolled back.
+func (r *nodeVaultRepository) CreateMany(ctx context.Context, vaults []*domain.NodeVault) error {
+	docs := make([]domain.NodeVault, len(vaults))
+	ids := make([]primitive.ObjectID, len(vaults))
+	for i, vault := range vaults {
+		if vault.ID.IsZero() {
+			vault.ID = primitive.NewObjectID()
+		}
+		docs[i] = *vault
+		ids[i] = vault.ID
+	}
+
+	inserted, err := r.model.InsertMany(ctx, docs)
+	if err != nil {
+		_, _ = r.model.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+		return err
+	}
+
+	for i := range inserted {
+		*vaults[i] = inserted[i]
+	}
+	return nil
+}
+
 func (r *nodeVaultRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.NodeVault, error) {
 	return r.model.FindOne(ctx, bson.M{"_id": id})
 }
@@ -55,6 +81,27 @@ func (r *nodeVaultRepository) FindByNodeID(ctx context.Context, nodeID primitive
 	return result, nil
 }
 
+// CountByNodeID counts the node's items within the project, so a node ID from
+// another project counts nothing
+func (r *nodeVaultRepository) CountByNodeID(ctx context.Context, projectID, nodeID primitive.ObjectID) (int64, error) {
+	return r.model.CountDocuments(ctx, bson.M{"project_id": projectID, "node_id": nodeID})
+}
+
+// FindByNodeIDAndType lists the node's items of one type. The project is part
+// of the filter so a node ID from another project matches nothing.
+func (r *nodeVaultRepository) FindByNodeIDAndType(ctx context.Context, projectID, nodeID primitive.ObjectID, vaultType string) ([]*domain.NodeVault, error) {
+	vaults, err := r.model.Find(ctx, bson.M{"project_id": projectID, "node_id": nodeID, "type": vaultType})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.NodeVault, 0, len(vaults))
+	for i := range vaults {
+		result = append(result, &vaults[i])
+	}
+	return result, nil
+}
+
 func (r *nodeVaultRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]*domain.NodeVault, error) {
 	vaults, err := r.model.Find(ctx, bson.M{"project_id": projectID})
 	if err != nil {
@@ -68,16 +115,59 @@ func (r *nodeVaultRepository) FindByProjectID(ctx context.Context, projectID pri
 	return result, nil
 }
 
+// FindRecentByProjectID returns the most recently updated vault items of a project,
+// newest first, together with the project's total vault items count
+func (r *nodeVaultRepository) FindRecentByProjectID(ctx context.Context, projectID primitive.ObjectID, limit int) ([]*domain.NodeVault, int64, error) {
+	filter := bson.M{"project_id": projectID}
+
+	total, err := r.model.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	vaults, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*domain.NodeVault, 0, len(vaults))
+	for i := range vaults {
+		result = append(result, &vaults[i])
+	}
+	return result, total, nil
+}
+
+// Update writes the vault item only if its stored version still equals
+// vault.Version, then bumps the version. It returns port.ErrVersionConflict
+// when another writer got there first.
 func (r *nodeVaultRepository) Update(ctx context.Context, vault *domain.NodeVault) error {
-	filter := bson.M{"_id": vault.ID}
+	filter := bson.M{"_id": vault.ID, "version": vault.Version}
+	if vault.Version == 0 {
+		// Items created before versioning have no version field at all
+		filter["version"] = bson.M{"$in": bson.A{0, nil}}
+	}
 	update := bson.D{
 		{Key: "$set", Value: bson.D{
+			{Key: "label", Value: vault.Label},
 			{Key: "encrypted_value", Value: vault.EncryptedValue},
 			{Key: "encrypted_value_signature", Value: vault.EncryptedValueSignature},
+			{Key: "version", Value: vault.Version + 1},
+			{Key: "updated_by", Value: vault.UpdatedBy},
 		}},
 	}
-	_, err := r.model.UpdateMany(ctx, filter, update)
-	return err
+	result, err := r.model.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return port.ErrVersionConflict
+	}
+	vault.Version++
+	return nil
 }
 
 func (r *nodeVaultRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
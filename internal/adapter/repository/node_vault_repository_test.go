@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Lyearn/mgod"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestNodeVaultRepositoryReadsLegacyItems(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("item without version", func(mt *mtest.T) {
+		mgod.SetDefaultConnection(mt.DB)
+		repo, err := NewNodeVaultRepository(mt.Coll.Name())
+		if err != nil {
+			mt.Fatal(err)
+		}
+
+		// Stored before versioning and before the audit fields existed
+		id := primitive.NewObjectID()
+		now := time.Now().Truncate(time.Millisecond)
+		legacy := bson.D{
+			{Key: "_id", Value: id},
+			{Key: "node_id", Value: primitive.NewObjectID()},
+			{Key: "project_id", Value: primitive.NewObjectID()},
+			{Key: "label", Value: "db password"},
+			{Key: "type", Value: "password"},
+			{Key: "createdAt", Value: now},
+			{Key: "updatedAt", Value: now},
+		}
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, legacy))
+
+		item, err := repo.FindByID(context.Background(), id)
+		if err != nil {
+			mt.Fatalf("FindByID: %v", err)
+		}
+		if item == nil || item.ID != id || item.Version != 0 {
+			mt.Errorf("item = %+v, want %s at version 0", item, id.Hex())
+		}
+	})
+}
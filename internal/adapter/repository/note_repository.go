@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"regexp"
 
 	"github.com/Lyearn/mgod"
 	"github.com/Lyearn/mgod/schema/schemaopt"
@@ -9,6 +10,7 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
@@ -42,6 +44,50 @@ func (r *noteRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*
 	return r.model.FindOne(ctx, bson.M{"_id": id})
 }
 
+func (r *noteRepository) FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Note, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	notes, err := r.model.Find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Note, 0, len(notes))
+	for i := range notes {
+		result = append(result, &notes[i])
+	}
+	return result, nil
+}
+
+// SearchByName finds up to limit notes of the given projects whose name
+// contains query, sorted by name. The query is matched literally. A $text
+// index only matches whole words, so a substring search needs the
+// unanchored regex, which scans the notes of the given projects.
+func (r *noteRepository) SearchByName(ctx context.Context, projectIDs []primitive.ObjectID, query string, limit int) ([]*domain.Note, error) {
+	if len(projectIDs) == 0 {
+		return nil, nil
+	}
+	filter := bson.M{
+		"project_id": bson.M{"$in": projectIDs},
+		"file_name":  bson.M{"$regex": primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "file_name", Value: 1}}).
+		SetCollation(&options.Collation{Locale: "en", Strength: 1}).
+		SetLimit(int64(limit))
+	notes, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Note, 0, len(notes))
+	for i := range notes {
+		result = append(result, &notes[i])
+	}
+	return result, nil
+}
+
 func (r *noteRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]*domain.Note, error) {
 	filter := bson.M{"project_id": projectID}
 
@@ -63,20 +109,119 @@ func (r *noteRepository) FindByProjectID(ctx context.Context, projectID primitiv
 	return result, nil
 }
 
-func (r *noteRepository) Update(ctx context.Context, note *domain.Note) error {
-	filter := bson.M{"_id": note.ID}
-	update := bson.D{
-		{Key: "$set", Value: bson.D{
-			{Key: "file_name", Value: note.FileName},
-			{Key: "type", Value: note.Type},
-			{Key: "parent_id", Value: note.ParentID},
-			{Key: "icon", Value: note.Icon},
-			{Key: "encrypted_content", Value: note.EncryptedContent},
-			{Key: "encrypted_content_signature", Value: note.EncryptedContentSignature},
-		}},
-	}
-	_, err := r.model.UpdateMany(ctx, filter, update)
-	return err
+func (r *noteRepository) CountByProjectID(ctx context.Context, projectID primitive.ObjectID) (int64, error) {
+	return r.model.CountDocuments(ctx, bson.M{"project_id": projectID})
+}
+
+func (r *noteRepository) FindByProjectIDAfter(ctx context.Context, projectID, afterID primitive.ObjectID, limit int) ([]*domain.Note, error) {
+	filter := bson.M{"project_id": projectID}
+	if !afterID.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	// Sort by _id so the cursor stays stable under concurrent inserts
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+
+	notes, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.Note, 0, len(notes))
+	for i := range notes {
+		result = append(result, &notes[i])
+	}
+	return result, nil
+}
+
+// FindRecentByProjectID returns the most recently updated notes of a project,
+// newest first, together with the project's total notes count
+func (r *noteRepository) FindRecentByProjectID(ctx context.Context, projectID primitive.ObjectID, limit int) ([]*domain.Note, int64, error) {
+	filter := bson.M{"project_id": projectID}
+
+	total, err := r.model.CountDocuments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}}).
+		SetLimit(int64(limit))
+
+	notes, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]*domain.Note, 0, len(notes))
+	for i := range notes {
+		result = append(result, &notes[i])
+	}
+	return result, total, nil
+}
+
+// UpdateFields sets only the fields present in update in a single atomic
+// update and returns the updated note, so renaming or moving a note cannot
+// write back stale content over a concurrent edit. Returns
+// mongo.ErrNoDocuments if the note does not exist.
+func (r *noteRepository) UpdateFields(ctx context.Context, noteID primitive.ObjectID, update domain.NoteUpdate) (*domain.Note, error) {
+	changes := noteUpdateChanges(update)
+	filter := bson.M{"_id": noteID}
+	if len(changes) == 0 {
+		note, err := r.model.FindOne(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if note == nil {
+			return nil, mongo.ErrNoDocuments
+		}
+		return note, nil
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	note, err := r.model.FindOneAndUpdate(ctx, filter, changes, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+// noteUpdateChanges builds the update document for UpdateFields. Moving a
+// note to the top level unsets parent_id rather than storing a nil ID.
+func noteUpdateChanges(update domain.NoteUpdate) bson.D {
+	set := bson.D{}
+	unset := bson.D{}
+	if update.FileName != nil {
+		set = append(set, bson.E{Key: "file_name", Value: *update.FileName})
+	}
+	if update.ParentID != nil {
+		if *update.ParentID == primitive.NilObjectID {
+			unset = append(unset, bson.E{Key: "parent_id", Value: ""})
+		} else {
+			set = append(set, bson.E{Key: "parent_id", Value: *update.ParentID})
+		}
+	}
+	if update.Icon != nil {
+		set = append(set, bson.E{Key: "icon", Value: *update.Icon})
+	}
+	if update.EncryptedContent != nil {
+		set = append(set, bson.E{Key: "encrypted_content", Value: *update.EncryptedContent})
+	}
+	if update.EncryptedContentSignature != nil {
+		set = append(set, bson.E{Key: "encrypted_content_signature", Value: *update.EncryptedContentSignature})
+	}
+	if (len(set) > 0 || len(unset) > 0) && !update.UpdatedBy.IsZero() {
+		set = append(set, bson.E{Key: "updated_by", Value: update.UpdatedBy})
+	}
+
+	changes := bson.D{}
+	if len(set) > 0 {
+		changes = append(changes, bson.E{Key: "$set", Value: set})
+	}
+	if len(unset) > 0 {
+		changes = append(changes, bson.E{Key: "$unset", Value: unset})
+	}
+	return changes
 }
 
 func (r *noteRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
@@ -84,6 +229,14 @@ func (r *noteRepository) Delete(ctx context.Context, id primitive.ObjectID) erro
 	return err
 }
 
+func (r *noteRepository) DeleteMany(ctx context.Context, ids []primitive.ObjectID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := r.model.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	return err
+}
+
 func (r *noteRepository) DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error {
 	_, err := r.model.DeleteMany(ctx, bson.M{"project_id": projectID})
 	return err
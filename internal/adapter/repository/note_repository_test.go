@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// bsonNoteStore keeps notes as BSON documents and applies UpdateFields with
+// the update document the real repository sends, so what a test reads back
+// is what Mongo would have stored
+type bsonNoteStore struct {
+	port.NoteRepository
+	t    *testing.T
+	docs map[primitive.ObjectID]bson.M
+}
+
+func (s *bsonNoteStore) put(note *domain.Note) {
+	raw, err := bson.Marshal(note)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+	var doc bson.M
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		s.t.Fatal(err)
+	}
+	s.docs[note.ID] = doc
+}
+
+func (s *bsonNoteStore) FindByID(_ context.Context, id primitive.ObjectID) (*domain.Note, error) {
+	doc, ok := s.docs[id]
+	if !ok {
+		return nil, nil
+	}
+	raw, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, err
+	}
+	var note domain.Note
+	if err := bson.Unmarshal(raw, &note); err != nil {
+		return nil, err
+	}
+	return &note, nil
+}
+
+func (s *bsonNoteStore) UpdateFields(ctx context.Context, noteID primitive.ObjectID, update domain.NoteUpdate) (*domain.Note, error) {
+	doc := s.docs[noteID]
+	for _, change := range noteUpdateChanges(update) {
+		for _, field := range change.Value.(bson.D) {
+			switch change.Key {
+			case "$set":
+				doc[field.Key] = field.Value
+			case "$unset":
+				delete(doc, field.Key)
+			default:
+				s.t.Fatalf("unexpected operator %s", change.Key)
+			}
+		}
+	}
+	return s.FindByID(ctx, noteID)
+}
+
+type ownerMemberRepo struct {
+	port.ProjectMemberRepository
+	projectID, userID primitive.ObjectID
+}
+
+func (r ownerMemberRepo) FindByProjectAndUser(_ context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
+	if projectID != r.projectID || userID != r.userID {
+		return nil, nil
+	}
+	return &domain.ProjectMember{ProjectID: projectID, UserID: userID, Role: domain.RoleOwner}, nil
+}
+
+type nopPublisher struct{}
+
+func (nopPublisher) Publish(event.Event) {}
+
+func TestUpdateNotePersistsParentAndIcon(t *testing.T) {
+	ctx := context.Background()
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	folder := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: domain.NoteTypeFolder, FileName: "Runbooks"}
+	note := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: domain.NoteTypeNote, FileName: "Failover", Icon: "📄"}
+
+	store := &bsonNoteStore{t: t, docs: make(map[primitive.ObjectID]bson.M)}
+	store.put(folder)
+	store.put(note)
+	authz := service.NewAuthorizationService(ownerMemberRepo{projectID: projectID, userID: userID})
+	notes := service.NewNoteService(store, authz, nil, service.PayloadLimits{}, nopPublisher{})
+
+	// Move into the folder and change the icon
+	parent, icon := folder.ID.Hex(), "🔥"
+	if _, err := notes.UpdateNote(ctx, note.ID, userID, nil, &parent, &icon, nil, nil); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	stored, _ := store.FindByID(ctx, note.ID)
+	if stored.ParentID == nil || *stored.ParentID != folder.ID {
+		t.Errorf("parent_id = %v, want %s", stored.ParentID, folder.ID.Hex())
+	}
+	if stored.Icon != icon || stored.FileName != "Failover" || stored.UpdatedBy != userID {
+		t.Errorf("stored note = %+v, want the new icon with other fields kept", stored)
+	}
+
+	// An update without them leaves both alone
+	name := "Failover plan"
+	if _, err := notes.UpdateNote(ctx, note.ID, userID, &name, nil, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	stored, _ = store.FindByID(ctx, note.ID)
+	if stored.ParentID == nil || stored.Icon != icon || stored.FileName != name {
+		t.Errorf("stored note = %+v, want parent and icon kept after a rename", stored)
+	}
+
+	// An empty parent moves the note back to the top level
+	root := ""
+	if _, err := notes.UpdateNote(ctx, note.ID, userID, nil, &root, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateNote: %v", err)
+	}
+	stored, _ = store.FindByID(ctx, note.ID)
+	if stored.ParentID != nil {
+		t.Errorf("parent_id = %v after moving to the top level, want unset", stored.ParentID)
+	}
+	if _, ok := store.docs[note.ID]["parent_id"]; ok {
+		t.Error("parent_id is still in the stored document")
+	}
+
+	// A parent that does not exist is refused without changing the note
+	missing := primitive.NewObjectID().Hex()
+	if _, err := notes.UpdateNote(ctx, note.ID, userID, nil, &missing, nil, nil, nil); err == nil {
+		t.Error("UpdateNote accepted a parent that does not exist")
+	}
+	if stored, _ = store.FindByID(ctx, note.ID); stored.ParentID != nil {
+		t.Errorf("parent_id = %v after a rejected move, want unset", stored.ParentID)
+	}
+}
+
+func TestNoteUpdateChangesRecordsEditor(t *testing.T) {
+	editor := primitive.NewObjectID()
+	name := "Failover plan"
+
+	changes := noteUpdateChanges(domain.NoteUpdate{FileName: &name, UpdatedBy: editor})
+	set, ok := changes.Map()["$set"].(bson.D)
+	if !ok || set.Map()["updated_by"] != editor || set.Map()["file_name"] != name {
+		t.Errorf("changes = %v, want file_name and updated_by set", changes)
+	}
+
+	// Nothing to change leaves the note, and its last editor, as they are
+	if changes := noteUpdateChanges(domain.NoteUpdate{UpdatedBy: editor}); len(changes) != 0 {
+		t.Errorf("changes = %v, want none", changes)
+	}
+}
@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Lyearn/mgod"
+	"github.com/Lyearn/mgod/schema/schemaopt"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type personalAccessTokenRepository struct {
+	model mgod.EntityMongoModel[domain.PersonalAccessToken]
+}
+
+func NewPersonalAccessTokenRepository(collectionName string) (port.PersonalAccessTokenRepository, error) {
+	opts := schemaopt.SchemaOptions{
+		Collection: collectionName,
+		Timestamps: true,
+	}
+	model, err := mgod.NewEntityMongoModel(domain.PersonalAccessToken{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &personalAccessTokenRepository{model: model}, nil
+}
+
+func (r *personalAccessTokenRepository) Create(ctx context.Context, token *domain.PersonalAccessToken) error {
+	result, err := r.model.InsertOne(ctx, *token)
+	if err != nil {
+		return err
+	}
+	token.ID = result.ID
+	return nil
+}
+
+// FindByHash returns the token with the given hash, or nil if there is none
+func (r *personalAccessTokenRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error) {
+	token, err := r.model.FindOne(ctx, bson.M{"token_hash": tokenHash})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+// FindByUserID lists the user's tokens, newest first
+func (r *personalAccessTokenRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.PersonalAccessToken, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	tokens, err := r.model.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.PersonalAccessToken, 0, len(tokens))
+	for i := range tokens {
+		result = append(result, &tokens[i])
+	}
+	return result, nil
+}
+
+// TouchLastUsed records when the token was last used
+func (r *personalAccessTokenRepository) TouchLastUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error {
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "last_used_at", Value: usedAt},
+		}},
+	}
+	_, err := r.model.UpdateMany(ctx, bson.M{"_id": id}, update)
+	return err
+}
+
+// Delete removes the token if it belongs to userID and reports whether it did
+func (r *personalAccessTokenRepository) Delete(ctx context.Context, id, userID primitive.ObjectID) (bool, error) {
+	result, err := r.model.DeleteMany(ctx, bson.M{"_id": id, "user_id": userID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
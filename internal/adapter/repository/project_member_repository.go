@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 
 	"github.com/Lyearn/mgod"
 	"github.com/Lyearn/mgod/schema/schemaopt"
@@ -9,6 +10,8 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type projectMemberRepository struct {
@@ -28,9 +31,14 @@ func NewProjectMemberRepository(collectionName string) (port.ProjectMemberReposi
 	return &projectMemberRepository{model: model}, nil
 }
 
+// Create inserts the member and fills in its ID and timestamps
 func (r *projectMemberRepository) Create(ctx context.Context, member *domain.ProjectMember) error {
-	_, err := r.model.InsertOne(ctx, *member)
-	return err
+	created, err := r.model.InsertOne(ctx, *member)
+	if err != nil {
+		return err
+	}
+	*member = created
+	return nil
 }
 
 func (r *projectMemberRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.ProjectMember, int64, error) {
@@ -65,27 +73,189 @@ func (r *projectMemberRepository) FindByProjectID(ctx context.Context, projectID
 	return result, totalCount, nil
 }
 
+func (r *projectMemberRepository) FindByProjectIDAfter(ctx context.Context, projectID, afterID primitive.ObjectID, limit int) ([]*domain.ProjectMember, error) {
+	filter := bson.M{"project_id": projectID}
+	if !afterID.IsZero() {
+		filter["_id"] = bson.M{"$gt": afterID}
+	}
+
+	// Sort by _id so the cursor stays stable under concurrent inserts
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+
+	members, err := r.model.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.ProjectMember, 0, len(members))
+	for i := range members {
+		result = append(result, &members[i])
+	}
+	return result, nil
+}
+
+// FindByProjectAndUser returns the membership unless the project is in the
+// recycle bin, so every authorization check treats a deleted project as gone
 func (r *projectMemberRepository) FindByProjectAndUser(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
 	return r.model.FindOne(ctx, bson.M{
-		"project_id": projectID,
-		"user_id":    userID,
+		"project_id":      projectID,
+		"user_id":         userID,
+		"project_deleted": bson.M{"$ne": true},
 	})
 }
 
-func (r *projectMemberRepository) Update(ctx context.Context, member *domain.ProjectMember) error {
+// FindUserIDsByProjectID returns the IDs of every member of the project
+func (r *projectMemberRepository) FindUserIDsByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]primitive.ObjectID, error) {
+	members, err := r.model.Find(ctx, bson.M{"project_id": projectID})
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]primitive.ObjectID, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	return userIDs, nil
+}
+
+// FindInDeletedProject returns the membership only while the project is in
+// the recycle bin
+func (r *projectMemberRepository) FindInDeletedProject(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
+	return r.model.FindOne(ctx, bson.M{
+		"project_id":      projectID,
+		"user_id":         userID,
+		"project_deleted": true,
+	})
+}
+
+// FindByUserID returns every membership of the user, leaving out projects in
+// the recycle bin
+func (r *projectMemberRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.ProjectMember, error) {
+	members, err := r.model.Find(ctx, bson.M{
+		"user_id":         userID,
+		"project_deleted": bson.M{"$ne": true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.ProjectMember, 0, len(members))
+	for i := range members {
+		result = append(result, &members[i])
+	}
+	return result, nil
+}
+
+// SetProjectDeleted flags or unflags every membership of the project
+func (r *projectMemberRepository) SetProjectDeleted(ctx context.Context, projectID primitive.ObjectID, deleted bool) error {
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "project_deleted", Value: ""}}}}
+	if deleted {
+		update = bson.D{{Key: "$set", Value: bson.D{{Key: "project_deleted", Value: true}}}}
+	}
+	_, err := r.model.UpdateMany(ctx, bson.M{"project_id": projectID}, update)
+	return err
+}
+
+// SetFavorite marks or unmarks the project as one of the user's favorites and
+// reports whether the user is a member
+func (r *projectMemberRepository) SetFavorite(ctx context.Context, projectID, userID primitive.ObjectID, favorite bool) (bool, error) {
+	update := bson.D{{Key: "$unset", Value: bson.D{{Key: "favorite", Value: ""}}}}
+	if favorite {
+		update = bson.D{{Key: "$set", Value: bson.D{{Key: "favorite", Value: true}}}}
+	}
+	result, err := r.model.UpdateMany(ctx, bson.M{
+		"project_id":      projectID,
+		"user_id":         userID,
+		"project_deleted": bson.M{"$ne": true},
+	}, update)
+	if err != nil {
+		return false, err
+	}
+	return result.MatchedCount > 0, nil
+}
+
+// CountByUserAndRole counts the user's memberships holding the given role,
+// ignoring projects in the recycle bin
+func (r *projectMemberRepository) CountByUserAndRole(ctx context.Context, userID primitive.ObjectID, role string) (int64, error) {
+	return r.model.CountDocuments(ctx, bson.M{
+		"user_id":         userID,
+		"role":            role,
+		"project_deleted": bson.M{"$ne": true},
+	})
+}
+
+// CountByCustomRole counts the project's members holding the custom role
+func (r *projectMemberRepository) CountByCustomRole(ctx context.Context, projectID primitive.ObjectID, name string) (int64, error) {
+	return r.model.CountDocuments(ctx, bson.M{
+		"project_id":  projectID,
+		"custom_role": name,
+	})
+}
+
+// ApplyCustomRole moves the project's members holding the custom role called
+// name onto role's current name and permissions. It returns the user IDs of
+// the members it changed.
+func (r *projectMemberRepository) ApplyCustomRole(ctx context.Context, projectID primitive.ObjectID, name string, role *domain.ProjectRole) ([]primitive.ObjectID, error) {
 	filter := bson.M{
-		"project_id": member.ProjectID,
-		"user_id":    member.UserID,
+		"project_id":  projectID,
+		"custom_role": name,
+	}
+	members, err := r.model.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return nil, nil
 	}
+
 	update := bson.D{
 		{Key: "$set", Value: bson.D{
-			{Key: "permissions", Value: member.Permissions},
-			{Key: "role", Value: member.Role},
-			{Key: "keyrings", Value: member.Keyrings},
+			{Key: "role", Value: domain.RoleCustom},
+			{Key: "custom_role", Value: role.Name},
+			{Key: "permissions", Value: role.Permissions},
 		}},
 	}
-	_, err := r.model.UpdateMany(ctx, filter, update)
-	return err
+	if _, err := r.model.UpdateMany(ctx, filter, update); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]primitive.ObjectID, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	return userIDs, nil
+}
+
+func (r *projectMemberRepository) Update(ctx context.Context, member *domain.ProjectMember) error {
+	filter := bson.M{
+		"project_id": member.ProjectID,
+		"user_id":    member.UserID,
+	}
+	set := bson.D{
+		{Key: "permissions", Value: member.Permissions},
+		{Key: "role", Value: member.Role},
+		{Key: "keyrings", Value: member.Keyrings},
+	}
+	if member.CustomRole != "" {
+		set = append(set, bson.E{Key: "custom_role", Value: member.CustomRole})
+	}
+	update := bson.D{{Key: "$set", Value: set}}
+	if member.CustomRole == "" {
+		update = append(update, bson.E{Key: "$unset", Value: bson.D{{Key: "custom_role", Value: ""}}})
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	// Refresh the member so UpdatedAt reflects the write; a member removed
+	// in the meantime is left alone
+	updated, err := r.model.FindOneAndUpdate(ctx, filter, update, opts)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil
+		}
+		return err
+	}
+	*member = updated
+	return nil
 }
 
 func (r *projectMemberRepository) Delete(ctx context.Context, projectID, userID primitive.ObjectID) error {
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lyearn/mgod"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestProjectMemberRepositorySetFavorite(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tests := []struct {
+		name     string
+		favorite bool
+		matched  int32
+		wantOp   string
+	}{
+		{name: "favorite", favorite: true, matched: 1, wantOp: "$set"},
+		{name: "unfavorite", favorite: false, matched: 1, wantOp: "$unset"},
+		{name: "not a member", favorite: true, matched: 0, wantOp: "$set"},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			mgod.SetDefaultConnection(mt.DB)
+			repo, err := NewProjectMemberRepository(mt.Coll.Name())
+			if err != nil {
+				mt.Fatal(err)
+			}
+			projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+			mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: tt.matched}))
+
+			isMember, err := repo.SetFavorite(context.Background(), projectID, userID, tt.favorite)
+			if err != nil {
+				mt.Fatal(err)
+			}
+			if isMember != (tt.matched > 0) {
+				mt.Errorf("isMember = %v with %d matched", isMember, tt.matched)
+			}
+
+			// Only the caller's own membership is changed
+			update := mt.GetStartedEvent().Command.Lookup("updates", "0").Document()
+			if got := update.Lookup("q", "user_id").ObjectID(); got != userID {
+				mt.Errorf("user_id = %s, want %s", got.Hex(), userID.Hex())
+			}
+			if got := update.Lookup("q", "project_id").ObjectID(); got != projectID {
+				mt.Errorf("project_id = %s, want %s", got.Hex(), projectID.Hex())
+			}
+			if _, err := update.LookupErr("u", tt.wantOp, "favorite"); err != nil {
+				mt.Errorf("update = %v, want %s favorite", update.Lookup("u"), tt.wantOp)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/Lyearn/mgod"
 	"github.com/Lyearn/mgod/schema/schemaopt"
@@ -9,6 +11,8 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type projectRepository struct {
@@ -37,76 +41,466 @@ func (r *projectRepository) Create(ctx context.Context, project *domain.Project)
 	return nil
 }
 
+// FindByID returns the project, or nil if it does not exist or is in the
+// recycle bin
 func (r *projectRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Project, error) {
-	return r.model.FindOne(ctx, bson.M{"_id": id})
+	return r.model.FindOne(ctx, bson.M{
+		"_id":        id,
+		"deleted_at": bson.M{"$exists": false},
+	})
 }
 
-func (r *projectRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID, offset, limit int) ([]*domain.Project, int64, error) {
-	// First, get all project IDs that the user is a member of
-	memberOpts := schemaopt.SchemaOptions{
-		Collection: "project_members",
-		Timestamps: false,
+// FindActiveByIDs returns the projects among ids that are not in the recycle
+// bin
+func (r *projectRepository) FindActiveByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Project, error) {
+	if len(ids) == 0 {
+		return nil, nil
 	}
-	memberModel, err := mgod.NewEntityMongoModel(domain.ProjectMember{}, memberOpts)
+	projects, err := r.model.Find(ctx, bson.M{
+		"_id":        bson.M{"$in": ids},
+		"deleted_at": bson.M{"$exists": false},
+	})
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
 
-	members, err := memberModel.Find(ctx, bson.M{"user_id": userID})
+	result := make([]*domain.Project, 0, len(projects))
+	for i := range projects {
+		result = append(result, &projects[i])
+	}
+	return result, nil
+}
+
+// FindByUserID pages through the user's projects together with the user's
+// role in each. query.Role narrows the list to owned projects or to projects
+// shared with the user; any other value returns all of them. The user's
+// favorites come first, then the rest; each group is ordered by query.Sort in
+// the projects query. A non-empty query.Tag keeps only projects carrying it,
+// and archived projects are left out unless query.IncludeArchived is set.
+func (r *projectRepository) FindByUserID(ctx context.Context, userID primitive.ObjectID, query domain.ProjectListQuery, offset, limit int) ([]*domain.MemberProject, int64, error) {
+	// First, get all memberships of the user. Memberships of deleted projects
+	// are flagged so they are not fetched at all.
+	memberFilter := bson.M{
+		"user_id":         userID,
+		"project_deleted": bson.M{"$ne": true},
+	}
+	switch query.Role {
+	case domain.ProjectRoleFilterOwner:
+		memberFilter["role"] = domain.RoleOwner
+	case domain.ProjectRoleFilterMember:
+		memberFilter["role"] = bson.M{"$ne": domain.RoleOwner}
+	}
+	if query.FavoritesOnly {
+		memberFilter["favorite"] = true
+	}
+
+	members, err := r.findMembers(ctx, memberFilter)
 	if err != nil {
 		return nil, 0, err
 	}
 
 	if len(members) == 0 {
-		return []*domain.Project{}, 0, nil
+		return []*domain.MemberProject{}, 0, nil
+	}
+
+	byProject := make(map[primitive.ObjectID]domain.ProjectMember, len(members))
+	var favoriteIDs, otherIDs []primitive.ObjectID
+	for _, member := range members {
+		byProject[member.ProjectID] = member
+		if member.Favorite {
+			favoriteIDs = append(favoriteIDs, member.ProjectID)
+		} else {
+			otherIDs = append(otherIDs, member.ProjectID)
+		}
+	}
+
+	filter := bson.M{
+		"deleted_at": bson.M{"$exists": false},
+	}
+	if query.Tag != "" {
+		filter["tags"] = query.Tag
+	}
+	if !query.IncludeArchived {
+		filter["archived_at"] = bson.M{"$exists": false}
+	}
+
+	// Page across both groups: favorites fill the first pages, and the
+	// other projects continue where they end
+	var result []*domain.MemberProject
+	var totalCount int64
+	skip := int64(offset)
+	remaining := int64(limit)
+	for _, ids := range [][]primitive.ObjectID{favoriteIDs, otherIDs} {
+		if len(ids) == 0 {
+			continue
+		}
+		groupFilter := bson.M{"_id": bson.M{"$in": ids}}
+		for key, value := range filter {
+			groupFilter[key] = value
+		}
+
+		count, err := r.model.CountDocuments(ctx, groupFilter)
+		if err != nil {
+			return nil, 0, err
+		}
+		totalCount += count
+
+		if skip >= count {
+			skip -= count
+			continue
+		}
+		if remaining == 0 {
+			continue
+		}
+
+		projects, err := r.findProjectsPage(ctx, groupFilter, query.Sort, skip, remaining)
+		if err != nil {
+			return nil, 0, err
+		}
+		skip = 0
+		remaining -= int64(len(projects))
+
+		// Pair each project with the user's membership
+		for i := range projects {
+			member := byProject[projects[i].ID]
+			result = append(result, &domain.MemberProject{
+				Project:  &projects[i],
+				Role:     member.Role,
+				Favorite: member.Favorite,
+			})
+		}
+	}
+
+	if result == nil {
+		result = []*domain.MemberProject{}
+	}
+	return result, totalCount, nil
+}
+
+// findProjectsPage sorts and pages projects in the query; _id breaks ties so
+// pages never overlap
+func (r *projectRepository) findProjectsPage(ctx context.Context, filter bson.M, sortOrder string, skip, limit int64) ([]domain.Project, error) {
+	opts := options.Find().SetSkip(skip).SetLimit(limit)
+	switch sortOrder {
+	case domain.ProjectSortName:
+		opts.SetSort(bson.D{{Key: "name", Value: 1}, {Key: "_id", Value: 1}}).
+			SetCollation(&options.Collation{Locale: "en", Strength: 1})
+	case domain.ProjectSortCreated:
+		opts.SetSort(bson.D{{Key: "createdAt", Value: -1}, {Key: "_id", Value: -1}})
+	default:
+		opts.SetSort(bson.D{{Key: "updatedAt", Value: -1}, {Key: "_id", Value: -1}})
+	}
+
+	return r.model.Find(ctx, filter, opts)
+}
+
+// FindDeletedByUserID returns the user's projects that are in the recycle
+// bin together with the user's role in each, soonest purge first
+func (r *projectRepository) FindDeletedByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.MemberProject, error) {
+	members, err := r.findMembers(ctx, bson.M{
+		"user_id":         userID,
+		"project_deleted": true,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	// Extract project IDs
+	if len(members) == 0 {
+		return []*domain.MemberProject{}, nil
+	}
+
+	roles := make(map[primitive.ObjectID]string, len(members))
 	projectIDs := make([]primitive.ObjectID, 0, len(members))
 	for _, member := range members {
+		roles[member.ProjectID] = member.Role
 		projectIDs = append(projectIDs, member.ProjectID)
 	}
 
-	// Get total count
-	totalCount := int64(len(projectIDs))
+	opts := options.Find().SetSort(bson.D{{Key: "purge_at", Value: 1}})
+	projects, err := r.model.Find(ctx, bson.M{
+		"_id":        bson.M{"$in": projectIDs},
+		"deleted_at": bson.M{"$exists": true},
+	}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.MemberProject, 0, len(projects))
+	for i := range projects {
+		result = append(result, &domain.MemberProject{
+			Project: &projects[i],
+			Role:    roles[projects[i].ID],
+		})
+	}
+	return result, nil
+}
+
+// findMembers returns the memberships matching filter
+func (r *projectRepository) findMembers(ctx context.Context, filter bson.M) ([]domain.ProjectMember, error) {
+	memberOpts := schemaopt.SchemaOptions{
+		Collection: "project_members",
+		Timestamps: false,
+	}
+	memberModel, err := mgod.NewEntityMongoModel(domain.ProjectMember{}, memberOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	return memberModel.Find(ctx, filter)
+}
 
-	// Apply pagination to project IDs
-	startIdx := offset
-	endIdx := offset + limit
-	if startIdx >= len(projectIDs) {
-		return []*domain.Project{}, totalCount, nil
+// Touch bumps the project's updatedAt so it sorts as recently active
+func (r *projectRepository) Touch(ctx context.Context, projectID primitive.ObjectID) error {
+	// The model adds the $currentDate for updatedAt itself
+	_, err := r.model.UpdateMany(ctx, bson.M{
+		"_id":        projectID,
+		"deleted_at": bson.M{"$exists": false},
+	}, bson.D{})
+	return err
+}
+
+// UpdateMetadata sets only the non-nil fields in a single atomic update and
+// returns the updated project. Nothing is read and written back, so it can
+// neither undo a concurrent key rotation nor another user's edit of the
+// other fields. Returns mongo.ErrNoDocuments if the project does not exist.
+func (r *projectRepository) UpdateMetadata(ctx context.Context, projectID primitive.ObjectID, name, description *string, tags []string) (*domain.Project, error) {
+	set := bson.D{}
+	if name != nil {
+		set = append(set, bson.E{Key: "name", Value: *name})
+	}
+	if description != nil {
+		set = append(set, bson.E{Key: "description", Value: *description})
 	}
-	if endIdx > len(projectIDs) {
-		endIdx = len(projectIDs)
+	if tags != nil {
+		set = append(set, bson.E{Key: "tags", Value: tags})
 	}
-	paginatedIDs := projectIDs[startIdx:endIdx]
 
-	// Find projects with paginated IDs
-	projects, err := r.model.Find(ctx, bson.M{"_id": bson.M{"$in": paginatedIDs}})
+	filter := bson.M{"_id": projectID}
+	if len(set) == 0 {
+		project, err := r.model.FindOne(ctx, filter)
+		if err != nil {
+			return nil, err
+		}
+		if project == nil {
+			return nil, mongo.ErrNoDocuments
+		}
+		return project, nil
+	}
+
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	project, err := r.model.FindOneAndUpdate(ctx, filter, bson.D{{Key: "$set", Value: set}}, opts)
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
+	return &project, nil
+}
 
-	// Convert to pointers
-	result := make([]*domain.Project, 0, len(projects))
-	for i := range projects {
-		result = append(result, &projects[i])
+// UpdateKeyEpoch writes the project's key epoch only
+func (r *projectRepository) UpdateKeyEpoch(ctx context.Context, projectID primitive.ObjectID, keyEpoch string) error {
+	filter := bson.M{"_id": projectID}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "key_epoch", Value: keyEpoch},
+		}},
+	}
+	result, err := r.model.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
 	}
+	return nil
+}
 
-	return result, totalCount, nil
+// Archive marks a project that is not archived yet as archived at archivedAt.
+// Returns mongo.ErrNoDocuments if the project does not exist, is in the
+// recycle bin or is archived already.
+func (r *projectRepository) Archive(ctx context.Context, projectID primitive.ObjectID, archivedAt time.Time) (*domain.Project, error) {
+	filter := bson.M{
+		"_id":         projectID,
+		"deleted_at":  bson.M{"$exists": false},
+		"archived_at": bson.M{"$exists": false},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "archived_at", Value: archivedAt},
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	project, err := r.model.FindOneAndUpdate(ctx, filter, update, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
 }
 
-func (r *projectRepository) Update(ctx context.Context, project *domain.Project) error {
-	filter := bson.M{"_id": project.ID}
+// Unarchive clears the project's archived state. Returns
+// mongo.ErrNoDocuments if the project does not exist or is in the recycle
+// bin.
+func (r *projectRepository) Unarchive(ctx context.Context, projectID primitive.ObjectID) (*domain.Project, error) {
+	filter := bson.M{
+		"_id":        projectID,
+		"deleted_at": bson.M{"$exists": false},
+	}
+	update := bson.D{
+		{Key: "$unset", Value: bson.D{
+			{Key: "archived_at", Value: ""},
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	project, err := r.model.FindOneAndUpdate(ctx, filter, update, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// MarkForDeletion records a deletion request unless one is already pending
+// and returns the updated project. Returns mongo.ErrNoDocuments if the
+// project does not exist or already has a pending request.
+func (r *projectRepository) MarkForDeletion(ctx context.Context, projectID, requestedBy primitive.ObjectID, requestedAt, scheduledAt time.Time) (*domain.Project, error) {
+	filter := bson.M{
+		"_id":                   projectID,
+		"deletion_scheduled_at": bson.M{"$exists": false},
+		"deleted_at":            bson.M{"$exists": false},
+	}
 	update := bson.D{
 		{Key: "$set", Value: bson.D{
-			{Key: "name", Value: project.Name},
-			{Key: "description", Value: project.Description},
-			{Key: "key_epoch", Value: project.KeyEpoch},
+			{Key: "deletion_requested_by", Value: requestedBy},
+			{Key: "deletion_requested_at", Value: requestedAt},
+			{Key: "deletion_scheduled_at", Value: scheduledAt},
 		}},
 	}
-	_, err := r.model.UpdateMany(ctx, filter, update)
-	return err
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	project, err := r.model.FindOneAndUpdate(ctx, filter, update, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ClearDeletion removes a pending deletion request and reports whether
+// there was one to remove
+func (r *projectRepository) ClearDeletion(ctx context.Context, projectID primitive.ObjectID) (bool, error) {
+	filter := bson.M{
+		"_id":                   projectID,
+		"deletion_scheduled_at": bson.M{"$exists": true},
+	}
+	update := bson.D{
+		{Key: "$unset", Value: bson.D{
+			{Key: "deletion_requested_by", Value: ""},
+			{Key: "deletion_requested_at", Value: ""},
+			{Key: "deletion_scheduled_at", Value: ""},
+		}},
+	}
+	result, err := r.model.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return false, err
+	}
+	return result.ModifiedCount > 0, nil
+}
+
+// ClaimDueDeletion atomically picks one project whose grace period has run
+// out and pushes its deletion time out by lease, so concurrent schedulers do
+// not purge the same project. It returns nil when nothing is due.
+func (r *projectRepository) ClaimDueDeletion(ctx context.Context, now time.Time, lease time.Duration) (*domain.Project, error) {
+	filter := bson.M{
+		"deletion_scheduled_at": bson.M{"$lte": now},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "deletion_scheduled_at", Value: now.Add(lease)},
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "deletion_scheduled_at", Value: 1}})
+
+	project, err := r.model.FindOneAndUpdate(ctx, filter, update, opts)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
+}
+
+// SoftDelete moves the project to the recycle bin until purgeAt. Any pending
+// deletion request is dropped since it has now been carried out.
+func (r *projectRepository) SoftDelete(ctx context.Context, projectID primitive.ObjectID, deletedAt, purgeAt time.Time) error {
+	filter := bson.M{
+		"_id":        projectID,
+		"deleted_at": bson.M{"$exists": false},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "deleted_at", Value: deletedAt},
+			{Key: "purge_at", Value: purgeAt},
+		}},
+		{Key: "$unset", Value: bson.D{
+			{Key: "deletion_requested_by", Value: ""},
+			{Key: "deletion_requested_at", Value: ""},
+			{Key: "deletion_scheduled_at", Value: ""},
+		}},
+	}
+	result, err := r.model.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
+}
+
+// Restore takes the project out of the recycle bin and returns it. Returns
+// mongo.ErrNoDocuments if the project is not in the recycle bin.
+func (r *projectRepository) Restore(ctx context.Context, projectID primitive.ObjectID) (*domain.Project, error) {
+	filter := bson.M{
+		"_id":        projectID,
+		"deleted_at": bson.M{"$exists": true},
+	}
+	update := bson.D{
+		{Key: "$unset", Value: bson.D{
+			{Key: "deleted_at", Value: ""},
+			{Key: "purge_at", Value: ""},
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+
+	project, err := r.model.FindOneAndUpdate(ctx, filter, update, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+// ClaimDuePurge atomically picks one deleted project whose retention has run
+// out and pushes its purge time out by lease, so concurrent schedulers do
+// not purge the same project. It returns nil when nothing is due.
+func (r *projectRepository) ClaimDuePurge(ctx context.Context, now time.Time, lease time.Duration) (*domain.Project, error) {
+	filter := bson.M{
+		"deleted_at": bson.M{"$exists": true},
+		"purge_at":   bson.M{"$lte": now},
+	}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "purge_at", Value: now.Add(lease)},
+		}},
+	}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "purge_at", Value: 1}})
+
+	project, err := r.model.FindOneAndUpdate(ctx, filter, update, opts)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &project, nil
 }
 
 func (r *projectRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
@@ -0,0 +1,430 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Lyearn/mgod"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// newMockProjectRepository builds the repository on mt's mocked deployment
+func newMockProjectRepository(mt *mtest.T) *projectRepository {
+	mgod.SetDefaultConnection(mt.DB)
+	repo, err := NewProjectRepository(mt.Coll.Name())
+	if err != nil {
+		mt.Fatal(err)
+	}
+	return repo.(*projectRepository)
+}
+
+// mockDocument converts v to the document a mocked server returns for it
+func mockDocument(mt *mtest.T, v any) bson.D {
+	raw, err := bson.Marshal(v)
+	if err != nil {
+		mt.Fatal(err)
+	}
+	var doc bson.D
+	if err := bson.Unmarshal(raw, &doc); err != nil {
+		mt.Fatal(err)
+	}
+	return doc
+}
+
+// mockMembership is a stored membership with every required field present
+func mockMembership(mt *mtest.T, projectID, userID primitive.ObjectID, role string) bson.D {
+	return mockDocument(mt, domain.ProjectMember{
+		ID:          primitive.NewObjectID(),
+		ProjectID:   projectID,
+		UserID:      userID,
+		Role:        role,
+		Permissions: []string{},
+	})
+}
+
+func TestProjectRepositoryUpdateMetadataKeepsKeyEpoch(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("rename after a rotation", func(mt *mtest.T) {
+		repo := newMockProjectRepository(mt)
+		ctx := context.Background()
+		projectID := primitive.NewObjectID()
+
+		// A rotation lands between the caller loading the project (at
+		// epoch-1) and saving its new name
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		if err := repo.UpdateKeyEpoch(ctx, projectID, "epoch-2"); err != nil {
+			mt.Fatal(err)
+		}
+		rotation := mt.GetStartedEvent().Command
+		if got := rotation.Lookup("updates", "0", "u", "$set", "key_epoch").StringValue(); got != "epoch-2" {
+			mt.Fatalf("rotation set key_epoch = %q, want epoch-2", got)
+		}
+
+		now := time.Now().Truncate(time.Millisecond)
+		stored := bson.D{
+			{Key: "_id", Value: projectID},
+			{Key: "name", Value: "renamed"},
+			{Key: "description", Value: "core network"},
+			{Key: "key_epoch", Value: "epoch-2"},
+			{Key: "createdAt", Value: now},
+			{Key: "updatedAt", Value: now},
+		}
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: stored}))
+
+		name := "renamed"
+		project, err := repo.UpdateMetadata(ctx, projectID, &name, nil, nil)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if project.KeyEpoch != "epoch-2" || project.Name != "renamed" {
+			mt.Errorf("project = %+v, want the new name at epoch-2", project)
+		}
+
+		// Only the name is written; nothing reverts the epoch
+		set, err := mt.GetStartedEvent().Command.LookupErr("update", "$set")
+		if err != nil {
+			mt.Fatal(err)
+		}
+		elements, err := set.Document().Elements()
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(elements) != 1 || elements[0].Key() != "name" {
+			mt.Errorf("$set = %v, want only name", set)
+		}
+	})
+}
+
+func TestProjectRepositoryFindByUserIDRoleFilter(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tests := []struct {
+		filter   string
+		wantRole any // the role condition on memberships; nil for none
+	}{
+		{filter: "", wantRole: nil},
+		{filter: domain.ProjectRoleFilterAll, wantRole: nil},
+		{filter: domain.ProjectRoleFilterOwner, wantRole: domain.RoleOwner},
+		{filter: domain.ProjectRoleFilterMember, wantRole: bson.D{{Key: "$ne", Value: domain.RoleOwner}}},
+	}
+	for _, tt := range tests {
+		mt.Run("role "+tt.filter, func(mt *mtest.T) {
+			repo := newMockProjectRepository(mt)
+			userID, projectID := primitive.NewObjectID(), primitive.NewObjectID()
+			ns := mt.DB.Name() + "." + mt.Coll.Name()
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockMembership(mt, projectID, userID, domain.RoleOwner)),
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockDocument(mt, domain.Project{ID: projectID, Name: "infra"})),
+			)
+
+			projects, total, err := repo.FindByUserID(context.Background(), userID, domain.ProjectListQuery{Role: tt.filter}, 0, 10)
+			if err != nil {
+				mt.Fatal(err)
+			}
+			if total != 1 || len(projects) != 1 {
+				mt.Fatalf("got %d projects of %d, want 1 of 1", len(projects), total)
+			}
+
+			memberFilter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+			role, err := memberFilter.LookupErr("role")
+			switch want := tt.wantRole.(type) {
+			case nil:
+				if err == nil {
+					mt.Errorf("membership filter has role %v, want none", role)
+				}
+			case string:
+				if err != nil || role.StringValue() != want {
+					mt.Errorf("membership filter role = %v, want %q", role, want)
+				}
+			case bson.D:
+				if err != nil || role.Document().Lookup("$ne").StringValue() != want[0].Value {
+					mt.Errorf("membership filter role = %v, want %v", role, want)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectRepositoryFindByUserIDCarriesRoles(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("role per project", func(mt *mtest.T) {
+		repo := newMockProjectRepository(mt)
+		userID := primitive.NewObjectID()
+		owned, shared := primitive.NewObjectID(), primitive.NewObjectID()
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				mockMembership(mt, owned, userID, domain.RoleOwner),
+				mockMembership(mt, shared, userID, domain.RoleEditor)),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(2)}}),
+			// Projects come back in their own sort order, not the memberships'
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+				mockDocument(mt, domain.Project{ID: shared, Name: "shared"}),
+				mockDocument(mt, domain.Project{ID: owned, Name: "owned"})),
+		)
+
+		projects, _, err := repo.FindByUserID(context.Background(), userID, domain.ProjectListQuery{}, 0, 10)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		roles := make(map[string]string)
+		for _, p := range projects {
+			roles[p.Project.Name] = p.Role
+		}
+		if roles["owned"] != domain.RoleOwner || roles["shared"] != domain.RoleEditor {
+			mt.Errorf("roles = %v, want owned by owner and shared as editor", roles)
+		}
+		if started := mt.GetAllStartedEvents(); len(started) != 3 {
+			mt.Errorf("sent %d commands, want one membership query, one count and one find", len(started))
+		}
+	})
+}
+
+func TestProjectRepositoryFindByUserIDSort(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	tests := []struct {
+		sort      string
+		wantSort  bson.D
+		collation bool
+	}{
+		{sort: "", wantSort: bson.D{{Key: "updatedAt", Value: int32(-1)}, {Key: "_id", Value: int32(-1)}}},
+		{sort: domain.ProjectSortRecent, wantSort: bson.D{{Key: "updatedAt", Value: int32(-1)}, {Key: "_id", Value: int32(-1)}}},
+		{sort: domain.ProjectSortName, wantSort: bson.D{{Key: "name", Value: int32(1)}, {Key: "_id", Value: int32(1)}}, collation: true},
+		{sort: domain.ProjectSortCreated, wantSort: bson.D{{Key: "createdAt", Value: int32(-1)}, {Key: "_id", Value: int32(-1)}}},
+	}
+	for _, tt := range tests {
+		mt.Run("sort "+tt.sort, func(mt *mtest.T) {
+			repo := newMockProjectRepository(mt)
+			userID, projectID := primitive.NewObjectID(), primitive.NewObjectID()
+			ns := mt.DB.Name() + "." + mt.Coll.Name()
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockMembership(mt, projectID, userID, domain.RoleOwner)),
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockDocument(mt, domain.Project{ID: projectID, Name: "infra"})),
+			)
+
+			if _, _, err := repo.FindByUserID(context.Background(), userID, domain.ProjectListQuery{Sort: tt.sort}, 0, 10); err != nil {
+				mt.Fatal(err)
+			}
+
+			// Skip the membership lookup and the count
+			mt.GetStartedEvent()
+			mt.GetStartedEvent()
+			find := mt.GetStartedEvent()
+			if find == nil || find.CommandName != "find" {
+				mt.Fatalf("third command = %v, want the project find", find)
+			}
+
+			var gotSort bson.D
+			if err := bson.Unmarshal(find.Command.Lookup("sort").Document(), &gotSort); err != nil {
+				mt.Fatal(err)
+			}
+			if !reflect.DeepEqual(gotSort, tt.wantSort) {
+				mt.Errorf("sort = %v, want %v", gotSort, tt.wantSort)
+			}
+			_, err := find.Command.LookupErr("collation")
+			if hasCollation := err == nil; hasCollation != tt.collation {
+				mt.Errorf("collation sent = %v, want %v", hasCollation, tt.collation)
+			}
+		})
+	}
+}
+
+func TestProjectRepositoryTouch(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("bumps updatedAt of a live project", func(mt *mtest.T) {
+		repo := newMockProjectRepository(mt)
+		projectID := primitive.NewObjectID()
+
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "n", Value: 1}))
+		if err := repo.Touch(context.Background(), projectID); err != nil {
+			mt.Fatal(err)
+		}
+
+		update := mt.GetStartedEvent().Command.Lookup("updates", "0").Document()
+		if id := update.Lookup("q", "_id").ObjectID(); id != projectID {
+			mt.Errorf("filter _id = %s, want %s", id.Hex(), projectID.Hex())
+		}
+		if _, err := update.LookupErr("q", "deleted_at", "$exists"); err != nil {
+			mt.Error("filter does not skip deleted projects")
+		}
+		if _, err := update.LookupErr("u", "$currentDate", "updatedAt"); err != nil {
+			mt.Errorf("update = %v, want $currentDate on updatedAt", update.Lookup("u"))
+		}
+		if _, err := update.LookupErr("u", "$set"); err == nil {
+			mt.Errorf("update = %v, want no fields set", update.Lookup("u"))
+		}
+	})
+}
+
+func TestProjectRepositoryFindByUserIDTagFilter(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	for _, tag := range []string{"", "prod"} {
+		mt.Run("tag "+tag, func(mt *mtest.T) {
+			repo := newMockProjectRepository(mt)
+			userID, projectID := primitive.NewObjectID(), primitive.NewObjectID()
+			ns := mt.DB.Name() + "." + mt.Coll.Name()
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockMembership(mt, projectID, userID, domain.RoleOwner)),
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockDocument(mt, domain.Project{ID: projectID, Name: "infra", Tags: []string{"prod"}})),
+			)
+
+			projects, _, err := repo.FindByUserID(context.Background(), userID, domain.ProjectListQuery{Tag: tag}, 0, 10)
+			if err != nil {
+				mt.Fatal(err)
+			}
+			if len(projects) != 1 || len(projects[0].Project.Tags) != 1 {
+				mt.Fatalf("projects = %+v, want the tagged project", projects)
+			}
+
+			// Both the count and the page filter on the tag in the query
+			mt.GetStartedEvent()
+			count := mt.GetStartedEvent().Command.Lookup("pipeline", "0", "$match")
+			find := mt.GetStartedEvent().Command.Lookup("filter")
+			for name, filter := range map[string]bson.Raw{"count": count.Document(), "find": find.Document()} {
+				got, err := filter.LookupErr("tags")
+				if tag == "" && err == nil {
+					mt.Errorf("%s filters on tags %v without a tag", name, got)
+				}
+				if tag != "" && (err != nil || got.StringValue() != tag) {
+					mt.Errorf("%s tags filter = %v, want %q", name, got, tag)
+				}
+			}
+		})
+	}
+}
+
+func TestProjectRepositoryFindByUserIDArchivedFilter(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	for _, includeArchived := range []bool{false, true} {
+		mt.Run(fmt.Sprintf("include archived %v", includeArchived), func(mt *mtest.T) {
+			repo := newMockProjectRepository(mt)
+			userID, projectID := primitive.NewObjectID(), primitive.NewObjectID()
+			ns := mt.DB.Name() + "." + mt.Coll.Name()
+			mt.AddMockResponses(
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockMembership(mt, projectID, userID, domain.RoleOwner)),
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+				mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockDocument(mt, domain.Project{ID: projectID, Name: "infra"})),
+			)
+
+			query := domain.ProjectListQuery{IncludeArchived: includeArchived}
+			if _, _, err := repo.FindByUserID(context.Background(), userID, query, 0, 10); err != nil {
+				mt.Fatal(err)
+			}
+
+			mt.GetStartedEvent()
+			mt.GetStartedEvent()
+			filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+			archived, err := filter.LookupErr("archived_at", "$exists")
+			if includeArchived && err == nil {
+				mt.Errorf("filter = %v, want archived projects kept", filter)
+			}
+			if !includeArchived && (err != nil || archived.Boolean()) {
+				mt.Errorf("filter = %v, want archived projects left out", filter)
+			}
+			if _, err := filter.LookupErr("deleted_at", "$exists"); err != nil {
+				mt.Errorf("filter = %v, want deleted projects left out either way", filter)
+			}
+		})
+	}
+}
+
+func TestProjectRepositoryArchiveSkipsArchived(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("archive", func(mt *mtest.T) {
+		repo := newMockProjectRepository(mt)
+		projectID := primitive.NewObjectID()
+
+		// Nothing matched: the project is archived already
+		mt.AddMockResponses(mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil}))
+		if _, err := repo.Archive(context.Background(), projectID, time.Now()); !errors.Is(err, mongo.ErrNoDocuments) {
+			mt.Errorf("err = %v, want %v", err, mongo.ErrNoDocuments)
+		}
+
+		command := mt.GetStartedEvent().Command
+		if _, err := command.LookupErr("query", "archived_at", "$exists"); err != nil {
+			mt.Errorf("query = %v, want only unarchived projects matched", command.Lookup("query"))
+		}
+		if _, err := command.LookupErr("update", "$set", "archived_at"); err != nil {
+			mt.Errorf("update = %v, want archived_at set", command.Lookup("update"))
+		}
+	})
+}
+
+func TestProjectRepositoryFindByUserIDFavoritesFirst(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("favorites lead the list", func(mt *mtest.T) {
+		repo := newMockProjectRepository(mt)
+		userID := primitive.NewObjectID()
+		plain, pinned := primitive.NewObjectID(), primitive.NewObjectID()
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+
+		favorite := mockMembership(mt, pinned, userID, domain.RoleViewer)
+		favorite = append(favorite, bson.E{Key: "favorite", Value: true})
+		mt.AddMockResponses(
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockMembership(mt, plain, userID, domain.RoleOwner), favorite),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockDocument(mt, domain.Project{ID: pinned, Name: "pinned"})),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, bson.D{{Key: "n", Value: int32(1)}}),
+			mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockDocument(mt, domain.Project{ID: plain, Name: "plain"})),
+		)
+
+		projects, total, err := repo.FindByUserID(context.Background(), userID, domain.ProjectListQuery{}, 0, 10)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if total != 2 || len(projects) != 2 {
+			mt.Fatalf("got %d projects of %d, want 2 of 2", len(projects), total)
+		}
+		if projects[0].Project.ID != pinned || !projects[0].Favorite || projects[1].Project.ID != plain || projects[1].Favorite {
+			mt.Errorf("order = %s (favorite %v), %s (favorite %v); want the pinned project first",
+				projects[0].Project.Name, projects[0].Favorite, projects[1].Project.Name, projects[1].Favorite)
+		}
+
+		// The favorites group is queried on its own, before the rest
+		mt.GetStartedEvent()
+		mt.GetStartedEvent()
+		ids, err := mt.GetStartedEvent().Command.Lookup("filter", "_id", "$in").Array().Values()
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(ids) != 1 || ids[0].ObjectID() != pinned {
+			mt.Errorf("first page queried %v, want only the favorite", ids)
+		}
+	})
+
+	mt.Run("favorites only", func(mt *mtest.T) {
+		repo := newMockProjectRepository(mt)
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch))
+
+		projects, total, err := repo.FindByUserID(context.Background(), primitive.NewObjectID(), domain.ProjectListQuery{FavoritesOnly: true}, 0, 10)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if total != 0 || len(projects) != 0 {
+			mt.Errorf("got %d projects of %d, want none", len(projects), total)
+		}
+		if favorite, err := mt.GetStartedEvent().Command.LookupErr("filter", "favorite"); err != nil || !favorite.Boolean() {
+			mt.Errorf("membership filter favorite = %v, want true", favorite)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/Lyearn/mgod"
+	"github.com/Lyearn/mgod/schema/schemaopt"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type projectRoleRepository struct {
+	model mgod.EntityMongoModel[domain.ProjectRole]
+}
+
+func NewProjectRoleRepository(collectionName string) (port.ProjectRoleRepository, error) {
+	opts := schemaopt.SchemaOptions{
+		Collection: collectionName,
+		Timestamps: true,
+	}
+	model, err := mgod.NewEntityMongoModel(domain.ProjectRole{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &projectRoleRepository{model: model}, nil
+}
+
+func (r *projectRoleRepository) Create(ctx context.Context, role *domain.ProjectRole) error {
+	created, err := r.model.InsertOne(ctx, *role)
+	if err != nil {
+		return err
+	}
+	*role = created
+	return nil
+}
+
+func (r *projectRoleRepository) FindByID(ctx context.Context, id primitive.ObjectID) (*domain.ProjectRole, error) {
+	return r.model.FindOne(ctx, bson.M{"_id": id})
+}
+
+// FindByProjectID lists the project's roles by name
+func (r *projectRoleRepository) FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]*domain.ProjectRole, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	roles, err := r.model.Find(ctx, bson.M{"project_id": projectID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.ProjectRole, 0, len(roles))
+	for i := range roles {
+		result = append(result, &roles[i])
+	}
+	return result, nil
+}
+
+func (r *projectRoleRepository) FindByProjectAndName(ctx context.Context, projectID primitive.ObjectID, name string) (*domain.ProjectRole, error) {
+	return r.model.FindOne(ctx, bson.M{"project_id": projectID, "name": name})
+}
+
+// Update writes the role's name and permissions and refreshes it
+func (r *projectRoleRepository) Update(ctx context.Context, role *domain.ProjectRole) error {
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: role.Name},
+		{Key: "permissions", Value: role.Permissions},
+	}}}
+	opts := options.FindOneAndUpdate().SetReturnDocument(options.After)
+	updated, err := r.model.FindOneAndUpdate(ctx, bson.M{"_id": role.ID}, update, opts)
+	if err != nil {
+		return err
+	}
+	*role = updated
+	return nil
+}
+
+func (r *projectRoleRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"_id": id})
+	return err
+}
+
+func (r *projectRoleRepository) DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
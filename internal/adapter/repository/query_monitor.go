@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// loggedCommands are the data commands the query monitor reports; handshakes,
+// pings and cursor housekeeping are left out
+var loggedCommands = map[string]bool{
+	"find":          true,
+	"insert":        true,
+	"update":        true,
+	"delete":        true,
+	"findAndModify": true,
+	"aggregate":     true,
+	"count":         true,
+	"distinct":      true,
+}
+
+// queryMonitor logs every data command at debug level with its collection,
+// filter and duration. Filters are sanitized with logger.Redact, so
+// encrypted values, passwords and tokens never reach the logs; documents
+// being written are not logged at all.
+type queryMonitor struct {
+	pending sync.Map // request ID -> startedQuery
+}
+
+type startedQuery struct {
+	collection string
+	filter     any
+}
+
+// NewQueryMonitor returns a command monitor for the Mongo client that logs
+// queries. It is only attached when debug logging is on, so it costs nothing
+// otherwise.
+func NewQueryMonitor() *event.CommandMonitor {
+	m := &queryMonitor{}
+	return &event.CommandMonitor{
+		Started:   m.started,
+		Succeeded: m.succeeded,
+		Failed:    m.failed,
+	}
+}
+
+func (m *queryMonitor) started(_ context.Context, e *event.CommandStartedEvent) {
+	if !loggedCommands[e.CommandName] {
+		return
+	}
+	collection, _ := e.Command.Lookup(e.CommandName).StringValueOK()
+	m.pending.Store(e.RequestID, startedQuery{
+		collection: collection,
+		filter:     commandFilter(e.CommandName, e.Command),
+	})
+}
+
+func (m *queryMonitor) succeeded(_ context.Context, e *event.CommandSucceededEvent) {
+	m.log(e.RequestID, e.CommandName, e.Duration, "")
+}
+
+func (m *queryMonitor) failed(_ context.Context, e *event.CommandFailedEvent) {
+	m.log(e.RequestID, e.CommandName, e.Duration, e.Failure)
+}
+
+func (m *queryMonitor) log(requestID int64, command string, duration time.Duration, failure string) {
+	value, ok := m.pending.LoadAndDelete(requestID)
+	if !ok {
+		return
+	}
+	query := value.(startedQuery)
+
+	entry := logger.Debug().
+		Str("collection", query.collection).
+		Str("operation", command).
+		Dur("duration", duration)
+	if query.filter != nil {
+		entry = entry.Interface("filter", query.filter)
+	}
+	if failure != "" {
+		entry = entry.Str("error", failure)
+	}
+	entry.Msg("Mongo query")
+}
+
+// commandFilter picks the filter, or the pipeline, out of a command and
+// sanitizes it. Updates and deletes may carry several statements; their
+// filters are returned as a list.
+func commandFilter(command string, raw bson.Raw) any {
+	var key string
+	switch command {
+	case "find":
+		key = "filter"
+	case "count", "distinct", "findAndModify":
+		key = "query"
+	case "aggregate":
+		key = "pipeline"
+	case "update", "delete":
+		var statements []bson.M
+		if err := raw.Lookup(command + "s").Unmarshal(&statements); err != nil {
+			return nil
+		}
+		filters := make([]any, 0, len(statements))
+		for _, statement := range statements {
+			filters = append(filters, sanitizeQueryValue("", statement["q"]))
+		}
+		return filters
+	default:
+		return nil
+	}
+
+	value, err := raw.LookupErr(key)
+	if err != nil {
+		return nil
+	}
+	var filter any
+	if err := value.Unmarshal(&filter); err != nil {
+		return nil
+	}
+	return sanitizeQueryValue("", filter)
+}
+
+// sanitizeQueryValue copies a decoded filter with every string passed through
+// logger.Redact under the field it belongs to and binary data redacted
+func sanitizeQueryValue(field string, value any) any {
+	switch v := value.(type) {
+	case bson.D:
+		out := make(bson.M, len(v))
+		for _, elem := range v {
+			out[elem.Key] = sanitizeQueryValue(fieldName(field, elem.Key), elem.Value)
+		}
+		return out
+	case bson.M:
+		out := make(bson.M, len(v))
+		for key, elem := range v {
+			out[key] = sanitizeQueryValue(fieldName(field, key), elem)
+		}
+		return out
+	case bson.A:
+		out := make(bson.A, len(v))
+		for i, elem := range v {
+			out[i] = sanitizeQueryValue(field, elem)
+		}
+		return out
+	case string:
+		return logger.Redact(field, v)
+	case primitive.Binary:
+		return logger.RedactString(string(v.Data))
+	case primitive.ObjectID:
+		return v.Hex()
+	}
+	return value
+}
+
+// fieldName keeps the document field an operator such as $in applies to
+func fieldName(parent, key string) string {
+	if len(key) > 0 && key[0] == '$' {
+		return parent
+	}
+	return key
+}
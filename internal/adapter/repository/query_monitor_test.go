@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"github.com/rs/zerolog"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/event"
+)
+
+// captureLogs sends the global logger to a buffer at level for the rest of
+// the test
+func captureLogs(t *testing.T, level zerolog.Level) *bytes.Buffer {
+	t.Helper()
+	previous, previousLevel := logger.Logger, zerolog.GlobalLevel()
+	t.Cleanup(func() {
+		logger.Logger = previous
+		zerolog.SetGlobalLevel(previousLevel)
+	})
+
+	var buf bytes.Buffer
+	logger.Logger = zerolog.New(&buf)
+	zerolog.SetGlobalLevel(level)
+	return &buf
+}
+
+// runCommand passes a command and its outcome through the monitor
+func runCommand(t *testing.T, monitor *event.CommandMonitor, requestID int64, name string, command bson.D, failure string) {
+	t.Helper()
+	raw, err := bson.Marshal(command)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	monitor.Started(ctx, &event.CommandStartedEvent{Command: raw, CommandName: name, RequestID: requestID})
+	finished := event.CommandFinishedEvent{CommandName: name, RequestID: requestID, Duration: 3 * time.Millisecond}
+	if failure != "" {
+		monitor.Failed(ctx, &event.CommandFailedEvent{CommandFinishedEvent: finished, Failure: failure})
+		return
+	}
+	monitor.Succeeded(ctx, &event.CommandSucceededEvent{CommandFinishedEvent: finished})
+}
+
+func TestQueryMonitorLogsAtDebug(t *testing.T) {
+	buf := captureLogs(t, zerolog.DebugLevel)
+	monitor := NewQueryMonitor()
+	userID := primitive.NewObjectID()
+
+	runCommand(t, monitor, 1, "find", bson.D{
+		{Key: "find", Value: "users"},
+		{Key: "filter", Value: bson.D{
+			{Key: "email", Value: "alice@example.com"},
+			{Key: "_id", Value: bson.D{{Key: "$in", Value: bson.A{userID}}}},
+		}},
+	}, "")
+	runCommand(t, monitor, 2, "update", bson.D{
+		{Key: "update", Value: "node_vaults"},
+		{Key: "updates", Value: bson.A{bson.D{
+			{Key: "q", Value: bson.D{{Key: "encrypted_value", Value: "c2VjcmV0LWJsb2I="}}},
+			{Key: "u", Value: bson.D{{Key: "$set", Value: bson.D{{Key: "password", Value: "hunter2"}}}}},
+		}}},
+	}, "")
+	runCommand(t, monitor, 3, "insert", bson.D{
+		{Key: "insert", Value: "users"},
+		{Key: "documents", Value: bson.A{bson.D{{Key: "password_hash", Value: "argon2-hash"}}}},
+	}, "E11000 duplicate key")
+	runCommand(t, monitor, 4, "ping", bson.D{{Key: "ping", Value: 1}}, "")
+
+	out := buf.String()
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("logged %d lines, want one per data command:\n%s", len(lines), out)
+	}
+
+	var find map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &find); err != nil {
+		t.Fatal(err)
+	}
+	if find["level"] != "debug" || find["collection"] != "users" || find["operation"] != "find" || find["duration"] == nil {
+		t.Errorf("find entry = %v", find)
+	}
+	filter, _ := json.Marshal(find["filter"])
+	if !strings.Contains(string(filter), "a***@example.com") || !strings.Contains(string(filter), userID.Hex()) {
+		t.Errorf("filter = %s, want the masked email and the ID", filter)
+	}
+
+	if !strings.Contains(lines[2], "E11000") {
+		t.Errorf("failed insert entry = %s, want the error", lines[2])
+	}
+	for _, secret := range []string{"alice@example.com", "c2VjcmV0LWJsb2I=", "hunter2", "argon2-hash"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("%q reached the logs:\n%s", secret, out)
+		}
+	}
+}
+
+func TestQueryMonitorSilentAboveDebug(t *testing.T) {
+	buf := captureLogs(t, zerolog.InfoLevel)
+	runCommand(t, NewQueryMonitor(), 1, "find", bson.D{
+		{Key: "find", Value: "projects"},
+		{Key: "filter", Value: bson.D{{Key: "name", Value: "infra"}}},
+	}, "")
+
+	if buf.Len() != 0 {
+		t.Errorf("logged at info level: %s", buf)
+	}
+}
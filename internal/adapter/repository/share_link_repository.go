@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Lyearn/mgod"
+	"github.com/Lyearn/mgod/schema/schemaopt"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type shareLinkRepository struct {
+	model mgod.EntityMongoModel[domain.ShareLink]
+}
+
+func NewShareLinkRepository(collectionName string) (port.ShareLinkRepository, error) {
+	opts := schemaopt.SchemaOptions{
+		Collection: collectionName,
+		Timestamps: true,
+	}
+	model, err := mgod.NewEntityMongoModel(domain.ShareLink{}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &shareLinkRepository{model: model}, nil
+}
+
+func (r *shareLinkRepository) Create(ctx context.Context, link *domain.ShareLink) error {
+	result, err := r.model.InsertOne(ctx, *link)
+	if err != nil {
+		return err
+	}
+	link.ID = result.ID
+	link.CreatedAt = result.CreatedAt
+	link.UpdatedAt = result.UpdatedAt
+	return nil
+}
+
+// FindByHash returns the link with the given token hash, or nil if there is none
+func (r *shareLinkRepository) FindByHash(ctx context.Context, tokenHash string) (*domain.ShareLink, error) {
+	link, err := r.model.FindOne(ctx, bson.M{"token_hash": tokenHash})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return link, nil
+}
+
+// FindByDiagramID lists the diagram's links, newest first
+func (r *shareLinkRepository) FindByDiagramID(ctx context.Context, diagramID primitive.ObjectID) ([]*domain.ShareLink, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: -1}})
+	links, err := r.model.Find(ctx, bson.M{"diagram_id": diagramID}, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*domain.ShareLink, 0, len(links))
+	for i := range links {
+		result = append(result, &links[i])
+	}
+	return result, nil
+}
+
+// Delete removes the link if it belongs to diagramID and reports whether it did
+func (r *shareLinkRepository) Delete(ctx context.Context, id, diagramID primitive.ObjectID) (bool, error) {
+	result, err := r.model.DeleteMany(ctx, bson.M{"_id": id, "diagram_id": diagramID})
+	if err != nil {
+		return false, err
+	}
+	return result.DeletedCount > 0, nil
+}
+
+func (r *shareLinkRepository) DeleteByDiagramID(ctx context.Context, diagramID primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"diagram_id": diagramID})
+	return err
+}
+
+func (r *shareLinkRepository) DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error {
+	_, err := r.model.DeleteMany(ctx, bson.M{"project_id": projectID})
+	return err
+}
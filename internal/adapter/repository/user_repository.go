@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"regexp"
 
 	"github.com/Lyearn/mgod"
 	"github.com/Lyearn/mgod/schema/schemaopt"
@@ -10,6 +11,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 type userRepository struct {
@@ -112,24 +114,28 @@ func (r *userRepository) ExistsByUsername(ctx context.Context, username string,
 	return result != nil, nil
 }
 
-func (r *userRepository) SearchUsers(ctx context.Context, query string, limit int) ([]*domain.User, error) {
+// SearchUsers finds up to limit users whose name, email or username contains
+// query, leaving out excludeIDs. The query is matched literally.
+func (r *userRepository) SearchUsers(ctx context.Context, query string, excludeIDs []primitive.ObjectID, limit int) ([]*domain.User, error) {
+	pattern := primitive.Regex{Pattern: regexp.QuoteMeta(query), Options: "i"}
 	filter := bson.M{
 		"$or": bson.A{
-			bson.M{"name": bson.M{"$regex": primitive.Regex{Pattern: query, Options: "i"}}},
-			bson.M{"email": bson.M{"$regex": primitive.Regex{Pattern: query, Options: "i"}}},
-			bson.M{"username": bson.M{"$regex": primitive.Regex{Pattern: query, Options: "i"}}},
+			bson.M{"name": bson.M{"$regex": pattern}},
+			bson.M{"email": bson.M{"$regex": pattern}},
+			bson.M{"username": bson.M{"$regex": pattern}},
 		},
 	}
+	if len(excludeIDs) > 0 {
+		filter["_id"] = bson.M{"$nin": excludeIDs}
+	}
 
-	results, err := r.model.Find(ctx, filter)
+	// Excluding in the query keeps the limit honest
+	opts := options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}).SetLimit(int64(limit))
+	results, err := r.model.Find(ctx, filter, opts)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(results) > limit {
-		results = results[:limit]
-	}
-
 	users := make([]*domain.User, 0, len(results))
 	for i := range results {
 		users = append(users, &results[i])
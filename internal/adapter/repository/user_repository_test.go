@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Lyearn/mgod"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestUserRepositorySearchUsersExcludesInQuery(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	mt.Run("exclusions and limit", func(mt *mtest.T) {
+		mgod.SetDefaultConnection(mt.DB)
+		repo, err := NewUserRepository(mt.Coll.Name())
+		if err != nil {
+			mt.Fatal(err)
+		}
+		callerID, memberID := primitive.NewObjectID(), primitive.NewObjectID()
+		ns := mt.DB.Name() + "." + mt.Coll.Name()
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch, mockDocument(mt, domain.User{
+			ID: primitive.NewObjectID(), Name: "alice", Email: "alice@example.com", Username: "alice",
+		})))
+
+		users, err := repo.SearchUsers(context.Background(), "a.c", []primitive.ObjectID{callerID, memberID}, 5)
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(users) != 1 {
+			mt.Fatalf("got %d users, want 1", len(users))
+		}
+
+		find := mt.GetStartedEvent().Command
+		if limit := find.Lookup("limit").AsInt64(); limit != 5 {
+			mt.Errorf("limit = %d, want 5", limit)
+		}
+		excluded, err := find.Lookup("filter", "_id", "$nin").Array().Values()
+		if err != nil {
+			mt.Fatal(err)
+		}
+		if len(excluded) != 2 || excluded[0].ObjectID() != callerID || excluded[1].ObjectID() != memberID {
+			mt.Errorf("$nin = %v, want the caller and the member", excluded)
+		}
+		// The query is matched literally
+		if pattern, _ := find.Lookup("filter", "$or", "0", "name", "$regex").Regex(); pattern != `a\.c` {
+			mt.Errorf("pattern = %q, want the query escaped", pattern)
+		}
+	})
+}
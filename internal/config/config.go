@@ -1,51 +1,172 @@
 package config
 
 import (
+	"errors"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 )
 
 type Config struct {
-	Port              string
-	MongoDBURI        string
-	MongoDBDatabase   string
-	JWTSecret         string
-	JWTAccessExpiry   time.Duration
-	JWTRefreshExpiry  time.Duration
-	Argon2Memory      uint32
-	Argon2Iterations  uint32
-	Argon2Parallelism uint8
-	Argon2SaltLength  uint32
-	Argon2KeyLength   uint32
-	LogLevel          string
-	Environment       string
-	CookieDomain      string
-	CookieSecure      bool
-	CookieSameSite    string
+	Port                   string
+	APIBasePath            string
+	TrustedProxies         []string
+	MongoDBURI             string
+	MongoDBDatabase        string
+	JWTSecret              string
+	JWTAccessExpiry        time.Duration
+	JWTRefreshExpiry       time.Duration
+	Argon2Memory           uint32
+	Argon2Iterations       uint32
+	Argon2Parallelism      uint8
+	Argon2SaltLength       uint32
+	Argon2KeyLength        uint32
+	LogLevel               string
+	LogSampleEvery         uint32
+	LogFile                string
+	LogMaxSizeMB           int
+	LogMaxBackups          int
+	LogMaxAge              time.Duration
+	LogStdout              bool
+	Environment            string
+	CookieDomain           string
+	CookieSecure           bool
+	CookieSameSite         string
+	MaxRequestBody         int64
+	RequestTimeout         time.Duration
+	LongRequestTimeout     time.Duration
+	BreadcrumbCacheTTL     time.Duration
+	NodeUpdateMinInterval  time.Duration
+	DiagramLockTTL         time.Duration
+	MaxDiagramData         int
+	MaxNodeData            int
+	MaxVaultValue          int
+	MaxNoteContent         int
+	MaxPageSize            int
+	BackupCompression      string
+	BackupCompressionLevel int
+	BackupPepperID         int
+	BackupPeppers          []string
+	BackupStorage          string
+	BackupStoragePath      string
+	S3Endpoint             string
+	S3Region               string
+	S3Bucket               string
+	S3AccessKeyID          string
+	S3SecretAccessKey      string
+	BackupSchedulerEnabled bool
+	BackupSchedulerTick    time.Duration
+	ProjectDeletionGrace   time.Duration
+	ProjectDeletionTick    time.Duration
+	ProjectRetention       time.Duration
+	IdempotencyTTL         time.Duration
+	MaintenanceMode        string
+	MaintenanceRetryAfter  time.Duration
+	AdminToken             string
+	MaxEventStreamsPerUser int
+	PasswordMinLength      int
+	PasswordMaxLength      int
+	PasswordRequireUpper   bool
+	PasswordRequireLower   bool
+	PasswordRequireDigit   bool
+	PasswordRequireSymbol  bool
+	PasswordRejectCommon   bool
+	Notifier               string
+	SMTPHost               string
+	SMTPPort               int
+	SMTPUsername           string
+	SMTPPassword           string
+	SMTPFrom               string
 }
 
 func Load() *Config {
 	return &Config{
-		Port:              getEnv("PORT", "8085"),
-		MongoDBURI:        getEnv("MONGODB_URI", "mongodb://localhost:27017"),
-		MongoDBDatabase:   getEnv("MONGODB_DATABASE", "infrantery"),
-		JWTSecret:         getEnv("JWT_SECRET", "your-super-secret-key"),
-		JWTAccessExpiry:   parseDuration(getEnv("JWT_ACCESS_EXPIRY", "15m")),
-		JWTRefreshExpiry:  parseDuration(getEnv("JWT_REFRESH_EXPIRY", "168h")),
-		Argon2Memory:      parseUint32(getEnv("ARGON2_MEMORY", "65536")),
-		Argon2Iterations:  parseUint32(getEnv("ARGON2_ITERATIONS", "3")),
-		Argon2Parallelism: parseUint8(getEnv("ARGON2_PARALLELISM", "2")),
-		Argon2SaltLength:  parseUint32(getEnv("ARGON2_SALT_LENGTH", "16")),
-		Argon2KeyLength:   parseUint32(getEnv("ARGON2_KEY_LENGTH", "32")),
-		LogLevel:          getEnv("LOG_LEVEL", "info"),
-		Environment:       getEnv("ENVIRONMENT", "development"),
-		CookieDomain:      getEnv("COOKIE_DOMAIN", "localhost"),
-		CookieSecure:      getEnv("COOKIE_SECURE", "false") == "true",
-		CookieSameSite:    getEnv("COOKIE_SAMESITE", "lax"),
+		Port:                   getEnv("PORT", "8085"),
+		APIBasePath:            parseBasePath(getEnv("API_BASE_PATH", "/api/v1")),
+		TrustedProxies:         parseList(getEnv("TRUSTED_PROXIES", "127.0.0.1,::1")),
+		MongoDBURI:             getEnv("MONGODB_URI", "mongodb://localhost:27017"),
+		MongoDBDatabase:        getEnv("MONGODB_DATABASE", "infrantery"),
+		JWTSecret:              getEnv("JWT_SECRET", "your-super-secret-key"),
+		JWTAccessExpiry:        parseDuration(getEnv("JWT_ACCESS_EXPIRY", "15m")),
+		JWTRefreshExpiry:       parseDuration(getEnv("JWT_REFRESH_EXPIRY", "168h")),
+		Argon2Memory:           parseUint32(getEnv("ARGON2_MEMORY", "65536")),
+		Argon2Iterations:       parseUint32(getEnv("ARGON2_ITERATIONS", "3")),
+		Argon2Parallelism:      parseUint8(getEnv("ARGON2_PARALLELISM", "2")),
+		Argon2SaltLength:       parseUint32(getEnv("ARGON2_SALT_LENGTH", "16")),
+		Argon2KeyLength:        parseUint32(getEnv("ARGON2_KEY_LENGTH", "32")),
+		LogLevel:               getEnv("LOG_LEVEL", "info"),
+		LogSampleEvery:         parseUint32(getEnv("LOG_SAMPLE_EVERY", "1")),
+		LogFile:                getEnv("LOG_FILE", ""),
+		LogMaxSizeMB:           parseInt(getEnv("LOG_MAX_SIZE", "100")),
+		LogMaxBackups:          parseInt(getEnv("LOG_MAX_BACKUPS", "5")),
+		LogMaxAge:              parseDuration(getEnv("LOG_MAX_AGE", "720h")),
+		LogStdout:              getEnv("LOG_STDOUT", "true") == "true",
+		Environment:            getEnv("ENVIRONMENT", "development"),
+		CookieDomain:           getEnv("COOKIE_DOMAIN", "localhost"),
+		CookieSecure:           getEnv("COOKIE_SECURE", "false") == "true",
+		CookieSameSite:         getEnv("COOKIE_SAMESITE", "lax"),
+		MaxRequestBody:         parseInt64(getEnv("MAX_REQUEST_BODY", "10485760")),
+		RequestTimeout:         parseDuration(getEnv("REQUEST_TIMEOUT", "30s")),
+		LongRequestTimeout:     parseDuration(getEnv("LONG_REQUEST_TIMEOUT", "5m")),
+		BreadcrumbCacheTTL:     parseDuration(getEnv("BREADCRUMB_CACHE_TTL", "30s")),
+		NodeUpdateMinInterval:  parseDuration(getEnv("NODE_UPDATE_MIN_INTERVAL", "0")),
+		DiagramLockTTL:         parseDuration(getEnv("DIAGRAM_LOCK_TTL", "5m")),
+		MaxDiagramData:         parseInt(getEnv("MAX_DIAGRAM_DATA", "5242880")),
+		MaxNodeData:            parseInt(getEnv("MAX_NODE_DATA", "2097152")),
+		MaxVaultValue:          parseInt(getEnv("MAX_VAULT_VALUE", "262144")),
+		MaxNoteContent:         parseInt(getEnv("MAX_NOTE_CONTENT", "5242880")),
+		MaxPageSize:            parseInt(getEnv("MAX_PAGE_SIZE", "100")),
+		BackupCompression:      getEnv("BACKUP_COMPRESSION", "zstd"),
+		BackupCompressionLevel: parseInt(getEnv("BACKUP_COMPRESSION_LEVEL", "0")),
+		BackupPepperID:         parseInt(getEnv("BACKUP_PEPPER_ID", "0")),
+		BackupPeppers:          parseList(getEnv("BACKUP_PEPPERS", "")),
+		BackupStorage:          getEnv("BACKUP_STORAGE", "local"),
+		BackupStoragePath:      getEnv("BACKUP_STORAGE_PATH", "./data/backups"),
+		S3Endpoint:             getEnv("S3_ENDPOINT", ""),
+		S3Region:               getEnv("S3_REGION", "us-east-1"),
+		S3Bucket:               getEnv("S3_BUCKET", ""),
+		S3AccessKeyID:          getEnv("S3_ACCESS_KEY_ID", ""),
+		S3SecretAccessKey:      getEnv("S3_SECRET_ACCESS_KEY", ""),
+		BackupSchedulerEnabled: getEnv("BACKUP_SCHEDULER_ENABLED", "true") == "true",
+		BackupSchedulerTick:    parseDuration(getEnv("BACKUP_SCHEDULER_TICK", "1m")),
+		ProjectDeletionGrace:   parseDuration(getEnv("PROJECT_DELETION_GRACE", "72h")),
+		ProjectDeletionTick:    parseDuration(getEnv("PROJECT_DELETION_TICK", "1m")),
+		ProjectRetention:       parseDuration(getEnv("PROJECT_RETENTION", "168h")),
+		IdempotencyTTL:         parseDuration(getEnv("IDEMPOTENCY_TTL", "24h")),
+		MaintenanceMode:        getEnv("MAINTENANCE_MODE", "off"),
+		MaintenanceRetryAfter:  parseDuration(getEnv("MAINTENANCE_RETRY_AFTER", "5m")),
+		AdminToken:             getEnv("ADMIN_TOKEN", ""),
+		MaxEventStreamsPerUser: parseInt(getEnv("MAX_EVENT_STREAMS_PER_USER", "5")),
+		PasswordMinLength:      parseInt(getEnv("PASSWORD_MIN_LENGTH", "8")),
+		PasswordMaxLength:      parseInt(getEnv("PASSWORD_MAX_LENGTH", "128")),
+		PasswordRequireUpper:   getEnv("PASSWORD_REQUIRE_UPPER", "false") == "true",
+		PasswordRequireLower:   getEnv("PASSWORD_REQUIRE_LOWER", "false") == "true",
+		PasswordRequireDigit:   getEnv("PASSWORD_REQUIRE_DIGIT", "false") == "true",
+		PasswordRequireSymbol:  getEnv("PASSWORD_REQUIRE_SYMBOL", "false") == "true",
+		PasswordRejectCommon:   getEnv("PASSWORD_REJECT_COMMON", "true") == "true",
+		Notifier:               getEnv("NOTIFIER", "none"),
+		SMTPHost:               getEnv("SMTP_HOST", ""),
+		SMTPPort:               parseInt(getEnv("SMTP_PORT", "587")),
+		SMTPUsername:           getEnv("SMTP_USERNAME", ""),
+		SMTPPassword:           getEnv("SMTP_PASSWORD", ""),
+		SMTPFrom:               getEnv("SMTP_FROM", ""),
 	}
 }
 
+// ErrSameSiteNoneInsecure is returned by Validate when cookies would be sent
+// with SameSite=None but without Secure. Browsers drop such cookies, so
+// login would appear to succeed while no session is ever stored.
+var ErrSameSiteNoneInsecure = errors.New("COOKIE_SAMESITE=none requires COOKIE_SECURE=true")
+
+// Validate rejects combinations of settings that cannot work together
+func (c *Config) Validate() error {
+	if c.CookieSameSite == "none" && !c.CookieSecure {
+		return ErrSameSiteNoneInsecure
+	}
+	return nil
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -58,6 +179,16 @@ func parseDuration(s string) time.Duration {
 	return d
 }
 
+// parseBasePath normalizes a route prefix to a leading slash and no trailing
+// one; "/" becomes the empty prefix
+func parseBasePath(s string) string {
+	s = strings.Trim(strings.TrimSpace(s), "/")
+	if s == "" {
+		return ""
+	}
+	return "/" + s
+}
+
 func parseUint32(s string) uint32 {
 	val, _ := strconv.ParseUint(s, 10, 32)
 	return uint32(val)
@@ -67,3 +198,29 @@ func parseUint8(s string) uint8 {
 	val, _ := strconv.ParseUint(s, 10, 8)
 	return uint8(val)
 }
+
+func parseInt64(s string) int64 {
+	val, _ := strconv.ParseInt(s, 10, 64)
+	return val
+}
+
+func parseInt(s string) int {
+	val, _ := strconv.Atoi(s)
+	return val
+}
+
+// parseList splits a comma-separated value, dropping blank entries. The
+// value "none" yields an empty list.
+func parseList(s string) []string {
+	if strings.TrimSpace(s) == "none" {
+		return []string{}
+	}
+
+	var list []string
+	for _, item := range strings.Split(s, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestValidateSameSiteNoneRequiresSecure(t *testing.T) {
+	tests := []struct {
+		sameSite string
+		secure   bool
+		wantErr  error
+	}{
+		{sameSite: "none", secure: false, wantErr: ErrSameSiteNoneInsecure},
+		{sameSite: "none", secure: true},
+		{sameSite: "lax", secure: false},
+		{sameSite: "strict", secure: false},
+	}
+	for _, tt := range tests {
+		cfg := &Config{CookieSameSite: tt.sameSite, CookieSecure: tt.secure}
+		if err := cfg.Validate(); !errors.Is(err, tt.wantErr) {
+			t.Errorf("SameSite=%s Secure=%v: err = %v, want %v", tt.sameSite, tt.secure, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLoadSameSiteFromEnvironment(t *testing.T) {
+	t.Setenv("COOKIE_SAMESITE", "none")
+	t.Setenv("COOKIE_SECURE", "false")
+	if err := Load().Validate(); !errors.Is(err, ErrSameSiteNoneInsecure) {
+		t.Errorf("err = %v, want %v", err, ErrSameSiteNoneInsecure)
+	}
+
+	t.Setenv("COOKIE_SECURE", "true")
+	if err := Load().Validate(); err != nil {
+		t.Errorf("secure cookies rejected: %v", err)
+	}
+}
+
+func TestLoadLogFileFromEnvironment(t *testing.T) {
+	t.Setenv("LOG_FILE", "/var/log/infrantery/app.log")
+	t.Setenv("LOG_MAX_SIZE", "50")
+	t.Setenv("LOG_MAX_BACKUPS", "3")
+	t.Setenv("LOG_MAX_AGE", "168h")
+	t.Setenv("LOG_STDOUT", "false")
+	t.Setenv("LOG_SAMPLE_EVERY", "10")
+
+	cfg := Load()
+	if cfg.LogFile != "/var/log/infrantery/app.log" || cfg.LogMaxSizeMB != 50 || cfg.LogMaxBackups != 3 ||
+		cfg.LogMaxAge != 168*time.Hour || cfg.LogStdout || cfg.LogSampleEvery != 10 {
+		t.Errorf("log settings = file %q, size %d, backups %d, age %v, stdout %v, sample %d",
+			cfg.LogFile, cfg.LogMaxSizeMB, cfg.LogMaxBackups, cfg.LogMaxAge, cfg.LogStdout, cfg.LogSampleEvery)
+	}
+}
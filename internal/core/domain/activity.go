@@ -0,0 +1,37 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ActivityType identifies the kind of resource an activity entry refers to
+type ActivityType string
+
+const (
+	ActivityTypeDiagram ActivityType = "diagram"
+	ActivityTypeNote    ActivityType = "note"
+	ActivityTypeVault   ActivityType = "vault"
+)
+
+// ActivityAction tells whether the resource was created or later modified
+type ActivityAction string
+
+const (
+	ActivityActionCreated ActivityAction = "created"
+	ActivityActionUpdated ActivityAction = "updated"
+)
+
+// Activity is a read-only view of a recently changed project resource.
+// It is assembled from the resource collections and never stored.
+type Activity struct {
+	Type       ActivityType
+	ResourceID primitive.ObjectID
+	// ParentID points at the owning resource when the entry cannot be
+	// opened on its own (the node of a vault item)
+	ParentID  *primitive.ObjectID
+	Label     string
+	Action    ActivityAction
+	Timestamp time.Time
+}
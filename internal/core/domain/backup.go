@@ -2,8 +2,18 @@ package domain
 
 import "time"
 
-// BackupVersion is the current backup format version.
-const BackupVersion = 1
+// Backup format versions. Version 2 added the unencrypted manifest, version 3
+// the compression algorithm byte, version 4 the pepper ID byte and version 5
+// the trailing checksum. Every version from BackupVersionV1 to BackupVersion
+// is accepted on restore; older archives are always zstd-compressed, use the
+// built-in pepper, and have their payloads upgraded before insert.
+const (
+	BackupVersionV1 = 1
+	BackupVersionV2 = 2
+	BackupVersionV3 = 3
+	BackupVersionV4 = 4
+	BackupVersion   = 5
+)
 
 // BackupMagic is the magic header bytes for backup files.
 var BackupMagic = []byte("INFBK")
@@ -14,17 +24,83 @@ var BackupMagic = []byte("INFBK")
 // they cannot derive the correct encryption key without this pepper.
 var BackupPepper = []byte("infrantery:backup:v1:a9f2c8e1-4d7b-4f3a-b5e6-8c1d9e0f7a2b")
 
+// BuiltinBackupPepperID identifies BackupPepper in archive headers. Archives
+// written before peppers could be rotated all use it.
+const BuiltinBackupPepperID = 0
+
+// Backup scopes. Archives without a scope predate diagram exports and are
+// treated as whole-project backups.
+const (
+	BackupScopeProject = "project"
+	BackupScopeDiagram = "diagram"
+)
+
 // BackupPayload is the top-level structure serialized to JSON
 // before compression and encryption.
 type BackupPayload struct {
-	Version   int             `json:"version"`
-	CreatedAt time.Time       `json:"created_at"`
-	Project   ProjectBackup   `json:"project"`
-	Member    MemberBackup    `json:"member"`
-	Diagrams  []DiagramBackup `json:"diagrams"`
-	Nodes     []NodeBackup    `json:"nodes"`
-	Vaults    []VaultBackup   `json:"vaults"`
-	Notes     []NoteBackup    `json:"notes"`
+	Version       int             `json:"version"`
+	Scope         string          `json:"scope,omitempty"`
+	RootDiagramID string          `json:"root_diagram_id,omitempty"`
+	CreatedAt     time.Time       `json:"created_at"`
+	Project       ProjectBackup   `json:"project"`
+	Member        MemberBackup    `json:"member"`
+	Diagrams      []DiagramBackup `json:"diagrams"`
+	Nodes         []NodeBackup    `json:"nodes"`
+	Vaults        []VaultBackup   `json:"vaults"`
+	Notes         []NoteBackup    `json:"notes"`
+}
+
+// IsDiagramScoped reports whether the payload holds a single diagram subtree
+// that must be imported into an existing project.
+func (p *BackupPayload) IsDiagramScoped() bool {
+	return p.Scope == BackupScopeDiagram
+}
+
+// BackupManifest is the unencrypted summary stored after the archive header
+// so backup files can be told apart without the password. It must only hold
+// descriptive metadata; all content stays in the encrypted payload.
+type BackupManifest struct {
+	Version     int          `json:"version"`
+	Scope       string       `json:"scope"`
+	ProjectName string       `json:"project_name"`
+	CreatedAt   time.Time    `json:"created_at"`
+	Counts      BackupCounts `json:"counts"`
+}
+
+// BackupCounts holds the number of items of each kind in a backup.
+type BackupCounts struct {
+	Diagrams int `json:"diagrams"`
+	Nodes    int `json:"nodes"`
+	Vaults   int `json:"vaults"`
+	Notes    int `json:"notes"`
+}
+
+// BackupVerification reports which checks a backup file passed.
+type BackupVerification struct {
+	// Version is the archive format version.
+	Version int
+	// ChecksumVerified is false for archives older than version 5, which
+	// carry no checksum.
+	ChecksumVerified bool
+	// PasswordVerified is set when a password was given and decrypted the
+	// payload.
+	PasswordVerified bool
+}
+
+// Manifest summarizes the payload for the unencrypted archive section.
+func (p *BackupPayload) Manifest() BackupManifest {
+	return BackupManifest{
+		Version:     p.Version,
+		Scope:       p.Scope,
+		ProjectName: p.Project.Name,
+		CreatedAt:   p.CreatedAt,
+		Counts: BackupCounts{
+			Diagrams: len(p.Diagrams),
+			Nodes:    len(p.Nodes),
+			Vaults:   len(p.Vaults),
+			Notes:    len(p.Notes),
+		},
+	}
 }
 
 // ProjectBackup is the portable representation of a Project.
@@ -69,6 +145,7 @@ type DiagramBackup struct {
 type NodeBackup struct {
 	ID                       string `json:"id"`
 	DiagramID                string `json:"diagram_id"`
+	Label                    string `json:"label,omitempty"`
 	EncryptedReadme          string `json:"encrypted_readme"`
 	EncryptedReadmeSignature string `json:"encrypted_readme_signature"`
 	EncryptedDict            string `json:"encrypted_dict"`
@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BackupArchive records an encrypted backup persisted to server-side
+// storage. The archive bytes live in the storage backend under Key.
+type BackupArchive struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID primitive.ObjectID `json:"project_id" bson:"project_id"`
+	Key       string             `json:"key" bson:"key"`
+	Filename  string             `json:"filename" bson:"filename"`
+	Size      int64              `json:"size" bson:"size"`
+	CreatedBy primitive.ObjectID `json:"created_by" bson:"created_by"`
+
+	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// BackupSchedule configures automatic backups for a project.
+//
+// Scheduled backups are encrypted with BackupKey, a server-generated secret
+// stored alongside the schedule. This is a weaker trust model than manual
+// backups, whose password never reaches the database: anyone with database
+// access can decrypt scheduled archives. Project content inside the archive
+// remains end-to-end encrypted either way.
+type BackupSchedule struct {
+	ID            primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID     primitive.ObjectID `json:"project_id" bson:"project_id"`
+	Enabled       bool               `json:"enabled" bson:"enabled"`
+	IntervalHours int                `json:"interval_hours" bson:"interval_hours"`
+	BackupKey     string             `json:"-" bson:"backup_key"`
+	// ConfiguredBy is the member whose keyrings are embedded in each backup
+	ConfiguredBy primitive.ObjectID `json:"configured_by" bson:"configured_by"`
+	NextRunAt    time.Time          `json:"next_run_at" bson:"next_run_at"`
+	LastRunAt    *time.Time         `json:"last_run_at,omitempty" bson:"last_run_at,omitempty"`
+	LastError    string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+
+	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
+}
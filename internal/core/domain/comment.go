@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Comment target types: what a comment is attached to
+const (
+	CommentTargetProject = "project"
+	CommentTargetDiagram = "diagram"
+	CommentTargetNode    = "node"
+)
+
+// IsValidCommentTarget reports whether t is one of the comment target types
+func IsValidCommentTarget(t string) bool {
+	return t == CommentTargetProject || t == CommentTargetDiagram || t == CommentTargetNode
+}
+
+// CommentTarget identifies what a comment is attached to. Project comments
+// use the project ID as their target ID.
+type CommentTarget struct {
+	Type string
+	ID   primitive.ObjectID
+}
+
+// Comment is a member's remark on a project, diagram or node. The body is
+// encrypted with the project key like every other piece of content. Replies
+// point at the top-level comment of their thread; threads are one level deep.
+type Comment struct {
+	ID         primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
+	ProjectID  primitive.ObjectID `bson:"project_id" json:"project_id"`
+	TargetType string             `bson:"target_type" json:"target_type"`
+	TargetID   primitive.ObjectID `bson:"target_id" json:"target_id"`
+	// DiagramID is the diagram the target lives in, so comments can be
+	// removed with their diagram; unset for project comments
+	DiagramID              primitive.ObjectID `bson:"diagram_id,omitempty" json:"-"`
+	ParentCommentID        primitive.ObjectID `bson:"parent_comment_id,omitempty" json:"parent_comment_id,omitempty"`
+	AuthorID               primitive.ObjectID `bson:"author_id" json:"author_id"`
+	EncryptedBody          string             `bson:"encrypted_body" json:"encrypted_body"`
+	EncryptedBodySignature string             `bson:"encrypted_body_signature" json:"encrypted_body_signature"`
+
+	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
+}
+
+// Target returns what the comment is attached to
+func (c *Comment) Target() CommentTarget {
+	return CommentTarget{Type: c.TargetType, ID: c.TargetID}
+}
+
+// IsReply reports whether the comment answers another one
+func (c *Comment) IsReply() bool {
+	return !c.ParentCommentID.IsZero()
+}
@@ -14,6 +14,10 @@ type Diagram struct {
 	Description            string              `bson:"description" json:"description"`
 	EncryptedData          *string             `bson:"encrypted_data,omitempty" json:"encrypted_data,omitempty"`
 	EncryptedDataSignature string              `bson:"encrypted_data_signature" json:"encrypted_data_signature"`
+	// CreatedBy and UpdatedBy are the members who created the diagram and
+	// last changed it. Diagrams from before attribution have neither.
+	CreatedBy primitive.ObjectID `bson:"created_by,omitempty" json:"created_by"`
+	UpdatedBy primitive.ObjectID `bson:"updated_by,omitempty" json:"updated_by"`
 
 	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
 	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// DiagramLock is an explicit edit lock on a diagram. While it is held, only
+// HolderID may change the diagram or its nodes; it lapses at ExpiresAt unless
+// the holder renews it.
+type DiagramLock struct {
+	DiagramID primitive.ObjectID
+	ProjectID primitive.ObjectID
+	HolderID  primitive.ObjectID
+	ExpiresAt time.Time
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// IdempotencyRecord stores the response of a create request made with an
+// Idempotency-Key so retries within the TTL replay it instead of creating
+// a duplicate. Keys are scoped per user.
+type IdempotencyRecord struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	UserID      primitive.ObjectID `json:"user_id" bson:"user_id"`
+	Key         string             `json:"key" bson:"key"`
+	Method      string             `json:"method" bson:"method"`
+	Path        string             `json:"path" bson:"path"`
+	StatusCode  int                `json:"status_code" bson:"status_code"`
+	ContentType string             `json:"content_type" bson:"content_type"`
+	Body        []byte             `json:"body" bson:"body"`
+	ExpiresAt   time.Time          `json:"expires_at" bson:"expires_at"`
+
+	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
+}
@@ -6,10 +6,12 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Invitation statuses. Only pending invitations can be accepted or declined.
 const (
 	InvitationStatusPending  = "pending"
 	InvitationStatusAccepted = "accepted"
 	InvitationStatusExpired  = "expired"
+	InvitationStatusDeclined = "declined"
 )
 
 type Invitation struct {
@@ -26,3 +28,11 @@ type Invitation struct {
 	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
 	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
 }
+
+// InvitationDraft is one invitation of a bulk request
+type InvitationDraft struct {
+	InviteeUserID     primitive.ObjectID
+	Role              string
+	Permissions       []string
+	EncryptedKeyrings string
+}
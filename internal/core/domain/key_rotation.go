@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// KeyRotation is an audit record of a project key epoch rotation.
+type KeyRotation struct {
+	ID          primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID   primitive.ObjectID `json:"project_id" bson:"project_id"`
+	Epoch       string             `json:"epoch" bson:"epoch"`
+	RotatedBy   primitive.ObjectID `json:"rotated_by" bson:"rotated_by"`
+	RotatedAt   time.Time          `json:"rotated_at" bson:"rotated_at"`
+	MemberCount int                `json:"member_count" bson:"member_count"`
+
+	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
+}
@@ -6,10 +6,13 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
-// Node represents a node in a diagram with encrypted extended data
+// Node represents a node in a diagram with encrypted extended data. Label is
+// a short plaintext display name the server can show in breadcrumbs; it is
+// not encrypted, so clients should not put secrets in it.
 type Node struct {
 	ID                       primitive.ObjectID `bson:"_id" json:"id"`
 	DiagramID                primitive.ObjectID `bson:"diagram_id" json:"diagram_id"`
+	Label                    string             `bson:"label,omitempty" json:"label,omitempty"`
 	EncryptedReadme          string             `bson:"encrypted_readme" json:"encrypted_readme"`
 	EncryptedReadmeSignature string             `bson:"encrypted_readme_signature" json:"encrypted_readme_signature"`
 	EncryptedDict            string             `bson:"encrypted_dict" json:"encrypted_dict"`
@@ -18,3 +21,13 @@ type Node struct {
 	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
 	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
 }
+
+// NodeContentUpdate holds the node fields to change; nil fields are left as
+// they are
+type NodeContentUpdate struct {
+	Label                    *string
+	EncryptedReadme          *string
+	EncryptedReadmeSignature *string
+	EncryptedDict            *string
+	EncryptedDictSignature   *string
+}
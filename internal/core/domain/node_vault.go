@@ -6,6 +6,26 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Known vault item types. Clients choose the type when creating an item;
+// list filters only accept these values.
+const (
+	VaultTypePassword    = "password"
+	VaultTypeAPIKey      = "api_key"
+	VaultTypeSSHKey      = "ssh_key"
+	VaultTypeCertificate = "certificate"
+	VaultTypeToken       = "token"
+	VaultTypeText        = "text"
+)
+
+// IsValidVaultType reports whether t is one of the known vault item types.
+func IsValidVaultType(t string) bool {
+	switch t {
+	case VaultTypePassword, VaultTypeAPIKey, VaultTypeSSHKey, VaultTypeCertificate, VaultTypeToken, VaultTypeText:
+		return true
+	}
+	return false
+}
+
 type NodeVault struct {
 	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	NodeId primitive.ObjectID `bson:"node_id" json:"node_id"`
@@ -15,6 +35,14 @@ type NodeVault struct {
 	Type                    string             `bson:"type" json:"type"`
 	EncryptedValue          *string            `bson:"encrypted_value,omitempty" json:"encrypted_value,omitempty"`
 	EncryptedValueSignature *string            `bson:"encrypted_value_signature,omitempty" json:"encrypted_value_signature,omitempty"`
+	// Version is incremented on every update and guards against concurrent
+	// edits overwriting each other. Items created before versioning have no
+	// version field; omitempty keeps mgod from rejecting them as missing a
+	// required field, so they read as 0 and are matched as version 0 on update.
+	Version int `bson:"version,omitempty" json:"version"`
+	// Members who created the item and last changed it; unset on older items
+	CreatedBy primitive.ObjectID `bson:"created_by,omitempty" json:"created_by"`
+	UpdatedBy primitive.ObjectID `bson:"updated_by,omitempty" json:"updated_by"`
 
 	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
 	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
@@ -6,16 +6,42 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Note types. Only folders can hold other notes.
+const (
+	NoteTypeNote   = "note"
+	NoteTypeFolder = "folder"
+)
+
+// IsValidNoteType reports whether t is one of the note types
+func IsValidNoteType(t string) bool {
+	return t == NoteTypeNote || t == NoteTypeFolder
+}
+
 type Note struct {
 	ID                        primitive.ObjectID  `bson:"_id,omitempty" json:"id,omitempty"`
 	ProjectID                 primitive.ObjectID  `bson:"project_id" json:"project_id"`
 	ParentID                  *primitive.ObjectID `bson:"parent_id,omitempty" json:"parent_id,omitempty"`
-	Type                      string              `bson:"type" json:"type"` // "note" or "folder"
+	Type                      string              `bson:"type" json:"type"` // NoteTypeNote or NoteTypeFolder
 	FileName                  string              `bson:"file_name" json:"file_name"`
 	Icon                      string              `bson:"icon,omitempty" json:"icon"`
 	EncryptedContent          *string             `bson:"encrypted_content,omitempty" json:"encrypted_content,omitempty"`
 	EncryptedContentSignature *string             `bson:"encrypted_content_signature" json:"encrypted_content_signature"`
+	// Members who created the note and last changed it; unset on older notes
+	CreatedBy primitive.ObjectID `bson:"created_by,omitempty" json:"created_by"`
+	UpdatedBy primitive.ObjectID `bson:"updated_by,omitempty" json:"updated_by"`
 
 	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
 	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
 }
+
+// NoteUpdate holds the note fields to change; nil fields are left as they
+// are. A ParentID of primitive.NilObjectID moves the note to the top level.
+// UpdatedBy is recorded only when something else changes.
+type NoteUpdate struct {
+	FileName                  *string
+	ParentID                  *primitive.ObjectID
+	Icon                      *string
+	EncryptedContent          *string
+	EncryptedContentSignature *string
+	UpdatedBy                 primitive.ObjectID
+}
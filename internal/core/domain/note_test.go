@@ -0,0 +1,18 @@
+package domain
+
+import "testing"
+
+func TestIsValidNoteType(t *testing.T) {
+	tests := map[string]bool{
+		NoteTypeNote:   true,
+		NoteTypeFolder: true,
+		"Folder":       false,
+		"markdown":     false,
+		"":             false,
+	}
+	for noteType, want := range tests {
+		if got := IsValidNoteType(noteType); got != want {
+			t.Errorf("IsValidNoteType(%q) = %v, want %v", noteType, got, want)
+		}
+	}
+}
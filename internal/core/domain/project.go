@@ -1,6 +1,7 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -10,13 +11,123 @@ type Project struct {
 	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	Name        string             `bson:"name" json:"name"`
 	Description string             `bson:"description" json:"description"`
+	// Plaintext labels for organizing projects, normalized by
+	// NormalizeProjectTags
+	Tags []string `bson:"tags,omitempty" json:"tags,omitempty"`
 
 	KeyEpoch string `bson:"key_epoch" json:"key_epoch"`
 
+	// A pending deletion request. The project is purged once
+	// DeletionScheduledAt passes unless an owner cancels first.
+	DeletionRequestedBy primitive.ObjectID `bson:"deletion_requested_by,omitempty" json:"deletion_requested_by,omitempty"`
+	DeletionRequestedAt *time.Time         `bson:"deletion_requested_at,omitempty" json:"deletion_requested_at,omitempty"`
+	DeletionScheduledAt *time.Time         `bson:"deletion_scheduled_at,omitempty" json:"deletion_scheduled_at,omitempty"`
+
+	// Set while the project sits in the recycle bin. It can be restored
+	// until PurgeAt, after which it and everything in it is removed.
+	DeletedAt *time.Time `bson:"deleted_at,omitempty" json:"deleted_at,omitempty"`
+	PurgeAt   *time.Time `bson:"purge_at,omitempty" json:"purge_at,omitempty"`
+
+	// Set while the project is archived. Archived projects are left out of
+	// the default project list but otherwise work as usual.
+	ArchivedAt *time.Time `bson:"archived_at,omitempty" json:"archived_at,omitempty"`
+
 	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
 	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
 }
 
+// DeletionPending reports whether an owner has requested deletion
+func (p *Project) DeletionPending() bool {
+	return p.DeletionScheduledAt != nil
+}
+
+// IsArchived reports whether the project is archived
+func (p *Project) IsArchived() bool {
+	return p.ArchivedAt != nil
+}
+
+// NormalizeProjectTag trims and lowercases a tag so tags compare regardless
+// of case and spacing
+func NormalizeProjectTag(tag string) string {
+	return strings.ToLower(strings.TrimSpace(tag))
+}
+
+// NormalizeProjectTags trims and lowercases tags and drops blanks and
+// duplicates, keeping the first occurrence's position. A nil slice stays nil.
+func NormalizeProjectTags(tags []string) []string {
+	if tags == nil {
+		return nil
+	}
+
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = NormalizeProjectTag(tag)
+		if tag == "" {
+			continue
+		}
+		if _, dup := seen[tag]; dup {
+			continue
+		}
+		seen[tag] = struct{}{}
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+// Project list filters by the caller's membership
+const (
+	ProjectRoleFilterAll    = "all"
+	ProjectRoleFilterOwner  = "owner"
+	ProjectRoleFilterMember = "member"
+)
+
+// IsValidProjectRoleFilter reports whether f is one of the known project list
+// filters. The empty string means all.
+func IsValidProjectRoleFilter(f string) bool {
+	switch f {
+	case "", ProjectRoleFilterAll, ProjectRoleFilterOwner, ProjectRoleFilterMember:
+		return true
+	}
+	return false
+}
+
+// Project list orders. Recent sorts by UpdatedAt, which also moves when the
+// project's diagrams, nodes, notes or vault items change.
+const (
+	ProjectSortRecent  = "recent"
+	ProjectSortName    = "name"
+	ProjectSortCreated = "created"
+)
+
+// IsValidProjectSort reports whether s is one of the project list orders.
+// The empty string means recent.
+func IsValidProjectSort(s string) bool {
+	switch s {
+	case "", ProjectSortRecent, ProjectSortName, ProjectSortCreated:
+		return true
+	}
+	return false
+}
+
+// ProjectListQuery selects and orders a user's project list. Favorites
+// always come first, each group in Sort order.
+type ProjectListQuery struct {
+	Role            string // one of the ProjectRoleFilter values
+	Sort            string // one of the ProjectSort values
+	Tag             string // keeps only projects carrying the tag when set
+	IncludeArchived bool
+	FavoritesOnly   bool
+}
+
+// MemberProject is a project together with the caller's role in it and
+// whether the caller marked it as a favorite
+type MemberProject struct {
+	Project  *Project
+	Role     string
+	Favorite bool
+}
+
 type MemberKeyringUpdate struct {
 	UserID              string
 	EncryptedPassphrase string
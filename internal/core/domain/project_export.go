@@ -0,0 +1,48 @@
+package domain
+
+import "time"
+
+// ProjectExportVersion is the version of the ProjectExport document layout.
+const ProjectExportVersion = 1
+
+// ProjectExport is a plain JSON dump of a project's structure for client-side
+// processing. Unlike a backup it is neither compressed nor encrypted as a
+// whole; every encrypted field is included as stored, so only a client that
+// holds the project keys can turn it into readable content.
+//
+// Diagrams and notes are nested into trees. Sections the exporting member may
+// not view are left empty and not listed in Sections.
+type ProjectExport struct {
+	Version    int             `json:"version"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Sections   []string        `json:"sections"`
+	Project    ProjectBackup   `json:"project"`
+	Diagrams   []DiagramExport `json:"diagrams"`
+	Notes      []NoteExport    `json:"notes"`
+}
+
+// Sections of a ProjectExport.
+const (
+	ExportSectionDiagrams = "diagrams"
+	ExportSectionVaults   = "vaults"
+	ExportSectionNotes    = "notes"
+)
+
+// DiagramExport is a diagram with its nodes and child diagrams.
+type DiagramExport struct {
+	DiagramBackup
+	Nodes    []NodeExport    `json:"nodes"`
+	Children []DiagramExport `json:"children"`
+}
+
+// NodeExport is a node with its vault items.
+type NodeExport struct {
+	NodeBackup
+	Vaults []VaultBackup `json:"vaults"`
+}
+
+// NoteExport is a note or folder with its children.
+type NoteExport struct {
+	NoteBackup
+	Children []NoteExport `json:"children"`
+}
@@ -6,21 +6,83 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// Built-in member roles
 const (
-	PermissionViewDiagram   = "view_diagram"
-	PermissionEditDiagram   = "edit_diagram"
-	PermissionViewNote      = "view_note"
-	PermissionEditNote      = "edit_note"
-	PermissionViewVault     = "view_vault"
-	PermissionEditVault     = "edit_vault"
-	PermissionManageProject = "manage_project"
+	RoleOwner  = "owner"
+	RoleEditor = "editor"
+	RoleViewer = "viewer"
+	RoleCustom = "custom"
 )
 
+// Permission identifies a single capability a member holds in a project
+type Permission string
+
+const (
+	PermissionViewDiagram   Permission = "view_diagram"
+	PermissionEditDiagram   Permission = "edit_diagram"
+	PermissionViewNote      Permission = "view_note"
+	PermissionEditNote      Permission = "edit_note"
+	PermissionViewVault     Permission = "view_vault"
+	PermissionEditVault     Permission = "edit_vault"
+	PermissionManageProject Permission = "manage_project"
+)
+
+// AllPermissions lists every permission in a stable order
+var AllPermissions = []Permission{
+	PermissionViewDiagram,
+	PermissionEditDiagram,
+	PermissionViewNote,
+	PermissionEditNote,
+	PermissionViewVault,
+	PermissionEditVault,
+	PermissionManageProject,
+}
+
+// PermissionDescriptions explains each permission for role pickers
+var PermissionDescriptions = map[Permission]string{
+	PermissionViewDiagram:   "View diagrams and their nodes",
+	PermissionEditDiagram:   "Create, edit and delete diagrams and nodes",
+	PermissionViewNote:      "View notes",
+	PermissionEditNote:      "Create, edit and delete notes",
+	PermissionViewVault:     "View node vault items",
+	PermissionEditVault:     "Create, edit and delete node vault items",
+	PermissionManageProject: "Manage project settings, members, invitations and backups",
+}
+
+// ValidPermissions is the set of permissions recognised by the system
+var ValidPermissions = map[Permission]struct{}{
+	PermissionViewDiagram:   {},
+	PermissionEditDiagram:   {},
+	PermissionViewNote:      {},
+	PermissionEditNote:      {},
+	PermissionViewVault:     {},
+	PermissionEditVault:     {},
+	PermissionManageProject: {},
+}
+
+// IsValidPermission reports whether the given value is a known permission
+func IsValidPermission(permission string) bool {
+	_, ok := ValidPermissions[Permission(permission)]
+	return ok
+}
+
+// PermissionStrings converts permissions to their stored string form
+func PermissionStrings(permissions []Permission) []string {
+	out := make([]string, len(permissions))
+	for i, p := range permissions {
+		out[i] = string(p)
+	}
+	return out
+}
+
 type ProjectMember struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id,omitempty"`
 	ProjectID   primitive.ObjectID `bson:"project_id" json:"project_id"`
 	UserID      primitive.ObjectID `bson:"user_id" json:"user_id"`
 	Permissions []string           `bson:"permissions" json:"permissions"`
 	Role        string             `bson:"role" json:"role"` // Optional preset name
+	// CustomRole names the project's ProjectRole the permissions come from
+	CustomRole string `bson:"custom_role,omitempty" json:"custom_role,omitempty"`
 
 	// User key pair
 	PublicKey string `bson:"public_key" json:"public_key"`
@@ -29,10 +91,41 @@ type ProjectMember struct {
 
 	Keyrings []ProjectMemberKeyring `bson:"keyrings,omitempty" json:"keyrings"`
 
+	// ProjectDeleted mirrors the project's soft-delete so authorization and
+	// listings can skip the membership without loading the project
+	ProjectDeleted bool `bson:"project_deleted,omitempty" json:"-"`
+
+	// Favorite pins the project to the top of this member's project list
+	Favorite bool `bson:"favorite,omitempty" json:"favorite"`
+
 	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
 	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
 }
 
+// IsOwner reports whether the member owns the project
+func (m *ProjectMember) IsOwner() bool {
+	return m.Role == RoleOwner
+}
+
+// HasPermission reports whether the member was explicitly granted the permission
+func (m *ProjectMember) HasPermission(permission Permission) bool {
+	for _, p := range m.Permissions {
+		if p == string(permission) {
+			return true
+		}
+	}
+	return false
+}
+
+// EffectivePermissions returns the permissions the member actually holds.
+// Owners hold every permission regardless of their stored list.
+func (m *ProjectMember) EffectivePermissions() []string {
+	if m.IsOwner() {
+		return PermissionStrings(AllPermissions)
+	}
+	return m.Permissions
+}
+
 type ProjectMemberKeyring struct {
 	Epoch string `bson:"epoch" json:"epoch"`
 
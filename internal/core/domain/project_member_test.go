@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestPermissionSetsAgree(t *testing.T) {
+	if len(ValidPermissions) != len(AllPermissions) {
+		t.Errorf("%d valid permissions, %d listed", len(ValidPermissions), len(AllPermissions))
+	}
+	for _, permission := range AllPermissions {
+		if !IsValidPermission(string(permission)) {
+			t.Errorf("%s is listed but not valid", permission)
+		}
+		if PermissionDescriptions[permission] == "" {
+			t.Errorf("%s has no description", permission)
+		}
+	}
+	for _, unknown := range []string{"", "admin", "View_Diagram", "edit_diagrams"} {
+		if IsValidPermission(unknown) {
+			t.Errorf("%q is accepted as a permission", unknown)
+		}
+	}
+}
+
+func TestProjectMemberPermissions(t *testing.T) {
+	owner := &ProjectMember{Role: RoleOwner}
+	editor := &ProjectMember{Role: RoleEditor, Permissions: []string{string(PermissionViewNote), string(PermissionEditNote)}}
+
+	if !editor.HasPermission(PermissionEditNote) || editor.HasPermission(PermissionManageProject) {
+		t.Errorf("editor permissions %v answered wrongly", editor.Permissions)
+	}
+	if !slices.Equal(editor.EffectivePermissions(), editor.Permissions) {
+		t.Errorf("editor effective permissions = %v, want the stored list", editor.EffectivePermissions())
+	}
+
+	// HasPermission reads the stored list only; the owner rule lives in
+	// EffectivePermissions and the authorization service
+	if owner.HasPermission(PermissionManageProject) {
+		t.Error("HasPermission granted an owner a permission it was not given")
+	}
+	if got := owner.EffectivePermissions(); !slices.Equal(got, PermissionStrings(AllPermissions)) {
+		t.Errorf("owner effective permissions = %v, want all", got)
+	}
+}
@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ProjectRole is a named permission set a project defines for its members,
+// such as "Auditor". Members holding it have the RoleCustom role, the role's
+// name in CustomRole and a copy of its permissions, kept in sync when the
+// role changes.
+type ProjectRole struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ProjectID   primitive.ObjectID `bson:"project_id" json:"project_id"`
+	Name        string             `bson:"name" json:"name"`
+	Permissions []string           `bson:"permissions" json:"permissions"`
+
+	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
+}
+
+// IsReservedRoleName reports whether name clashes with a built-in role
+func IsReservedRoleName(name string) bool {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case RoleOwner, RoleEditor, RoleViewer, RoleCustom:
+		return true
+	}
+	return false
+}
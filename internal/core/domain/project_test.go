@@ -0,0 +1,42 @@
+package domain
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestIsValidProjectSort(t *testing.T) {
+	tests := map[string]bool{
+		"":                 true,
+		ProjectSortRecent:  true,
+		ProjectSortName:    true,
+		ProjectSortCreated: true,
+		"updated":          false,
+		"Name":             false,
+	}
+	for sort, want := range tests {
+		if got := IsValidProjectSort(sort); got != want {
+			t.Errorf("IsValidProjectSort(%q) = %v, want %v", sort, got, want)
+		}
+	}
+}
+
+func TestNormalizeProjectTags(t *testing.T) {
+	tests := []struct {
+		name string
+		tags []string
+		want []string
+	}{
+		{name: "nil stays nil", tags: nil, want: nil},
+		{name: "empty clears", tags: []string{}, want: []string{}},
+		{name: "case and spacing", tags: []string{" Prod ", "k8s", "PROD", "  ", "k8s"}, want: []string{"prod", "k8s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NormalizeProjectTags(tt.tags)
+			if (got == nil) != (tt.want == nil) || !slices.Equal(got, tt.want) {
+				t.Errorf("NormalizeProjectTags(%q) = %#v, want %#v", tt.tags, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ShareLink grants read-only access to a single diagram to anyone holding
+// the token. Only a hash of the token is stored. The diagram stays
+// encrypted; the decryption key travels with the link on the client side.
+type ShareLink struct {
+	ID        primitive.ObjectID `json:"id" bson:"_id,omitempty"`
+	ProjectID primitive.ObjectID `json:"project_id" bson:"project_id"`
+	DiagramID primitive.ObjectID `json:"diagram_id" bson:"diagram_id"`
+	TokenHash string             `json:"-" bson:"token_hash"`
+	// Prefix is the start of the token, kept so members can tell links apart
+	Prefix       string             `json:"prefix" bson:"prefix"`
+	PasswordHash string             `json:"-" bson:"password_hash,omitempty"`
+	ExpiresAt    time.Time          `json:"expires_at" bson:"expires_at"`
+	CreatedBy    primitive.ObjectID `json:"created_by" bson:"created_by"`
+
+	CreatedAt time.Time `bson:"createdAt,omitempty" json:"created_at"`
+	UpdatedAt time.Time `bson:"updatedAt,omitempty" json:"updated_at"`
+}
+
+// HasPassword reports whether opening the link requires a password
+func (l *ShareLink) HasPassword() bool {
+	return l.PasswordHash != ""
+}
+
+// IsExpired reports whether the link has passed its expiry at now
+func (l *ShareLink) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
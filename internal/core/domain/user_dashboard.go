@@ -0,0 +1,24 @@
+package domain
+
+// UserDashboard summarizes a user's project memberships and pending
+// invitations
+type UserDashboard struct {
+	User *User
+	// ProjectsByRole counts the user's memberships for every member role
+	ProjectsByRole     map[string]int64
+	PendingInvitations int64
+}
+
+// TotalProjects is the number of projects the user belongs to
+func (d *UserDashboard) TotalProjects() int64 {
+	var total int64
+	for _, count := range d.ProjectsByRole {
+		total += count
+	}
+	return total
+}
+
+// OwnedProjects is the number of projects the user owns
+func (d *UserDashboard) OwnedProjects() int64 {
+	return d.ProjectsByRole[RoleOwner]
+}
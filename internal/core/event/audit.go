@@ -0,0 +1,17 @@
+package event
+
+import "github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+
+// AuditLog records every event in the structured log. Register it at
+// startup so each change leaves a trail even without an audit collection.
+func AuditLog(event Event) {
+	entry := logger.Info().
+		Str("event", string(event.Type)).
+		Str("project_id", event.ProjectID.Hex()).
+		Str("actor_id", logger.SanitizeUserID(event.ActorID.Hex())).
+		Time("occurred_at", event.OccurredAt)
+	if !event.ResourceID.IsZero() {
+		entry = entry.Str("resource_id", event.ResourceID.Hex())
+	}
+	entry.Msg("Audit event")
+}
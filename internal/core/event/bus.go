@@ -0,0 +1,211 @@
+// Package event is an in-process publish/subscribe bus for domain changes.
+// Services publish after a mutation has succeeded. Long-lived subscribers
+// (the audit log, webhook dispatchers) are registered once at startup with
+// Register; short-lived ones such as a project event stream use Subscribe.
+// Delivery is best-effort: a slow subscriber loses events rather than
+// slowing down or failing the request that produced them.
+package event
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Type names a kind of domain change. The values are part of the public
+// event stream, so they must stay stable.
+type Type string
+
+const (
+	ProjectCreated Type = "project.created"
+	ProjectUpdated Type = "project.updated"
+	ProjectDeleted Type = "project.deleted"
+	KeyRotated     Type = "project.key_rotated"
+
+	ProjectArchived   Type = "project.archived"
+	ProjectUnarchived Type = "project.unarchived"
+
+	ProjectDeletionRequested Type = "project.deletion_requested"
+	ProjectDeletionCancelled Type = "project.deletion_cancelled"
+	ProjectRestored          Type = "project.restored"
+	ProjectPurged            Type = "project.purged"
+
+	MemberAdded   Type = "member.added"
+	MemberUpdated Type = "member.updated"
+	MemberRemoved Type = "member.removed"
+
+	RoleCreated Type = "role.created"
+	RoleUpdated Type = "role.updated"
+	RoleDeleted Type = "role.deleted"
+
+	InvitationCreated  Type = "invitation.created"
+	InvitationRevoked  Type = "invitation.revoked"
+	InvitationDeclined Type = "invitation.declined"
+	InvitationAccepted Type = "invitation.accepted"
+
+	DiagramCreated Type = "diagram.created"
+	DiagramUpdated Type = "diagram.updated"
+	DiagramDeleted Type = "diagram.deleted"
+	// DiagramLocked and DiagramUnlocked tell editors a diagram turned
+	// read-only for them or became editable again
+	DiagramLocked   Type = "diagram.locked"
+	DiagramUnlocked Type = "diagram.unlocked"
+
+	NodeUpdated Type = "node.updated"
+	NodeDeleted Type = "node.deleted"
+
+	NoteCreated Type = "note.created"
+	NoteUpdated Type = "note.updated"
+	NoteDeleted Type = "note.deleted"
+
+	VaultItemCreated Type = "vault_item.created"
+	VaultItemUpdated Type = "vault_item.updated"
+	VaultItemDeleted Type = "vault_item.deleted"
+
+	CommentCreated Type = "comment.created"
+	CommentUpdated Type = "comment.updated"
+	CommentDeleted Type = "comment.deleted"
+
+	BackupCreated  Type = "backup.created"
+	BackupRestored Type = "backup.restored"
+)
+
+// Event describes a change to a project resource. It carries identifiers
+// only, never resource contents, so it is safe to fan out to any member.
+type Event struct {
+	Type       Type
+	ProjectID  primitive.ObjectID
+	ActorID    primitive.ObjectID
+	ResourceID primitive.ObjectID
+	OccurredAt time.Time
+}
+
+// Publisher is the side of the bus services depend on
+type Publisher interface {
+	Publish(event Event)
+}
+
+// Handler consumes events for a registered subscriber
+type Handler func(event Event)
+
+// Bus delivers every published event to all matching subscriptions
+type Bus struct {
+	mu       sync.RWMutex
+	subs     map[*Subscription]struct{}
+	handlers sync.WaitGroup
+}
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{subs: make(map[*Subscription]struct{})}
+}
+
+// Publish hands the event to every subscription whose filter accepts it.
+// It never blocks: subscriptions with a full buffer miss the event.
+func (b *Bus) Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now().UTC()
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for sub := range b.subs {
+		if sub.filter != nil && !sub.filter(event) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			logger.Warn().
+				Str("event", string(event.Type)).
+				Str("project_id", event.ProjectID.Hex()).
+				Msg("Dropped event for slow subscriber")
+		}
+	}
+}
+
+// Subscribe registers a subscription buffering up to buffer events. A nil
+// filter receives everything. The caller must Close the subscription.
+func (b *Bus) Subscribe(buffer int, filter func(Event) bool) *Subscription {
+	sub := &Subscription{
+		bus:    b,
+		events: make(chan Event, buffer),
+		filter: filter,
+	}
+
+	b.mu.Lock()
+	b.subs[sub] = struct{}{}
+	b.mu.Unlock()
+
+	return sub
+}
+
+// Register starts a named subscriber that handles every event on its own
+// goroutine until the bus is closed. A panicking handler is logged and
+// skips only the event that caused it.
+func (b *Bus) Register(name string, buffer int, handler Handler) {
+	sub := b.Subscribe(buffer, nil)
+
+	b.handlers.Add(1)
+	go func() {
+		defer b.handlers.Done()
+		for event := range sub.Events() {
+			handle(name, handler, event)
+		}
+	}()
+}
+
+// Close ends every subscription and waits for registered subscribers to
+// drain what they had already received
+func (b *Bus) Close() {
+	b.mu.RLock()
+	subs := make([]*Subscription, 0, len(b.subs))
+	for sub := range b.subs {
+		subs = append(subs, sub)
+	}
+	b.mu.RUnlock()
+
+	for _, sub := range subs {
+		sub.Close()
+	}
+	b.handlers.Wait()
+}
+
+func handle(name string, handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			logger.Error().
+				Interface("panic", r).
+				Str("subscriber", name).
+				Str("event", string(event.Type)).
+				Msg("Event subscriber panicked")
+		}
+	}()
+	handler(event)
+}
+
+// Subscription receives events from a Bus until closed
+type Subscription struct {
+	bus       *Bus
+	events    chan Event
+	filter    func(Event) bool
+	closeOnce sync.Once
+}
+
+// Events returns the delivery channel. It is closed by Close.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Close unregisters the subscription. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.closeOnce.Do(func() {
+		s.bus.mu.Lock()
+		delete(s.bus.subs, s)
+		s.bus.mu.Unlock()
+		close(s.events)
+	})
+}
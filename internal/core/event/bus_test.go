@@ -0,0 +1,114 @@
+package event
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// received drains what is already buffered on the subscription
+func received(sub *Subscription) []Event {
+	var events []Event
+	for {
+		select {
+		case event := <-sub.Events():
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestBusFiltersEvents(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	projectID := primitive.NewObjectID()
+	all := bus.Subscribe(4, nil)
+	project := bus.Subscribe(4, func(e Event) bool { return e.ProjectID == projectID })
+
+	bus.Publish(Event{Type: DiagramUpdated, ProjectID: projectID})
+	bus.Publish(Event{Type: DiagramUpdated, ProjectID: primitive.NewObjectID()})
+
+	if got := received(all); len(got) != 2 {
+		t.Errorf("unfiltered subscription got %d events, want 2", len(got))
+	}
+	got := received(project)
+	if len(got) != 1 || got[0].ProjectID != projectID {
+		t.Fatalf("filtered subscription got %+v, want the one event of its project", got)
+	}
+	if got[0].OccurredAt.IsZero() {
+		t.Error("Publish did not stamp OccurredAt")
+	}
+}
+
+func TestBusDropsEventsForFullSubscriber(t *testing.T) {
+	bus := NewBus()
+	defer bus.Close()
+
+	slow := bus.Subscribe(1, nil)
+	fast := bus.Subscribe(3, nil)
+
+	done := make(chan struct{})
+	go func() {
+		for _, eventType := range []Type{NoteCreated, NoteUpdated, NoteDeleted} {
+			bus.Publish(Event{Type: eventType})
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Publish blocked on a full subscriber")
+	}
+
+	if got := received(slow); len(got) != 1 || got[0].Type != NoteCreated {
+		t.Errorf("slow subscriber got %+v, want only the first event", got)
+	}
+	if got := received(fast); len(got) != 3 {
+		t.Errorf("fast subscriber got %d events, want 3", len(got))
+	}
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	bus := NewBus()
+	sub := bus.Subscribe(1, nil)
+
+	sub.Close()
+	sub.Close()
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("Events is still open after Close")
+	}
+
+	// Publishing after the subscription left must not panic on its channel
+	bus.Publish(Event{Type: ProjectUpdated})
+	bus.Close()
+}
+
+func TestBusCloseDrainsRegisteredSubscribers(t *testing.T) {
+	bus := NewBus()
+
+	var mu sync.Mutex
+	var handled []Type
+	bus.Register("test", 4, func(e Event) {
+		if e.Type == MemberAdded {
+			panic("subscriber failure")
+		}
+		mu.Lock()
+		handled = append(handled, e.Type)
+		mu.Unlock()
+	})
+
+	bus.Publish(Event{Type: MemberAdded})
+	bus.Publish(Event{Type: MemberRemoved})
+	bus.Close()
+
+	// Close waited for the handler, which survived the panic
+	mu.Lock()
+	defer mu.Unlock()
+	if len(handled) != 1 || handled[0] != MemberRemoved {
+		t.Errorf("handled = %v, want [%s]", handled, MemberRemoved)
+	}
+}
@@ -0,0 +1,15 @@
+package port
+
+import "context"
+
+// Notification is a plain-text message for one recipient
+type Notification struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Notifier delivers notifications outside the app, such as by email.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
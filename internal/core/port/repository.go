@@ -2,11 +2,17 @@ package port
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ErrVersionConflict is returned by versioned updates when the stored
+// document no longer matches the expected version.
+var ErrVersionConflict = errors.New("version conflict")
+
 type UserRepository interface {
 	Create(ctx context.Context, user *domain.User) error
 	FindByEmail(ctx context.Context, email string) (*domain.User, error)
@@ -15,19 +21,47 @@ type UserRepository interface {
 	Update(ctx context.Context, user *domain.User) error
 	ExistsByEmail(ctx context.Context, email string, excludeUserID primitive.ObjectID) (bool, error)
 	ExistsByUsername(ctx context.Context, username string, excludeUserID primitive.ObjectID) (bool, error)
-	SearchUsers(ctx context.Context, query string, limit int) ([]*domain.User, error)
+	SearchUsers(ctx context.Context, query string, excludeIDs []primitive.ObjectID, limit int) ([]*domain.User, error)
 }
 
 type InvitationRepository interface {
 	Create(ctx context.Context, invitation *domain.Invitation) (*domain.Invitation, error)
+	CreateMany(ctx context.Context, invitations []*domain.Invitation) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Invitation, error)
 	FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.Invitation, int64, error)
 	FindByInviteeID(ctx context.Context, inviteeUserID primitive.ObjectID, offset, limit int) ([]*domain.Invitation, int64, error)
 	FindByProjectAndInvitee(ctx context.Context, projectID, inviteeUserID primitive.ObjectID) (*domain.Invitation, error)
+	CountByInviteeAndStatus(ctx context.Context, inviteeUserID primitive.ObjectID, status string) (int64, error)
 	Update(ctx context.Context, invitation *domain.Invitation) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 }
 
+type BackupArchiveRepository interface {
+	Create(ctx context.Context, archive *domain.BackupArchive) (*domain.BackupArchive, error)
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.BackupArchive, error)
+	FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.BackupArchive, int64, error)
+	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
+}
+
+type BackupScheduleRepository interface {
+	Create(ctx context.Context, schedule *domain.BackupSchedule) error
+	FindByProjectID(ctx context.Context, projectID primitive.ObjectID) (*domain.BackupSchedule, error)
+	Update(ctx context.Context, schedule *domain.BackupSchedule) error
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.BackupSchedule, error)
+	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
+}
+
+type IdempotencyRepository interface {
+	Create(ctx context.Context, record *domain.IdempotencyRecord) error
+	FindActive(ctx context.Context, userID primitive.ObjectID, key string) (*domain.IdempotencyRecord, error)
+}
+
+type KeyRotationRepository interface {
+	Create(ctx context.Context, rotation *domain.KeyRotation) error
+	FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.KeyRotation, int64, error)
+	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
+}
+
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, token *domain.RefreshToken) error
 	FindByToken(ctx context.Context, token string) (*domain.RefreshToken, error)
@@ -35,18 +69,76 @@ type RefreshTokenRepository interface {
 	DeleteExpired(ctx context.Context) error
 }
 
+type PersonalAccessTokenRepository interface {
+	Create(ctx context.Context, token *domain.PersonalAccessToken) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.PersonalAccessToken, error)
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.PersonalAccessToken, error)
+	TouchLastUsed(ctx context.Context, id primitive.ObjectID, usedAt time.Time) error
+	Delete(ctx context.Context, id, userID primitive.ObjectID) (bool, error)
+}
+
+type ShareLinkRepository interface {
+	Create(ctx context.Context, link *domain.ShareLink) error
+	FindByHash(ctx context.Context, tokenHash string) (*domain.ShareLink, error)
+	FindByDiagramID(ctx context.Context, diagramID primitive.ObjectID) ([]*domain.ShareLink, error)
+	Delete(ctx context.Context, id, diagramID primitive.ObjectID) (bool, error)
+	DeleteByDiagramID(ctx context.Context, diagramID primitive.ObjectID) error
+	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
+}
+
+type ProjectRoleRepository interface {
+	Create(ctx context.Context, role *domain.ProjectRole) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.ProjectRole, error)
+	FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]*domain.ProjectRole, error)
+	FindByProjectAndName(ctx context.Context, projectID primitive.ObjectID, name string) (*domain.ProjectRole, error)
+	Update(ctx context.Context, role *domain.ProjectRole) error
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
+}
+
+type CommentRepository interface {
+	Create(ctx context.Context, comment *domain.Comment) error
+	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Comment, error)
+	FindByTargetAfter(ctx context.Context, projectID primitive.ObjectID, target domain.CommentTarget, parentID, afterID primitive.ObjectID, limit int) ([]*domain.Comment, error)
+	UpdateBody(ctx context.Context, id primitive.ObjectID, encryptedBody, signature string) (*domain.Comment, error)
+	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteByDiagramID(ctx context.Context, diagramID primitive.ObjectID) error
+	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
+}
+
 type ProjectRepository interface {
 	Create(ctx context.Context, project *domain.Project) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Project, error)
-	FindByUserID(ctx context.Context, userID primitive.ObjectID, offset, limit int) ([]*domain.Project, int64, error)
-	Update(ctx context.Context, project *domain.Project) error
+	FindByUserID(ctx context.Context, userID primitive.ObjectID, query domain.ProjectListQuery, offset, limit int) ([]*domain.MemberProject, int64, error)
+	FindActiveByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Project, error)
+	UpdateMetadata(ctx context.Context, projectID primitive.ObjectID, name, description *string, tags []string) (*domain.Project, error)
+	Touch(ctx context.Context, projectID primitive.ObjectID) error
+	UpdateKeyEpoch(ctx context.Context, projectID primitive.ObjectID, keyEpoch string) error
+	Archive(ctx context.Context, projectID primitive.ObjectID, archivedAt time.Time) (*domain.Project, error)
+	Unarchive(ctx context.Context, projectID primitive.ObjectID) (*domain.Project, error)
+	MarkForDeletion(ctx context.Context, projectID, requestedBy primitive.ObjectID, requestedAt, scheduledAt time.Time) (*domain.Project, error)
+	ClearDeletion(ctx context.Context, projectID primitive.ObjectID) (bool, error)
+	ClaimDueDeletion(ctx context.Context, now time.Time, lease time.Duration) (*domain.Project, error)
+	SoftDelete(ctx context.Context, projectID primitive.ObjectID, deletedAt, purgeAt time.Time) error
+	Restore(ctx context.Context, projectID primitive.ObjectID) (*domain.Project, error)
+	FindDeletedByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.MemberProject, error)
+	ClaimDuePurge(ctx context.Context, now time.Time, lease time.Duration) (*domain.Project, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
 }
 
 type ProjectMemberRepository interface {
 	Create(ctx context.Context, member *domain.ProjectMember) error
 	FindByProjectID(ctx context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.ProjectMember, int64, error)
+	FindByProjectIDAfter(ctx context.Context, projectID, afterID primitive.ObjectID, limit int) ([]*domain.ProjectMember, error)
 	FindByProjectAndUser(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error)
+	FindUserIDsByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]primitive.ObjectID, error)
+	FindInDeletedProject(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error)
+	FindByUserID(ctx context.Context, userID primitive.ObjectID) ([]*domain.ProjectMember, error)
+	SetProjectDeleted(ctx context.Context, projectID primitive.ObjectID, deleted bool) error
+	SetFavorite(ctx context.Context, projectID, userID primitive.ObjectID, favorite bool) (bool, error)
+	CountByUserAndRole(ctx context.Context, userID primitive.ObjectID, role string) (int64, error)
+	CountByCustomRole(ctx context.Context, projectID primitive.ObjectID, name string) (int64, error)
+	ApplyCustomRole(ctx context.Context, projectID primitive.ObjectID, name string, role *domain.ProjectRole) ([]primitive.ObjectID, error)
 	Update(ctx context.Context, member *domain.ProjectMember) error
 	Delete(ctx context.Context, projectID, userID primitive.ObjectID) error
 	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
@@ -55,19 +147,30 @@ type ProjectMemberRepository interface {
 type NoteRepository interface {
 	Create(ctx context.Context, note *domain.Note) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Note, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Note, error)
+	SearchByName(ctx context.Context, projectIDs []primitive.ObjectID, query string, limit int) ([]*domain.Note, error)
 	FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]*domain.Note, error)
-	Update(ctx context.Context, note *domain.Note) error
+	FindByProjectIDAfter(ctx context.Context, projectID, afterID primitive.ObjectID, limit int) ([]*domain.Note, error)
+	CountByProjectID(ctx context.Context, projectID primitive.ObjectID) (int64, error)
+	FindRecentByProjectID(ctx context.Context, projectID primitive.ObjectID, limit int) ([]*domain.Note, int64, error)
+	UpdateFields(ctx context.Context, noteID primitive.ObjectID, update domain.NoteUpdate) (*domain.Note, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteMany(ctx context.Context, ids []primitive.ObjectID) error
 	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
 }
 
 type DiagramRepository interface {
 	Create(ctx context.Context, diagram *domain.Diagram) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Diagram, error)
+	FindByIDs(ctx context.Context, ids []primitive.ObjectID) ([]*domain.Diagram, error)
+	SearchByName(ctx context.Context, projectIDs []primitive.ObjectID, query string, limit int) ([]*domain.Diagram, error)
 	FindByProjectID(ctx context.Context, projectID primitive.ObjectID, rootOnly bool, offset, limit int) ([]*domain.Diagram, int64, error)
+	FindByProjectIDAfter(ctx context.Context, projectID, afterID primitive.ObjectID, rootOnly bool, limit int) ([]*domain.Diagram, error)
 	FindAllByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]*domain.Diagram, error)
+	FindRecentByProjectID(ctx context.Context, projectID primitive.ObjectID, limit int) ([]*domain.Diagram, int64, error)
 	Update(ctx context.Context, diagram *domain.Diagram) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
+	DeleteMany(ctx context.Context, ids []primitive.ObjectID) error
 	DeleteByProjectID(ctx context.Context, projectID primitive.ObjectID) error
 }
 
@@ -76,16 +179,20 @@ type NodeRepository interface {
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.Node, error)
 	FindByDiagramID(ctx context.Context, diagramID primitive.ObjectID, offset, limit int) ([]*domain.Node, int64, error)
 	FindByDiagramIDs(ctx context.Context, diagramIDs []primitive.ObjectID) ([]*domain.Node, error)
-	Update(ctx context.Context, node *domain.Node) error
+	UpdateContent(ctx context.Context, nodeID primitive.ObjectID, update domain.NodeContentUpdate) (*domain.Node, error)
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	DeleteByDiagramID(ctx context.Context, diagramID primitive.ObjectID) error
 }
 
 type NodeVaultRepository interface {
 	Create(ctx context.Context, vault *domain.NodeVault) error
+	CreateMany(ctx context.Context, vaults []*domain.NodeVault) error
 	FindByID(ctx context.Context, id primitive.ObjectID) (*domain.NodeVault, error)
 	FindByNodeID(ctx context.Context, nodeID primitive.ObjectID) ([]*domain.NodeVault, error)
+	FindByNodeIDAndType(ctx context.Context, projectID, nodeID primitive.ObjectID, vaultType string) ([]*domain.NodeVault, error)
+	CountByNodeID(ctx context.Context, projectID, nodeID primitive.ObjectID) (int64, error)
 	FindByProjectID(ctx context.Context, projectID primitive.ObjectID) ([]*domain.NodeVault, error)
+	FindRecentByProjectID(ctx context.Context, projectID primitive.ObjectID, limit int) ([]*domain.NodeVault, int64, error)
 	Update(ctx context.Context, vault *domain.NodeVault) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	DeleteByNodeID(ctx context.Context, nodeID primitive.ObjectID) error
@@ -0,0 +1,17 @@
+package port
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrObjectNotFound is returned by BackupStorage when a key does not exist.
+var ErrObjectNotFound = errors.New("object not found")
+
+// BackupStorage persists encrypted backup archives under opaque keys.
+type BackupStorage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
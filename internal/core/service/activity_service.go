@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MaxActivityWindow caps how far back the activity feed can be paged.
+// Each collection is queried for at most this many recent documents.
+const MaxActivityWindow = 200
+
+// activityCreatedTolerance absorbs the gap between the createdAt and
+// updatedAt stamps written on insert
+const activityCreatedTolerance = time.Second
+
+type ActivityService struct {
+	diagramRepo   port.DiagramRepository
+	noteRepo      port.NoteRepository
+	nodeVaultRepo port.NodeVaultRepository
+	authz         *AuthorizationService
+}
+
+func NewActivityService(
+	diagramRepo port.DiagramRepository,
+	noteRepo port.NoteRepository,
+	nodeVaultRepo port.NodeVaultRepository,
+	authz *AuthorizationService,
+) *ActivityService {
+	return &ActivityService{
+		diagramRepo:   diagramRepo,
+		noteRepo:      noteRepo,
+		nodeVaultRepo: nodeVaultRepo,
+		authz:         authz,
+	}
+}
+
+// ListProjectActivity returns recently created or updated diagrams, notes and
+// vault items of a project, newest first. Only resource types the member may
+// view are included. The total is capped at MaxActivityWindow.
+func (s *ActivityService) ListProjectActivity(ctx context.Context, projectID, userID primitive.ObjectID, offset, limit int) ([]domain.Activity, int64, error) {
+	member, err := s.authz.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, 0, concealNonMember(err, ErrProjectNotFound)
+	}
+
+	// Every page is cut from the merged head of the three collections, so
+	// each one has to supply offset+limit entries
+	window := offset + limit
+	if window > MaxActivityWindow {
+		window = MaxActivityWindow
+	}
+	if offset >= window {
+		return []domain.Activity{}, 0, nil
+	}
+
+	var activities []domain.Activity
+	var total int64
+
+	if s.authz.Can(member, domain.PermissionViewDiagram) {
+		diagrams, count, err := s.diagramRepo.FindRecentByProjectID(ctx, projectID, window)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += count
+		for _, diagram := range diagrams {
+			activities = append(activities, newActivity(domain.ActivityTypeDiagram, diagram.ID, nil, diagram.DiagramName, diagram.CreatedAt, diagram.UpdatedAt))
+		}
+	}
+
+	if s.authz.Can(member, domain.PermissionViewNote) {
+		notes, count, err := s.noteRepo.FindRecentByProjectID(ctx, projectID, window)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += count
+		for _, note := range notes {
+			activities = append(activities, newActivity(domain.ActivityTypeNote, note.ID, nil, note.FileName, note.CreatedAt, note.UpdatedAt))
+		}
+	}
+
+	if s.authz.Can(member, domain.PermissionViewVault) {
+		vaults, count, err := s.nodeVaultRepo.FindRecentByProjectID(ctx, projectID, window)
+		if err != nil {
+			return nil, 0, err
+		}
+		total += count
+		for _, vault := range vaults {
+			nodeID := vault.NodeId
+			activities = append(activities, newActivity(domain.ActivityTypeVault, vault.ID, &nodeID, vault.Label, vault.CreatedAt, vault.UpdatedAt))
+		}
+	}
+
+	if total > MaxActivityWindow {
+		total = MaxActivityWindow
+	}
+
+	sort.SliceStable(activities, func(i, j int) bool {
+		return activities[i].Timestamp.After(activities[j].Timestamp)
+	})
+
+	if offset >= len(activities) {
+		return []domain.Activity{}, total, nil
+	}
+	end := offset + limit
+	if end > len(activities) {
+		end = len(activities)
+	}
+	if end > window {
+		end = window
+	}
+
+	return activities[offset:end], total, nil
+}
+
+func newActivity(activityType domain.ActivityType, id primitive.ObjectID, parentID *primitive.ObjectID, label string, createdAt, updatedAt time.Time) domain.Activity {
+	action := domain.ActivityActionUpdated
+	if updatedAt.Sub(createdAt) < activityCreatedTolerance {
+		action = domain.ActivityActionCreated
+	}
+
+	return domain.Activity{
+		Type:       activityType,
+		ResourceID: id,
+		ParentID:   parentID,
+		Label:      label,
+		Action:     action,
+		Timestamp:  updatedAt,
+	}
+}
@@ -3,12 +3,29 @@ package service
 import (
 	"crypto/rand"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"strings"
 
 	"golang.org/x/crypto/argon2"
 )
 
+// MaxPasswordLength is the hard upper bound, in bytes, on any password fed
+// to Argon2. Hashing cost grows with input length, so unbounded passwords
+// would let a single request burn CPU. Request DTOs use the same limit.
+const MaxPasswordLength = 1024
+
+var ErrPasswordTooLong = errors.New("password exceeds maximum length")
+
+// checkPasswordLength rejects passwords over MaxPasswordLength before any
+// key derivation runs
+func checkPasswordLength(password string) error {
+	if len(password) > MaxPasswordLength {
+		return ErrPasswordTooLong
+	}
+	return nil
+}
+
 type Argon2Params struct {
 	Memory      uint32
 	Iterations  uint32
@@ -19,6 +36,10 @@ type Argon2Params struct {
 
 // HashPassword hashes a password using Argon2id
 func HashPassword(password string, params *Argon2Params) (string, error) {
+	if err := checkPasswordLength(password); err != nil {
+		return "", err
+	}
+
 	salt := make([]byte, params.SaltLength)
 	if _, err := rand.Read(salt); err != nil {
 		return "", err
@@ -49,6 +70,10 @@ func HashPassword(password string, params *Argon2Params) (string, error) {
 
 // ComparePassword verifies a password against an Argon2 hash
 func ComparePassword(password, encodedHash string) (bool, error) {
+	if err := checkPasswordLength(password); err != nil {
+		return false, err
+	}
+
 	parts := strings.Split(encodedHash, "$")
 	if len(parts) != 6 {
 		return false, fmt.Errorf("invalid hash format")
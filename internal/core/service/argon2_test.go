@@ -0,0 +1,27 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestPasswordLengthCap(t *testing.T) {
+	atCap := strings.Repeat("a", MaxPasswordLength)
+	overCap := atCap + "a"
+
+	hash, err := HashPassword(atCap, testArgon2Params)
+	if err != nil {
+		t.Fatalf("HashPassword at the cap: %v", err)
+	}
+	if match, err := ComparePassword(atCap, hash); err != nil || !match {
+		t.Errorf("ComparePassword at the cap = %v, %v; want a match", match, err)
+	}
+
+	if _, err := HashPassword(overCap, testArgon2Params); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("HashPassword over the cap = %v, want ErrPasswordTooLong", err)
+	}
+	if _, err := ComparePassword(overCap, hash); !errors.Is(err, ErrPasswordTooLong) {
+		t.Errorf("ComparePassword over the cap = %v, want ErrPasswordTooLong", err)
+	}
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TestMutationsRecordTheActingMember checks that diagrams, notes and vault
+// items remember who created them and who changed them last
+func TestMutationsRecordTheActingMember(t *testing.T) {
+	ctx := context.Background()
+	projectID := primitive.NewObjectID()
+	creator, editor := primitive.NewObjectID(), primitive.NewObjectID()
+	authz := NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: creator, Role: domain.RoleOwner},
+		{ProjectID: projectID, UserID: editor, Role: domain.RoleOwner},
+	}})
+	check := func(t *testing.T, createdBy, updatedBy, wantUpdatedBy primitive.ObjectID) {
+		t.Helper()
+		if createdBy != creator || updatedBy != wantUpdatedBy {
+			t.Errorf("created_by = %s, updated_by = %s; want %s, %s", createdBy.Hex(), updatedBy.Hex(), creator.Hex(), wantUpdatedBy.Hex())
+		}
+	}
+
+	t.Run("diagram", func(t *testing.T) {
+		repo := &fakeDiagramRepo{}
+		svc := NewDiagramService(repo, authz, nil, nil, nil, nil, nil,
+			NewDiagramLocks(0), NewDiagramPathCache(0), PayloadLimits{}, &fakePublisher{})
+
+		diagram, err := svc.CreateDiagram(ctx, projectID, creator, "Network", "", nil, nil, "sig")
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, diagram.CreatedBy, diagram.UpdatedBy, creator)
+
+		name := "Network v2"
+		if _, err := svc.UpdateDiagram(ctx, diagram.ID, editor, &name, nil, nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		stored, _ := repo.FindByID(ctx, diagram.ID)
+		check(t, stored.CreatedBy, stored.UpdatedBy, editor)
+	})
+
+	t.Run("note", func(t *testing.T) {
+		svc, repo := newTestNoteService(projectID, creator)
+		note, err := svc.CreateNote(ctx, projectID, creator, nil, domain.NoteTypeFolder, "Runbooks", "", nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		stored, _ := repo.FindByID(ctx, note.ID)
+		check(t, stored.CreatedBy, stored.UpdatedBy, creator)
+	})
+
+	t.Run("vault item", func(t *testing.T) {
+		repo := newFakeVaultRepo()
+		svc := NewNodeVaultService(repo, nil, nil, authz, PayloadLimits{}, &fakePublisher{})
+		nodeID := primitive.NewObjectID()
+
+		item, err := svc.CreateVaultItem(ctx, nodeID.Hex(), projectID, creator, dto.CreateNodeVaultRequest{
+			Label: "db password", Type: "password", EncryptedValue: "c2VjcmV0", EncryptedValueSignature: "c2ln",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		check(t, item.CreatedBy, item.UpdatedBy, creator)
+
+		label := "root password"
+		if _, err := svc.UpdateVaultItem(ctx, item.ID.Hex(), nodeID.Hex(), projectID, editor, dto.UpdateNodeVaultRequest{Label: &label}); err != nil {
+			t.Fatal(err)
+		}
+		stored, _ := repo.FindByID(ctx, item.ID)
+		check(t, stored.CreatedBy, stored.UpdatedBy, editor)
+	})
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
 )
 
 var (
@@ -21,6 +22,13 @@ type AuthService struct {
 	refreshTokenRepo port.RefreshTokenRepository
 	jwtService       *JWTService
 	argon2Params     *Argon2Params
+	passwordPolicy   PasswordPolicy
+	// dummyHash is compared against when no user matches a login so that
+	// unknown accounts cost the same Argon2 work as a wrong password
+	dummyHash string
+	// comparePassword checks login passwords; ComparePassword except in
+	// tests, which observe the comparisons made
+	comparePassword func(password, encodedHash string) (bool, error)
 }
 
 func NewAuthService(
@@ -28,17 +36,29 @@ func NewAuthService(
 	refreshTokenRepo port.RefreshTokenRepository,
 	jwtService *JWTService,
 	argon2Params *Argon2Params,
+	passwordPolicy PasswordPolicy,
 ) *AuthService {
+	// Hashing with the live params keeps the dummy comparison as slow as a
+	// real one. A failure here only disables the padding, not login.
+	dummyHash, _ := HashPassword("infrantery-dummy-password", argon2Params)
+
 	return &AuthService{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
 		jwtService:       jwtService,
 		argon2Params:     argon2Params,
+		passwordPolicy:   passwordPolicy,
+		dummyHash:        dummyHash,
+		comparePassword:  ComparePassword,
 	}
 }
 
 // Register creates a new user account
 func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest) (*dto.AuthResponse, error) {
+	if err := s.passwordPolicy.Check("password", req.Password); err != nil {
+		return nil, err
+	}
+
 	// Check if user already exists
 	existingEmail, err := s.userRepo.FindByEmail(ctx, req.Email)
 	if err != nil {
@@ -87,32 +107,29 @@ func (s *AuthService) Register(ctx context.Context, req dto.RegisterRequest) (*d
 
 // Login authenticates a user
 func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.AuthResponse, error) {
-	// Find user by email or username
-	var user *domain.User
-	var err error
-
-	// Try email first
-	user, err = s.userRepo.FindByEmail(ctx, req.EmailOrUsername)
+	user, err := s.findLoginUser(ctx, req.EmailOrUsername)
 	if err != nil {
 		return nil, err
 	}
 
-	// If not found, try username
 	if user == nil {
-		user, err = s.userRepo.FindByUsername(ctx, req.EmailOrUsername)
-		if err != nil {
-			return nil, err
+		// Burn the same Argon2 cost as a real comparison so response time
+		// does not reveal whether the account exists
+		if s.dummyHash != "" {
+			_, _ = s.comparePassword(req.Password, s.dummyHash)
 		}
-	}
-
-	if user == nil {
 		return nil, ErrInvalidCredentials
 	}
 
-	// Verify password
-	match, err := ComparePassword(req.Password, user.Password)
+	// Verify password. A stored hash that fails to parse is reported as bad
+	// credentials rather than a 500, which would only happen for accounts
+	// that exist.
+	match, err := s.comparePassword(req.Password, user.Password)
 	if err != nil {
-		return nil, err
+		if !errors.Is(err, ErrPasswordTooLong) {
+			logger.Error().Err(err).Str("user_id", user.ID.Hex()).Msg("Failed to verify stored password hash")
+		}
+		return nil, ErrInvalidCredentials
 	}
 	if !match {
 		return nil, ErrInvalidCredentials
@@ -121,6 +138,29 @@ func (s *AuthService) Login(ctx context.Context, req dto.LoginRequest) (*dto.Aut
 	return s.generateTokens(ctx, user)
 }
 
+// findLoginUser resolves a login identifier by email, then by username. An
+// email lookup error does not block the username path; it is only returned
+// when the username lookup cannot answer either.
+func (s *AuthService) findLoginUser(ctx context.Context, identifier string) (*domain.User, error) {
+	user, emailErr := s.userRepo.FindByEmail(ctx, identifier)
+	if emailErr == nil && user != nil {
+		return user, nil
+	}
+	if emailErr != nil {
+		logger.Warn().Err(emailErr).Msg("Login email lookup failed, falling back to username")
+	}
+
+	user, err := s.userRepo.FindByUsername(ctx, identifier)
+	if err != nil {
+		return nil, err
+	}
+	if user == nil && emailErr != nil {
+		// The account may still exist under that email
+		return nil, emailErr
+	}
+	return user, nil
+}
+
 // RefreshAccessToken generates a new access token from a refresh token
 func (s *AuthService) RefreshAccessToken(ctx context.Context, refreshTokenString string) (*dto.AuthResponse, error) {
 	// Find refresh token
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const testPassword = "correct horse battery staple"
+
+func newTestAuthService(t *testing.T) (*AuthService, *domain.User) {
+	t.Helper()
+	hash, err := HashPassword(testPassword, testArgon2Params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{ID: primitive.NewObjectID(), Email: "ada@example.com", Username: "ada", Password: hash}
+	svc := NewAuthService(
+		&fakeUserRepo{users: []*domain.User{user}},
+		&fakeRefreshTokenRepo{},
+		NewJWTService("test-secret", time.Minute, time.Hour),
+		testArgon2Params,
+		PasswordPolicy{},
+	)
+	return svc, user
+}
+
+func TestLoginRejectsOverlongPassword(t *testing.T) {
+	svc, user := newTestAuthService(t)
+
+	password := testPassword + strings.Repeat("x", MaxPasswordLength)
+	_, err := svc.Login(context.Background(), dto.LoginRequest{EmailOrUsername: user.Email, Password: password})
+	if !errors.Is(err, ErrInvalidCredentials) {
+		t.Errorf("Login = %v, want ErrInvalidCredentials", err)
+	}
+
+	if _, err := svc.Login(context.Background(), dto.LoginRequest{EmailOrUsername: user.Email, Password: testPassword}); err != nil {
+		t.Errorf("Login with the right password: %v", err)
+	}
+}
+
+func TestLoginUnknownUserRunsDummyComparison(t *testing.T) {
+	svc, user := newTestAuthService(t)
+	var compared []string
+	svc.comparePassword = func(password, encodedHash string) (bool, error) {
+		compared = append(compared, encodedHash)
+		return ComparePassword(password, encodedHash)
+	}
+
+	_, unknownErr := svc.Login(context.Background(), dto.LoginRequest{EmailOrUsername: "nobody@example.com", Password: testPassword})
+	if len(compared) != 1 || compared[0] != svc.dummyHash || svc.dummyHash == "" {
+		t.Fatalf("unknown user compared against %v, want only the dummy hash", compared)
+	}
+	if !strings.Contains(svc.dummyHash, "m=8192,t=1,p=1") {
+		t.Errorf("dummy hash %q does not use the configured Argon2 params", svc.dummyHash)
+	}
+
+	compared = nil
+	_, wrongErr := svc.Login(context.Background(), dto.LoginRequest{EmailOrUsername: user.Username, Password: "wrong password"})
+	if len(compared) != 1 || compared[0] != user.Password {
+		t.Fatalf("wrong password compared against %v, want the user's hash", compared)
+	}
+
+	if unknownErr != ErrInvalidCredentials || wrongErr != ErrInvalidCredentials {
+		t.Errorf("errors = %v and %v, want ErrInvalidCredentials for both", unknownErr, wrongErr)
+	}
+}
+
+func TestLoginFailuresShareOneError(t *testing.T) {
+	svc, user := newTestAuthService(t)
+	corrupt := &domain.User{ID: primitive.NewObjectID(), Email: "broken@example.com", Username: "broken", Password: "not-a-hash"}
+	svc.userRepo.(*fakeUserRepo).users = append(svc.userRepo.(*fakeUserRepo).users, corrupt)
+
+	tests := []struct {
+		name       string
+		identifier string
+		password   string
+	}{
+		{name: "unknown email", identifier: "nobody@example.com", password: testPassword},
+		{name: "unknown username", identifier: "nobody", password: testPassword},
+		{name: "wrong password by email", identifier: user.Email, password: "wrong password"},
+		{name: "wrong password by username", identifier: user.Username, password: "wrong password"},
+		{name: "unreadable stored hash", identifier: corrupt.Email, password: testPassword},
+		{name: "overlong password", identifier: user.Email, password: strings.Repeat("x", MaxPasswordLength+1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response, err := svc.Login(context.Background(), dto.LoginRequest{EmailOrUsername: tt.identifier, Password: tt.password})
+			// The handler matches the sentinel itself, not a wrapped error
+			if err != ErrInvalidCredentials || response != nil {
+				t.Errorf("Login = %v, %v; want nil, ErrInvalidCredentials", response, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AuthorizationService answers project permission questions uniformly.
+// Owners implicitly hold every permission.
+//
+// Not-found policy: a caller who is not a member of a project must not be able
+// to tell whether the project, or anything inside it, exists. Services report
+// non-members with the same not-found error they use for a missing resource
+// (see concealNonMember); members who lack a permission still get an
+// access-denied error, since they already know the project exists.
+type AuthorizationService struct {
+	memberRepo port.ProjectMemberRepository
+}
+
+func NewAuthorizationService(memberRepo port.ProjectMemberRepository) *AuthorizationService {
+	return &AuthorizationService{
+		memberRepo: memberRepo,
+	}
+}
+
+// Can reports whether the member holds the permission
+func (s *AuthorizationService) Can(member *domain.ProjectMember, permission domain.Permission) bool {
+	return member.IsOwner() || member.HasPermission(permission)
+}
+
+// GetMember loads the user's membership in a project.
+// Returns ErrProjectAccessDenied if the user is not a member.
+func (s *AuthorizationService) GetMember(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
+	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProjectAccessDenied
+		}
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrProjectAccessDenied
+	}
+	return member, nil
+}
+
+// Authorize loads the membership once and verifies the permission.
+// Returns ErrProjectAccessDenied for non-members and ErrInsufficientPermission
+// when the member lacks the permission.
+func (s *AuthorizationService) Authorize(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	permission domain.Permission,
+) (*domain.ProjectMember, error) {
+	member, err := s.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !s.Can(member, permission) {
+		return nil, ErrInsufficientPermission
+	}
+
+	return member, nil
+}
+
+// concealNonMember applies the not-found policy: it replaces the non-member
+// error with notFound and passes every other error through unchanged.
+func concealNonMember(err, notFound error) error {
+	if errors.Is(err, ErrProjectAccessDenied) {
+		return notFound
+	}
+	return err
+}
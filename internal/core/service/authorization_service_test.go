@@ -0,0 +1,161 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestAuthorize(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	owner, editor, bare, stranger := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	authz := NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+		// Owners hold every permission even with an empty stored list
+		{ProjectID: projectID, UserID: owner, Role: domain.RoleOwner},
+		{ProjectID: projectID, UserID: editor, Role: domain.RoleEditor, Permissions: []string{string(domain.PermissionEditDiagram)}},
+		{ProjectID: projectID, UserID: bare, Role: domain.RoleViewer},
+	}})
+
+	tests := []struct {
+		name    string
+		userID  primitive.ObjectID
+		wantErr error
+	}{
+		{name: "owner with empty permissions", userID: owner},
+		{name: "member with the permission", userID: editor},
+		{name: "member without the permission", userID: bare, wantErr: ErrInsufficientPermission},
+		{name: "non-member", userID: stranger, wantErr: ErrProjectAccessDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member, err := authz.Authorize(context.Background(), projectID, tt.userID, domain.PermissionEditDiagram)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && member.UserID != tt.userID {
+				t.Errorf("member = %s, want %s", member.UserID.Hex(), tt.userID.Hex())
+			}
+		})
+	}
+}
+
+// TestServicesConcealNonMembers checks the not-found policy: a non-member
+// gets the same error as for a missing resource, while a member without the
+// permission is told access is denied.
+func TestServicesConcealNonMembers(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	bare, stranger := primitive.NewObjectID(), primitive.NewObjectID()
+	authz := NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: bare, Role: domain.RoleViewer},
+	}})
+
+	diagram := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID}
+	note := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID}
+	vault := &domain.NodeVault{ID: primitive.NewObjectID(), NodeId: primitive.NewObjectID(), ProjectId: projectID}
+
+	projects := NewProjectService(&fakeProjectRepo{}, nil, nil, nil, nil, &fakeInvitationRepo{}, nil, nil, nil, nil, nil, nil, nil,
+		authz, nil, &fakePublisher{}, 0, 0)
+	diagrams := NewDiagramService(&fakeDiagramRepo{diagrams: []*domain.Diagram{diagram}}, authz, nil, nil, nil, nil, nil,
+		NewDiagramLocks(0), NewDiagramPathCache(0), PayloadLimits{}, &fakePublisher{})
+	notes := NewNoteService(&fakeNoteRepo{notes: []*domain.Note{note}}, authz, nil, PayloadLimits{}, &fakePublisher{})
+	vaults := NewNodeVaultService(newFakeVaultRepo(vault), nil, nil, authz, PayloadLimits{}, &fakePublisher{})
+
+	tests := []struct {
+		name       string
+		call       func(userID primitive.ObjectID) error
+		notFound   error
+		wantDenied error
+	}{
+		{
+			name: "project",
+			call: func(userID primitive.ObjectID) error {
+				_, _, err := projects.GetProjectInvitations(context.Background(), projectID, userID, 0, 10)
+				return err
+			},
+			notFound:   ErrProjectNotFound,
+			wantDenied: ErrInsufficientPermission,
+		},
+		{
+			name: "diagram",
+			call: func(userID primitive.ObjectID) error {
+				_, err := diagrams.GetDiagram(context.Background(), diagram.ID, userID)
+				return err
+			},
+			notFound:   ErrDiagramNotFound,
+			wantDenied: ErrInsufficientPermission,
+		},
+		{
+			name: "note",
+			call: func(userID primitive.ObjectID) error {
+				_, err := notes.GetNote(context.Background(), note.ID, userID)
+				return err
+			},
+			notFound:   ErrNoteNotFound,
+			wantDenied: ErrInsufficientPermission,
+		},
+		{
+			name: "vault item",
+			call: func(userID primitive.ObjectID) error {
+				_, err := vaults.GetVaultItem(context.Background(), vault.ID.Hex(), vault.NodeId.Hex(), projectID, userID)
+				return err
+			},
+			notFound:   ErrVaultItemNotFound,
+			wantDenied: ErrVaultAccessDenied,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(stranger); !errors.Is(err, tt.notFound) {
+				t.Errorf("non-member: err = %v, want %v", err, tt.notFound)
+			}
+			if err := tt.call(bare); !errors.Is(err, tt.wantDenied) {
+				t.Errorf("member without permission: err = %v, want %v", err, tt.wantDenied)
+			}
+		})
+	}
+
+	// A missing diagram looks the same to a non-member as an existing one
+	if _, err := diagrams.GetDiagram(context.Background(), primitive.NewObjectID(), stranger); !errors.Is(err, ErrDiagramNotFound) {
+		t.Errorf("missing diagram: err = %v, want %v", err, ErrDiagramNotFound)
+	}
+}
+
+// TestOwnerHoldsEveryPermission pins down that owners are never refused,
+// whatever their stored permission list says
+func TestOwnerHoldsEveryPermission(t *testing.T) {
+	projectID, owner := primitive.NewObjectID(), primitive.NewObjectID()
+	authz := NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: owner, Role: domain.RoleOwner, Permissions: []string{}},
+	}})
+	ctx := context.Background()
+
+	for _, permission := range domain.AllPermissions {
+		if _, err := authz.Authorize(ctx, projectID, owner, permission); err != nil {
+			t.Errorf("Authorize(%s) = %v", permission, err)
+		}
+	}
+
+	diagram := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID}
+	note := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID}
+	vault := &domain.NodeVault{ID: primitive.NewObjectID(), NodeId: primitive.NewObjectID(), ProjectId: projectID}
+
+	diagrams := NewDiagramService(&fakeDiagramRepo{diagrams: []*domain.Diagram{diagram}}, authz, nil, nil, nil, nil, nil,
+		NewDiagramLocks(0), NewDiagramPathCache(0), PayloadLimits{}, &fakePublisher{})
+	notes := NewNoteService(&fakeNoteRepo{notes: []*domain.Note{note}}, authz, nil, PayloadLimits{}, &fakePublisher{})
+	vaults := NewNodeVaultService(newFakeVaultRepo(vault), nil, nil, authz, PayloadLimits{}, &fakePublisher{})
+
+	if _, err := diagrams.GetDiagram(ctx, diagram.ID, owner); err != nil {
+		t.Errorf("GetDiagram: %v", err)
+	}
+	if _, err := notes.GetNote(ctx, note.ID, owner); err != nil {
+		t.Errorf("GetNote: %v", err)
+	}
+	if _, err := vaults.GetVaultItem(ctx, vault.ID.Hex(), vault.NodeId.Hex(), projectID, owner); err != nil {
+		t.Errorf("GetVaultItem: %v", err)
+	}
+}
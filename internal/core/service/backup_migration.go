@@ -0,0 +1,71 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+// payloadMigrations upgrade a decoded payload by one version each, keyed by
+// the version they upgrade from. Older payloads decode into the current
+// BackupPayload because fields are only ever added; a migration fills in what
+// an older writer left out so restore code only handles the current shape.
+//
+// A change to the payload that older readers cannot absorb bumps
+// domain.BackupVersion and adds an entry here.
+var payloadMigrations = map[int]func(*domain.BackupPayload) error{
+	domain.BackupVersionV1: migratePayloadV1,
+	domain.BackupVersionV2: migratePayloadV2,
+	domain.BackupVersionV3: migratePayloadV3,
+	domain.BackupVersionV4: migratePayloadV4,
+}
+
+// migratePayload upgrades payload to domain.BackupVersion. Payloads without
+// a version take the one from the archive header.
+func migratePayload(payload *domain.BackupPayload, archiveVersion int) error {
+	if payload.Version == 0 {
+		payload.Version = archiveVersion
+	}
+	if payload.Version < domain.BackupVersionV1 || payload.Version > domain.BackupVersion {
+		return ErrBackupVersionMismatch
+	}
+
+	for payload.Version < domain.BackupVersion {
+		migrate, ok := payloadMigrations[payload.Version]
+		if !ok {
+			return fmt.Errorf("%w: no migration from version %d", ErrBackupVersionMismatch, payload.Version)
+		}
+		if err := migrate(payload); err != nil {
+			return fmt.Errorf("migrating backup from version %d: %w", payload.Version, err)
+		}
+		payload.Version++
+	}
+	return nil
+}
+
+// migratePayloadV1 upgrades a version 1 payload. Payloads written before
+// diagram exports carry no scope and always hold a whole project.
+func migratePayloadV1(payload *domain.BackupPayload) error {
+	if payload.Scope == "" {
+		payload.Scope = domain.BackupScopeProject
+	}
+	return nil
+}
+
+// migratePayloadV2 upgrades a version 2 payload. Version 3 only changed the
+// archive header, so the payload is unchanged.
+func migratePayloadV2(*domain.BackupPayload) error {
+	return nil
+}
+
+// migratePayloadV3 upgrades a version 3 payload. Version 4 only added the
+// pepper ID to the archive header, so the payload is unchanged.
+func migratePayloadV3(*domain.BackupPayload) error {
+	return nil
+}
+
+// migratePayloadV4 upgrades a version 4 payload. Version 5 only added the
+// archive checksum, so the payload is unchanged.
+func migratePayloadV4(*domain.BackupPayload) error {
+	return nil
+}
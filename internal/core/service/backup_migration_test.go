@@ -0,0 +1,56 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+func TestMigratePayload(t *testing.T) {
+	tests := []struct {
+		name           string
+		version        int
+		scope          string
+		archiveVersion int
+		wantScope      string
+		wantErr        error
+	}{
+		{name: "v1 without scope", version: domain.BackupVersionV1, wantScope: domain.BackupScopeProject},
+		{name: "v2", version: domain.BackupVersionV2, scope: domain.BackupScopeProject, wantScope: domain.BackupScopeProject},
+		{name: "v3", version: domain.BackupVersionV3, scope: domain.BackupScopeDiagram, wantScope: domain.BackupScopeDiagram},
+		{name: "v4", version: domain.BackupVersionV4, scope: domain.BackupScopeProject, wantScope: domain.BackupScopeProject},
+		{name: "current", version: domain.BackupVersion, scope: domain.BackupScopeDiagram, wantScope: domain.BackupScopeDiagram},
+		{name: "version from the header", archiveVersion: domain.BackupVersionV1, wantScope: domain.BackupScopeProject},
+		{name: "no version anywhere", wantErr: ErrBackupVersionMismatch},
+		{name: "negative", version: -1, wantErr: ErrBackupVersionMismatch},
+		{name: "newer than supported", version: domain.BackupVersion + 1, wantErr: ErrBackupVersionMismatch},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload := &domain.BackupPayload{Version: tt.version, Scope: tt.scope}
+			err := migratePayload(payload, tt.archiveVersion)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if payload.Version != domain.BackupVersion {
+				t.Errorf("version = %d, want %d", payload.Version, domain.BackupVersion)
+			}
+			if payload.Scope != tt.wantScope {
+				t.Errorf("scope = %q, want %q", payload.Scope, tt.wantScope)
+			}
+		})
+	}
+}
+
+func TestMigratePayloadCoversEveryOlderVersion(t *testing.T) {
+	for version := domain.BackupVersionV1; version < domain.BackupVersion; version++ {
+		if _, ok := payloadMigrations[version]; !ok {
+			t.Errorf("no migration from version %d", version)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+// BackupPeppers holds the secrets mixed into backup key derivation, keyed by
+// the ID recorded in each archive header. New archives use Current; the
+// others only decrypt archives written before a rotation. The built-in
+// domain.BackupPepper is always available under its ID.
+type BackupPeppers struct {
+	Current uint8
+	Peppers map[uint8][]byte
+}
+
+// ParseBackupPeppers builds the pepper set from "id:secret" entries and the ID
+// new archives should use. IDs run from 1 to 255; 0 is the built-in pepper.
+func ParseBackupPeppers(current int, entries []string) (BackupPeppers, error) {
+	peppers := BackupPeppers{
+		Peppers: map[uint8][]byte{domain.BuiltinBackupPepperID: domain.BackupPepper},
+	}
+
+	for _, entry := range entries {
+		rawID, secret, ok := strings.Cut(entry, ":")
+		if !ok || secret == "" {
+			// The entry is left out of the error so the secret is not logged
+			return BackupPeppers{}, errors.New("invalid backup pepper entry: expected id:secret")
+		}
+		id, err := strconv.ParseUint(rawID, 10, 8)
+		if err != nil || id == domain.BuiltinBackupPepperID {
+			return BackupPeppers{}, fmt.Errorf("invalid backup pepper ID %q: must be 1-255", rawID)
+		}
+		if _, exists := peppers.Peppers[uint8(id)]; exists {
+			return BackupPeppers{}, fmt.Errorf("duplicate backup pepper ID %d", id)
+		}
+		peppers.Peppers[uint8(id)] = []byte(secret)
+	}
+
+	if current < 0 || current > 255 {
+		return BackupPeppers{}, fmt.Errorf("invalid backup pepper ID %d: must be 0-255", current)
+	}
+	if _, ok := peppers.Peppers[uint8(current)]; !ok {
+		return BackupPeppers{}, fmt.Errorf("backup pepper ID %d is not configured", current)
+	}
+	peppers.Current = uint8(current)
+
+	return peppers, nil
+}
+
+// lookup returns the pepper for id. The zero value still knows the built-in
+// pepper.
+func (p BackupPeppers) lookup(id uint8) ([]byte, bool) {
+	if pepper, ok := p.Peppers[id]; ok {
+		return pepper, true
+	}
+	if id == domain.BuiltinBackupPepperID {
+		return domain.BackupPepper, true
+	}
+	return nil, false
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+)
+
+func TestParseBackupPeppers(t *testing.T) {
+	tests := []struct {
+		name        string
+		current     int
+		entries     []string
+		wantCurrent uint8
+		wantErr     bool
+	}{
+		{name: "built-in only", current: 0, wantCurrent: domain.BuiltinBackupPepperID},
+		{name: "rotated", current: 2, entries: []string{"1:old-secret", "2:new-secret"}, wantCurrent: 2},
+		{name: "secret with a colon", current: 1, entries: []string{"1:a:b"}, wantCurrent: 1},
+		{name: "missing secret", entries: []string{"1:"}, wantErr: true},
+		{name: "missing separator", entries: []string{"secret"}, wantErr: true},
+		{name: "built-in ID", entries: []string{"0:secret"}, wantErr: true},
+		{name: "ID out of range", entries: []string{"256:secret"}, wantErr: true},
+		{name: "ID not a number", entries: []string{"one:secret"}, wantErr: true},
+		{name: "duplicate ID", entries: []string{"1:a", "1:b"}, wantErr: true},
+		{name: "current not configured", current: 3, entries: []string{"1:secret"}, wantErr: true},
+		{name: "current negative", current: -1, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			peppers, err := ParseBackupPeppers(tt.current, tt.entries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if peppers.Current != tt.wantCurrent {
+				t.Errorf("Current = %d, want %d", peppers.Current, tt.wantCurrent)
+			}
+			if _, ok := peppers.lookup(domain.BuiltinBackupPepperID); !ok {
+				t.Error("the built-in pepper is missing")
+			}
+		})
+	}
+}
+
+func TestParseArchiveAfterPepperRotation(t *testing.T) {
+	const password = "correct horse battery staple"
+	svc := newRestoreTestService(t, newRestoreStore())
+	withPeppers := func(current int, entries ...string) {
+		t.Helper()
+		peppers, err := ParseBackupPeppers(current, entries)
+		if err != nil {
+			t.Fatal(err)
+		}
+		svc.peppers = peppers
+	}
+
+	builtin, err := svc.buildArchive(testBackupPayload(), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	withPeppers(1, "1:first-secret")
+	first, err := svc.buildArchive(testBackupPayload(), password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After rotating to pepper 2, archives of both older peppers still open
+	withPeppers(2, "1:first-secret", "2:second-secret")
+	for name, archive := range map[string][]byte{"built-in pepper": builtin, "pepper 1": first} {
+		payload, err := svc.parseArchive(archive, password)
+		if err != nil {
+			t.Errorf("%s: %v", name, err)
+			continue
+		}
+		if payload.Project.Name != "Infra" {
+			t.Errorf("%s: project name = %q, want Infra", name, payload.Project.Name)
+		}
+	}
+
+	// Dropping pepper 1 from the configuration makes its archives unreadable
+	withPeppers(2, "2:second-secret")
+	if _, err := svc.parseArchive(first, password); !errors.Is(err, ErrBackupPepperUnknown) {
+		t.Errorf("err = %v, want %v", err, ErrBackupPepperUnknown)
+	}
+
+	// The same ID with another secret cannot decrypt it either
+	withPeppers(1, "1:other-secret")
+	if _, err := svc.parseArchive(first, password); !errors.Is(err, ErrBackupDecryptionFailed) {
+		t.Errorf("err = %v, want %v", err, ErrBackupDecryptionFailed)
+	}
+}
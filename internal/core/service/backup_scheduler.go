@@ -0,0 +1,292 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+const (
+	// DefaultBackupIntervalHours is used when a schedule is enabled without
+	// an explicit interval.
+	DefaultBackupIntervalHours = 24
+
+	// scheduledBackupLease is how long a claimed schedule is reserved for
+	// the claiming instance before another may retry it.
+	scheduledBackupLease = time.Hour
+)
+
+var (
+	ErrScheduledBackupUnauthorized   = errors.New("schedule owner can no longer manage the project")
+	ErrScheduledBackupProjectDeleted = errors.New("project is in the recycle bin")
+)
+
+// ---------------------------------------------------------------------------
+// Schedule Configuration
+// ---------------------------------------------------------------------------
+
+// GetBackupSchedule returns the project's schedule, or a disabled default
+// when none has been configured.
+func (s *BackupService) GetBackupSchedule(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+) (*domain.BackupSchedule, error) {
+	if err := s.projectService.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, err
+	}
+
+	schedule, err := s.scheduleRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	if schedule == nil {
+		return &domain.BackupSchedule{
+			ProjectID:     projectID,
+			IntervalHours: DefaultBackupIntervalHours,
+		}, nil
+	}
+	return schedule, nil
+}
+
+// UpdateBackupSchedule enables, disables or reconfigures automatic backups.
+// The caller becomes the member whose keyrings are embedded in future
+// backups. A backup key is generated on first enable or when rotateKey is
+// set; the new key is returned once so the owner can restore the archives.
+func (s *BackupService) UpdateBackupSchedule(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	enabled bool,
+	intervalHours int,
+	rotateKey bool,
+) (*domain.BackupSchedule, string, error) {
+	if err := s.projectService.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, "", err
+	}
+	if enabled && s.storage == nil {
+		return nil, "", ErrBackupStorageDisabled
+	}
+	if intervalHours <= 0 {
+		intervalHours = DefaultBackupIntervalHours
+	}
+
+	schedule, err := s.scheduleRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	isNew := schedule == nil
+	if isNew {
+		schedule = &domain.BackupSchedule{ProjectID: projectID}
+	}
+
+	now := time.Now().UTC()
+	switch {
+	case enabled && !schedule.Enabled:
+		// Run soon after enabling so the owner sees a first archive
+		schedule.NextRunAt = now
+	case enabled && schedule.IntervalHours != intervalHours:
+		base := now
+		if schedule.LastRunAt != nil {
+			base = *schedule.LastRunAt
+		}
+		schedule.NextRunAt = base.Add(time.Duration(intervalHours) * time.Hour)
+	}
+
+	schedule.Enabled = enabled
+	schedule.IntervalHours = intervalHours
+	schedule.ConfiguredBy = userID
+
+	var newKey string
+	if schedule.BackupKey == "" || rotateKey {
+		newKey, err = generateBackupKey()
+		if err != nil {
+			return nil, "", err
+		}
+		schedule.BackupKey = newKey
+	}
+
+	if isNew {
+		err = s.scheduleRepo.Create(ctx, schedule)
+	} else {
+		err = s.scheduleRepo.Update(ctx, schedule)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	return schedule, newKey, nil
+}
+
+// ---------------------------------------------------------------------------
+// Scheduled Execution
+// ---------------------------------------------------------------------------
+
+// RunDueBackups runs every schedule that is due and returns how many
+// backups were stored. Failures are recorded on the schedule and do not
+// stop the remaining runs.
+func (s *BackupService) RunDueBackups(ctx context.Context) (int, error) {
+	if s.storage == nil {
+		return 0, nil
+	}
+
+	stored := 0
+	for ctx.Err() == nil {
+		now := time.Now().UTC()
+		schedule, err := s.scheduleRepo.ClaimDue(ctx, now, scheduledBackupLease)
+		if err != nil {
+			return stored, err
+		}
+		if schedule == nil {
+			break
+		}
+
+		runErr := s.runScheduledBackup(ctx, schedule)
+
+		schedule.LastRunAt = &now
+		schedule.NextRunAt = now.Add(time.Duration(schedule.IntervalHours) * time.Hour)
+		schedule.LastError = ""
+		if runErr != nil {
+			schedule.LastError = runErr.Error()
+			if errors.Is(runErr, ErrScheduledBackupUnauthorized) {
+				schedule.Enabled = false
+			}
+			logger.Error().
+				Err(runErr).
+				Str("project_id", schedule.ProjectID.Hex()).
+				Msg("Scheduled backup failed")
+		} else {
+			stored++
+		}
+
+		if err := s.scheduleRepo.Update(ctx, schedule); err != nil {
+			logger.Error().
+				Err(err).
+				Str("project_id", schedule.ProjectID.Hex()).
+				Msg("Failed to update backup schedule")
+		}
+	}
+
+	return stored, nil
+}
+
+func (s *BackupService) runScheduledBackup(ctx context.Context, schedule *domain.BackupSchedule) error {
+	member, err := s.memberRepo.FindByProjectAndUser(ctx, schedule.ProjectID, schedule.ConfiguredBy)
+	if err != nil {
+		return fmt.Errorf("fetching member for backup: %w", err)
+	}
+	if member == nil {
+		// Skip deleted projects without disabling the schedule, so it
+		// resumes if the project is restored
+		deleted, err := s.memberRepo.FindInDeletedProject(ctx, schedule.ProjectID, schedule.ConfiguredBy)
+		if err == nil && deleted != nil {
+			return ErrScheduledBackupProjectDeleted
+		}
+	}
+	if member == nil || !s.authz.Can(member, domain.PermissionManageProject) {
+		return ErrScheduledBackupUnauthorized
+	}
+
+	payload, err := s.collectProjectData(ctx, schedule.ProjectID, member, backupFilter{})
+	if err != nil {
+		return fmt.Errorf("collecting project data: %w", err)
+	}
+
+	archive, err := s.buildArchive(payload, schedule.BackupKey)
+	if err != nil {
+		return fmt.Errorf("building archive: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s_auto_%s.infbk",
+		sanitizeFilename(payload.Project.Name),
+		time.Now().Format("20060102_150405"),
+	)
+
+	if _, err := s.storeArchive(ctx, schedule.ProjectID, schedule.ConfiguredBy, filename, archive); err != nil {
+		return fmt.Errorf("storing archive: %w", err)
+	}
+	return nil
+}
+
+// generateBackupKey returns a random secret usable as a backup password.
+func generateBackupKey() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generating backup key: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// ---------------------------------------------------------------------------
+// Scheduler
+// ---------------------------------------------------------------------------
+
+// BackupScheduler periodically runs due scheduled backups in the background.
+type BackupScheduler struct {
+	backupService *BackupService
+	tick          time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewBackupScheduler creates a scheduler that checks for due backups every tick.
+func NewBackupScheduler(backupService *BackupService, tick time.Duration) *BackupScheduler {
+	if tick <= 0 {
+		tick = time.Minute
+	}
+	return &BackupScheduler{
+		backupService: backupService,
+		tick:          tick,
+	}
+}
+
+// Start launches the scheduler goroutine. It stops when Stop is called.
+func (s *BackupScheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.tick)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				stored, err := s.backupService.RunDueBackups(ctx)
+				if err != nil && ctx.Err() == nil {
+					logger.Error().Err(err).Msg("Backup scheduler run failed")
+				}
+				if stored > 0 {
+					logger.Info().Int("stored", stored).Msg("Scheduled backups stored")
+				}
+			}
+		}
+	}()
+
+	logger.Info().Dur("tick", s.tick).Msg("Backup scheduler started")
+}
+
+// Stop cancels the scheduler and waits for an in-flight run to finish.
+func (s *BackupScheduler) Stop() {
+	s.once.Do(func() {
+		if s.cancel == nil {
+			return
+		}
+		s.cancel()
+		<-s.done
+	})
+}
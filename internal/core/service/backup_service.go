@@ -3,6 +3,8 @@ package service
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -10,18 +12,40 @@ import (
 	"time"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/compression"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/crypto"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 const (
 	// MaxBackupSize is the maximum allowed backup file size (100 MB).
 	MaxBackupSize = 100 * 1024 * 1024
 
+	// BackupFileExtension is the extension given to every backup archive.
+	BackupFileExtension = ".infbk"
+
 	// archiveHeaderSize = magic(5) + version(1) + nonce(12) + salt(32) = 50 bytes.
+	// Version 2 archives insert the manifest between version and nonce,
+	// version 3 adds the compression algorithm byte before the manifest and
+	// version 4 the pepper ID byte after it.
 	archiveHeaderSize = 5 + 1 + crypto.NonceSize + crypto.SaltSize
+
+	// archiveChecksumSize is the SHA-256 of everything before it, appended to
+	// version 5 archives so damaged files are told apart from wrong passwords.
+	archiveChecksumSize = sha256.Size
+
+	// manifestLengthSize is the big-endian uint32 prefix before the manifest JSON.
+	manifestLengthSize = 4
+
+	// maxManifestSize bounds the plaintext manifest (64 KB).
+	maxManifestSize = 64 * 1024
+
+	// restoreCleanupTimeout bounds the deletes that undo a failed restore.
+	restoreCleanupTimeout = time.Minute
 )
 
 var (
@@ -29,40 +53,84 @@ var (
 	ErrBackupInvalidFormat    = errors.New("invalid backup file format")
 	ErrBackupVersionMismatch  = errors.New("unsupported backup version")
 	ErrBackupDecryptionFailed = errors.New("decryption failed: wrong password or corrupted file")
+	ErrBackupTargetRequired   = errors.New("diagram backups must be restored into an existing project")
+	ErrBackupManifestMissing  = errors.New("backup has no manifest")
+	ErrBackupStorageDisabled  = errors.New("backup storage is not configured")
+	ErrBackupArchiveNotFound  = errors.New("backup archive not found")
+	ErrBackupRestoreFailed    = errors.New("restore failed, nothing was imported")
+	ErrBackupPepperUnknown    = errors.New("backup was encrypted with a pepper this server does not have")
+	ErrBackupCorrupted        = errors.New("backup file is corrupted or incomplete")
 )
 
+// backupFilter narrows what collectProjectData gathers. The zero value
+// collects the whole project.
+type backupFilter struct {
+	// rootDiagramID limits the backup to this diagram, its descendants,
+	// their nodes and those nodes' vaults. Notes are left out.
+	rootDiagramID *primitive.ObjectID
+}
+
+// BackupCompression selects how backup payloads are compressed before
+// encryption. The algorithm is recorded in each archive.
+type BackupCompression struct {
+	Algorithm compression.Algorithm
+	Level     int
+}
+
 // BackupService handles project backup and restore operations.
 type BackupService struct {
 	projectService *ProjectService
+	authz          *AuthorizationService
 	projectRepo    port.ProjectRepository
 	memberRepo     port.ProjectMemberRepository
 	noteRepo       port.NoteRepository
 	diagramRepo    port.DiagramRepository
 	nodeRepo       port.NodeRepository
 	nodeVaultRepo  port.NodeVaultRepository
+	archiveRepo    port.BackupArchiveRepository
+	scheduleRepo   port.BackupScheduleRepository
+	storage        port.BackupStorage
 	argon2Params   *Argon2Params
+	compression    BackupCompression
+	peppers        BackupPeppers
+	events         event.Publisher
 }
 
-// NewBackupService creates a new BackupService.
+// NewBackupService creates a new BackupService. storage may be nil, in which
+// case backups can only be downloaded, not stored server-side.
 func NewBackupService(
 	projectService *ProjectService,
+	authz *AuthorizationService,
 	projectRepo port.ProjectRepository,
 	memberRepo port.ProjectMemberRepository,
 	noteRepo port.NoteRepository,
 	diagramRepo port.DiagramRepository,
 	nodeRepo port.NodeRepository,
 	nodeVaultRepo port.NodeVaultRepository,
+	archiveRepo port.BackupArchiveRepository,
+	scheduleRepo port.BackupScheduleRepository,
+	storage port.BackupStorage,
 	argon2Params *Argon2Params,
+	compressionOpts BackupCompression,
+	peppers BackupPeppers,
+	events event.Publisher,
 ) *BackupService {
 	return &BackupService{
 		projectService: projectService,
+		authz:          authz,
 		projectRepo:    projectRepo,
 		memberRepo:     memberRepo,
 		noteRepo:       noteRepo,
 		diagramRepo:    diagramRepo,
 		nodeRepo:       nodeRepo,
 		nodeVaultRepo:  nodeVaultRepo,
+		archiveRepo:    archiveRepo,
+		scheduleRepo:   scheduleRepo,
+		storage:        storage,
 		argon2Params:   argon2Params,
+		compression:    compressionOpts,
+		peppers:        peppers,
+		events:         events,
 	}
 }
 
@@ -72,31 +140,37 @@ func NewBackupService(
 
 // CreateBackup collects all project data, serializes, compresses, encrypts,
 // and returns the archive as an io.Reader along with a suggested filename.
+// When store is set the archive is also persisted to backup storage and its
+// metadata record is returned.
 func (s *BackupService) CreateBackup(
 	ctx context.Context,
 	projectID, userID primitive.ObjectID,
 	password string,
-) (io.Reader, string, error) {
+	store bool,
+) (io.Reader, string, *domain.BackupArchive, error) {
 	// 1. Verify permission
 	if err := s.projectService.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
-		return nil, "", err
+		return nil, "", nil, err
+	}
+	if store && s.storage == nil {
+		return nil, "", nil, ErrBackupStorageDisabled
 	}
 
 	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, userID)
 	if err != nil {
-		return nil, "", fmt.Errorf("fetching member for backup: %w", err)
+		return nil, "", nil, fmt.Errorf("fetching member for backup: %w", err)
 	}
 
 	// 2. Collect all data
-	payload, err := s.collectProjectData(ctx, projectID, member)
+	payload, err := s.collectProjectData(ctx, projectID, member, backupFilter{})
 	if err != nil {
-		return nil, "", fmt.Errorf("collecting project data: %w", err)
+		return nil, "", nil, fmt.Errorf("collecting project data: %w", err)
 	}
 
 	// 3. Build the encrypted archive
 	archive, err := s.buildArchive(payload, password)
 	if err != nil {
-		return nil, "", fmt.Errorf("building archive: %w", err)
+		return nil, "", nil, fmt.Errorf("building archive: %w", err)
 	}
 
 	filename := fmt.Sprintf("%s_%s.infbk",
@@ -104,17 +178,194 @@ func (s *BackupService) CreateBackup(
 		time.Now().Format("20060102_150405"),
 	)
 
+	// 4. Optionally persist the archive; storeArchive announces stored ones
+	var stored *domain.BackupArchive
+	if store {
+		stored, err = s.storeArchive(ctx, projectID, userID, filename, archive)
+		if err != nil {
+			return nil, "", nil, fmt.Errorf("storing archive: %w", err)
+		}
+	} else {
+		s.publish(event.BackupCreated, projectID, userID, primitive.NilObjectID)
+	}
+
+	return bytes.NewReader(archive), filename, stored, nil
+}
+
+// ListStoredBackups lists the archives persisted for a project, newest first.
+func (s *BackupService) ListStoredBackups(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	offset, limit int,
+) ([]*domain.BackupArchive, int64, error) {
+	if err := s.projectService.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, 0, err
+	}
+
+	return s.archiveRepo.FindByProjectID(ctx, projectID, offset, limit)
+}
+
+// OpenStoredBackup returns a reader over a stored archive. The caller must
+// close it.
+func (s *BackupService) OpenStoredBackup(
+	ctx context.Context,
+	projectID, archiveID, userID primitive.ObjectID,
+) (io.ReadCloser, *domain.BackupArchive, error) {
+	if err := s.projectService.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, nil, err
+	}
+	if s.storage == nil {
+		return nil, nil, ErrBackupStorageDisabled
+	}
+
+	archive, err := s.archiveRepo.FindByID(ctx, archiveID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, ErrBackupArchiveNotFound
+		}
+		return nil, nil, err
+	}
+	if archive == nil || archive.ProjectID != projectID {
+		return nil, nil, ErrBackupArchiveNotFound
+	}
+
+	reader, err := s.storage.Get(ctx, archive.Key)
+	if err != nil {
+		if errors.Is(err, port.ErrObjectNotFound) {
+			return nil, nil, ErrBackupArchiveNotFound
+		}
+		return nil, nil, fmt.Errorf("reading stored archive: %w", err)
+	}
+
+	return reader, archive, nil
+}
+
+// InspectBackup reads the unencrypted manifest of a backup without needing
+// the password. Version 1 archives have no manifest.
+func (s *BackupService) InspectBackup(backupReader io.Reader) (*domain.BackupManifest, error) {
+	data, err := io.ReadAll(io.LimitReader(backupReader, MaxBackupSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading backup file: %w", err)
+	}
+	if len(data) > MaxBackupSize {
+		return nil, ErrBackupTooLarge
+	}
+
+	header, _, err := splitArchive(data)
+	if err != nil {
+		return nil, err
+	}
+	manifest := header.manifest
+	if manifest == nil {
+		return nil, ErrBackupManifestMissing
+	}
+
+	return manifest, nil
+}
+
+// VerifyBackup checks a backup file without restoring it. The checksum is
+// verified without the password; when a password is given the payload is
+// also decrypted and decoded. Damaged files fail with ErrBackupCorrupted and
+// a wrong password with ErrBackupDecryptionFailed.
+func (s *BackupService) VerifyBackup(backupReader io.Reader, password string) (*domain.BackupVerification, error) {
+	if password != "" {
+		if err := checkPasswordLength(password); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := io.ReadAll(io.LimitReader(backupReader, MaxBackupSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading backup file: %w", err)
+	}
+	if len(data) > MaxBackupSize {
+		return nil, ErrBackupTooLarge
+	}
+
+	header, _, err := splitArchive(data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.BackupVerification{
+		Version:          header.version,
+		ChecksumVerified: header.version >= domain.BackupVersion,
+	}
+	if password == "" {
+		return result, nil
+	}
+
+	if _, err := s.parseArchive(data, password); err != nil {
+		return nil, err
+	}
+	result.PasswordVerified = true
+
+	return result, nil
+}
+
+// ExportDiagram builds an archive in the same format as CreateBackup, scoped
+// to one diagram, its descendant diagrams, their nodes and those nodes' vaults.
+func (s *BackupService) ExportDiagram(
+	ctx context.Context,
+	projectID, diagramID, userID primitive.ObjectID,
+	password string,
+) (io.Reader, string, error) {
+	// 1. Verify permission
+	member, err := s.authz.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, "", concealNonMember(err, ErrProjectNotFound)
+	}
+	if !s.authz.Can(member, domain.PermissionViewDiagram) || !s.authz.Can(member, domain.PermissionViewVault) {
+		return nil, "", ErrInsufficientPermission
+	}
+
+	diagram, err := s.diagramRepo.FindByID(ctx, diagramID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, "", ErrDiagramNotFound
+		}
+		return nil, "", fmt.Errorf("fetching diagram: %w", err)
+	}
+	if diagram == nil || diagram.ProjectID != projectID {
+		return nil, "", ErrDiagramNotFound
+	}
+
+	// 2. Collect the diagram subtree
+	payload, err := s.collectProjectData(ctx, projectID, member, backupFilter{rootDiagramID: &diagram.ID})
+	if err != nil {
+		return nil, "", fmt.Errorf("collecting diagram data: %w", err)
+	}
+
+	// 3. Build the encrypted archive
+	archive, err := s.buildArchive(payload, password)
+	if err != nil {
+		return nil, "", fmt.Errorf("building archive: %w", err)
+	}
+
+	filename := fmt.Sprintf("%s_%s.infbk",
+		sanitizeFilename(diagram.DiagramName),
+		time.Now().Format("20060102_150405"),
+	)
+
 	return bytes.NewReader(archive), filename, nil
 }
 
 // RestoreBackup reads an encrypted backup, decrypts, decompresses, validates,
 // and inserts all data as a new project. The restoring user becomes the owner.
+//
+// Diagram-scoped archives are imported into targetProjectID instead, which
+// must be set and editable by the user. It is ignored for project archives.
 func (s *BackupService) RestoreBackup(
 	ctx context.Context,
 	userID primitive.ObjectID,
 	password string,
 	backupReader io.Reader,
+	targetProjectID *primitive.ObjectID,
 ) (*domain.Project, error) {
+	if err := checkPasswordLength(password); err != nil {
+		return nil, err
+	}
+
 	// 1. Read and validate size
 	data, err := io.ReadAll(io.LimitReader(backupReader, MaxBackupSize+1))
 	if err != nil {
@@ -131,14 +382,166 @@ func (s *BackupService) RestoreBackup(
 	}
 
 	// 3. Insert into database
-	project, err := s.insertRestoredData(ctx, userID, payload)
+	if payload.IsDiagramScoped() {
+		if targetProjectID == nil {
+			return nil, ErrBackupTargetRequired
+		}
+		project, err := s.importDiagramPayload(ctx, *targetProjectID, userID, payload)
+		if err != nil {
+			return nil, err
+		}
+		s.publish(event.BackupRestored, *targetProjectID, userID, *targetProjectID)
+		return project, nil
+	}
+
+	project, err := s.insertPayload(ctx, userID, payload)
 	if err != nil {
 		return nil, fmt.Errorf("inserting restored data: %w", err)
 	}
 
+	s.publish(event.BackupRestored, project.ID, userID, project.ID)
 	return project, nil
 }
 
+// MergeBackup imports a backup's diagrams, nodes, vaults and notes into an
+// existing project instead of creating a new one. The backup's project
+// details and member keys are left out. Top-level diagrams and notes whose
+// names are already taken in the target are kept with a " (restored)" suffix.
+//
+// Encrypted content is copied as-is, as with diagram imports, so it is only
+// readable in the target when both projects share key material.
+func (s *BackupService) MergeBackup(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	password string,
+	backupReader io.Reader,
+) (*domain.Project, error) {
+	if err := checkPasswordLength(password); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.authz.Authorize(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, concealNonMember(err, ErrProjectNotFound)
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("fetching target project: %w", err)
+	}
+	if project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	data, err := io.ReadAll(io.LimitReader(backupReader, MaxBackupSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("reading backup file: %w", err)
+	}
+	if len(data) > MaxBackupSize {
+		return nil, ErrBackupTooLarge
+	}
+
+	payload, err := s.parseArchive(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.renameCollisions(ctx, projectID, payload); err != nil {
+		return nil, err
+	}
+
+	inserted := &insertedRecords{}
+	idMap := make(map[string]primitive.ObjectID)
+	if err := s.insertDiagramTree(ctx, projectID, userID, payload, idMap, inserted); err != nil {
+		return nil, s.undoInsert(ctx, inserted, fmt.Errorf("merging diagram data: %w", err))
+	}
+	if err := s.insertNotes(ctx, projectID, userID, payload, idMap, inserted); err != nil {
+		return nil, s.undoInsert(ctx, inserted, fmt.Errorf("merging note data: %w", err))
+	}
+
+	s.publish(event.BackupRestored, projectID, userID, projectID)
+	return project, nil
+}
+
+// CloneProject copies a live project into a brand-new project owned by the
+// caller. Encrypted content cannot be re-keyed server-side, so the clone keeps
+// the source key epoch and the caller's keyrings.
+func (s *BackupService) CloneProject(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	name *string,
+) (*domain.Project, error) {
+	// 1. Verify the caller can view everything being copied
+	member, err := s.authz.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, concealNonMember(err, ErrProjectNotFound)
+	}
+	for _, permission := range []domain.Permission{
+		domain.PermissionViewDiagram,
+		domain.PermissionViewNote,
+		domain.PermissionViewVault,
+	} {
+		if !s.authz.Can(member, permission) {
+			return nil, ErrInsufficientPermission
+		}
+	}
+
+	// 2. Collect live data in backup form
+	payload, err := s.collectProjectData(ctx, projectID, member, backupFilter{})
+	if err != nil {
+		return nil, fmt.Errorf("collecting project data: %w", err)
+	}
+
+	if name != nil {
+		payload.Project.Name = *name
+	} else {
+		payload.Project.Name += " (copy)"
+	}
+
+	// 3. Insert as a new project
+	project, err := s.insertPayload(ctx, userID, payload)
+	if err != nil {
+		return nil, fmt.Errorf("inserting cloned data: %w", err)
+	}
+
+	s.publish(event.ProjectCreated, project.ID, userID, project.ID)
+	return project, nil
+}
+
+// storeArchive uploads an encrypted archive and records its metadata. The
+// object is removed again if the metadata cannot be saved.
+func (s *BackupService) storeArchive(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	filename string,
+	data []byte,
+) (*domain.BackupArchive, error) {
+	archiveID := primitive.NewObjectID()
+	key := fmt.Sprintf("projects/%s/%s.infbk", projectID.Hex(), archiveID.Hex())
+
+	if err := s.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data))); err != nil {
+		return nil, err
+	}
+
+	archive, err := s.archiveRepo.Create(ctx, &domain.BackupArchive{
+		ID:        archiveID,
+		ProjectID: projectID,
+		Key:       key,
+		Filename:  filename,
+		Size:      int64(len(data)),
+		CreatedBy: userID,
+	})
+	if err != nil {
+		_ = s.storage.Delete(ctx, key)
+		return nil, err
+	}
+
+	s.publish(event.BackupCreated, projectID, userID, archive.ID)
+	return archive, nil
+}
+
 // ---------------------------------------------------------------------------
 // Data Collection
 // ---------------------------------------------------------------------------
@@ -147,17 +550,35 @@ func (s *BackupService) collectProjectData(
 	ctx context.Context,
 	projectID primitive.ObjectID,
 	member *domain.ProjectMember,
+	filter backupFilter,
 ) (*domain.BackupPayload, error) {
 	project, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("fetching project: %w", err)
 	}
+	if project == nil {
+		return nil, ErrProjectNotFound
+	}
 
 	diagrams, err := s.diagramRepo.FindAllByProjectID(ctx, projectID)
 	if err != nil {
 		return nil, fmt.Errorf("fetching diagrams: %w", err)
 	}
 
+	if filter.rootDiagramID != nil {
+		var root *domain.Diagram
+		for _, d := range diagrams {
+			if d.ID == *filter.rootDiagramID {
+				root = d
+				break
+			}
+		}
+		if root == nil {
+			return nil, ErrDiagramNotFound
+		}
+		diagrams = append([]*domain.Diagram{root}, collectDescendantDiagrams(diagrams, root.ID)...)
+	}
+
 	// Collect diagram IDs for bulk node fetch
 	var nodes []*domain.Node
 	if len(diagrams) > 0 {
@@ -177,21 +598,44 @@ func (s *BackupService) collectProjectData(
 		return nil, fmt.Errorf("fetching vaults: %w", err)
 	}
 
-	notes, err := s.noteRepo.FindByProjectID(ctx, projectID)
-	if err != nil {
-		return nil, fmt.Errorf("fetching notes: %w", err)
-	}
-
-	return &domain.BackupPayload{
+	payload := &domain.BackupPayload{
 		Version:   domain.BackupVersion,
+		Scope:     domain.BackupScopeProject,
 		CreatedAt: time.Now().UTC(),
 		Project:   toProjectBackup(project),
 		Member:    toMemberBackup(member),
 		Diagrams:  toDiagramBackups(diagrams),
 		Nodes:     toNodeBackups(nodes),
-		Vaults:    toVaultBackups(vaults),
-		Notes:     toNoteBackups(notes),
-	}, nil
+	}
+
+	if filter.rootDiagramID != nil {
+		// Keep only vaults attached to the collected nodes
+		nodeIDs := make(map[primitive.ObjectID]bool, len(nodes))
+		for _, n := range nodes {
+			nodeIDs[n.ID] = true
+		}
+		scoped := make([]*domain.NodeVault, 0, len(vaults))
+		for _, v := range vaults {
+			if nodeIDs[v.NodeId] {
+				scoped = append(scoped, v)
+			}
+		}
+
+		payload.Scope = domain.BackupScopeDiagram
+		payload.RootDiagramID = filter.rootDiagramID.Hex()
+		payload.Vaults = toVaultBackups(scoped)
+		payload.Notes = []domain.NoteBackup{}
+		return payload, nil
+	}
+
+	notes, err := s.noteRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching notes: %w", err)
+	}
+
+	payload.Vaults = toVaultBackups(vaults)
+	payload.Notes = toNoteBackups(notes)
+	return payload, nil
 }
 
 // ---------------------------------------------------------------------------
@@ -199,6 +643,10 @@ func (s *BackupService) collectProjectData(
 // ---------------------------------------------------------------------------
 
 func (s *BackupService) buildArchive(payload *domain.BackupPayload, password string) ([]byte, error) {
+	if err := checkPasswordLength(password); err != nil {
+		return nil, err
+	}
+
 	// 1. Serialize to JSON
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
@@ -206,7 +654,7 @@ func (s *BackupService) buildArchive(payload *domain.BackupPayload, password str
 	}
 
 	// 2. Compress
-	compressed, err := compression.Compress(jsonData)
+	compressed, err := compression.CompressWith(jsonData, s.compression.Algorithm, s.compression.Level)
 	if err != nil {
 		return nil, fmt.Errorf("compressing payload: %w", err)
 	}
@@ -217,7 +665,11 @@ func (s *BackupService) buildArchive(payload *domain.BackupPayload, password str
 		return nil, fmt.Errorf("generating salt: %w", err)
 	}
 
-	key := crypto.DeriveBackupKey(password, domain.BackupPepper, salt, s.toCryptoParams())
+	pepper, ok := s.peppers.lookup(s.peppers.Current)
+	if !ok {
+		return nil, fmt.Errorf("backup pepper %d is not configured", s.peppers.Current)
+	}
+	key := crypto.DeriveBackupKey(password, pepper, salt, s.toCryptoParams())
 
 	// 4. Encrypt
 	nonce, ciphertext, err := crypto.Encrypt(compressed, key)
@@ -225,14 +677,31 @@ func (s *BackupService) buildArchive(payload *domain.BackupPayload, password str
 		return nil, fmt.Errorf("encrypting payload: %w", err)
 	}
 
-	// 5. Assemble archive: magic + version + nonce + salt + ciphertext
+	// 5. Serialize the plaintext manifest
+	manifest, err := json.Marshal(payload.Manifest())
+	if err != nil {
+		return nil, fmt.Errorf("marshaling manifest: %w", err)
+	}
+	if len(manifest) > maxManifestSize {
+		return nil, fmt.Errorf("manifest exceeds %d bytes", maxManifestSize)
+	}
+
+	// 6. Assemble archive: magic + version + algorithm + pepper ID + manifest length + manifest + nonce + salt + ciphertext + checksum
 	var buf bytes.Buffer
-	buf.Grow(archiveHeaderSize + len(ciphertext))
+	buf.Grow(archiveHeaderSize + 2 + manifestLengthSize + len(manifest) + len(ciphertext) + archiveChecksumSize)
 	buf.Write(domain.BackupMagic)
 	buf.WriteByte(byte(domain.BackupVersion))
+	buf.WriteByte(byte(s.compression.Algorithm))
+	buf.WriteByte(s.peppers.Current)
+	var length [manifestLengthSize]byte
+	binary.BigEndian.PutUint32(length[:], uint32(len(manifest)))
+	buf.Write(length[:])
+	buf.Write(manifest)
 	buf.Write(nonce)
 	buf.Write(salt)
 	buf.Write(ciphertext)
+	checksum := sha256.Sum256(buf.Bytes())
+	buf.Write(checksum[:])
 
 	return buf.Bytes(), nil
 }
@@ -242,31 +711,26 @@ func (s *BackupService) buildArchive(payload *domain.BackupPayload, password str
 // ---------------------------------------------------------------------------
 
 func (s *BackupService) parseArchive(data []byte, password string) (*domain.BackupPayload, error) {
-	if len(data) < archiveHeaderSize {
-		return nil, ErrBackupInvalidFormat
-	}
-
-	// 1. Validate magic
-	if !bytes.Equal(data[:5], domain.BackupMagic) {
-		return nil, ErrBackupInvalidFormat
-	}
-
-	// 2. Validate version
-	version := data[5]
-	if int(version) != domain.BackupVersion {
-		return nil, ErrBackupVersionMismatch
+	// 1-2. Validate magic and version, skip the manifest
+	header, rest, err := splitArchive(data)
+	if err != nil {
+		return nil, err
 	}
 
 	// 3. Extract nonce and salt
-	offset := 6
-	nonce := data[offset : offset+crypto.NonceSize]
+	offset := 0
+	nonce := rest[offset : offset+crypto.NonceSize]
 	offset += crypto.NonceSize
-	salt := data[offset : offset+crypto.SaltSize]
+	salt := rest[offset : offset+crypto.SaltSize]
 	offset += crypto.SaltSize
-	ciphertext := data[offset:]
+	ciphertext := rest[offset:]
 
-	// 4. Derive key and decrypt
-	key := crypto.DeriveBackupKey(password, domain.BackupPepper, salt, s.toCryptoParams())
+	// 4. Derive key with the archive's pepper and decrypt
+	pepper, ok := s.peppers.lookup(header.pepperID)
+	if !ok {
+		return nil, ErrBackupPepperUnknown
+	}
+	key := crypto.DeriveBackupKey(password, pepper, salt, s.toCryptoParams())
 
 	compressed, err := crypto.Decrypt(ciphertext, key, nonce)
 	if err != nil {
@@ -274,7 +738,7 @@ func (s *BackupService) parseArchive(data []byte, password string) (*domain.Back
 	}
 
 	// 5. Decompress
-	jsonData, err := compression.Decompress(compressed)
+	jsonData, err := compression.DecompressWith(compressed, header.algorithm)
 	if err != nil {
 		return nil, fmt.Errorf("decompressing backup: %w", err)
 	}
@@ -285,18 +749,121 @@ func (s *BackupService) parseArchive(data []byte, password string) (*domain.Back
 		return nil, fmt.Errorf("unmarshaling backup: %w", err)
 	}
 
+	// 7. Upgrade payloads written by older versions
+	if err := migratePayload(&payload, header.version); err != nil {
+		return nil, err
+	}
+
 	return &payload, nil
 }
 
+// archiveHeader holds the plaintext fields that precede the nonce.
+type archiveHeader struct {
+	version   int
+	algorithm compression.Algorithm
+	pepperID  uint8
+	manifest  *domain.BackupManifest // nil for version 1 archives
+}
+
+// splitArchive validates the magic, version and checksum, decodes the
+// version-specific header fields, and returns the remaining nonce + salt +
+// ciphertext.
+func splitArchive(data []byte) (*archiveHeader, []byte, error) {
+	if len(data) < archiveHeaderSize {
+		return nil, nil, ErrBackupInvalidFormat
+	}
+
+	if !bytes.Equal(data[:5], domain.BackupMagic) {
+		return nil, nil, ErrBackupInvalidFormat
+	}
+
+	header := &archiveHeader{
+		version:   int(data[5]),
+		algorithm: compression.AlgorithmZstd,
+		pepperID:  domain.BuiltinBackupPepperID,
+	}
+
+	// Checked before any field is trusted, so truncation or tampering is
+	// reported as such instead of as a wrong password
+	if header.version == domain.BackupVersion {
+		if len(data) < archiveHeaderSize+archiveChecksumSize {
+			return nil, nil, ErrBackupCorrupted
+		}
+		body := data[:len(data)-archiveChecksumSize]
+		checksum := sha256.Sum256(body)
+		if !bytes.Equal(checksum[:], data[len(body):]) {
+			return nil, nil, ErrBackupCorrupted
+		}
+		data = body
+	}
+	rest := data[6:]
+
+	switch header.version {
+	case domain.BackupVersionV1:
+		// nonce + salt follow the version byte directly
+	case domain.BackupVersionV2, domain.BackupVersionV3, domain.BackupVersionV4, domain.BackupVersion:
+		if header.version >= domain.BackupVersionV3 {
+			if len(rest) < 1 {
+				return nil, nil, ErrBackupInvalidFormat
+			}
+			header.algorithm = compression.Algorithm(rest[0])
+			if !header.algorithm.IsValid() {
+				return nil, nil, ErrBackupInvalidFormat
+			}
+			rest = rest[1:]
+		}
+		if header.version >= domain.BackupVersionV4 {
+			if len(rest) < 1 {
+				return nil, nil, ErrBackupInvalidFormat
+			}
+			header.pepperID = rest[0]
+			rest = rest[1:]
+		}
+
+		if len(rest) < manifestLengthSize {
+			return nil, nil, ErrBackupInvalidFormat
+		}
+		length := binary.BigEndian.Uint32(rest[:manifestLengthSize])
+		rest = rest[manifestLengthSize:]
+		if length > maxManifestSize || int(length) > len(rest) {
+			return nil, nil, ErrBackupInvalidFormat
+		}
+		header.manifest = &domain.BackupManifest{}
+		if err := json.Unmarshal(rest[:length], header.manifest); err != nil {
+			return nil, nil, ErrBackupInvalidFormat
+		}
+		rest = rest[length:]
+	default:
+		return nil, nil, ErrBackupVersionMismatch
+	}
+
+	if len(rest) < crypto.NonceSize+crypto.SaltSize {
+		return nil, nil, ErrBackupInvalidFormat
+	}
+
+	return header, rest, nil
+}
+
 // ---------------------------------------------------------------------------
 // Data Restoration (ID remap → insert)
 // ---------------------------------------------------------------------------
 
-func (s *BackupService) insertRestoredData(
+// insertPayload creates a new project owned by userID from a backup payload,
+// generating fresh IDs for every entity. Used by restore and clone.
+func (s *BackupService) insertPayload(
 	ctx context.Context,
 	userID primitive.ObjectID,
 	payload *domain.BackupPayload,
-) (*domain.Project, error) {
+) (_ *domain.Project, err error) {
+	// Without transactions a failed insert is undone by deleting what
+	// already went in, so no half-restored project is left behind
+	inserted := &insertedRecords{}
+	defer func() {
+		if err != nil {
+			err = s.undoInsert(ctx, inserted, err)
+		}
+	}()
+
 	// Build old → new ID mapping for all entities
 	idMap := make(map[string]primitive.ObjectID)
 
@@ -316,6 +883,7 @@ func (s *BackupService) insertRestoredData(
 	if err := s.projectRepo.Create(ctx, project); err != nil {
 		return nil, fmt.Errorf("creating project: %w", err)
 	}
+	inserted.project = &newProjectID
 
 	// 2. Create member with keyrings from backup
 	keyrings := make([]domain.ProjectMemberKeyring, len(payload.Member.Keyrings))
@@ -331,8 +899,8 @@ func (s *BackupService) insertRestoredData(
 	ownerMember := &domain.ProjectMember{
 		ProjectID:           newProjectID,
 		UserID:              userID,
-		Role:                "owner",
-		Permissions:         RolePresets["owner"],
+		Role:                domain.RoleOwner,
+		Permissions:         domain.PermissionStrings(RolePresets[domain.RoleOwner]),
 		PublicKey:           payload.Member.PublicKey,
 		EncryptedPrivateKey: payload.Member.EncryptedPrivateKey,
 		Keyrings:            keyrings,
@@ -340,8 +908,184 @@ func (s *BackupService) insertRestoredData(
 	if err := s.memberRepo.Create(ctx, ownerMember); err != nil {
 		return nil, fmt.Errorf("creating owner member: %w", err)
 	}
+	inserted.owner = &userID
+
+	// 3. Insert diagrams, nodes and vaults
+	if err := s.insertDiagramTree(ctx, newProjectID, userID, payload, idMap, inserted); err != nil {
+		return nil, err
+	}
 
-	// 3. Pre-generate IDs for diagrams so parent references can be resolved
+	// 4. Insert notes
+	if err := s.insertNotes(ctx, newProjectID, userID, payload, idMap, inserted); err != nil {
+		return nil, err
+	}
+
+	return project, nil
+}
+
+// insertNotes inserts the payload's notes into projectID with fresh IDs,
+// attributed to userID, recording them in idMap and inserted. Parent
+// references outside the payload are dropped.
+func (s *BackupService) insertNotes(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	payload *domain.BackupPayload,
+	idMap map[string]primitive.ObjectID,
+	inserted *insertedRecords,
+) error {
+	// Pre-generate IDs for notes so parent references can be resolved
+	for _, n := range payload.Notes {
+		idMap[n.ID] = primitive.NewObjectID()
+	}
+
+	for _, n := range payload.Notes {
+		note := &domain.Note{
+			ID:                        idMap[n.ID],
+			ProjectID:                 projectID,
+			Type:                      n.Type,
+			FileName:                  n.FileName,
+			Icon:                      n.Icon,
+			EncryptedContent:          n.EncryptedContent,
+			EncryptedContentSignature: n.EncryptedContentSignature,
+			CreatedBy:                 userID,
+			UpdatedBy:                 userID,
+		}
+		if n.ParentID != nil {
+			if newParent, ok := idMap[*n.ParentID]; ok {
+				note.ParentID = &newParent
+			}
+		}
+		if err := s.noteRepo.Create(ctx, note); err != nil {
+			return fmt.Errorf("creating note %q: %w", n.FileName, err)
+		}
+		inserted.notes = append(inserted.notes, note.ID)
+	}
+
+	return nil
+}
+
+// renameCollisions gives the payload's top-level diagrams and notes a
+// " (restored)" suffix where the target project already has an item of the
+// same name at its top level, so merged items stay distinguishable.
+func (s *BackupService) renameCollisions(ctx context.Context, projectID primitive.ObjectID, payload *domain.BackupPayload) error {
+	diagrams, err := s.diagramRepo.FindAllByProjectID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("fetching target diagrams: %w", err)
+	}
+	takenDiagrams := make(map[string]struct{})
+	for _, d := range diagrams {
+		if d.ParentDiagramID == nil {
+			takenDiagrams[d.DiagramName] = struct{}{}
+		}
+	}
+
+	notes, err := s.noteRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return fmt.Errorf("fetching target notes: %w", err)
+	}
+	takenNotes := make(map[string]struct{})
+	for _, n := range notes {
+		if n.ParentID == nil {
+			takenNotes[n.FileName] = struct{}{}
+		}
+	}
+
+	// Items whose parent is outside the payload land at the top level
+	diagramIDs := make(map[string]struct{}, len(payload.Diagrams))
+	for _, d := range payload.Diagrams {
+		diagramIDs[d.ID] = struct{}{}
+	}
+	for i := range payload.Diagrams {
+		d := &payload.Diagrams[i]
+		if d.ParentDiagramID != nil {
+			if _, ok := diagramIDs[*d.ParentDiagramID]; ok {
+				continue
+			}
+		}
+		d.DiagramName = uniqueName(d.DiagramName, takenDiagrams)
+	}
+
+	noteIDs := make(map[string]struct{}, len(payload.Notes))
+	for _, n := range payload.Notes {
+		noteIDs[n.ID] = struct{}{}
+	}
+	for i := range payload.Notes {
+		n := &payload.Notes[i]
+		if n.ParentID != nil {
+			if _, ok := noteIDs[*n.ParentID]; ok {
+				continue
+			}
+		}
+		n.FileName = uniqueName(n.FileName, takenNotes)
+	}
+
+	return nil
+}
+
+// uniqueName returns name, or name with a " (restored)" suffix numbered as
+// needed when it is already in taken. The returned name is added to taken.
+func uniqueName(name string, taken map[string]struct{}) string {
+	candidate := name
+	for i := 1; ; i++ {
+		if _, ok := taken[candidate]; !ok {
+			break
+		}
+		if i == 1 {
+			candidate = name + " (restored)"
+		} else {
+			candidate = fmt.Sprintf("%s (restored %d)", name, i)
+		}
+	}
+	taken[candidate] = struct{}{}
+	return candidate
+}
+
+// importDiagramPayload inserts a diagram-scoped payload into an existing
+// project. The exported root diagram lands at the top level of the target.
+func (s *BackupService) importDiagramPayload(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	payload *domain.BackupPayload,
+) (*domain.Project, error) {
+	member, err := s.authz.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, concealNonMember(err, ErrProjectNotFound)
+	}
+	if !s.authz.Can(member, domain.PermissionEditDiagram) || !s.authz.Can(member, domain.PermissionEditVault) {
+		return nil, ErrInsufficientPermission
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("fetching target project: %w", err)
+	}
+	if project == nil {
+		return nil, ErrProjectNotFound
+	}
+
+	inserted := &insertedRecords{}
+	if err := s.insertDiagramTree(ctx, projectID, userID, payload, make(map[string]primitive.ObjectID), inserted); err != nil {
+		return nil, s.undoInsert(ctx, inserted, fmt.Errorf("importing diagram data: %w", err))
+	}
+
+	return project, nil
+}
+
+// insertDiagramTree inserts the payload's diagrams, nodes and vaults into
+// projectID with fresh IDs, attributed to userID, recording old → new IDs in
+// idMap and every inserted document in inserted. Parent references outside
+// the payload are dropped.
+func (s *BackupService) insertDiagramTree(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	payload *domain.BackupPayload,
+	idMap map[string]primitive.ObjectID,
+	inserted *insertedRecords,
+) error {
+	// 1. Pre-generate IDs for diagrams so parent references can be resolved
 	for _, d := range payload.Diagrams {
 		idMap[d.ID] = primitive.NewObjectID()
 	}
@@ -350,11 +1094,13 @@ func (s *BackupService) insertRestoredData(
 	for _, d := range payload.Diagrams {
 		diagram := &domain.Diagram{
 			ID:                     idMap[d.ID],
-			ProjectID:              newProjectID,
+			ProjectID:              projectID,
 			DiagramName:            d.DiagramName,
 			Description:            d.Description,
 			EncryptedData:          d.EncryptedData,
 			EncryptedDataSignature: d.EncryptedDataSignature,
+			CreatedBy:              userID,
+			UpdatedBy:              userID,
 		}
 		if d.ParentDiagramID != nil {
 			if newParent, ok := idMap[*d.ParentDiagramID]; ok {
@@ -362,11 +1108,12 @@ func (s *BackupService) insertRestoredData(
 			}
 		}
 		if err := s.diagramRepo.Create(ctx, diagram); err != nil {
-			return nil, fmt.Errorf("creating diagram %q: %w", d.DiagramName, err)
+			return fmt.Errorf("creating diagram %q: %w", d.DiagramName, err)
 		}
+		inserted.diagrams = append(inserted.diagrams, diagram.ID)
 	}
 
-	// 4. Pre-generate IDs for nodes
+	// 2. Pre-generate IDs for nodes
 	for _, n := range payload.Nodes {
 		idMap[n.ID] = primitive.NewObjectID()
 	}
@@ -376,58 +1123,86 @@ func (s *BackupService) insertRestoredData(
 		node := &domain.Node{
 			ID:                       idMap[n.ID],
 			DiagramID:                idMap[n.DiagramID],
+			Label:                    n.Label,
 			EncryptedReadme:          n.EncryptedReadme,
 			EncryptedReadmeSignature: n.EncryptedReadmeSignature,
 			EncryptedDict:            n.EncryptedDict,
 			EncryptedDictSignature:   n.EncryptedDictSignature,
 		}
 		if err := s.nodeRepo.Create(ctx, node); err != nil {
-			return nil, fmt.Errorf("creating node: %w", err)
+			return fmt.Errorf("creating node: %w", err)
 		}
+		inserted.nodes = append(inserted.nodes, node.ID)
 	}
 
-	// 5. Insert vaults
+	// 3. Insert vaults
 	for _, v := range payload.Vaults {
 		vault := &domain.NodeVault{
-			ProjectId:               newProjectID,
+			ProjectId:               projectID,
 			NodeId:                  idMap[v.NodeID],
 			Label:                   v.Label,
 			Type:                    v.Type,
 			EncryptedValue:          v.EncryptedValue,
 			EncryptedValueSignature: v.EncryptedValueSignature,
+			CreatedBy:               userID,
+			UpdatedBy:               userID,
 		}
 		if err := s.nodeVaultRepo.Create(ctx, vault); err != nil {
-			return nil, fmt.Errorf("creating vault: %w", err)
+			return fmt.Errorf("creating vault: %w", err)
 		}
+		inserted.vaults = append(inserted.vaults, vault.ID)
 	}
 
-	// 6. Pre-generate IDs for notes so parent references can be resolved
-	for _, n := range payload.Notes {
-		idMap[n.ID] = primitive.NewObjectID()
-	}
+	return nil
+}
 
-	// Insert notes
-	for _, n := range payload.Notes {
-		note := &domain.Note{
-			ID:                        idMap[n.ID],
-			ProjectID:                 newProjectID,
-			Type:                      n.Type,
-			FileName:                  n.FileName,
-			Icon:                      n.Icon,
-			EncryptedContent:          n.EncryptedContent,
-			EncryptedContentSignature: n.EncryptedContentSignature,
-		}
-		if n.ParentID != nil {
-			if newParent, ok := idMap[*n.ParentID]; ok {
-				note.ParentID = &newParent
-			}
-		}
-		if err := s.noteRepo.Create(ctx, note); err != nil {
-			return nil, fmt.Errorf("creating note %q: %w", n.FileName, err)
+// insertedRecords tracks the documents a restore has written so far
+type insertedRecords struct {
+	project  *primitive.ObjectID
+	owner    *primitive.ObjectID
+	diagrams []primitive.ObjectID
+	nodes    []primitive.ObjectID
+	vaults   []primitive.ObjectID
+	notes    []primitive.ObjectID
+}
+
+// undoInsert deletes everything recorded in inserted after cause made a
+// restore fail. It returns cause wrapped in ErrBackupRestoreFailed once the
+// cleanup succeeded; if documents may remain, cause is returned without it.
+func (s *BackupService) undoInsert(ctx context.Context, inserted *insertedRecords, cause error) error {
+	// The request may have failed because its context ended; the cleanup
+	// must still run
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), restoreCleanupTimeout)
+	defer cancel()
+
+	var errs []error
+	for _, id := range inserted.vaults {
+		errs = append(errs, s.nodeVaultRepo.Delete(ctx, id))
+	}
+	for _, id := range inserted.nodes {
+		errs = append(errs, s.nodeRepo.Delete(ctx, id))
+	}
+	for _, id := range inserted.diagrams {
+		errs = append(errs, s.diagramRepo.Delete(ctx, id))
+	}
+	for _, id := range inserted.notes {
+		errs = append(errs, s.noteRepo.Delete(ctx, id))
+	}
+	if inserted.project != nil {
+		if inserted.owner != nil {
+			errs = append(errs, s.memberRepo.Delete(ctx, *inserted.project, *inserted.owner))
 		}
+		errs = append(errs, s.projectRepo.Delete(ctx, *inserted.project))
 	}
 
-	return project, nil
+	if err := errors.Join(errs...); err != nil {
+		logger.Error().
+			Err(err).
+			AnErr("cause", cause).
+			Msg("Failed to clean up after a failed restore")
+		return cause
+	}
+	return fmt.Errorf("%w: %w", ErrBackupRestoreFailed, cause)
 }
 
 // ---------------------------------------------------------------------------
@@ -488,6 +1263,7 @@ func toNodeBackups(nodes []*domain.Node) []domain.NodeBackup {
 		result[i] = domain.NodeBackup{
 			ID:                       n.ID.Hex(),
 			DiagramID:                n.DiagramID.Hex(),
+			Label:                    n.Label,
 			EncryptedReadme:          n.EncryptedReadme,
 			EncryptedReadmeSignature: n.EncryptedReadmeSignature,
 			EncryptedDict:            n.EncryptedDict,
@@ -541,6 +1317,15 @@ func toNoteBackups(notes []*domain.Note) []domain.NoteBackup {
 // Helpers
 // ---------------------------------------------------------------------------
 
+func (s *BackupService) publish(eventType event.Type, projectID, userID, resourceID primitive.ObjectID) {
+	s.events.Publish(event.Event{
+		Type:       eventType,
+		ProjectID:  projectID,
+		ActorID:    userID,
+		ResourceID: resourceID,
+	})
+}
+
 // toCryptoParams converts the service-level Argon2 params to the crypto
 // package format, always using 32-byte (AES-256) key length.
 func (s *BackupService) toCryptoParams() *crypto.Argon2Params {
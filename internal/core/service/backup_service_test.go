@@ -0,0 +1,429 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/compression"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// testArgon2Params keeps key derivation cheap in tests
+var testArgon2Params = &Argon2Params{Memory: 8 * 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+
+// restoreStore records the documents a restore wrote, per collection, and
+// can fail the nth create or every delete of a collection
+type restoreStore struct {
+	docs        map[string]map[primitive.ObjectID]bool
+	failCreate  map[string]int
+	createErr   error
+	failDeletes map[string]error
+}
+
+func newRestoreStore() *restoreStore {
+	return &restoreStore{
+		docs:        make(map[string]map[primitive.ObjectID]bool),
+		failCreate:  make(map[string]int),
+		failDeletes: make(map[string]error),
+	}
+}
+
+func (s *restoreStore) create(collection string, id primitive.ObjectID) error {
+	if n, ok := s.failCreate[collection]; ok {
+		if n == 0 {
+			return s.createErr
+		}
+		s.failCreate[collection] = n - 1
+	}
+	if s.docs[collection] == nil {
+		s.docs[collection] = make(map[primitive.ObjectID]bool)
+	}
+	s.docs[collection][id] = true
+	return nil
+}
+
+func (s *restoreStore) delete(collection string, id primitive.ObjectID) error {
+	if err := s.failDeletes[collection]; err != nil {
+		return err
+	}
+	delete(s.docs[collection], id)
+	return nil
+}
+
+func (s *restoreStore) remaining() map[string]int {
+	left := make(map[string]int)
+	for collection, docs := range s.docs {
+		if len(docs) > 0 {
+			left[collection] = len(docs)
+		}
+	}
+	return left
+}
+
+type restoreProjectRepo struct {
+	port.ProjectRepository
+	store *restoreStore
+}
+
+func (r restoreProjectRepo) Create(_ context.Context, p *domain.Project) error {
+	return r.store.create("projects", p.ID)
+}
+
+func (r restoreProjectRepo) Delete(_ context.Context, id primitive.ObjectID) error {
+	return r.store.delete("projects", id)
+}
+
+// Memberships are keyed by project, as a restore creates only the owner
+type restoreMemberRepo struct {
+	port.ProjectMemberRepository
+	store *restoreStore
+}
+
+func (r restoreMemberRepo) Create(_ context.Context, m *domain.ProjectMember) error {
+	return r.store.create("members", m.ProjectID)
+}
+
+func (r restoreMemberRepo) Delete(_ context.Context, projectID, _ primitive.ObjectID) error {
+	return r.store.delete("members", projectID)
+}
+
+type restoreDiagramRepo struct {
+	port.DiagramRepository
+	store *restoreStore
+}
+
+func (r restoreDiagramRepo) Create(_ context.Context, d *domain.Diagram) error {
+	return r.store.create("diagrams", d.ID)
+}
+
+func (r restoreDiagramRepo) Delete(_ context.Context, id primitive.ObjectID) error {
+	return r.store.delete("diagrams", id)
+}
+
+type restoreNodeRepo struct {
+	port.NodeRepository
+	store *restoreStore
+}
+
+func (r restoreNodeRepo) Create(_ context.Context, n *domain.Node) error {
+	return r.store.create("nodes", n.ID)
+}
+
+func (r restoreNodeRepo) Delete(_ context.Context, id primitive.ObjectID) error {
+	return r.store.delete("nodes", id)
+}
+
+type restoreVaultRepo struct {
+	port.NodeVaultRepository
+	store *restoreStore
+}
+
+func (r restoreVaultRepo) Create(_ context.Context, v *domain.NodeVault) error {
+	v.ID = primitive.NewObjectID()
+	return r.store.create("vaults", v.ID)
+}
+
+func (r restoreVaultRepo) Delete(_ context.Context, id primitive.ObjectID) error {
+	return r.store.delete("vaults", id)
+}
+
+type restoreNoteRepo struct {
+	port.NoteRepository
+	store *restoreStore
+}
+
+func (r restoreNoteRepo) Create(_ context.Context, n *domain.Note) error {
+	return r.store.create("notes", n.ID)
+}
+
+func (r restoreNoteRepo) Delete(_ context.Context, id primitive.ObjectID) error {
+	return r.store.delete("notes", id)
+}
+
+func newRestoreTestService(t testing.TB, store *restoreStore) *BackupService {
+	t.Helper()
+	peppers, err := ParseBackupPeppers(0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewBackupService(nil, nil,
+		restoreProjectRepo{store: store},
+		restoreMemberRepo{store: store},
+		restoreNoteRepo{store: store},
+		restoreDiagramRepo{store: store},
+		restoreNodeRepo{store: store},
+		restoreVaultRepo{store: store},
+		nil, nil, nil,
+		testArgon2Params,
+		BackupCompression{Algorithm: compression.AlgorithmZstd},
+		peppers,
+		&fakePublisher{},
+	)
+}
+
+func testBackupPayload() *domain.BackupPayload {
+	folderID, diagramID, nodeID := "n1", "d1", "x1"
+	return &domain.BackupPayload{
+		Version:  domain.BackupVersion,
+		Project:  domain.ProjectBackup{ID: "p1", Name: "Infra"},
+		Diagrams: []domain.DiagramBackup{{ID: diagramID, DiagramName: "Network"}},
+		Nodes:    []domain.NodeBackup{{ID: nodeID, DiagramID: diagramID, Label: "router"}},
+		Vaults:   []domain.VaultBackup{{NodeID: nodeID, Label: "admin", Type: domain.VaultTypePassword}},
+		Notes: []domain.NoteBackup{
+			{ID: folderID, Type: domain.NoteTypeFolder, FileName: "Runbooks"},
+			{ID: "n2", ParentID: &folderID, Type: domain.NoteTypeNote, FileName: "Failover"},
+		},
+	}
+}
+
+func TestRestoreBackupUndoesPartialRestore(t *testing.T) {
+	const password = "correct horse battery staple"
+	noteFailure := errors.New("note insert failed")
+
+	tests := []struct {
+		name string
+		// failDelete makes the cleanup fail for one collection
+		failDelete string
+		wantLeft   map[string]int
+	}{
+		{name: "cleanup succeeds", wantLeft: map[string]int{}},
+		{name: "cleanup fails", failDelete: "vaults", wantLeft: map[string]int{"vaults": 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := newRestoreStore()
+			svc := newRestoreTestService(t, store)
+			archive, err := svc.buildArchive(testBackupPayload(), password)
+			if err != nil {
+				t.Fatalf("buildArchive: %v", err)
+			}
+
+			// The second note fails after everything else went in
+			store.failCreate["notes"] = 1
+			store.createErr = noteFailure
+			if tt.failDelete != "" {
+				store.failDeletes[tt.failDelete] = errors.New("delete failed")
+			}
+
+			_, err = svc.RestoreBackup(context.Background(), primitive.NewObjectID(), password, bytes.NewReader(archive), nil)
+			if !errors.Is(err, noteFailure) {
+				t.Fatalf("RestoreBackup = %v, want the insert failure as cause", err)
+			}
+			cleanedUp := tt.failDelete == ""
+			if errors.Is(err, ErrBackupRestoreFailed) != cleanedUp {
+				t.Errorf("errors.Is(err, ErrBackupRestoreFailed) = %v, want %v", !cleanedUp, cleanedUp)
+			}
+
+			left := store.remaining()
+			if len(left) != len(tt.wantLeft) {
+				t.Fatalf("documents left = %v, want %v", left, tt.wantLeft)
+			}
+			for collection, n := range tt.wantLeft {
+				if left[collection] != n {
+					t.Errorf("%s left = %d, want %d", collection, left[collection], n)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreBackupInsertsEverything(t *testing.T) {
+	const password = "correct horse battery staple"
+	store := newRestoreStore()
+	svc := newRestoreTestService(t, store)
+	archive, err := svc.buildArchive(testBackupPayload(), password)
+	if err != nil {
+		t.Fatalf("buildArchive: %v", err)
+	}
+
+	project, err := svc.RestoreBackup(context.Background(), primitive.NewObjectID(), password, bytes.NewReader(archive), nil)
+	if err != nil {
+		t.Fatalf("RestoreBackup: %v", err)
+	}
+	if project.Name != "Infra" {
+		t.Errorf("project name = %q, want Infra", project.Name)
+	}
+	want := map[string]int{"projects": 1, "members": 1, "diagrams": 1, "nodes": 1, "vaults": 1, "notes": 2}
+	left := store.remaining()
+	for collection, n := range want {
+		if left[collection] != n {
+			t.Errorf("%s = %d, want %d", collection, left[collection], n)
+		}
+	}
+}
+
+// mergeStore keeps the full documents a merge or duplication writes next to
+// the project's existing ones, so remapped references can be followed
+type mergeStore struct {
+	diagrams []*domain.Diagram
+	nodes    []*domain.Node
+	vaults   []*domain.NodeVault
+	notes    []*domain.Note
+}
+
+type mergeDiagramRepo struct {
+	port.DiagramRepository
+	store *mergeStore
+}
+
+func (r mergeDiagramRepo) FindAllByProjectID(_ context.Context, projectID primitive.ObjectID) ([]*domain.Diagram, error) {
+	var found []*domain.Diagram
+	for _, d := range r.store.diagrams {
+		if d.ProjectID == projectID {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+func (r mergeDiagramRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.Diagram, error) {
+	for _, d := range r.store.diagrams {
+		if d.ID == id {
+			return d, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r mergeDiagramRepo) Create(_ context.Context, d *domain.Diagram) error {
+	r.store.diagrams = append(r.store.diagrams, d)
+	return nil
+}
+
+type mergeNodeRepo struct {
+	port.NodeRepository
+	store *mergeStore
+}
+
+func (r mergeNodeRepo) FindByDiagramIDs(_ context.Context, diagramIDs []primitive.ObjectID) ([]*domain.Node, error) {
+	var found []*domain.Node
+	for _, n := range r.store.nodes {
+		if slices.Contains(diagramIDs, n.DiagramID) {
+			found = append(found, n)
+		}
+	}
+	return found, nil
+}
+
+func (r mergeNodeRepo) Create(_ context.Context, n *domain.Node) error {
+	r.store.nodes = append(r.store.nodes, n)
+	return nil
+}
+
+type mergeVaultRepo struct {
+	port.NodeVaultRepository
+	store *mergeStore
+}
+
+func (r mergeVaultRepo) FindByProjectID(_ context.Context, projectID primitive.ObjectID) ([]*domain.NodeVault, error) {
+	var found []*domain.NodeVault
+	for _, v := range r.store.vaults {
+		if v.ProjectId == projectID {
+			found = append(found, v)
+		}
+	}
+	return found, nil
+}
+
+func (r mergeVaultRepo) Create(_ context.Context, v *domain.NodeVault) error {
+	v.ID = primitive.NewObjectID()
+	r.store.vaults = append(r.store.vaults, v)
+	return nil
+}
+
+type mergeNoteRepo struct {
+	port.NoteRepository
+	store *mergeStore
+}
+
+func (r mergeNoteRepo) FindByProjectID(_ context.Context, projectID primitive.ObjectID) ([]*domain.Note, error) {
+	var found []*domain.Note
+	for _, n := range r.store.notes {
+		if n.ProjectID == projectID {
+			found = append(found, n)
+		}
+	}
+	return found, nil
+}
+
+func (r mergeNoteRepo) Create(_ context.Context, n *domain.Note) error {
+	r.store.notes = append(r.store.notes, n)
+	return nil
+}
+
+func TestMergeBackupRemapsIDsAndRenamesCollisions(t *testing.T) {
+	const password = "correct horse battery staple"
+	projectID, ownerID := primitive.NewObjectID(), primitive.NewObjectID()
+	store := &mergeStore{
+		diagrams: []*domain.Diagram{{ID: primitive.NewObjectID(), ProjectID: projectID, DiagramName: "Network"}},
+		notes: []*domain.Note{
+			{ID: primitive.NewObjectID(), ProjectID: projectID, FileName: "Runbooks"},
+			{ID: primitive.NewObjectID(), ProjectID: projectID, FileName: "Runbooks (restored)"},
+		},
+	}
+	members := &fakeMemberRepo{members: []*domain.ProjectMember{{ProjectID: projectID, UserID: ownerID, Role: domain.RoleOwner}}}
+	peppers, err := ParseBackupPeppers(0, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewBackupService(nil, NewAuthorizationService(members),
+		&fakeProjectRepo{projects: []*domain.Project{{ID: projectID, Name: "Target"}}},
+		members, mergeNoteRepo{store: store}, mergeDiagramRepo{store: store}, mergeNodeRepo{store: store}, mergeVaultRepo{store: store},
+		nil, nil, nil, testArgon2Params, BackupCompression{Algorithm: compression.AlgorithmZstd}, peppers, &fakePublisher{})
+
+	// A child diagram keeps its name; only top-level items can collide
+	payload := testBackupPayload()
+	parentID := "d1"
+	payload.Diagrams = append(payload.Diagrams, domain.DiagramBackup{ID: "d2", ParentDiagramID: &parentID, DiagramName: "Network"})
+	payload.Nodes[0].DiagramID = "d2"
+	archive, err := svc.buildArchive(payload, password)
+	if err != nil {
+		t.Fatalf("buildArchive: %v", err)
+	}
+
+	if _, err := svc.MergeBackup(context.Background(), projectID, ownerID, password, bytes.NewReader(archive)); err != nil {
+		t.Fatalf("MergeBackup: %v", err)
+	}
+
+	merged := store.diagrams[1:]
+	if len(merged) != 2 {
+		t.Fatalf("merged %d diagrams, want 2", len(merged))
+	}
+	root, child := merged[0], merged[1]
+	if root.DiagramName != "Network (restored)" || root.ParentDiagramID != nil {
+		t.Errorf("root = %q under %v, want \"Network (restored)\" at the top level", root.DiagramName, root.ParentDiagramID)
+	}
+	if child.DiagramName != "Network" || child.ParentDiagramID == nil || *child.ParentDiagramID != root.ID {
+		t.Errorf("child = %q under %v, want \"Network\" under the merged root", child.DiagramName, child.ParentDiagramID)
+	}
+	for _, d := range merged {
+		if d.ProjectID != projectID || d.ID == store.diagrams[0].ID {
+			t.Errorf("diagram %s was not given a fresh ID in the target project", d.ID.Hex())
+		}
+	}
+
+	if len(store.nodes) != 1 || store.nodes[0].DiagramID != child.ID {
+		t.Fatalf("nodes = %+v, want one in the merged child diagram", store.nodes)
+	}
+	if len(store.vaults) != 1 || store.vaults[0].NodeId != store.nodes[0].ID || store.vaults[0].ProjectId != projectID {
+		t.Errorf("vaults = %+v, want one on the merged node", store.vaults)
+	}
+
+	notes := store.notes[2:]
+	if len(notes) != 2 {
+		t.Fatalf("merged %d notes, want 2", len(notes))
+	}
+	folder, note := notes[0], notes[1]
+	if folder.FileName != "Runbooks (restored 2)" {
+		t.Errorf("folder name = %q, want \"Runbooks (restored 2)\"", folder.FileName)
+	}
+	if note.FileName != "Failover" || note.ParentID == nil || *note.ParentID != folder.ID {
+		t.Errorf("note = %q under %v, want \"Failover\" under the merged folder", note.FileName, note.ParentID)
+	}
+}
@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// countingMemberRepo counts membership lookups, i.e. permission checks
+type countingMemberRepo struct {
+	fakeMemberRepo
+	lookups int
+}
+
+func (r *countingMemberRepo) FindByProjectAndUser(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
+	r.lookups++
+	return r.fakeMemberRepo.FindByProjectAndUser(ctx, projectID, userID)
+}
+
+// diagramCascade records which diagrams had their dependents removed
+type diagramCascade struct {
+	cleared []primitive.ObjectID
+}
+
+type cascadeNodeRepo struct {
+	port.NodeRepository
+	*diagramCascade
+}
+
+func (r cascadeNodeRepo) DeleteByDiagramID(_ context.Context, id primitive.ObjectID) error {
+	r.cleared = append(r.cleared, id)
+	return nil
+}
+
+type cascadeShareLinkRepo struct{ port.ShareLinkRepository }
+
+func (cascadeShareLinkRepo) DeleteByDiagramID(context.Context, primitive.ObjectID) error { return nil }
+
+type cascadeCommentRepo struct{ port.CommentRepository }
+
+func (cascadeCommentRepo) DeleteByDiagramID(context.Context, primitive.ObjectID) error { return nil }
+
+func editorMembers(projectID, userID primitive.ObjectID, permissions ...domain.Permission) *countingMemberRepo {
+	return &countingMemberRepo{fakeMemberRepo: fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: userID, Role: domain.RoleEditor, Permissions: domain.PermissionStrings(permissions)},
+	}}}
+}
+
+func TestDeleteNotesMixedBatch(t *testing.T) {
+	ctx := context.Background()
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	first := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: domain.NoteTypeNote}
+	second := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: domain.NoteTypeFolder}
+	foreign := &domain.Note{ID: primitive.NewObjectID(), ProjectID: primitive.NewObjectID(), Type: domain.NoteTypeNote}
+	child := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, ParentID: &second.ID, Type: domain.NoteTypeNote}
+	missing := primitive.NewObjectID()
+
+	repo := &fakeNoteRepo{notes: []*domain.Note{first, second, foreign, child}}
+	members := editorMembers(projectID, userID, domain.PermissionViewNote, domain.PermissionEditNote)
+	events := &fakePublisher{}
+	svc := NewNoteService(repo, NewAuthorizationService(members), nil, PayloadLimits{}, events)
+
+	errs, err := svc.DeleteNotes(ctx, projectID, userID, []primitive.ObjectID{first.ID, missing, foreign.ID, second.ID, first.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []error{nil, ErrNoteNotFound, ErrNoteNotFound, nil, nil}
+	for i := range want {
+		if !errors.Is(errs[i], want[i]) {
+			t.Errorf("result %d = %v, want %v", i, errs[i], want[i])
+		}
+	}
+	if members.lookups != 1 {
+		t.Errorf("permission checked %d times, want once", members.lookups)
+	}
+	// The other project's note and the folder's child stay
+	if len(repo.notes) != 2 || !slices.Contains(repo.notes, foreign) || !slices.Contains(repo.notes, child) {
+		t.Errorf("left %d notes, want the foreign note and the child", len(repo.notes))
+	}
+	if len(events.events) != 2 || events.events[0].Type != event.NoteDeleted {
+		t.Errorf("published %v, want one deletion per deleted note", events.events)
+	}
+}
+
+func TestDeleteNotesNeedsEditPermission(t *testing.T) {
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	note := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: domain.NoteTypeNote}
+	repo := &fakeNoteRepo{notes: []*domain.Note{note}}
+	members := editorMembers(projectID, userID, domain.PermissionViewNote)
+	svc := NewNoteService(repo, NewAuthorizationService(members), nil, PayloadLimits{}, &fakePublisher{})
+
+	if _, err := svc.DeleteNotes(context.Background(), projectID, userID, []primitive.ObjectID{note.ID}); !errors.Is(err, ErrInsufficientPermission) {
+		t.Errorf("err = %v, want %v", err, ErrInsufficientPermission)
+	}
+	if len(repo.notes) != 1 {
+		t.Error("note deleted without edit permission")
+	}
+}
+
+func TestDeleteDiagramsMixedBatch(t *testing.T) {
+	ctx := context.Background()
+	projectID, userID, other := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	free := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID}
+	locked := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID}
+	foreign := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: primitive.NewObjectID()}
+
+	repo := &fakeDiagramRepo{diagrams: []*domain.Diagram{free, locked, foreign}}
+	members := editorMembers(projectID, userID, domain.PermissionViewDiagram, domain.PermissionEditDiagram)
+	locks := NewDiagramLocks(time.Minute)
+	if _, err := locks.acquire(projectID, locked.ID, other, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	cascade := &diagramCascade{}
+	svc := NewDiagramService(repo, NewAuthorizationService(members), nil, cascadeNodeRepo{diagramCascade: cascade}, nil,
+		cascadeShareLinkRepo{}, cascadeCommentRepo{}, locks, NewDiagramPathCache(0), PayloadLimits{}, &fakePublisher{})
+
+	errs, err := svc.DeleteDiagrams(ctx, projectID, userID, []primitive.ObjectID{free.ID, locked.ID, foreign.ID, primitive.NewObjectID()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []error{nil, ErrDiagramLocked, ErrDiagramNotFound, ErrDiagramNotFound}
+	for i := range want {
+		if !errors.Is(errs[i], want[i]) {
+			t.Errorf("result %d = %v, want %v", i, errs[i], want[i])
+		}
+	}
+	if members.lookups != 1 {
+		t.Errorf("permission checked %d times, want once", members.lookups)
+	}
+	if len(repo.diagrams) != 2 || slices.Contains(repo.diagrams, free) {
+		t.Errorf("left %d diagrams, want all but the free one", len(repo.diagrams))
+	}
+	if !slices.Equal(cascade.cleared, []primitive.ObjectID{free.ID}) {
+		t.Errorf("cleared nodes of %v, want only the deleted diagram", cascade.cleared)
+	}
+}
@@ -0,0 +1,113 @@
+package service
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxDiagramPathEntries bounds the cache; past it expired entries are swept
+// and, if that is not enough, the cache starts over.
+const maxDiagramPathEntries = 10000
+
+// DiagramPathCache keeps computed diagram breadcrumb paths, ancestors and
+// their siblings, for a short time. Any diagram change in a project drops
+// all of that project's entries, since a rename or move changes the
+// siblings shown for other diagrams too. It is shared by the breadcrumb
+// service, which fills it, and the diagram service, which drops entries as
+// soon as a diagram changes.
+type DiagramPathCache struct {
+	ttl     time.Duration
+	mu      sync.Mutex
+	entries map[primitive.ObjectID]diagramPathEntry
+}
+
+type diagramPathEntry struct {
+	projectID primitive.ObjectID
+	path      []dto.BreadcrumbItem
+	expiresAt time.Time
+}
+
+// NewDiagramPathCache returns a cache holding paths for ttl; a ttl of zero
+// disables caching
+func NewDiagramPathCache(ttl time.Duration) *DiagramPathCache {
+	return &DiagramPathCache{
+		ttl:     ttl,
+		entries: make(map[primitive.ObjectID]diagramPathEntry),
+	}
+}
+
+func (c *DiagramPathCache) get(projectID, diagramID primitive.ObjectID) ([]dto.BreadcrumbItem, bool) {
+	if c.ttl <= 0 {
+		return nil, false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[diagramID]
+	if !ok || entry.projectID != projectID {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(c.entries, diagramID)
+		return nil, false
+	}
+	// Callers mark the last item active on their copy
+	return slices.Clone(entry.path), true
+}
+
+func (c *DiagramPathCache) put(projectID, diagramID primitive.ObjectID, path []dto.BreadcrumbItem) {
+	if c.ttl <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if len(c.entries) >= maxDiagramPathEntries {
+		for id, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, id)
+			}
+		}
+		if len(c.entries) >= maxDiagramPathEntries {
+			clear(c.entries)
+		}
+	}
+
+	c.entries[diagramID] = diagramPathEntry{
+		projectID: projectID,
+		path:      slices.Clone(path),
+		expiresAt: now.Add(c.ttl),
+	}
+}
+
+// invalidateProject drops every path cached for the project
+func (c *DiagramPathCache) invalidateProject(projectID primitive.ObjectID) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for id, entry := range c.entries {
+		if entry.projectID == projectID {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// HandleEvent drops cached breadcrumbs of projects whose diagrams changed.
+// The diagram service drops its own changes directly, since the bus delivers
+// late and may drop events; this covers restores and project deletions. It
+// is registered on the event bus at startup.
+func (s *BreadcrumbService) HandleEvent(e event.Event) {
+	switch e.Type {
+	case event.DiagramCreated, event.DiagramUpdated, event.DiagramDeleted,
+		event.BackupRestored, event.ProjectDeleted, event.ProjectPurged:
+		s.diagramPaths.invalidateProject(e.ProjectID)
+	}
+}
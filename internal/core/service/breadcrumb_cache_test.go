@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiagramPathCache(t *testing.T) {
+	projectID, otherProjectID := primitive.NewObjectID(), primitive.NewObjectID()
+	diagramID, otherDiagramID := primitive.NewObjectID(), primitive.NewObjectID()
+	path := []dto.BreadcrumbItem{{Type: "diagram", ID: diagramID.Hex(), Label: "Network"}}
+
+	cache := NewDiagramPathCache(time.Minute)
+	cache.put(projectID, diagramID, path)
+	cache.put(otherProjectID, otherDiagramID, path)
+
+	got, ok := cache.get(projectID, diagramID)
+	if !ok || len(got) != 1 || got[0].Label != "Network" {
+		t.Fatalf("get = %v, %v; want the cached path", got, ok)
+	}
+	got[0].Active = true
+	if again, _ := cache.get(projectID, diagramID); again[0].Active {
+		t.Error("get returned the cached slice itself, not a copy")
+	}
+	if _, ok := cache.get(otherProjectID, diagramID); ok {
+		t.Error("path was served for another project")
+	}
+
+	cache.invalidateProject(projectID)
+	if _, ok := cache.get(projectID, diagramID); ok {
+		t.Error("path survived invalidating its project")
+	}
+	if _, ok := cache.get(otherProjectID, otherDiagramID); !ok {
+		t.Error("invalidating one project dropped another project's path")
+	}
+
+	disabled := NewDiagramPathCache(0)
+	disabled.put(projectID, diagramID, path)
+	if _, ok := disabled.get(projectID, diagramID); ok {
+		t.Error("a zero ttl cache returned a path")
+	}
+
+	expiring := NewDiagramPathCache(time.Millisecond)
+	expiring.put(projectID, diagramID, path)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := expiring.get(projectID, diagramID); ok {
+		t.Error("expired path was returned")
+	}
+}
+
+func TestDiagramChangesDropCachedBreadcrumbs(t *testing.T) {
+	ctx := context.Background()
+	userID := primitive.NewObjectID()
+	project := &domain.Project{ID: primitive.NewObjectID(), Name: "Infra"}
+	diagram := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: project.ID, DiagramName: "Network"}
+
+	members := &fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: project.ID, UserID: userID, Role: domain.RoleOwner},
+	}}
+	projects := &fakeProjectRepo{projects: []*domain.Project{project}}
+	diagrams := &fakeDiagramRepo{diagrams: []*domain.Diagram{diagram}}
+	authz := NewAuthorizationService(members)
+	paths := NewDiagramPathCache(time.Minute)
+
+	// No bus is wired up: the diagram service must drop the paths itself
+	breadcrumbs := NewBreadcrumbService(projects, nil, diagrams, nil, nil, authz, paths)
+	diagramService := NewDiagramService(diagrams, authz, projects, nil, nil, nil, nil,
+		NewDiagramLocks(time.Minute), paths, PayloadLimits{}, &fakePublisher{})
+
+	label := func() string {
+		t.Helper()
+		response, err := breadcrumbs.GetBreadcrumbs(ctx, project.ID.Hex(), userID, "diagram", diagram.ID.Hex())
+		if err != nil {
+			t.Fatalf("GetBreadcrumbs: %v", err)
+		}
+		return response.Path[len(response.Path)-1].Label
+	}
+
+	if got := label(); got != "Network" {
+		t.Fatalf("label = %q, want Network", got)
+	}
+	finds := diagrams.finds
+	if got := label(); got != "Network" || diagrams.finds != finds {
+		t.Fatalf("second lookup = %q with %d diagram reads, want a cache hit", got, diagrams.finds-finds)
+	}
+
+	renamed := "Network v2"
+	if _, err := diagramService.UpdateDiagram(ctx, diagram.ID, userID, &renamed, nil, nil, nil); err != nil {
+		t.Fatalf("UpdateDiagram: %v", err)
+	}
+	if got := label(); got != renamed {
+		t.Errorf("label after rename = %q, want %q", got, renamed)
+	}
+}
@@ -12,6 +12,10 @@ import (
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// maxNoteBreadcrumbDepth bounds how many folder levels a note breadcrumb
+// shows, guarding against corrupt parent chains
+const maxNoteBreadcrumbDepth = 64
+
 var (
 	ErrInvalidID           = errors.New("invalid id format")
 	ErrInvalidResourceType = errors.New("invalid resource type")
@@ -24,14 +28,20 @@ type BreadcrumbService struct {
 	diagramRepo   port.DiagramRepository
 	nodeRepo      port.NodeRepository
 	nodeVaultRepo port.NodeVaultRepository
+	authz         *AuthorizationService
+	diagramPaths  *DiagramPathCache
 }
 
+// NewBreadcrumbService creates a BreadcrumbService that caches diagram paths
+// in diagramPaths
 func NewBreadcrumbService(
 	projectRepo port.ProjectRepository,
 	noteRepo port.NoteRepository,
 	diagramRepo port.DiagramRepository,
 	nodeRepo port.NodeRepository,
 	nodeVaultRepo port.NodeVaultRepository,
+	authz *AuthorizationService,
+	diagramPaths *DiagramPathCache,
 ) *BreadcrumbService {
 	return &BreadcrumbService{
 		projectRepo:   projectRepo,
@@ -39,15 +49,22 @@ func NewBreadcrumbService(
 		diagramRepo:   diagramRepo,
 		nodeRepo:      nodeRepo,
 		nodeVaultRepo: nodeVaultRepo,
+		authz:         authz,
+		diagramPaths:  diagramPaths,
 	}
 }
 
-func (s *BreadcrumbService) GetBreadcrumbs(ctx context.Context, projectIDStr, resourceType, resourceIDStr string) (*dto.BreadcrumbResponse, error) {
+func (s *BreadcrumbService) GetBreadcrumbs(ctx context.Context, projectIDStr string, userID primitive.ObjectID, resourceType, resourceIDStr string) (*dto.BreadcrumbResponse, error) {
 	projectID, err := primitive.ObjectIDFromHex(projectIDStr)
 	if err != nil {
 		return nil, ErrInvalidID
 	}
 
+	// Breadcrumbs expose names, so only members may resolve them
+	if _, err := s.authz.GetMember(ctx, projectID, userID); err != nil {
+		return nil, concealNonMember(err, ErrProjectNotFound)
+	}
+
 	// Verify project exists
 	project, err := s.projectRepo.FindByID(ctx, projectID)
 	if err != nil {
@@ -130,30 +147,46 @@ func (s *BreadcrumbService) handleNoteBreadcrumb(ctx context.Context, projectID,
 		return nil, ErrResourceNotFound
 	}
 
-	// Fetch siblings (other notes in project) - fetch up to 100 for now
+	// One query covers every level; folders and their siblings are resolved
+	// in memory
 	notes, err := s.noteRepo.FindByProjectID(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
-
-	siblings := make([]dto.BreadcrumbItem, 0, len(notes))
+	byID := make(map[primitive.ObjectID]*domain.Note, len(notes))
 	for _, n := range notes {
-		if n.ID != note.ID {
-			siblings = append(siblings, dto.BreadcrumbItem{
-				Type:  "note",
-				ID:    n.ID.Hex(),
-				Label: n.FileName,
-			})
+		byID[n.ID] = n
+	}
+
+	// Walk up the folders; a parent that is gone, or a cycle, ends the path
+	// there rather than failing the request
+	chain := []*domain.Note{note}
+	visited := map[primitive.ObjectID]struct{}{note.ID: {}}
+	for current := note; current.ParentID != nil && len(chain) < maxNoteBreadcrumbDepth; {
+		parent, ok := byID[*current.ParentID]
+		if !ok {
+			break
 		}
+		if _, seen := visited[parent.ID]; seen {
+			logger.Warn().Msgf("Note folder cycle detected: NoteID=%s, ProjectID=%s", parent.ID.Hex(), projectID.Hex())
+			break
+		}
+		visited[parent.ID] = struct{}{}
+		chain = append(chain, parent)
+		current = parent
 	}
 
-	path := append(basePath, dto.BreadcrumbItem{
-		Type:     "note",
-		ID:       note.ID.Hex(),
-		Label:    note.FileName,
-		Active:   true,
-		Siblings: siblings,
-	})
+	path := basePath
+	for i := len(chain) - 1; i >= 0; i-- {
+		n := chain[i]
+		path = append(path, dto.BreadcrumbItem{
+			Type:     "note",
+			ID:       n.ID.Hex(),
+			Label:    n.FileName,
+			Siblings: noteSiblings(notes, n),
+		})
+	}
+	path[len(path)-1].Active = true
 
 	return &dto.BreadcrumbResponse{
 		ProjectID: projectID.Hex(),
@@ -161,6 +194,26 @@ func (s *BreadcrumbService) handleNoteBreadcrumb(ctx context.Context, projectID,
 	}, nil
 }
 
+// noteSiblings lists the notes sharing note's folder, excluding note itself
+func noteSiblings(notes []*domain.Note, note *domain.Note) []dto.BreadcrumbItem {
+	siblings := make([]dto.BreadcrumbItem, 0)
+	for _, n := range notes {
+		if n.ID == note.ID {
+			continue
+		}
+		sameParent := (n.ParentID == nil && note.ParentID == nil) ||
+			(n.ParentID != nil && note.ParentID != nil && *n.ParentID == *note.ParentID)
+		if sameParent {
+			siblings = append(siblings, dto.BreadcrumbItem{
+				Type:  "note",
+				ID:    n.ID.Hex(),
+				Label: n.FileName,
+			})
+		}
+	}
+	return siblings
+}
+
 func (s *BreadcrumbService) handleDiagramBreadcrumb(ctx context.Context, projectID, diagramID primitive.ObjectID, basePath []dto.BreadcrumbItem) (*dto.BreadcrumbResponse, error) {
 	diagramPath, err := s.buildDiagramPath(ctx, projectID, diagramID)
 	if err != nil {
@@ -183,6 +236,10 @@ type diagramNode struct {
 }
 
 func (s *BreadcrumbService) buildDiagramPath(ctx context.Context, projectID, diagramID primitive.ObjectID) ([]dto.BreadcrumbItem, error) {
+	if path, ok := s.diagramPaths.get(projectID, diagramID); ok {
+		return path, nil
+	}
+
 	var path []dto.BreadcrumbItem
 
 	// We need to traverse up from the current diagram to the root (where ParentDiagramID is nil)
@@ -221,6 +278,7 @@ func (s *BreadcrumbService) buildDiagramPath(ctx context.Context, projectID, dia
 		})
 	}
 
+	s.diagramPaths.put(projectID, diagramID, path)
 	return path, nil
 }
 
@@ -279,7 +337,7 @@ func (s *BreadcrumbService) handleNodeBreadcrumb(ctx context.Context, projectID,
 	path = append(path, dto.BreadcrumbItem{
 		Type:   "node",
 		ID:     node.ID.Hex(),
-		Label:  "Node", // Nodes might not have names in current model, using generic label
+		Label:  nodeLabel(node),
 		Active: true,
 	})
 
@@ -317,7 +375,7 @@ func (s *BreadcrumbService) handleVaultBreadcrumb(ctx context.Context, projectID
 	path = append(path, dto.BreadcrumbItem{
 		Type:  "node",
 		ID:    node.ID.Hex(),
-		Label: "Node",
+		Label: nodeLabel(node),
 	})
 	path = append(path, dto.BreadcrumbItem{
 		Type:   "vault",
@@ -352,7 +410,7 @@ func (s *BreadcrumbService) handleNodeVaultListBreadcrumb(ctx context.Context, p
 	path = append(path, dto.BreadcrumbItem{
 		Type:  "node",
 		ID:    node.ID.Hex(),
-		Label: "Node",
+		Label: nodeLabel(node),
 	})
 	path = append(path, dto.BreadcrumbItem{
 		Type:   "node_vault",
@@ -366,3 +424,11 @@ func (s *BreadcrumbService) handleNodeVaultListBreadcrumb(ctx context.Context, p
 		Path:      path,
 	}, nil
 }
+
+// nodeLabel is the node's display label, or a generic one for unlabeled nodes
+func nodeLabel(node *domain.Node) string {
+	if node.Label != "" {
+		return node.Label
+	}
+	return "Node"
+}
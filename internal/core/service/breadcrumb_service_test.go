@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func breadcrumbLabels(items []dto.BreadcrumbItem) []string {
+	labels := make([]string, 0, len(items))
+	for _, item := range items {
+		labels = append(labels, item.Label)
+	}
+	return labels
+}
+
+func TestNoteBreadcrumbFollowsFolders(t *testing.T) {
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	newNote := func(noteType, name string, parent *domain.Note) *domain.Note {
+		n := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: noteType, FileName: name}
+		if parent != nil {
+			n.ParentID = &parent.ID
+		}
+		return n
+	}
+
+	folder := func(name string, parent *domain.Note) *domain.Note {
+		return newNote(domain.NoteTypeFolder, name, parent)
+	}
+
+	ops := folder("Ops", nil)
+	runbooks := folder("Runbooks", ops)
+	failover := newNote(domain.NoteTypeNote, "Failover", runbooks)
+	restore := newNote(domain.NoteTypeNote, "Restore", runbooks)
+	onCall := folder("On-call", ops)
+	topLevel := newNote(domain.NoteTypeNote, "Readme", nil)
+
+	// a and b are each other's parent
+	loopA, loopB := folder("Loop A", nil), folder("Loop B", nil)
+	loopA.ParentID, loopB.ParentID = &loopB.ID, &loopA.ID
+
+	notes := &fakeNoteRepo{notes: []*domain.Note{ops, runbooks, failover, restore, onCall, topLevel, loopA, loopB}}
+	svc := NewBreadcrumbService(
+		&fakeProjectRepo{projects: []*domain.Project{{ID: projectID, Name: "Infra"}}},
+		notes, nil, nil, nil,
+		NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+			{ProjectID: projectID, UserID: userID, Role: domain.RoleViewer},
+		}}),
+		NewDiagramPathCache(0),
+	)
+
+	t.Run("nested note", func(t *testing.T) {
+		resp, err := svc.GetBreadcrumbs(context.Background(), projectID.Hex(), userID, "note", failover.ID.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"Infra", "Ops", "Runbooks", "Failover"}
+		if got := breadcrumbLabels(resp.Path); !slices.Equal(got, want) {
+			t.Fatalf("path = %v, want %v", got, want)
+		}
+		last := resp.Path[len(resp.Path)-1]
+		if !last.Active || resp.Path[2].Active {
+			t.Error("only the note itself should be active")
+		}
+		if got := breadcrumbLabels(last.Siblings); !slices.Equal(got, []string{"Restore"}) {
+			t.Errorf("note siblings = %v, want only its folder mates", got)
+		}
+		if got := breadcrumbLabels(resp.Path[2].Siblings); !slices.Equal(got, []string{"On-call"}) {
+			t.Errorf("folder siblings = %v, want [On-call]", got)
+		}
+		if got := breadcrumbLabels(resp.Path[1].Siblings); !slices.Contains(got, "Readme") || slices.Contains(got, "Failover") {
+			t.Errorf("top-level siblings = %v", got)
+		}
+	})
+
+	t.Run("parent cycle", func(t *testing.T) {
+		resp, err := svc.GetBreadcrumbs(context.Background(), projectID.Hex(), userID, "note", loopA.ID.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := []string{"Infra", "Loop B", "Loop A"}
+		if got := breadcrumbLabels(resp.Path); !slices.Equal(got, want) {
+			t.Errorf("path = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("depth guard", func(t *testing.T) {
+		deep := &fakeNoteRepo{}
+		var parent *domain.Note
+		for range maxNoteBreadcrumbDepth + 10 {
+			parent = folder("level", parent)
+			deep.notes = append(deep.notes, parent)
+		}
+		deepSvc := *svc
+		deepSvc.noteRepo = deep
+		resp, err := deepSvc.GetBreadcrumbs(context.Background(), projectID.Hex(), userID, "note", parent.ID.Hex())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := len(resp.Path) - 1; got != maxNoteBreadcrumbDepth {
+			t.Errorf("path holds %d note levels, want %d", got, maxNoteBreadcrumbDepth)
+		}
+	})
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrCommentNotFound      = errors.New("comment not found")
+	ErrInvalidCommentTarget = errors.New("invalid comment target")
+)
+
+// CommentService manages discussion threads on projects, diagrams and nodes.
+// Any member may comment on the project itself; comments on diagrams and
+// nodes need view_diagram. Only the author edits a comment, while the author
+// or a member with manage_project may delete it.
+type CommentService struct {
+	commentRepo port.CommentRepository
+	diagramRepo port.DiagramRepository
+	nodeRepo    port.NodeRepository
+	authz       *AuthorizationService
+	events      event.Publisher
+}
+
+func NewCommentService(
+	commentRepo port.CommentRepository,
+	diagramRepo port.DiagramRepository,
+	nodeRepo port.NodeRepository,
+	authz *AuthorizationService,
+	events event.Publisher,
+) *CommentService {
+	return &CommentService{
+		commentRepo: commentRepo,
+		diagramRepo: diagramRepo,
+		nodeRepo:    nodeRepo,
+		authz:       authz,
+		events:      events,
+	}
+}
+
+// CreateComment adds a comment to the target. A reply to a reply joins the
+// thread of the comment it answers.
+func (s *CommentService) CreateComment(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	target domain.CommentTarget,
+	parentCommentID primitive.ObjectID,
+	encryptedBody, signature string,
+) (*domain.Comment, error) {
+	target, diagramID, _, err := s.checkTarget(ctx, projectID, userID, target)
+	if err != nil {
+		return nil, err
+	}
+
+	if !parentCommentID.IsZero() {
+		parent, err := s.findComment(ctx, projectID, userID, parentCommentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.Target() != target {
+			return nil, ErrCommentNotFound
+		}
+		if parent.IsReply() {
+			parentCommentID = parent.ParentCommentID
+		}
+	}
+
+	comment := &domain.Comment{
+		ProjectID:              projectID,
+		TargetType:             target.Type,
+		TargetID:               target.ID,
+		DiagramID:              diagramID,
+		ParentCommentID:        parentCommentID,
+		AuthorID:               userID,
+		EncryptedBody:          encryptedBody,
+		EncryptedBodySignature: signature,
+	}
+	if err := s.commentRepo.Create(ctx, comment); err != nil {
+		return nil, err
+	}
+
+	s.publish(event.CommentCreated, comment, userID)
+	return comment, nil
+}
+
+// ListComments lists the target's top-level comments, or the replies to
+// parentCommentID when it is set, oldest first after afterID
+func (s *CommentService) ListComments(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	target domain.CommentTarget,
+	parentCommentID, afterID primitive.ObjectID,
+	limit int,
+) ([]*domain.Comment, error) {
+	target, _, _, err := s.checkTarget(ctx, projectID, userID, target)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.commentRepo.FindByTargetAfter(ctx, projectID, target, parentCommentID, afterID, limit)
+}
+
+// UpdateComment replaces the body of the user's own comment
+func (s *CommentService) UpdateComment(
+	ctx context.Context,
+	projectID, commentID, userID primitive.ObjectID,
+	encryptedBody, signature string,
+) (*domain.Comment, error) {
+	comment, err := s.findComment(ctx, projectID, userID, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, _, err := s.checkTarget(ctx, projectID, userID, comment.Target()); err != nil {
+		return nil, err
+	}
+	if comment.AuthorID != userID {
+		return nil, ErrInsufficientPermission
+	}
+
+	updated, err := s.commentRepo.UpdateBody(ctx, commentID, encryptedBody, signature)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+
+	s.publish(event.CommentUpdated, updated, userID)
+	return updated, nil
+}
+
+// DeleteComment removes a comment and, for a top-level comment, its replies
+func (s *CommentService) DeleteComment(ctx context.Context, projectID, commentID, userID primitive.ObjectID) error {
+	comment, err := s.findComment(ctx, projectID, userID, commentID)
+	if err != nil {
+		return err
+	}
+	_, _, member, err := s.checkTarget(ctx, projectID, userID, comment.Target())
+	if err != nil {
+		return err
+	}
+	if comment.AuthorID != userID && !s.authz.Can(member, domain.PermissionManageProject) {
+		return ErrInsufficientPermission
+	}
+
+	if err := s.commentRepo.Delete(ctx, commentID); err != nil {
+		return err
+	}
+
+	s.publish(event.CommentDeleted, comment, userID)
+	return nil
+}
+
+// checkTarget verifies the user may see the target and that it belongs to
+// the project. It returns the target with a project target's ID filled in,
+// the diagram the target lives in and the user's membership. Non-members get
+// ErrProjectNotFound.
+func (s *CommentService) checkTarget(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	target domain.CommentTarget,
+) (domain.CommentTarget, primitive.ObjectID, *domain.ProjectMember, error) {
+	var noDiagram primitive.ObjectID
+	if !domain.IsValidCommentTarget(target.Type) {
+		return target, noDiagram, nil, ErrInvalidCommentTarget
+	}
+
+	member, err := s.authz.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return target, noDiagram, nil, concealNonMember(err, ErrProjectNotFound)
+	}
+
+	if target.Type == domain.CommentTargetProject {
+		target.ID = projectID
+		return target, noDiagram, member, nil
+	}
+
+	if target.ID.IsZero() {
+		return target, noDiagram, nil, ErrInvalidCommentTarget
+	}
+	if !s.authz.Can(member, domain.PermissionViewDiagram) {
+		return target, noDiagram, nil, ErrInsufficientPermission
+	}
+
+	diagramID := target.ID
+	notFound := ErrDiagramNotFound
+	if target.Type == domain.CommentTargetNode {
+		notFound = ErrNodeNotFound
+		node, err := s.nodeRepo.FindByID(ctx, target.ID)
+		if err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return target, noDiagram, nil, ErrNodeNotFound
+			}
+			return target, noDiagram, nil, err
+		}
+		if node == nil {
+			return target, noDiagram, nil, ErrNodeNotFound
+		}
+		diagramID = node.DiagramID
+	}
+
+	diagram, err := s.diagramRepo.FindByID(ctx, diagramID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return target, noDiagram, nil, notFound
+		}
+		return target, noDiagram, nil, err
+	}
+	if diagram == nil || diagram.ProjectID != projectID {
+		return target, noDiagram, nil, notFound
+	}
+	return target, diagramID, member, nil
+}
+
+// findComment loads a comment of the project. Membership is checked first so
+// non-members cannot probe for comment IDs.
+func (s *CommentService) findComment(ctx context.Context, projectID, userID, commentID primitive.ObjectID) (*domain.Comment, error) {
+	if _, err := s.authz.GetMember(ctx, projectID, userID); err != nil {
+		return nil, concealNonMember(err, ErrProjectNotFound)
+	}
+
+	comment, err := s.commentRepo.FindByID(ctx, commentID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrCommentNotFound
+		}
+		return nil, err
+	}
+	if comment == nil || comment.ProjectID != projectID {
+		return nil, ErrCommentNotFound
+	}
+	return comment, nil
+}
+
+func (s *CommentService) publish(eventType event.Type, comment *domain.Comment, userID primitive.ObjectID) {
+	s.events.Publish(event.Event{
+		Type:       eventType,
+		ProjectID:  comment.ProjectID,
+		ActorID:    userID,
+		ResourceID: comment.ID,
+	})
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDiagramLocksRenewalAndExpiry(t *testing.T) {
+	const ttl = time.Minute
+	locks := NewDiagramLocks(ttl)
+	projectID, diagramID := primitive.NewObjectID(), primitive.NewObjectID()
+	holder, other := primitive.NewObjectID(), primitive.NewObjectID()
+	start := time.Now()
+
+	lock, err := locks.acquire(projectID, diagramID, holder, start)
+	if err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	if !lock.ExpiresAt.Equal(start.Add(ttl)) {
+		t.Errorf("ExpiresAt = %v, want %v", lock.ExpiresAt, start.Add(ttl))
+	}
+
+	// The holder renews before expiry, pushing it out from the renewal
+	renewedAt := start.Add(ttl / 2)
+	renewed, err := locks.acquire(projectID, diagramID, holder, renewedAt)
+	if err != nil || !renewed.ExpiresAt.Equal(renewedAt.Add(ttl)) {
+		t.Fatalf("renew = %v, %v; want expiry at %v", renewed.ExpiresAt, err, renewedAt.Add(ttl))
+	}
+
+	// Past the original expiry the renewed lock still holds
+	stillHeld := start.Add(ttl + time.Second)
+	if _, err := locks.acquire(projectID, diagramID, other, stillHeld); !errors.Is(err, ErrDiagramLocked) {
+		t.Errorf("acquire by other while held = %v, want ErrDiagramLocked", err)
+	}
+	if err := locks.checkWrite(diagramID, other, stillHeld); !errors.Is(err, ErrDiagramLocked) {
+		t.Errorf("checkWrite by other while held = %v, want ErrDiagramLocked", err)
+	}
+	if err := locks.checkWrite(diagramID, holder, stillHeld); err != nil {
+		t.Errorf("checkWrite by holder = %v, want nil", err)
+	}
+
+	// Once the TTL runs out without renewal anyone may write or lock
+	expired := renewedAt.Add(ttl)
+	if err := locks.checkWrite(diagramID, other, expired); err != nil {
+		t.Errorf("checkWrite after expiry = %v, want nil", err)
+	}
+	if _, ok := locks.current(diagramID, expired); ok {
+		t.Error("expired lock is still reported")
+	}
+	taken, err := locks.acquire(projectID, diagramID, other, expired)
+	if err != nil || taken.HolderID != other {
+		t.Errorf("acquire after expiry = %+v, %v; want other as holder", taken, err)
+	}
+}
+
+func TestDiagramLockBlocksOtherEditors(t *testing.T) {
+	ctx := context.Background()
+	project := &domain.Project{ID: primitive.NewObjectID(), Name: "Infra"}
+	diagram := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: project.ID, DiagramName: "Network"}
+	node := &domain.Node{ID: primitive.NewObjectID(), DiagramID: diagram.ID, Label: "router"}
+
+	editor := []string{string(domain.PermissionViewDiagram), string(domain.PermissionEditDiagram)}
+	holder, other, manager := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	members := &fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: project.ID, UserID: holder, Role: domain.RoleEditor, Permissions: editor},
+		{ProjectID: project.ID, UserID: other, Role: domain.RoleEditor, Permissions: editor},
+		{ProjectID: project.ID, UserID: manager, Role: domain.RoleCustom,
+			Permissions: []string{string(domain.PermissionViewDiagram), string(domain.PermissionEditDiagram), string(domain.PermissionManageProject)}},
+	}}
+	diagrams := &fakeDiagramRepo{diagrams: []*domain.Diagram{diagram}}
+	projects := &fakeProjectRepo{projects: []*domain.Project{project}}
+	authz := NewAuthorizationService(members)
+	locks := NewDiagramLocks(time.Minute)
+	events := &fakePublisher{}
+
+	diagramService := NewDiagramService(diagrams, authz, projects, nil, nil, nil, nil,
+		locks, NewDiagramPathCache(0), PayloadLimits{}, events)
+	nodeService := NewNodeService(&fakeNodeRepo{nodes: []*domain.Node{node}}, diagrams, authz,
+		PayloadLimits{}, events, 0, locks)
+
+	rename := func(userID primitive.ObjectID) error {
+		name := "Network " + userID.Hex()
+		_, err := diagramService.UpdateDiagram(ctx, diagram.ID, userID, &name, nil, nil, nil)
+		return err
+	}
+	relabel := func(userID primitive.ObjectID) error {
+		label := "router " + userID.Hex()
+		_, err := nodeService.UpdateNode(ctx, node.ID.Hex(), userID, dto.UpdateNodeRequest{Label: &label})
+		return err
+	}
+
+	if _, err := diagramService.LockDiagram(ctx, project.ID, diagram.ID, holder); err != nil {
+		t.Fatalf("LockDiagram: %v", err)
+	}
+	if lock := diagramService.DiagramLock(diagram.ID); lock == nil || lock.HolderID != holder {
+		t.Fatalf("DiagramLock = %+v, want held by the holder", lock)
+	}
+
+	if err := rename(other); !errors.Is(err, ErrDiagramLocked) {
+		t.Errorf("UpdateDiagram by non-holder = %v, want ErrDiagramLocked", err)
+	}
+	if err := relabel(other); !errors.Is(err, ErrDiagramLocked) {
+		t.Errorf("UpdateNode by non-holder = %v, want ErrDiagramLocked", err)
+	}
+	if _, err := diagramService.LockDiagram(ctx, project.ID, diagram.ID, other); !errors.Is(err, ErrDiagramLocked) {
+		t.Errorf("LockDiagram by non-holder = %v, want ErrDiagramLocked", err)
+	}
+	if err := diagramService.UnlockDiagram(ctx, project.ID, diagram.ID, other); !errors.Is(err, ErrDiagramLocked) {
+		t.Errorf("UnlockDiagram by non-holder = %v, want ErrDiagramLocked", err)
+	}
+
+	if err := rename(holder); err != nil {
+		t.Errorf("UpdateDiagram by holder: %v", err)
+	}
+	if err := relabel(holder); err != nil {
+		t.Errorf("UpdateNode by holder: %v", err)
+	}
+
+	// manage_project breaks someone else's lock
+	if err := diagramService.UnlockDiagram(ctx, project.ID, diagram.ID, manager); err != nil {
+		t.Fatalf("forced UnlockDiagram: %v", err)
+	}
+	if lock := diagramService.DiagramLock(diagram.ID); lock != nil {
+		t.Errorf("lock survived a forced unlock: %+v", lock)
+	}
+	last := events.events[len(events.events)-1]
+	if last.Type != event.DiagramUnlocked || last.ActorID != manager {
+		t.Errorf("last event = %+v, want DiagramUnlocked by the manager", last)
+	}
+
+	if err := rename(other); err != nil {
+		t.Errorf("UpdateDiagram after unlock: %v", err)
+	}
+	if err := relabel(other); err != nil {
+		t.Errorf("UpdateNode after unlock: %v", err)
+	}
+}
@@ -3,36 +3,65 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
+	"time"
 
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// maxDiagramTreeDepth bounds how many levels GetDiagramTree returns
+const maxDiagramTreeDepth = 64
+
 var (
-	ErrDiagramNotFound     = errors.New("diagram not found")
-	ErrDiagramAccessDenied = errors.New("diagram access denied")
+	ErrDiagramNotFound    = errors.New("diagram not found")
+	ErrInvalidDiagramData = errors.New("invalid diagram data")
 )
 
 type DiagramService struct {
-	diagramRepo port.DiagramRepository
-	memberRepo  port.ProjectMemberRepository
-	projectRepo port.ProjectRepository
-	nodeRepo    port.NodeRepository
+	diagramRepo   port.DiagramRepository
+	authz         *AuthorizationService
+	projectRepo   port.ProjectRepository
+	nodeRepo      port.NodeRepository
+	vaultRepo     port.NodeVaultRepository
+	shareLinkRepo port.ShareLinkRepository
+	commentRepo   port.CommentRepository
+	locks         *DiagramLocks
+	diagramPaths  *DiagramPathCache
+	limits        PayloadLimits
+	events        event.Publisher
 }
 
 func NewDiagramService(
 	diagramRepo port.DiagramRepository,
-	memberRepo port.ProjectMemberRepository,
+	authz *AuthorizationService,
 	projectRepo port.ProjectRepository,
 	nodeRepo port.NodeRepository,
+	vaultRepo port.NodeVaultRepository,
+	shareLinkRepo port.ShareLinkRepository,
+	commentRepo port.CommentRepository,
+	locks *DiagramLocks,
+	diagramPaths *DiagramPathCache,
+	limits PayloadLimits,
+	events event.Publisher,
 ) *DiagramService {
 	return &DiagramService{
-		diagramRepo: diagramRepo,
-		memberRepo:  memberRepo,
-		projectRepo: projectRepo,
-		nodeRepo:    nodeRepo,
+		diagramRepo:   diagramRepo,
+		authz:         authz,
+		projectRepo:   projectRepo,
+		nodeRepo:      nodeRepo,
+		vaultRepo:     vaultRepo,
+		shareLinkRepo: shareLinkRepo,
+		commentRepo:   commentRepo,
+		locks:         locks,
+		diagramPaths:  diagramPaths,
+		limits:        limits,
+		events:        events,
 	}
 }
 
@@ -45,8 +74,12 @@ func (s *DiagramService) CreateDiagram(
 	encryptedData *string,
 	signature string,
 ) (*domain.Diagram, error) {
+	if exceedsLimit(encryptedData, s.limits.DiagramData) {
+		return nil, ErrInvalidDiagramData
+	}
+
 	// Check permission
-	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionEditDiagram); err != nil {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionEditDiagram, ErrProjectNotFound); err != nil {
 		return nil, err
 	}
 
@@ -58,12 +91,16 @@ func (s *DiagramService) CreateDiagram(
 		ParentDiagramID:        parentDiagramID,
 		EncryptedData:          encryptedData,
 		EncryptedDataSignature: signature,
+		CreatedBy:              userID,
+		UpdatedBy:              userID,
 	}
 
 	if err := s.diagramRepo.Create(ctx, diagram); err != nil {
 		return nil, err
 	}
 
+	s.diagramPaths.invalidateProject(diagram.ProjectID)
+	s.publish(event.DiagramCreated, diagram, userID)
 	return diagram, nil
 }
 
@@ -79,9 +116,12 @@ func (s *DiagramService) GetDiagram(
 		}
 		return nil, err
 	}
+	if diagram == nil {
+		return nil, ErrDiagramNotFound
+	}
 
 	// Check permission
-	if err := s.hasPermission(ctx, diagram.ProjectID, userID, domain.PermissionViewDiagram); err != nil {
+	if err := s.hasPermission(ctx, diagram.ProjectID, userID, domain.PermissionViewDiagram, ErrDiagramNotFound); err != nil {
 		return nil, err
 	}
 
@@ -96,13 +136,98 @@ func (s *DiagramService) ListDiagrams(
 	offset, limit int,
 ) ([]*domain.Diagram, int64, error) {
 	// Check permission
-	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewDiagram); err != nil {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewDiagram, ErrProjectNotFound); err != nil {
 		return nil, 0, err
 	}
 
 	return s.diagramRepo.FindByProjectID(ctx, projectID, rootOnly, offset, limit)
 }
 
+// GetDiagramTree returns the project's diagrams nested under their parents.
+// Diagrams whose parent is missing, or that sit in a parent cycle, are placed
+// at the top level; levels deeper than maxDiagramTreeDepth are left out. Each
+// level is sorted by name.
+func (s *DiagramService) GetDiagramTree(ctx context.Context, projectID, userID primitive.ObjectID) ([]dto.DiagramTreeNode, error) {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewDiagram, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+
+	diagrams, err := s.diagramRepo.FindAllByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*domain.Diagram, len(diagrams))
+	for _, d := range diagrams {
+		byID[d.ID] = d
+	}
+	children := make(map[primitive.ObjectID][]*domain.Diagram)
+	var roots []*domain.Diagram
+	for _, d := range diagrams {
+		if d.ParentDiagramID != nil {
+			if _, ok := byID[*d.ParentDiagramID]; ok {
+				children[*d.ParentDiagramID] = append(children[*d.ParentDiagramID], d)
+				continue
+			}
+		}
+		roots = append(roots, d)
+	}
+
+	placed := make(map[primitive.ObjectID]struct{}, len(diagrams))
+	var build func(level []*domain.Diagram, depth int) []dto.DiagramTreeNode
+	build = func(level []*domain.Diagram, depth int) []dto.DiagramTreeNode {
+		sort.SliceStable(level, func(i, j int) bool {
+			return strings.ToLower(level[i].DiagramName) < strings.ToLower(level[j].DiagramName)
+		})
+		nodes := make([]dto.DiagramTreeNode, 0, len(level))
+		for _, d := range level {
+			if _, ok := placed[d.ID]; ok {
+				continue
+			}
+			placed[d.ID] = struct{}{}
+			node := dto.DiagramTreeNode{
+				ID:   d.ID.Hex(),
+				Name: d.DiagramName,
+			}
+			if depth < maxDiagramTreeDepth {
+				node.Children = build(children[d.ID], depth+1)
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	tree := build(roots, 1)
+
+	// Diagrams in a parent cycle are unreachable from the top level; list
+	// them there so they stay visible and can be moved out
+	var unplaced []*domain.Diagram
+	for _, d := range diagrams {
+		if _, ok := placed[d.ID]; !ok && !diagramReachesRoot(d, byID) {
+			unplaced = append(unplaced, d)
+		}
+	}
+	if len(unplaced) > 0 {
+		tree = append(tree, build(unplaced, 1)...)
+	}
+
+	return tree, nil
+}
+
+// ListDiagramsAfter lists diagrams ordered by ID, starting after the given cursor ID
+func (s *DiagramService) ListDiagramsAfter(
+	ctx context.Context,
+	projectID, userID, afterID primitive.ObjectID,
+	rootOnly bool,
+	limit int,
+) ([]*domain.Diagram, error) {
+	// Check permission
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewDiagram, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+
+	return s.diagramRepo.FindByProjectIDAfter(ctx, projectID, afterID, rootOnly, limit)
+}
+
 // UpdateDiagram updates an existing diagram
 func (s *DiagramService) UpdateDiagram(
 	ctx context.Context,
@@ -110,6 +235,10 @@ func (s *DiagramService) UpdateDiagram(
 	diagramName, description *string,
 	encryptedData, signature *string,
 ) (*domain.Diagram, error) {
+	if exceedsLimit(encryptedData, s.limits.DiagramData) {
+		return nil, ErrInvalidDiagramData
+	}
+
 	diagram, err := s.diagramRepo.FindByID(ctx, diagramID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
@@ -117,9 +246,17 @@ func (s *DiagramService) UpdateDiagram(
 		}
 		return nil, err
 	}
+	if diagram == nil {
+		return nil, ErrDiagramNotFound
+	}
 
 	// Check permission
-	if err := s.hasPermission(ctx, diagram.ProjectID, userID, domain.PermissionEditDiagram); err != nil {
+	if err := s.hasPermission(ctx, diagram.ProjectID, userID, domain.PermissionEditDiagram, ErrDiagramNotFound); err != nil {
+		return nil, err
+	}
+
+	// Another member's edit lock keeps the diagram read-only for everyone else
+	if err := s.locks.checkWrite(diagramID, userID, time.Now()); err != nil {
 		return nil, err
 	}
 
@@ -136,11 +273,14 @@ func (s *DiagramService) UpdateDiagram(
 	if signature != nil {
 		diagram.EncryptedDataSignature = *signature
 	}
+	diagram.UpdatedBy = userID
 
 	if err := s.diagramRepo.Update(ctx, diagram); err != nil {
 		return nil, err
 	}
 
+	s.diagramPaths.invalidateProject(diagram.ProjectID)
+	s.publish(event.DiagramUpdated, diagram, userID)
 	return diagram, nil
 }
 
@@ -156,9 +296,16 @@ func (s *DiagramService) DeleteDiagram(
 		}
 		return err
 	}
+	if diagram == nil {
+		return ErrDiagramNotFound
+	}
 
 	// Check permission
-	if err := s.hasPermission(ctx, diagram.ProjectID, userID, domain.PermissionEditDiagram); err != nil {
+	if err := s.hasPermission(ctx, diagram.ProjectID, userID, domain.PermissionEditDiagram, ErrDiagramNotFound); err != nil {
+		return err
+	}
+
+	if err := s.locks.checkWrite(diagramID, userID, time.Now()); err != nil {
 		return err
 	}
 
@@ -167,28 +314,294 @@ func (s *DiagramService) DeleteDiagram(
 		return err
 	}
 
-	return s.diagramRepo.Delete(ctx, diagramID)
+	// Links to a deleted diagram must not come back to life
+	if err := s.shareLinkRepo.DeleteByDiagramID(ctx, diagramID); err != nil {
+		return err
+	}
+
+	// Comments on the diagram and its nodes go with it
+	if err := s.commentRepo.DeleteByDiagramID(ctx, diagramID); err != nil {
+		return err
+	}
+
+	if err := s.diagramRepo.Delete(ctx, diagramID); err != nil {
+		return err
+	}
+	s.locks.drop(diagramID)
+	s.diagramPaths.invalidateProject(diagram.ProjectID)
+
+	s.publish(event.DiagramDeleted, diagram, userID)
+	return nil
 }
 
-// hasPermission checks if user has a specific permission for the project
-func (s *DiagramService) hasPermission(
+// DeleteDiagrams deletes several diagrams of a project at once. Edit
+// permission is checked once; the returned errors hold each ID's outcome in
+// request order, so an ID that is missing, belongs to another project or is
+// locked by another member only fails itself. Each diagram takes its nodes,
+// share links and comments with it, as with DeleteDiagram; child diagrams are
+// left in place.
+func (s *DiagramService) DeleteDiagrams(
 	ctx context.Context,
 	projectID, userID primitive.ObjectID,
-	permission string,
-) error {
-	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, userID)
+	diagramIDs []primitive.ObjectID,
+) ([]error, error) {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionEditDiagram, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+
+	diagrams, err := s.diagramRepo.FindByIDs(ctx, diagramIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[primitive.ObjectID]*domain.Diagram, len(diagrams))
+	for _, diagram := range diagrams {
+		if diagram.ProjectID == projectID {
+			found[diagram.ID] = diagram
+		}
+	}
+
+	now := time.Now()
+	errs := make([]error, len(diagramIDs))
+	deleting := make([]*domain.Diagram, 0, len(found))
+	queued := make(map[primitive.ObjectID]bool, len(found))
+	for i, id := range diagramIDs {
+		diagram, ok := found[id]
+		if !ok {
+			errs[i] = ErrDiagramNotFound
+			continue
+		}
+		if err := s.locks.checkWrite(id, userID, now); err != nil {
+			errs[i] = err
+			continue
+		}
+		if !queued[id] {
+			queued[id] = true
+			deleting = append(deleting, diagram)
+		}
+	}
+	if len(deleting) == 0 {
+		return errs, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(deleting))
+	for _, diagram := range deleting {
+		if err := s.nodeRepo.DeleteByDiagramID(ctx, diagram.ID); err != nil {
+			return nil, err
+		}
+		if err := s.shareLinkRepo.DeleteByDiagramID(ctx, diagram.ID); err != nil {
+			return nil, err
+		}
+		if err := s.commentRepo.DeleteByDiagramID(ctx, diagram.ID); err != nil {
+			return nil, err
+		}
+		ids = append(ids, diagram.ID)
+	}
+	if err := s.diagramRepo.DeleteMany(ctx, ids); err != nil {
+		return nil, err
+	}
+	s.diagramPaths.invalidateProject(projectID)
+
+	for _, diagram := range deleting {
+		s.locks.drop(diagram.ID)
+		s.publish(event.DiagramDeleted, diagram, userID)
+	}
+	return errs, nil
+}
+
+// DuplicateDiagram deep-copies a diagram with its nodes and vault items into new
+// IDs within the same project. Encrypted blobs are copied verbatim. When
+// includeChildren is set, the whole subtree of child diagrams is copied as well.
+func (s *DiagramService) DuplicateDiagram(
+	ctx context.Context,
+	projectID, diagramID, userID primitive.ObjectID,
+	diagramName *string,
+	includeChildren bool,
+) (*domain.Diagram, error) {
+	source, err := s.diagramRepo.FindByID(ctx, diagramID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return ErrDiagramAccessDenied
+			return nil, ErrDiagramNotFound
+		}
+		return nil, err
+	}
+	if source == nil {
+		return nil, ErrDiagramNotFound
+	}
+	if source.ProjectID != projectID {
+		return nil, ErrDiagramNotFound
+	}
+
+	// Check permission
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionEditDiagram, ErrDiagramNotFound); err != nil {
+		return nil, err
+	}
+
+	// 1. Collect diagrams to copy (source first, then its descendants)
+	diagrams := []*domain.Diagram{source}
+	if includeChildren {
+		all, err := s.diagramRepo.FindAllByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		diagrams = append(diagrams, collectDescendantDiagrams(all, source.ID)...)
+	}
+
+	// 2. Pre-generate IDs so parent references can be resolved
+	idMap := make(map[primitive.ObjectID]primitive.ObjectID)
+	diagramIDs := make([]primitive.ObjectID, len(diagrams))
+	for i, d := range diagrams {
+		idMap[d.ID] = primitive.NewObjectID()
+		diagramIDs[i] = d.ID
+	}
+
+	// 3. Insert diagrams
+	var root *domain.Diagram
+	for _, d := range diagrams {
+		clone := &domain.Diagram{
+			ID:                     idMap[d.ID],
+			ProjectID:              projectID,
+			DiagramName:            d.DiagramName,
+			Description:            d.Description,
+			EncryptedData:          d.EncryptedData,
+			EncryptedDataSignature: d.EncryptedDataSignature,
+			CreatedBy:              userID,
+			UpdatedBy:              userID,
+		}
+		if d.ID == source.ID {
+			// The copy sits next to the original
+			clone.ParentDiagramID = source.ParentDiagramID
+			clone.DiagramName = source.DiagramName + " (copy)"
+			if diagramName != nil {
+				clone.DiagramName = *diagramName
+			}
+			root = clone
+		} else if d.ParentDiagramID != nil {
+			if newParent, ok := idMap[*d.ParentDiagramID]; ok {
+				clone.ParentDiagramID = &newParent
+			}
+		}
+		if err := s.diagramRepo.Create(ctx, clone); err != nil {
+			return nil, err
+		}
+	}
+
+	// 4. Copy nodes
+	nodes, err := s.nodeRepo.FindByDiagramIDs(ctx, diagramIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range nodes {
+		idMap[n.ID] = primitive.NewObjectID()
+		clone := &domain.Node{
+			ID:                       idMap[n.ID],
+			DiagramID:                idMap[n.DiagramID],
+			Label:                    n.Label,
+			EncryptedReadme:          n.EncryptedReadme,
+			EncryptedReadmeSignature: n.EncryptedReadmeSignature,
+			EncryptedDict:            n.EncryptedDict,
+			EncryptedDictSignature:   n.EncryptedDictSignature,
+		}
+		if err := s.nodeRepo.Create(ctx, clone); err != nil {
+			return nil, err
+		}
+	}
+
+	// 5. Copy vault items belonging to the copied nodes
+	if len(nodes) > 0 {
+		vaults, err := s.vaultRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range vaults {
+			newNodeID, ok := idMap[v.NodeId]
+			if !ok {
+				continue
+			}
+			clone := &domain.NodeVault{
+				NodeId:                  newNodeID,
+				ProjectId:               projectID,
+				Label:                   v.Label,
+				Type:                    v.Type,
+				EncryptedValue:          v.EncryptedValue,
+				EncryptedValueSignature: v.EncryptedValueSignature,
+				CreatedBy:               userID,
+				UpdatedBy:               userID,
+			}
+			if err := s.vaultRepo.Create(ctx, clone); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	s.diagramPaths.invalidateProject(root.ProjectID)
+	s.publish(event.DiagramCreated, root, userID)
+	return root, nil
+}
+
+// diagramReachesRoot reports whether following the diagram's parents ends at
+// the top level rather than looping
+func diagramReachesRoot(diagram *domain.Diagram, byID map[primitive.ObjectID]*domain.Diagram) bool {
+	visited := make(map[primitive.ObjectID]struct{})
+	for current := diagram; current.ParentDiagramID != nil; {
+		if _, seen := visited[current.ID]; seen {
+			return false
+		}
+		visited[current.ID] = struct{}{}
+		parent, ok := byID[*current.ParentDiagramID]
+		if !ok {
+			return true
+		}
+		current = parent
+	}
+	return true
+}
+
+// collectDescendantDiagrams returns every diagram below rootID, parents before children
+func collectDescendantDiagrams(all []*domain.Diagram, rootID primitive.ObjectID) []*domain.Diagram {
+	children := make(map[primitive.ObjectID][]*domain.Diagram)
+	for _, d := range all {
+		if d.ParentDiagramID != nil {
+			children[*d.ParentDiagramID] = append(children[*d.ParentDiagramID], d)
 		}
-		return err
 	}
 
-	for _, p := range member.Permissions {
-		if p == permission {
-			return nil
+	var result []*domain.Diagram
+	visited := map[primitive.ObjectID]bool{rootID: true}
+	queue := []primitive.ObjectID{rootID}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, child := range children[current] {
+			if visited[child.ID] {
+				continue
+			}
+			visited[child.ID] = true
+			result = append(result, child)
+			queue = append(queue, child.ID)
 		}
 	}
+	return result
+}
+
+// hasPermission checks if user has a specific permission for the project.
+// Non-members get notFound so they cannot probe which IDs exist.
+func (s *DiagramService) hasPermission(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	permission domain.Permission,
+	notFound error,
+) error {
+	if _, err := s.authz.Authorize(ctx, projectID, userID, permission); err != nil {
+		return concealNonMember(err, notFound)
+	}
+	return nil
+}
 
-	return ErrInsufficientPermission
+func (s *DiagramService) publish(eventType event.Type, diagram *domain.Diagram, userID primitive.ObjectID) {
+	s.events.Publish(event.Event{
+		Type:       eventType,
+		ProjectID:  diagram.ProjectID,
+		ActorID:    userID,
+		ResourceID: diagram.ID,
+	})
 }
@@ -0,0 +1,169 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestDuplicateDiagramCopiesSubtree(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	editor, viewer := primitive.NewObjectID(), primitive.NewObjectID()
+	data := "ciphertext"
+	secret := "secret"
+
+	source := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID, DiagramName: "Network", EncryptedData: &data, EncryptedDataSignature: "sig"}
+	child := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID, ParentDiagramID: &source.ID, DiagramName: "Subnet"}
+	other := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID, DiagramName: "Other"}
+	sourceNode := &domain.Node{ID: primitive.NewObjectID(), DiagramID: source.ID, Label: "router", EncryptedReadme: "readme", EncryptedDict: "dict"}
+	childNode := &domain.Node{ID: primitive.NewObjectID(), DiagramID: child.ID, Label: "switch"}
+	otherNode := &domain.Node{ID: primitive.NewObjectID(), DiagramID: other.ID}
+
+	store := &mergeStore{
+		diagrams: []*domain.Diagram{source, child, other},
+		nodes:    []*domain.Node{sourceNode, childNode, otherNode},
+		vaults: []*domain.NodeVault{
+			{ID: primitive.NewObjectID(), NodeId: sourceNode.ID, ProjectId: projectID, Label: "admin", EncryptedValue: &secret},
+			{ID: primitive.NewObjectID(), NodeId: otherNode.ID, ProjectId: projectID, Label: "unrelated"},
+		},
+	}
+	authz := NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: editor, Role: domain.RoleEditor, Permissions: []string{string(domain.PermissionEditDiagram)}},
+		{ProjectID: projectID, UserID: viewer, Role: domain.RoleViewer, Permissions: []string{string(domain.PermissionViewDiagram)}},
+	}})
+	svc := NewDiagramService(mergeDiagramRepo{store: store}, authz, nil, mergeNodeRepo{store: store}, mergeVaultRepo{store: store}, nil, nil,
+		NewDiagramLocks(0), NewDiagramPathCache(0), PayloadLimits{}, &fakePublisher{})
+	ctx := context.Background()
+
+	if _, err := svc.DuplicateDiagram(ctx, projectID, source.ID, viewer, nil, true); !errors.Is(err, ErrInsufficientPermission) {
+		t.Fatalf("viewer: err = %v, want %v", err, ErrInsufficientPermission)
+	}
+
+	root, err := svc.DuplicateDiagram(ctx, projectID, source.ID, editor, nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if root.ID == source.ID || root.DiagramName != "Network (copy)" || root.ParentDiagramID != nil {
+		t.Errorf("root = %+v, want a new top-level \"Network (copy)\"", root)
+	}
+	if root.EncryptedData == nil || *root.EncryptedData != data || root.EncryptedDataSignature != "sig" || root.CreatedBy != editor {
+		t.Errorf("root did not keep the encrypted data verbatim: %+v", root)
+	}
+
+	added := store.diagrams[3:]
+	if len(added) != 2 {
+		t.Fatalf("created %d diagrams, want the root and its child", len(added))
+	}
+	clonedChild := added[1]
+	if clonedChild.DiagramName != "Subnet" || clonedChild.ParentDiagramID == nil || *clonedChild.ParentDiagramID != root.ID {
+		t.Errorf("child copy = %+v, want Subnet under the new root", clonedChild)
+	}
+
+	addedNodes := store.nodes[3:]
+	if len(addedNodes) != 2 {
+		t.Fatalf("created %d nodes, want 2", len(addedNodes))
+	}
+	nodeCopies := map[string]*domain.Node{}
+	for _, n := range addedNodes {
+		nodeCopies[n.Label] = n
+	}
+	if n := nodeCopies["router"]; n == nil || n.DiagramID != root.ID || n.ID == sourceNode.ID || n.EncryptedReadme != "readme" || n.EncryptedDict != "dict" {
+		t.Errorf("router copy = %+v, want it on the new root with its blobs", n)
+	}
+	if n := nodeCopies["switch"]; n == nil || n.DiagramID != clonedChild.ID {
+		t.Errorf("switch copy = %+v, want it on the child copy", n)
+	}
+
+	addedVaults := store.vaults[2:]
+	if len(addedVaults) != 1 {
+		t.Fatalf("created %d vault items, want only the copied node's", len(addedVaults))
+	}
+	if v := addedVaults[0]; v.NodeId != nodeCopies["router"].ID || v.Label != "admin" || v.EncryptedValue == nil || *v.EncryptedValue != secret {
+		t.Errorf("vault copy = %+v, want admin on the router copy", v)
+	}
+
+	// Without children only the diagram itself is copied, under the given name
+	name := "Network v2"
+	single, err := svc.DuplicateDiagram(ctx, projectID, source.ID, editor, &name, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if single.DiagramName != name || len(store.diagrams) != 6 || len(store.nodes) != 6 {
+		t.Errorf("got %q with %d diagrams and %d nodes stored, want %q with 6 and 6", single.DiagramName, len(store.diagrams), len(store.nodes), name)
+	}
+}
+
+func TestGetDiagramTree(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	viewer, stranger := primitive.NewObjectID(), primitive.NewObjectID()
+	data := "ciphertext"
+	newDiagram := func(name string, parent *domain.Diagram) *domain.Diagram {
+		d := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: projectID, DiagramName: name, EncryptedData: &data}
+		if parent != nil {
+			d.ParentDiagramID = &parent.ID
+		}
+		return d
+	}
+
+	network := newDiagram("Network", nil)
+	dmz := newDiagram("dmz", network)
+	lan := newDiagram("LAN", network)
+	rack := newDiagram("Rack 1", lan)
+	apps := newDiagram("Apps", nil)
+	gone := primitive.NewObjectID()
+	orphan := newDiagram("Orphan", nil)
+	orphan.ParentDiagramID = &gone
+	loopA, loopB := newDiagram("Loop A", nil), newDiagram("Loop B", nil)
+	loopA.ParentDiagramID, loopB.ParentDiagramID = &loopB.ID, &loopA.ID
+
+	store := &mergeStore{diagrams: []*domain.Diagram{rack, lan, network, orphan, dmz, apps, loopA, loopB}}
+	authz := NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: viewer, Role: domain.RoleViewer, Permissions: []string{string(domain.PermissionViewDiagram)}},
+		{ProjectID: projectID, UserID: stranger, Role: domain.RoleViewer},
+	}})
+	svc := NewDiagramService(mergeDiagramRepo{store: store}, authz, nil, nil, nil, nil, nil,
+		NewDiagramLocks(0), NewDiagramPathCache(0), PayloadLimits{}, &fakePublisher{})
+
+	if _, err := svc.GetDiagramTree(context.Background(), projectID, stranger); !errors.Is(err, ErrInsufficientPermission) {
+		t.Errorf("without view_diagram: err = %v, want %v", err, ErrInsufficientPermission)
+	}
+
+	tree, err := svc.GetDiagramTree(context.Background(), projectID, viewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// render flattens the tree to "depth:name" in order
+	var render func(nodes []dto.DiagramTreeNode, depth int) []string
+	render = func(nodes []dto.DiagramTreeNode, depth int) []string {
+		var out []string
+		for _, n := range nodes {
+			out = append(out, fmt.Sprintf("%d:%s", depth, n.Name))
+			out = append(out, render(n.Children, depth+1)...)
+		}
+		return out
+	}
+	want := []string{
+		"0:Apps", "0:Network", "1:dmz", "1:LAN", "2:Rack 1", "0:Orphan",
+		"0:Loop A", "1:Loop B",
+	}
+	if got := render(tree, 0); !slices.Equal(got, want) {
+		t.Errorf("tree = %v\nwant   %v", got, want)
+	}
+
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), data) {
+		t.Errorf("tree carries diagram data: %s", raw)
+	}
+}
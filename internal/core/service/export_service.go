@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ExportService builds plain JSON dumps of a project for client-side
+// decryption and rendering. See domain.ProjectExport.
+type ExportService struct {
+	authz         *AuthorizationService
+	projectRepo   port.ProjectRepository
+	diagramRepo   port.DiagramRepository
+	nodeRepo      port.NodeRepository
+	nodeVaultRepo port.NodeVaultRepository
+	noteRepo      port.NoteRepository
+}
+
+func NewExportService(
+	authz *AuthorizationService,
+	projectRepo port.ProjectRepository,
+	diagramRepo port.DiagramRepository,
+	nodeRepo port.NodeRepository,
+	nodeVaultRepo port.NodeVaultRepository,
+	noteRepo port.NoteRepository,
+) *ExportService {
+	return &ExportService{
+		authz:         authz,
+		projectRepo:   projectRepo,
+		diagramRepo:   diagramRepo,
+		nodeRepo:      nodeRepo,
+		nodeVaultRepo: nodeVaultRepo,
+		noteRepo:      noteRepo,
+	}
+}
+
+// ExportProject returns the project's diagram and note trees with nodes and
+// vault items inlined, along with a suggested filename. Any member may
+// export; each section is only filled when the member may view it.
+func (s *ExportService) ExportProject(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectExport, string, error) {
+	member, err := s.authz.GetMember(ctx, projectID, userID)
+	if err != nil {
+		return nil, "", concealNonMember(err, ErrProjectNotFound)
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		return nil, "", fmt.Errorf("fetching project: %w", err)
+	}
+	if project == nil {
+		return nil, "", ErrProjectNotFound
+	}
+
+	export := &domain.ProjectExport{
+		Version:    domain.ProjectExportVersion,
+		ExportedAt: time.Now().UTC(),
+		Sections:   []string{},
+		Project:    toProjectBackup(project),
+		Diagrams:   []domain.DiagramExport{},
+		Notes:      []domain.NoteExport{},
+	}
+
+	if s.authz.Can(member, domain.PermissionViewDiagram) {
+		includeVaults := s.authz.Can(member, domain.PermissionViewVault)
+		diagrams, err := s.exportDiagrams(ctx, projectID, includeVaults)
+		if err != nil {
+			return nil, "", err
+		}
+		export.Diagrams = diagrams
+		export.Sections = append(export.Sections, domain.ExportSectionDiagrams)
+		if includeVaults {
+			export.Sections = append(export.Sections, domain.ExportSectionVaults)
+		}
+	}
+
+	if s.authz.Can(member, domain.PermissionViewNote) {
+		notes, err := s.noteRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, "", fmt.Errorf("fetching notes: %w", err)
+		}
+		export.Notes = buildNoteTree(toNoteBackups(notes))
+		export.Sections = append(export.Sections, domain.ExportSectionNotes)
+	}
+
+	filename := fmt.Sprintf("%s_%s.json",
+		sanitizeFilename(project.Name),
+		time.Now().Format("20060102_150405"),
+	)
+
+	return export, filename, nil
+}
+
+func (s *ExportService) exportDiagrams(ctx context.Context, projectID primitive.ObjectID, includeVaults bool) ([]domain.DiagramExport, error) {
+	diagrams, err := s.diagramRepo.FindAllByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, fmt.Errorf("fetching diagrams: %w", err)
+	}
+	if len(diagrams) == 0 {
+		return []domain.DiagramExport{}, nil
+	}
+
+	diagramIDs := make([]primitive.ObjectID, len(diagrams))
+	for i, d := range diagrams {
+		diagramIDs[i] = d.ID
+	}
+	nodes, err := s.nodeRepo.FindByDiagramIDs(ctx, diagramIDs)
+	if err != nil {
+		return nil, fmt.Errorf("fetching nodes: %w", err)
+	}
+
+	vaultsByNode := make(map[string][]domain.VaultBackup)
+	if includeVaults {
+		vaults, err := s.nodeVaultRepo.FindByProjectID(ctx, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("fetching vaults: %w", err)
+		}
+		for _, v := range toVaultBackups(vaults) {
+			vaultsByNode[v.NodeID] = append(vaultsByNode[v.NodeID], v)
+		}
+	}
+
+	nodesByDiagram := make(map[string][]domain.NodeExport)
+	for _, n := range toNodeBackups(nodes) {
+		vaults := vaultsByNode[n.ID]
+		if vaults == nil {
+			vaults = []domain.VaultBackup{}
+		}
+		nodesByDiagram[n.DiagramID] = append(nodesByDiagram[n.DiagramID], domain.NodeExport{
+			NodeBackup: n,
+			Vaults:     vaults,
+		})
+	}
+
+	return buildDiagramTree(toDiagramBackups(diagrams), nodesByDiagram), nil
+}
+
+// buildDiagramTree nests diagrams under their parents. Diagrams whose parent
+// is missing are treated as roots so nothing is dropped from the export.
+func buildDiagramTree(diagrams []domain.DiagramBackup, nodesByDiagram map[string][]domain.NodeExport) []domain.DiagramExport {
+	known := make(map[string]bool, len(diagrams))
+	for _, d := range diagrams {
+		known[d.ID] = true
+	}
+
+	children := make(map[string][]domain.DiagramBackup)
+	var roots []domain.DiagramBackup
+	for _, d := range diagrams {
+		if d.ParentDiagramID != nil && known[*d.ParentDiagramID] {
+			children[*d.ParentDiagramID] = append(children[*d.ParentDiagramID], d)
+		} else {
+			roots = append(roots, d)
+		}
+	}
+
+	var build func(level []domain.DiagramBackup) []domain.DiagramExport
+	build = func(level []domain.DiagramBackup) []domain.DiagramExport {
+		result := make([]domain.DiagramExport, len(level))
+		for i, d := range level {
+			nodes := nodesByDiagram[d.ID]
+			if nodes == nil {
+				nodes = []domain.NodeExport{}
+			}
+			result[i] = domain.DiagramExport{
+				DiagramBackup: d,
+				Nodes:         nodes,
+				Children:      build(children[d.ID]),
+			}
+		}
+		return result
+	}
+	return build(roots)
+}
+
+// buildNoteTree nests notes under their parent folders. Notes whose parent is
+// missing are treated as roots.
+func buildNoteTree(notes []domain.NoteBackup) []domain.NoteExport {
+	known := make(map[string]bool, len(notes))
+	for _, n := range notes {
+		known[n.ID] = true
+	}
+
+	children := make(map[string][]domain.NoteBackup)
+	var roots []domain.NoteBackup
+	for _, n := range notes {
+		if n.ParentID != nil && known[*n.ParentID] {
+			children[*n.ParentID] = append(children[*n.ParentID], n)
+		} else {
+			roots = append(roots, n)
+		}
+	}
+
+	var build func(level []domain.NoteBackup) []domain.NoteExport
+	build = func(level []domain.NoteBackup) []domain.NoteExport {
+		result := make([]domain.NoteExport, len(level))
+		for i, n := range level {
+			result[i] = domain.NoteExport{
+				NoteBackup: n,
+				Children:   build(children[n.ID]),
+			}
+		}
+		return result
+	}
+	return build(roots)
+}
@@ -0,0 +1,477 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// In-memory stand-ins for the repositories. Each embeds its port so only the
+// methods a test exercises need implementing; lookups of missing documents
+// return nil, nil like mgod does.
+
+type fakeMemberRepo struct {
+	port.ProjectMemberRepository
+	members []*domain.ProjectMember
+}
+
+func (r *fakeMemberRepo) FindByProjectAndUser(_ context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
+	for _, m := range r.members {
+		if m.ProjectID == projectID && m.UserID == userID {
+			return m, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeMemberRepo) Create(_ context.Context, member *domain.ProjectMember) error {
+	r.members = append(r.members, member)
+	return nil
+}
+
+func (r *fakeMemberRepo) SetFavorite(_ context.Context, projectID, userID primitive.ObjectID, favorite bool) (bool, error) {
+	for _, m := range r.members {
+		if m.ProjectID == projectID && m.UserID == userID {
+			m.Favorite = favorite
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeMemberRepo) FindByUserID(_ context.Context, userID primitive.ObjectID) ([]*domain.ProjectMember, error) {
+	var found []*domain.ProjectMember
+	for _, m := range r.members {
+		if m.UserID == userID {
+			found = append(found, m)
+		}
+	}
+	return found, nil
+}
+
+type fakeProjectRepo struct {
+	port.ProjectRepository
+	projects []*domain.Project
+}
+
+func (r *fakeProjectRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.Project, error) {
+	for _, p := range r.projects {
+		if p.ID == id && p.DeletedAt == nil {
+			return p, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeProjectRepo) FindActiveByIDs(_ context.Context, ids []primitive.ObjectID) ([]*domain.Project, error) {
+	var found []*domain.Project
+	for _, p := range r.projects {
+		if p.DeletedAt == nil && slices.Contains(ids, p.ID) {
+			found = append(found, p)
+		}
+	}
+	return found, nil
+}
+
+type fakeDiagramRepo struct {
+	port.DiagramRepository
+	mu       sync.Mutex
+	diagrams []*domain.Diagram
+	// finds counts FindByID calls; scope is the last SearchByName scope
+	finds int
+	scope []primitive.ObjectID
+}
+
+func (r *fakeDiagramRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.Diagram, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.finds++
+	for _, d := range r.diagrams {
+		if d.ID == id {
+			clone := *d
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeDiagramRepo) FindByIDs(_ context.Context, ids []primitive.ObjectID) ([]*domain.Diagram, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*domain.Diagram
+	for _, d := range r.diagrams {
+		if slices.Contains(ids, d.ID) {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeDiagramRepo) FindByProjectID(_ context.Context, projectID primitive.ObjectID, rootOnly bool, offset, limit int) ([]*domain.Diagram, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*domain.Diagram
+	for _, d := range r.diagrams {
+		if d.ProjectID == projectID && (!rootOnly || d.ParentDiagramID == nil) {
+			found = append(found, d)
+		}
+	}
+	total := int64(len(found))
+	found = found[min(offset, len(found)):]
+	return found[:min(limit, len(found))], total, nil
+}
+
+func (r *fakeDiagramRepo) SearchByName(_ context.Context, projectIDs []primitive.ObjectID, query string, limit int) ([]*domain.Diagram, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scope = projectIDs
+	var found []*domain.Diagram
+	for _, d := range r.diagrams {
+		if slices.Contains(projectIDs, d.ProjectID) && containsFold(d.DiagramName, query) && len(found) < limit {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeDiagramRepo) Create(_ context.Context, diagram *domain.Diagram) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.diagrams = append(r.diagrams, diagram)
+	return nil
+}
+
+func (r *fakeDiagramRepo) Update(_ context.Context, diagram *domain.Diagram) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, d := range r.diagrams {
+		if d.ID == diagram.ID {
+			clone := *diagram
+			r.diagrams[i] = &clone
+		}
+	}
+	return nil
+}
+
+func (r *fakeDiagramRepo) DeleteMany(_ context.Context, ids []primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.diagrams = slices.DeleteFunc(r.diagrams, func(d *domain.Diagram) bool { return slices.Contains(ids, d.ID) })
+	return nil
+}
+
+type fakeNoteRepo struct {
+	port.NoteRepository
+	notes []*domain.Note
+	scope []primitive.ObjectID
+}
+
+func (r *fakeNoteRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.Note, error) {
+	for _, n := range r.notes {
+		if n.ID == id {
+			clone := *n
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeNoteRepo) FindByIDs(_ context.Context, ids []primitive.ObjectID) ([]*domain.Note, error) {
+	var found []*domain.Note
+	for _, n := range r.notes {
+		if slices.Contains(ids, n.ID) {
+			found = append(found, n)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeNoteRepo) Create(_ context.Context, note *domain.Note) error {
+	r.notes = append(r.notes, note)
+	return nil
+}
+
+func (r *fakeNoteRepo) DeleteMany(_ context.Context, ids []primitive.ObjectID) error {
+	r.notes = slices.DeleteFunc(r.notes, func(n *domain.Note) bool { return slices.Contains(ids, n.ID) })
+	return nil
+}
+
+func (r *fakeNoteRepo) FindByProjectID(_ context.Context, projectID primitive.ObjectID) ([]*domain.Note, error) {
+	var found []*domain.Note
+	for _, n := range r.notes {
+		if n.ProjectID == projectID {
+			found = append(found, n)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeNoteRepo) SearchByName(_ context.Context, projectIDs []primitive.ObjectID, query string, limit int) ([]*domain.Note, error) {
+	r.scope = projectIDs
+	var found []*domain.Note
+	for _, n := range r.notes {
+		if slices.Contains(projectIDs, n.ProjectID) && containsFold(n.FileName, query) && len(found) < limit {
+			found = append(found, n)
+		}
+	}
+	return found, nil
+}
+
+type fakePublisher struct {
+	mu     sync.Mutex
+	events []event.Event
+}
+
+func (p *fakePublisher) Publish(e event.Event) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, e)
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// fakeVaultRepo stores each item's version as the database would: nil for
+// items created before versioning. Update matches like the real filter, a
+// missing version counting as 0. With readers set, FindByID blocks until that
+// many callers have read, so concurrent updates all start from the same copy.
+type fakeVaultRepo struct {
+	port.NodeVaultRepository
+	mu       sync.Mutex
+	items    map[primitive.ObjectID]domain.NodeVault
+	versions map[primitive.ObjectID]*int
+	readers  *sync.WaitGroup
+}
+
+func newFakeVaultRepo(items ...*domain.NodeVault) *fakeVaultRepo {
+	r := &fakeVaultRepo{
+		items:    make(map[primitive.ObjectID]domain.NodeVault),
+		versions: make(map[primitive.ObjectID]*int),
+	}
+	for _, item := range items {
+		r.items[item.ID] = *item
+	}
+	return r
+}
+
+func (r *fakeVaultRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.NodeVault, error) {
+	r.mu.Lock()
+	item, ok := r.items[id]
+	if version := r.versions[id]; version != nil {
+		item.Version = *version
+	} else {
+		item.Version = 0
+	}
+	r.mu.Unlock()
+
+	if r.readers != nil {
+		r.readers.Done()
+		r.readers.Wait()
+	}
+	if !ok {
+		return nil, nil
+	}
+	return &item, nil
+}
+
+func (r *fakeVaultRepo) Update(_ context.Context, vault *domain.NodeVault) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := r.versions[vault.ID]
+	matched := (stored == nil && vault.Version == 0) || (stored != nil && *stored == vault.Version)
+	if _, ok := r.items[vault.ID]; !ok || !matched {
+		return port.ErrVersionConflict
+	}
+	next := vault.Version + 1
+	r.items[vault.ID] = *vault
+	r.versions[vault.ID] = &next
+	vault.Version = next
+	return nil
+}
+
+type fakeUserRepo struct {
+	port.UserRepository
+	users []*domain.User
+}
+
+func (r *fakeUserRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) FindByEmail(_ context.Context, email string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Email == email {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeUserRepo) FindByUsername(_ context.Context, username string) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+
+type fakeRefreshTokenRepo struct {
+	port.RefreshTokenRepository
+	tokens []*domain.RefreshToken
+}
+
+func (r *fakeRefreshTokenRepo) Create(_ context.Context, token *domain.RefreshToken) error {
+	r.tokens = append(r.tokens, token)
+	return nil
+}
+
+type fakeNodeRepo struct {
+	port.NodeRepository
+	nodes []*domain.Node
+}
+
+func (r *fakeNodeRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.Node, error) {
+	for _, n := range r.nodes {
+		if n.ID == id {
+			clone := *n
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeNodeRepo) UpdateContent(_ context.Context, nodeID primitive.ObjectID, update domain.NodeContentUpdate) (*domain.Node, error) {
+	for _, n := range r.nodes {
+		if n.ID == nodeID {
+			if update.Label != nil {
+				n.Label = *update.Label
+			}
+			clone := *n
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+// fakeInvitationRepo fails Create and CreateMany with createErr when it is
+// set, so a test can check what a failed insert leaves behind
+type fakeInvitationRepo struct {
+	port.InvitationRepository
+	invitations []*domain.Invitation
+	createErr   error
+}
+
+func (r *fakeInvitationRepo) Create(_ context.Context, invitation *domain.Invitation) (*domain.Invitation, error) {
+	if r.createErr != nil {
+		return nil, r.createErr
+	}
+	clone := *invitation
+	r.invitations = append(r.invitations, &clone)
+	return invitation, nil
+}
+
+func (r *fakeInvitationRepo) CreateMany(_ context.Context, invitations []*domain.Invitation) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	for _, invitation := range invitations {
+		clone := *invitation
+		r.invitations = append(r.invitations, &clone)
+	}
+	return nil
+}
+
+func (r *fakeInvitationRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.Invitation, error) {
+	for _, i := range r.invitations {
+		if i.ID == id {
+			clone := *i
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeInvitationRepo) FindByProjectAndInvitee(_ context.Context, projectID, inviteeUserID primitive.ObjectID) (*domain.Invitation, error) {
+	for _, i := range r.invitations {
+		if i.ProjectID == projectID && i.InviteeUserID == inviteeUserID && i.Status == domain.InvitationStatusPending {
+			clone := *i
+			return &clone, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeInvitationRepo) Update(_ context.Context, invitation *domain.Invitation) error {
+	for _, i := range r.invitations {
+		if i.ID == invitation.ID {
+			i.Status = invitation.Status
+		}
+	}
+	return nil
+}
+
+// status returns the stored status of the invitation, or "" when missing
+func (r *fakeInvitationRepo) status(id primitive.ObjectID) string {
+	for _, i := range r.invitations {
+		if i.ID == id {
+			return i.Status
+		}
+	}
+	return ""
+}
+
+func (r *fakeVaultRepo) FindByNodeIDAndType(_ context.Context, projectID, nodeID primitive.ObjectID, vaultType string) ([]*domain.NodeVault, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []*domain.NodeVault
+	for _, item := range r.items {
+		if item.ProjectId == projectID && item.NodeId == nodeID && item.Type == vaultType {
+			found = append(found, &item)
+		}
+	}
+	return found, nil
+}
+
+func (r *fakeVaultRepo) CountByNodeID(_ context.Context, projectID, nodeID primitive.ObjectID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, item := range r.items {
+		if item.ProjectId == projectID && item.NodeId == nodeID {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeVaultRepo) Create(_ context.Context, vault *domain.NodeVault) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	vault.ID = primitive.NewObjectID()
+	r.items[vault.ID] = *vault
+	return nil
+}
+
+func (r *fakeVaultRepo) CreateMany(_ context.Context, vaults []*domain.NodeVault) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, vault := range vaults {
+		vault.ID = primitive.NewObjectID()
+		r.items[vault.ID] = *vault
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+)
+
+// invitationNotifyTimeout bounds the lookups and delivery of one notification
+const invitationNotifyTimeout = 30 * time.Second
+
+// InvitationNotifier tells invitees about new invitations and inviters about
+// accepted ones. It runs as an event bus subscriber, so notifications are
+// sent in the background and a failure is only logged; the invitation itself
+// has already succeeded.
+type InvitationNotifier struct {
+	invitationRepo port.InvitationRepository
+	userRepo       port.UserRepository
+	projectRepo    port.ProjectRepository
+	notifier       port.Notifier
+}
+
+func NewInvitationNotifier(
+	invitationRepo port.InvitationRepository,
+	userRepo port.UserRepository,
+	projectRepo port.ProjectRepository,
+	notifier port.Notifier,
+) *InvitationNotifier {
+	return &InvitationNotifier{
+		invitationRepo: invitationRepo,
+		userRepo:       userRepo,
+		projectRepo:    projectRepo,
+		notifier:       notifier,
+	}
+}
+
+// HandleEvent sends the notification for an invitation event. It is
+// registered on the event bus at startup.
+func (n *InvitationNotifier) HandleEvent(e event.Event) {
+	if e.Type != event.InvitationCreated && e.Type != event.InvitationAccepted {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), invitationNotifyTimeout)
+	defer cancel()
+
+	if err := n.notify(ctx, e); err != nil {
+		logger.Warn().Err(err).
+			Str("event", string(e.Type)).
+			Str("invitation_id", e.ResourceID.Hex()).
+			Msg("Failed to send invitation notification")
+	}
+}
+
+func (n *InvitationNotifier) notify(ctx context.Context, e event.Event) error {
+	invitation, err := n.invitationRepo.FindByID(ctx, e.ResourceID)
+	if err != nil {
+		return err
+	}
+	// Invitations without an invitee are shared by link; nobody to tell.
+	// The invitation may also be gone by the time the event is handled.
+	if invitation == nil || invitation.InviteeUserID.IsZero() {
+		return nil
+	}
+
+	project, err := n.projectRepo.FindByID(ctx, invitation.ProjectID)
+	if err != nil {
+		return err
+	}
+	inviter, err := n.userRepo.FindByID(ctx, invitation.InviterUserID)
+	if err != nil {
+		return err
+	}
+	invitee, err := n.userRepo.FindByID(ctx, invitation.InviteeUserID)
+	if err != nil {
+		return err
+	}
+	// The project or an account was deleted in the meantime
+	if project == nil || inviter == nil || invitee == nil {
+		return nil
+	}
+
+	var notification port.Notification
+	switch e.Type {
+	case event.InvitationCreated:
+		notification = invitationCreatedNotification(invitation, project, inviter, invitee)
+	case event.InvitationAccepted:
+		notification = invitationAcceptedNotification(invitation, project, inviter, invitee)
+	}
+	return n.notifier.Notify(ctx, notification)
+}
+
+func invitationCreatedNotification(invitation *domain.Invitation, project *domain.Project, inviter, invitee *domain.User) port.Notification {
+	return port.Notification{
+		To:      invitee.Email,
+		Subject: fmt.Sprintf("%s invited you to %s", inviter.Name, project.Name),
+		Body: fmt.Sprintf("Hi %s,\n\n%s (@%s) invited you to join the project %q as %s.\n\n"+
+			"Sign in to accept or decline the invitation.\n",
+			invitee.Name, inviter.Name, inviter.Username, project.Name, invitation.Role),
+	}
+}
+
+func invitationAcceptedNotification(invitation *domain.Invitation, project *domain.Project, inviter, invitee *domain.User) port.Notification {
+	return port.Notification{
+		To:      inviter.Email,
+		Subject: fmt.Sprintf("%s joined %s", invitee.Name, project.Name),
+		Body: fmt.Sprintf("Hi %s,\n\n%s (@%s) accepted your invitation and joined the project %q as %s.\n",
+			inviter.Name, invitee.Name, invitee.Username, project.Name, invitation.Role),
+	}
+}
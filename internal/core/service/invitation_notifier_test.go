@@ -0,0 +1,98 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// recordingNotifier keeps what it was asked to send and fails with err
+type recordingNotifier struct {
+	mu   sync.Mutex
+	sent []port.Notification
+	err  error
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, notification port.Notification) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sent = append(n.sent, notification)
+	return n.err
+}
+
+func TestInvitationNotifierRecipients(t *testing.T) {
+	env := newInvitationTestEnv()
+	inviteeID := primitive.NewObjectID()
+	users := &fakeUserRepo{users: []*domain.User{
+		{ID: env.ownerID, Name: "Olga", Username: "olga", Email: "olga@example.com"},
+		{ID: inviteeID, Name: "Ivan", Username: "ivan", Email: "ivan@example.com"},
+	}}
+	projects := &fakeProjectRepo{projects: []*domain.Project{{ID: env.projectID, Name: "infra"}}}
+
+	invitation, err := env.invite(inviteeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	created := env.events.events[len(env.events.events)-1]
+	if created.Type != event.InvitationCreated {
+		t.Fatalf("last event = %s, want %s", created.Type, event.InvitationCreated)
+	}
+
+	tests := []struct {
+		name        string
+		event       event.Event
+		wantTo      string
+		wantSubject string
+	}{
+		{name: "created tells the invitee", event: created, wantTo: "ivan@example.com", wantSubject: "Olga invited you to infra"},
+		{name: "accepted tells the inviter", event: event.Event{Type: event.InvitationAccepted, ProjectID: env.projectID, ResourceID: invitation.ID},
+			wantTo: "olga@example.com", wantSubject: "Ivan joined infra"},
+		{name: "other events are ignored", event: event.Event{Type: event.InvitationDeclined, ProjectID: env.projectID, ResourceID: invitation.ID}},
+		{name: "missing invitation", event: event.Event{Type: event.InvitationCreated, ProjectID: env.projectID, ResourceID: primitive.NewObjectID()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifier := &recordingNotifier{}
+			NewInvitationNotifier(env.invitations, users, projects, notifier).HandleEvent(tt.event)
+
+			if tt.wantTo == "" {
+				if len(notifier.sent) != 0 {
+					t.Errorf("sent %+v, want nothing", notifier.sent)
+				}
+				return
+			}
+			if len(notifier.sent) != 1 {
+				t.Fatalf("sent %d notifications, want 1", len(notifier.sent))
+			}
+			if got := notifier.sent[0]; got.To != tt.wantTo || !strings.Contains(got.Subject, tt.wantSubject) {
+				t.Errorf("notification = %+v, want %q to %s", got, tt.wantSubject, tt.wantTo)
+			}
+		})
+	}
+
+	t.Run("link invitations have no invitee", func(t *testing.T) {
+		link := &domain.Invitation{ID: primitive.NewObjectID(), ProjectID: env.projectID, InviterUserID: env.ownerID}
+		env.invitations.invitations = append(env.invitations.invitations, link)
+		notifier := &recordingNotifier{}
+		NewInvitationNotifier(env.invitations, users, projects, notifier).HandleEvent(
+			event.Event{Type: event.InvitationCreated, ProjectID: env.projectID, ResourceID: link.ID})
+		if len(notifier.sent) != 0 {
+			t.Errorf("sent %+v, want nothing", notifier.sent)
+		}
+	})
+
+	t.Run("delivery failure is only logged", func(t *testing.T) {
+		notifier := &recordingNotifier{err: errors.New("smtp: connection refused")}
+		NewInvitationNotifier(env.invitations, users, projects, notifier).HandleEvent(created)
+		if len(notifier.sent) != 1 {
+			t.Errorf("attempted %d deliveries, want 1", len(notifier.sent))
+		}
+	})
+}
@@ -3,9 +3,11 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,23 +17,41 @@ var (
 	ErrNodeNotFound     = errors.New(dto.ErrCodeNodeNotFound)
 	ErrNodeAccessDenied = errors.New(dto.ErrCodeNodeAccessDenied)
 	ErrInvalidNodeID    = errors.New(dto.ErrCodeInvalidNodeID)
+	ErrInvalidNodeData  = errors.New(dto.ErrCodeInvalidNodeData)
+	ErrNodeRateLimited  = errors.New(dto.ErrCodeNodeRateLimited)
 )
 
 type NodeService struct {
-	nodeRepo          port.NodeRepository
-	diagramRepo       port.DiagramRepository
-	projectMemberRepo port.ProjectMemberRepository
+	nodeRepo    port.NodeRepository
+	diagramRepo port.DiagramRepository
+	authz       *AuthorizationService
+	limits      PayloadLimits
+	events      event.Publisher
+	updates     *idThrottle
+	locks       *DiagramLocks
 }
 
+// NewNodeService creates the node service. Updates to one node closer
+// together than minUpdateInterval are rejected with ErrNodeRateLimited; zero
+// allows any rate. Nodes of a diagram another member has locked are
+// read-only.
 func NewNodeService(
 	nodeRepo port.NodeRepository,
 	diagramRepo port.DiagramRepository,
-	projectMemberRepo port.ProjectMemberRepository,
+	authz *AuthorizationService,
+	limits PayloadLimits,
+	events event.Publisher,
+	minUpdateInterval time.Duration,
+	locks *DiagramLocks,
 ) *NodeService {
 	return &NodeService{
-		nodeRepo:          nodeRepo,
-		diagramRepo:       diagramRepo,
-		projectMemberRepo: projectMemberRepo,
+		nodeRepo:    nodeRepo,
+		diagramRepo: diagramRepo,
+		authz:       authz,
+		limits:      limits,
+		events:      events,
+		updates:     newIDThrottle(minUpdateInterval),
+		locks:       locks,
 	}
 }
 
@@ -53,11 +73,11 @@ func (s *NodeService) GetOrCreateNode(ctx context.Context, nodeIDStr string, dia
 		// Node exists: Verify diagram match and permission
 		if node.DiagramID != diagramID {
 			// Preventing ID manipulation: Node belongs to a different diagram
-			return nil, ErrNodeAccessDenied
+			return nil, ErrNodeNotFound
 		}
 
 		// Verify view permission on parent diagram
-		if err := s.verifyDiagramPermission(ctx, diagramID, userID, "view_diagram"); err != nil {
+		if _, err := s.verifyDiagramPermission(ctx, diagramID, userID, domain.PermissionViewDiagram); err != nil {
 			return nil, err
 		}
 
@@ -65,7 +85,7 @@ func (s *NodeService) GetOrCreateNode(ctx context.Context, nodeIDStr string, dia
 	}
 
 	// Node doesn't exist: Create it (requires edit permission)
-	if err := s.verifyDiagramPermission(ctx, diagramID, userID, "edit_diagram"); err != nil {
+	if _, err := s.verifyDiagramPermission(ctx, diagramID, userID, domain.PermissionEditDiagram); err != nil {
 		return nil, err
 	}
 
@@ -96,30 +116,46 @@ func (s *NodeService) UpdateNode(ctx context.Context, nodeIDStr string, userID p
 		}
 		return nil, err
 	}
+	if node == nil {
+		return nil, ErrNodeNotFound
+	}
 
 	// Verify edit permission
-	if err := s.verifyDiagramPermission(ctx, node.DiagramID, userID, "edit_diagram"); err != nil {
+	projectID, err := s.verifyDiagramPermission(ctx, node.DiagramID, userID, domain.PermissionEditDiagram)
+	if err != nil {
 		return nil, err
 	}
 
-	// Update fields
-	if req.EncryptedReadme != nil {
-		node.EncryptedReadme = *req.EncryptedReadme
+	if exceedsLimit(req.EncryptedReadme, s.limits.NodeData) || exceedsLimit(req.EncryptedDict, s.limits.NodeData) {
+		return nil, ErrInvalidNodeData
 	}
-	if req.EncryptedReadmeSignature != nil {
-		node.EncryptedReadmeSignature = *req.EncryptedReadmeSignature
-	}
-	if req.EncryptedDict != nil {
-		node.EncryptedDict = *req.EncryptedDict
+
+	if err := s.locks.checkWrite(node.DiagramID, userID, time.Now()); err != nil {
+		return nil, err
 	}
-	if req.EncryptedDictSignature != nil {
-		node.EncryptedDictSignature = *req.EncryptedDictSignature
+
+	// Autosaving clients can send a write per keystroke; past the configured
+	// rate they are asked to back off instead of each hitting the database
+	if !s.updates.allow(nodeID, time.Now()) {
+		return nil, ErrNodeRateLimited
 	}
 
-	if err := s.nodeRepo.Update(ctx, node); err != nil {
+	// Only the fields sent are written
+	node, err = s.nodeRepo.UpdateContent(ctx, nodeID, domain.NodeContentUpdate{
+		Label:                    req.Label,
+		EncryptedReadme:          req.EncryptedReadme,
+		EncryptedReadmeSignature: req.EncryptedReadmeSignature,
+		EncryptedDict:            req.EncryptedDict,
+		EncryptedDictSignature:   req.EncryptedDictSignature,
+	})
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNodeNotFound
+		}
 		return nil, err
 	}
 
+	s.publish(event.NodeUpdated, projectID, node, userID)
 	return node, nil
 }
 
@@ -137,74 +173,61 @@ func (s *NodeService) DeleteNode(ctx context.Context, nodeIDStr string, userID p
 		}
 		return err
 	}
-
 	if node == nil {
 		return nil // Idempotent: Node already gone
 	}
 
 	// Verify edit permission
-	if err := s.verifyDiagramPermission(ctx, node.DiagramID, userID, "edit_diagram"); err != nil {
+	projectID, err := s.verifyDiagramPermission(ctx, node.DiagramID, userID, domain.PermissionEditDiagram)
+	if err != nil {
+		return err
+	}
+
+	if err := s.locks.checkWrite(node.DiagramID, userID, time.Now()); err != nil {
+		return err
+	}
+
+	if err := s.nodeRepo.Delete(ctx, nodeID); err != nil {
 		return err
 	}
 
-	return s.nodeRepo.Delete(ctx, nodeID)
+	s.publish(event.NodeDeleted, projectID, node, userID)
+	return nil
 }
 
-// Helper to verify diagram permissions
-func (s *NodeService) verifyDiagramPermission(ctx context.Context, diagramID, userID primitive.ObjectID, requiredPermission string) error {
+// Helper to verify diagram permissions. Returns the diagram's project ID.
+func (s *NodeService) verifyDiagramPermission(ctx context.Context, diagramID, userID primitive.ObjectID, requiredPermission domain.Permission) (primitive.ObjectID, error) {
 	// 1. Get diagram to find project ID
 	diagram, err := s.diagramRepo.FindByID(ctx, diagramID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return errors.New(dto.ErrCodeDiagramNotFound)
+			return primitive.NilObjectID, ErrNodeNotFound
 		}
-		return err
+		return primitive.NilObjectID, err
 	}
-
-	// 2. Check project membership/permissions
-	member, err := s.projectMemberRepo.FindByProjectAndUser(ctx, diagram.ProjectID, userID)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return ErrNodeAccessDenied
-		}
-		return err
+	if diagram == nil {
+		return primitive.NilObjectID, ErrNodeNotFound
 	}
 
-	// Check specific permission (simplified for now, assuming role check or direct permission exist)
-	// In previous steps, we used HasPermission method if available, or role checks.
-	// ProjectMember struct likely has Permissions list or Role.
-	// Let's assume verifying member existence is enough for now, OR check role.
-	// Actually, we should check strictly. Let's look at ProjectMember definition or DiagramService pattern.
-	// For now, I'll restrict to Member exists. To be more strict, I should check permissions.
-	// But `ProjectMember` struct might not be fully visible here without importing domain.
-
-	// Based on DiagramService implementation:
-	// permission := "view_diagram"
-	// hasPermission := false
-	// for _, p := range member.Permissions {
-	//     if p == permission {
-	//         hasPermission = true
-	//         break
-	//     }
-	// }
-
-	hasPermission := false
-	for _, p := range member.Permissions {
-		if p == requiredPermission {
-			hasPermission = true
-			break
+	// 2. Check project membership/permissions; non-members see not found
+	if _, err := s.authz.Authorize(ctx, diagram.ProjectID, userID, requiredPermission); err != nil {
+		if errors.Is(err, ErrProjectAccessDenied) {
+			return primitive.NilObjectID, ErrNodeNotFound
 		}
-	}
-	// Owners always have permission (usually)
-	// But let's stick to explicit permissions if that's the model.
-	// Or check role == "owner".
-	if member.Role == "owner" {
-		hasPermission = true
+		if errors.Is(err, ErrInsufficientPermission) {
+			return primitive.NilObjectID, ErrNodeAccessDenied
+		}
+		return primitive.NilObjectID, err
 	}
 
-	if !hasPermission {
-		return ErrNodeAccessDenied
-	}
+	return diagram.ProjectID, nil
+}
 
-	return nil
+func (s *NodeService) publish(eventType event.Type, projectID primitive.ObjectID, node *domain.Node, userID primitive.ObjectID) {
+	s.events.Publish(event.Event{
+		Type:       eventType,
+		ProjectID:  projectID,
+		ActorID:    userID,
+		ResourceID: node.ID,
+	})
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -15,26 +16,34 @@ var (
 	ErrVaultItemNotFound = errors.New(dto.ErrCodeVaultItemNotFound)
 	ErrVaultAccessDenied = errors.New(dto.ErrCodeVaultAccessDenied)
 	ErrInvalidRequest    = errors.New(dto.ErrCodeInvalidRequest)
+	ErrInvalidVaultData  = errors.New(dto.ErrCodeInvalidVaultItemData)
+	ErrVaultConflict     = errors.New(dto.ErrCodeVaultVersionConflict)
 )
 
 type NodeVaultService struct {
-	nodeVaultRepo     port.NodeVaultRepository
-	nodeRepo          port.NodeRepository
-	diagramRepo       port.DiagramRepository
-	projectMemberRepo port.ProjectMemberRepository
+	nodeVaultRepo port.NodeVaultRepository
+	nodeRepo      port.NodeRepository
+	diagramRepo   port.DiagramRepository
+	authz         *AuthorizationService
+	limits        PayloadLimits
+	events        event.Publisher
 }
 
 func NewNodeVaultService(
 	nodeVaultRepo port.NodeVaultRepository,
 	nodeRepo port.NodeRepository,
 	diagramRepo port.DiagramRepository,
-	projectMemberRepo port.ProjectMemberRepository,
+	authz *AuthorizationService,
+	limits PayloadLimits,
+	events event.Publisher,
 ) *NodeVaultService {
 	return &NodeVaultService{
-		nodeVaultRepo:     nodeVaultRepo,
-		nodeRepo:          nodeRepo,
-		diagramRepo:       diagramRepo,
-		projectMemberRepo: projectMemberRepo,
+		nodeVaultRepo: nodeVaultRepo,
+		nodeRepo:      nodeRepo,
+		diagramRepo:   diagramRepo,
+		authz:         authz,
+		limits:        limits,
+		events:        events,
 	}
 }
 
@@ -46,10 +55,14 @@ func (s *NodeVaultService) CreateVaultItem(ctx context.Context, nodeIDStr string
 	}
 
 	// 1. Verify Edit Permission using passed ProjectID
-	if err := s.verifyProjectPermission(ctx, projectID, userID, "edit_vault"); err != nil {
+	if err := s.verifyProjectPermission(ctx, projectID, userID, domain.PermissionEditVault, ErrProjectNotFound); err != nil {
 		return nil, err
 	}
 
+	if exceedsLimit(&req.EncryptedValue, s.limits.VaultValue) {
+		return nil, ErrInvalidVaultData
+	}
+
 	vaultItem := &domain.NodeVault{
 		NodeId:                  nodeID,
 		ProjectId:               projectID,
@@ -57,51 +70,96 @@ func (s *NodeVaultService) CreateVaultItem(ctx context.Context, nodeIDStr string
 		Type:                    req.Type,
 		EncryptedValue:          &req.EncryptedValue,
 		EncryptedValueSignature: &req.EncryptedValueSignature,
+		CreatedBy:               userID,
+		UpdatedBy:               userID,
 	}
 
 	if err := s.nodeVaultRepo.Create(ctx, vaultItem); err != nil {
 		return nil, err
 	}
 
+	s.publish(event.VaultItemCreated, vaultItem, userID)
 	return vaultItem, nil
 }
 
-// GetVaultItem gets a specific vault item by ID
-func (s *NodeVaultService) GetVaultItem(ctx context.Context, vaultIDStr string, userID primitive.ObjectID) (*domain.NodeVault, error) {
-	vaultID, err := primitive.ObjectIDFromHex(vaultIDStr)
+// CreateVaultItems creates several vault items for a node in one insert.
+// Either every item is created or none is.
+func (s *NodeVaultService) CreateVaultItems(ctx context.Context, nodeIDStr string, projectID primitive.ObjectID, userID primitive.ObjectID, reqs []dto.CreateNodeVaultRequest) ([]*domain.NodeVault, error) {
+	nodeID, err := primitive.ObjectIDFromHex(nodeIDStr)
 	if err != nil {
-		return nil, ErrInvalidRequest
+		return nil, ErrInvalidNodeID
 	}
 
-	vaultItem, err := s.nodeVaultRepo.FindByID(ctx, vaultID)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, ErrVaultItemNotFound
+	if err := s.verifyProjectPermission(ctx, projectID, userID, domain.PermissionEditVault, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+
+	vaultItems := make([]*domain.NodeVault, len(reqs))
+	for i := range reqs {
+		req := &reqs[i]
+		if exceedsLimit(&req.EncryptedValue, s.limits.VaultValue) {
+			return nil, ErrInvalidVaultData
+		}
+		vaultItems[i] = &domain.NodeVault{
+			NodeId:                  nodeID,
+			ProjectId:               projectID,
+			Label:                   req.Label,
+			Type:                    req.Type,
+			EncryptedValue:          &req.EncryptedValue,
+			EncryptedValueSignature: &req.EncryptedValueSignature,
+			CreatedBy:               userID,
+			UpdatedBy:               userID,
 		}
+	}
+
+	if err := s.nodeVaultRepo.CreateMany(ctx, vaultItems); err != nil {
+		return nil, err
+	}
+
+	for _, vaultItem := range vaultItems {
+		s.publish(event.VaultItemCreated, vaultItem, userID)
+	}
+	return vaultItems, nil
+}
+
+// GetVaultItem gets a specific vault item by ID
+func (s *NodeVaultService) GetVaultItem(ctx context.Context, vaultIDStr, nodeIDStr string, projectID primitive.ObjectID, userID primitive.ObjectID) (*domain.NodeVault, error) {
+	vaultItem, err := s.loadVaultItem(ctx, vaultIDStr, nodeIDStr, projectID)
+	if err != nil {
 		return nil, err
 	}
 
 	// Verify Edit/View Permission (using view_vault as minimum)
-	if err := s.verifyProjectPermission(ctx, vaultItem.ProjectId, userID, "view_vault"); err != nil {
+	if err := s.verifyProjectPermission(ctx, vaultItem.ProjectId, userID, domain.PermissionViewVault, ErrVaultItemNotFound); err != nil {
 		return nil, err
 	}
 
 	return vaultItem, nil
 }
 
-// ListVaultItems lists all vault items for a node
-func (s *NodeVaultService) ListVaultItems(ctx context.Context, nodeIDStr string, projectID primitive.ObjectID, userID primitive.ObjectID) ([]*domain.NodeVault, error) {
+// ListVaultItems lists the vault items for a node. A non-empty vaultType
+// limits the list to items of that type.
+func (s *NodeVaultService) ListVaultItems(ctx context.Context, nodeIDStr string, projectID primitive.ObjectID, userID primitive.ObjectID, vaultType string) ([]*domain.NodeVault, error) {
 	nodeID, err := primitive.ObjectIDFromHex(nodeIDStr)
 	if err != nil {
 		return nil, ErrInvalidNodeID
 	}
 
 	// 1. Verify View Permission using passed ProjectID
-	if err := s.verifyProjectPermission(ctx, projectID, userID, "view_vault"); err != nil {
+	if err := s.verifyProjectPermission(ctx, projectID, userID, domain.PermissionViewVault, ErrProjectNotFound); err != nil {
 		return nil, err
 	}
 
-	items, err := s.nodeVaultRepo.FindByNodeID(ctx, nodeID)
+	if vaultType != "" && !domain.IsValidVaultType(vaultType) {
+		return nil, ErrInvalidVaultData
+	}
+
+	var items []*domain.NodeVault
+	if vaultType != "" {
+		items, err = s.nodeVaultRepo.FindByNodeIDAndType(ctx, projectID, nodeID, vaultType)
+	} else {
+		items, err = s.nodeVaultRepo.FindByNodeID(ctx, nodeID)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -115,26 +173,41 @@ func (s *NodeVaultService) ListVaultItems(ctx context.Context, nodeIDStr string,
 	return items, nil
 }
 
-// UpdateVaultItem updates a vault item
-func (s *NodeVaultService) UpdateVaultItem(ctx context.Context, vaultIDStr string, userID primitive.ObjectID, req dto.UpdateNodeVaultRequest) (*domain.NodeVault, error) {
-	vaultID, err := primitive.ObjectIDFromHex(vaultIDStr)
+// CountVaultItems returns how many vault items a node has
+func (s *NodeVaultService) CountVaultItems(ctx context.Context, nodeIDStr string, projectID primitive.ObjectID, userID primitive.ObjectID) (int64, error) {
+	nodeID, err := primitive.ObjectIDFromHex(nodeIDStr)
 	if err != nil {
-		return nil, ErrInvalidRequest
+		return 0, ErrInvalidNodeID
 	}
 
-	vaultItem, err := s.nodeVaultRepo.FindByID(ctx, vaultID)
+	if err := s.verifyProjectPermission(ctx, projectID, userID, domain.PermissionViewVault, ErrProjectNotFound); err != nil {
+		return 0, err
+	}
+
+	return s.nodeVaultRepo.CountByNodeID(ctx, projectID, nodeID)
+}
+
+// UpdateVaultItem updates a vault item
+func (s *NodeVaultService) UpdateVaultItem(ctx context.Context, vaultIDStr, nodeIDStr string, projectID primitive.ObjectID, userID primitive.ObjectID, req dto.UpdateNodeVaultRequest) (*domain.NodeVault, error) {
+	vaultItem, err := s.loadVaultItem(ctx, vaultIDStr, nodeIDStr, projectID)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, ErrVaultItemNotFound
-		}
 		return nil, err
 	}
 
 	// Verify Edit Permission using denormalized ProjectID
-	if err := s.verifyProjectPermission(ctx, vaultItem.ProjectId, userID, "edit_vault"); err != nil {
+	if err := s.verifyProjectPermission(ctx, vaultItem.ProjectId, userID, domain.PermissionEditVault, ErrVaultItemNotFound); err != nil {
 		return nil, err
 	}
 
+	if exceedsLimit(req.EncryptedValue, s.limits.VaultValue) {
+		return nil, ErrInvalidVaultData
+	}
+
+	// Reject edits made against a stale copy of the item
+	if req.Version != nil && *req.Version != vaultItem.Version {
+		return nil, ErrVaultConflict
+	}
+
 	if req.Label != nil {
 		vaultItem.Label = *req.Label
 	}
@@ -144,55 +217,84 @@ func (s *NodeVaultService) UpdateVaultItem(ctx context.Context, vaultIDStr strin
 	if req.EncryptedValueSignature != nil {
 		vaultItem.EncryptedValueSignature = req.EncryptedValueSignature
 	}
+	vaultItem.UpdatedBy = userID
 
 	if err := s.nodeVaultRepo.Update(ctx, vaultItem); err != nil {
+		if errors.Is(err, port.ErrVersionConflict) {
+			return nil, ErrVaultConflict
+		}
 		return nil, err
 	}
 
+	s.publish(event.VaultItemUpdated, vaultItem, userID)
 	return vaultItem, nil
 }
 
 // DeleteVaultItem deletes a vault item
-func (s *NodeVaultService) DeleteVaultItem(ctx context.Context, vaultIDStr string, userID primitive.ObjectID) error {
-	vaultID, err := primitive.ObjectIDFromHex(vaultIDStr)
+func (s *NodeVaultService) DeleteVaultItem(ctx context.Context, vaultIDStr, nodeIDStr string, projectID primitive.ObjectID, userID primitive.ObjectID) error {
+	vaultItem, err := s.loadVaultItem(ctx, vaultIDStr, nodeIDStr, projectID)
 	if err != nil {
-		return ErrInvalidRequest
+		return err
 	}
 
-	vaultItem, err := s.nodeVaultRepo.FindByID(ctx, vaultID)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return ErrVaultItemNotFound
-		}
+	// Verify Edit Permission using denormalized ProjectID
+	if err := s.verifyProjectPermission(ctx, vaultItem.ProjectId, userID, domain.PermissionEditVault, ErrVaultItemNotFound); err != nil {
 		return err
 	}
 
-	// Verify Edit Permission using denormalized ProjectID
-	if err := s.verifyProjectPermission(ctx, vaultItem.ProjectId, userID, "edit_vault"); err != nil {
+	if err := s.nodeVaultRepo.Delete(ctx, vaultItem.ID); err != nil {
 		return err
 	}
 
-	return s.nodeVaultRepo.Delete(ctx, vaultID)
+	s.publish(event.VaultItemDeleted, vaultItem, userID)
+	return nil
 }
 
-func (s *NodeVaultService) verifyProjectPermission(ctx context.Context, projectID, userID primitive.ObjectID, permission string) error {
-	member, err := s.projectMemberRepo.FindByProjectAndUser(ctx, projectID, userID)
+// loadVaultItem fetches a vault item and checks it lives under the node and
+// project named in the request path. A mismatch is reported as not found so
+// items cannot be reached through another node's or project's URL.
+func (s *NodeVaultService) loadVaultItem(ctx context.Context, vaultIDStr, nodeIDStr string, projectID primitive.ObjectID) (*domain.NodeVault, error) {
+	vaultID, err := primitive.ObjectIDFromHex(vaultIDStr)
+	if err != nil {
+		return nil, ErrInvalidRequest
+	}
+	nodeID, err := primitive.ObjectIDFromHex(nodeIDStr)
+	if err != nil {
+		return nil, ErrInvalidNodeID
+	}
+
+	vaultItem, err := s.nodeVaultRepo.FindByID(ctx, vaultID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
-			return ErrVaultAccessDenied
+			return nil, ErrVaultItemNotFound
 		}
-		return err
+		return nil, err
 	}
-
-	// Check permission or owner role
-	if member.Role == "owner" {
-		return nil
+	if vaultItem == nil || vaultItem.NodeId != nodeID || vaultItem.ProjectId != projectID {
+		return nil, ErrVaultItemNotFound
 	}
-	for _, p := range member.Permissions {
-		if p == permission {
-			return nil
+
+	return vaultItem, nil
+}
+
+// verifyProjectPermission checks the user's permission in the project.
+// Non-members get notFound so they cannot probe which IDs exist.
+func (s *NodeVaultService) verifyProjectPermission(ctx context.Context, projectID, userID primitive.ObjectID, permission domain.Permission, notFound error) error {
+	if _, err := s.authz.Authorize(ctx, projectID, userID, permission); err != nil {
+		err = concealNonMember(err, notFound)
+		if errors.Is(err, ErrInsufficientPermission) {
+			return ErrVaultAccessDenied
 		}
+		return err
 	}
+	return nil
+}
 
-	return ErrVaultAccessDenied // Or ErrInsufficientPermission
+func (s *NodeVaultService) publish(eventType event.Type, vaultItem *domain.NodeVault, userID primitive.ObjectID) {
+	s.events.Publish(event.Event{
+		Type:       eventType,
+		ProjectID:  vaultItem.ProjectId,
+		ActorID:    userID,
+		ResourceID: vaultItem.ID,
+	})
 }
@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestUpdateVaultItemConcurrentEdits(t *testing.T) {
+	tests := []struct {
+		name string
+		// stored is the version field in the database; nil for items
+		// created before versioning
+		stored *int
+	}{
+		{name: "versioned item", stored: func() *int { v := 3; return &v }()},
+		{name: "legacy item without version", stored: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := primitive.NewObjectID()
+			projectID := primitive.NewObjectID()
+			item := &domain.NodeVault{
+				ID:        primitive.NewObjectID(),
+				NodeId:    primitive.NewObjectID(),
+				ProjectId: projectID,
+				Label:     "db password",
+			}
+			vaults := newFakeVaultRepo(item)
+			vaults.versions[item.ID] = tt.stored
+			vaults.readers = &sync.WaitGroup{}
+			vaults.readers.Add(2)
+
+			members := &fakeMemberRepo{members: []*domain.ProjectMember{
+				{ProjectID: projectID, UserID: userID, Role: domain.RoleOwner},
+			}}
+			svc := NewNodeVaultService(vaults, nil, nil, NewAuthorizationService(members), PayloadLimits{}, &fakePublisher{})
+
+			version := 0
+			if tt.stored != nil {
+				version = *tt.stored
+			}
+
+			// Both edits were made against the same copy of the item
+			errs := make([]error, 2)
+			var wg sync.WaitGroup
+			for i, label := range []string{"first", "second"} {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, errs[i] = svc.UpdateVaultItem(context.Background(), item.ID.Hex(), item.NodeId.Hex(), projectID, userID,
+						dto.UpdateNodeVaultRequest{Version: &version, Label: &label})
+				}()
+			}
+			wg.Wait()
+
+			succeeded, conflicted := 0, 0
+			for _, err := range errs {
+				switch {
+				case err == nil:
+					succeeded++
+				case errors.Is(err, ErrVaultConflict):
+					conflicted++
+				default:
+					t.Fatalf("UpdateVaultItem: unexpected error %v", err)
+				}
+			}
+			if succeeded != 1 || conflicted != 1 {
+				t.Fatalf("got %d successes and %d conflicts, want one of each", succeeded, conflicted)
+			}
+			if got := *vaults.versions[item.ID]; got != version+1 {
+				t.Errorf("stored version = %d, want %d", got, version+1)
+			}
+
+			// A retry against the stale version is refused before writing
+			vaults.readers = nil
+			label := "stale"
+			_, err := svc.UpdateVaultItem(context.Background(), item.ID.Hex(), item.NodeId.Hex(), projectID, userID,
+				dto.UpdateNodeVaultRequest{Version: &version, Label: &label})
+			if !errors.Is(err, ErrVaultConflict) {
+				t.Errorf("stale retry = %v, want ErrVaultConflict", err)
+			}
+		})
+	}
+}
+
+func TestListVaultItemsByTypeStaysInProject(t *testing.T) {
+	userID := primitive.NewObjectID()
+	projectID, otherProjectID := primitive.NewObjectID(), primitive.NewObjectID()
+	nodeID, otherNodeID := primitive.NewObjectID(), primitive.NewObjectID()
+	secret := "ciphertext"
+	vaults := newFakeVaultRepo(
+		&domain.NodeVault{ID: primitive.NewObjectID(), NodeId: nodeID, ProjectId: projectID, Type: domain.VaultTypePassword, EncryptedValue: &secret},
+		&domain.NodeVault{ID: primitive.NewObjectID(), NodeId: nodeID, ProjectId: projectID, Type: domain.VaultTypeToken},
+		&domain.NodeVault{ID: primitive.NewObjectID(), NodeId: otherNodeID, ProjectId: otherProjectID, Type: domain.VaultTypePassword},
+	)
+	members := &fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: userID, Role: domain.RoleOwner},
+	}}
+	svc := NewNodeVaultService(vaults, nil, nil, NewAuthorizationService(members), PayloadLimits{}, &fakePublisher{})
+	ctx := context.Background()
+
+	items, err := svc.ListVaultItems(ctx, nodeID.Hex(), projectID, userID, domain.VaultTypePassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Type != domain.VaultTypePassword || items[0].EncryptedValue != nil {
+		t.Errorf("items = %+v, want the one password without its value", items)
+	}
+
+	// The caller's project with a node of another project
+	items, err = svc.ListVaultItems(ctx, otherNodeID.Hex(), projectID, userID, domain.VaultTypePassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 0 {
+		t.Errorf("listed %d items of another project's node", len(items))
+	}
+}
+
+func TestCountVaultItemsStaysInProject(t *testing.T) {
+	userID := primitive.NewObjectID()
+	projectID, otherProjectID := primitive.NewObjectID(), primitive.NewObjectID()
+	nodeID, otherNodeID := primitive.NewObjectID(), primitive.NewObjectID()
+	vaults := newFakeVaultRepo(
+		&domain.NodeVault{ID: primitive.NewObjectID(), NodeId: nodeID, ProjectId: projectID},
+		&domain.NodeVault{ID: primitive.NewObjectID(), NodeId: nodeID, ProjectId: projectID},
+		&domain.NodeVault{ID: primitive.NewObjectID(), NodeId: otherNodeID, ProjectId: otherProjectID},
+	)
+	members := &fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: userID, Role: domain.RoleOwner},
+	}}
+	svc := NewNodeVaultService(vaults, nil, nil, NewAuthorizationService(members), PayloadLimits{}, &fakePublisher{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		nodeID primitive.ObjectID
+		want   int64
+	}{
+		{name: "own node", nodeID: nodeID, want: 2},
+		{name: "another project's node", nodeID: otherNodeID, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			count, err := svc.CountVaultItems(ctx, tt.nodeID.Hex(), projectID, userID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != tt.want {
+				t.Errorf("count = %d, want %d", count, tt.want)
+			}
+		})
+	}
+
+	if _, err := svc.CountVaultItems(ctx, otherNodeID.Hex(), otherProjectID, userID); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("non-member count: err = %v, want %v", err, ErrProjectNotFound)
+	}
+}
+
+func TestCreateVaultItemsIsAllOrNothing(t *testing.T) {
+	userID := primitive.NewObjectID()
+	projectID, nodeID := primitive.NewObjectID(), primitive.NewObjectID()
+	vaults := newFakeVaultRepo()
+	members := &fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: userID, Role: domain.RoleOwner},
+	}}
+	pub := &fakePublisher{}
+	svc := NewNodeVaultService(vaults, nil, nil, NewAuthorizationService(members), PayloadLimits{VaultValue: 8}, pub)
+	ctx := context.Background()
+
+	item := func(label, value string) dto.CreateNodeVaultRequest {
+		return dto.CreateNodeVaultRequest{Label: label, Type: domain.VaultTypePassword, EncryptedValue: value, EncryptedValueSignature: "c2ln"}
+	}
+
+	// The oversized item comes last, after an item that would fit
+	_, err := svc.CreateVaultItems(ctx, nodeID.Hex(), projectID, userID,
+		[]dto.CreateNodeVaultRequest{item("fits", "12345678"), item("too big", "123456789")})
+	if !errors.Is(err, ErrInvalidVaultData) {
+		t.Fatalf("err = %v, want %v", err, ErrInvalidVaultData)
+	}
+	if len(vaults.items) != 0 || len(pub.events) != 0 {
+		t.Fatalf("stored %d items and published %d events after a rejected batch", len(vaults.items), len(pub.events))
+	}
+
+	created, err := svc.CreateVaultItems(ctx, nodeID.Hex(), projectID, userID,
+		[]dto.CreateNodeVaultRequest{item("first", "1234"), item("second", "5678")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(created) != 2 || len(vaults.items) != 2 {
+		t.Fatalf("created %d items, stored %d, want 2", len(created), len(vaults.items))
+	}
+	for _, vault := range created {
+		if vault.ID.IsZero() || vault.NodeId != nodeID || vault.ProjectId != projectID {
+			t.Errorf("created item %+v is missing its ID or owner", vault)
+		}
+	}
+	if len(pub.events) != 2 {
+		t.Errorf("published %d events, want 2", len(pub.events))
+	}
+}
@@ -3,33 +3,46 @@ package service
 import (
 	"context"
 	"errors"
+	"sort"
+	"strings"
 
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
+// maxNoteTreeDepth bounds how many folder levels GetNoteTree returns
+const maxNoteTreeDepth = 64
+
 var (
-	ErrNoteNotFound     = errors.New("note not found")
-	ErrNoteAccessDenied = errors.New("note access denied")
+	ErrNoteNotFound    = errors.New("note not found")
+	ErrInvalidNoteData = errors.New("invalid note data")
 )
 
 type NoteService struct {
 	noteRepo    port.NoteRepository
-	memberRepo  port.ProjectMemberRepository
+	authz       *AuthorizationService
 	projectRepo port.ProjectRepository
+	limits      PayloadLimits
+	events      event.Publisher
 }
 
 func NewNoteService(
 	noteRepo port.NoteRepository,
-	memberRepo port.ProjectMemberRepository,
+	authz *AuthorizationService,
 	projectRepo port.ProjectRepository,
+	limits PayloadLimits,
+	events event.Publisher,
 ) *NoteService {
 	return &NoteService{
 		noteRepo:    noteRepo,
-		memberRepo:  memberRepo,
+		authz:       authz,
 		projectRepo: projectRepo,
+		limits:      limits,
+		events:      events,
 	}
 }
 
@@ -45,8 +58,12 @@ func (s *NoteService) CreateNote(
 	encryptedContent *string,
 	signature *string,
 ) (*domain.Note, error) {
+	if !domain.IsValidNoteType(noteType) || exceedsLimit(encryptedContent, s.limits.NoteContent) {
+		return nil, ErrInvalidNoteData
+	}
+
 	// Check permission
-	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionEditNote); err != nil {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionEditNote, ErrProjectNotFound); err != nil {
 		return nil, err
 	}
 
@@ -66,12 +83,15 @@ func (s *NoteService) CreateNote(
 		Icon:                      icon,
 		EncryptedContent:          encryptedContent,
 		EncryptedContentSignature: signature,
+		CreatedBy:                 userID,
+		UpdatedBy:                 userID,
 	}
 
 	if err := s.noteRepo.Create(ctx, note); err != nil {
 		return nil, err
 	}
 
+	s.publish(event.NoteCreated, note, userID)
 	return note, nil
 }
 
@@ -87,9 +107,12 @@ func (s *NoteService) GetNote(
 		}
 		return nil, err
 	}
+	if note == nil {
+		return nil, ErrNoteNotFound
+	}
 
 	// Check permission
-	if err := s.hasPermission(ctx, note.ProjectID, userID, domain.PermissionViewNote); err != nil {
+	if err := s.hasPermission(ctx, note.ProjectID, userID, domain.PermissionViewNote, ErrNoteNotFound); err != nil {
 		return nil, err
 	}
 
@@ -102,7 +125,7 @@ func (s *NoteService) ListNotes(
 	projectID, userID primitive.ObjectID,
 ) ([]*domain.Note, error) {
 	// Check permission
-	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewNote); err != nil {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewNote, ErrProjectNotFound); err != nil {
 		return nil, err
 	}
 
@@ -110,6 +133,99 @@ func (s *NoteService) ListNotes(
 	return s.noteRepo.FindByProjectID(ctx, projectID)
 }
 
+// GetNoteTree returns the project's notes nested under their folders. Notes
+// whose parent is missing, or that sit in a parent cycle, are placed at the
+// top level; levels deeper than maxNoteTreeDepth are left out. Folders come
+// before notes, each sorted by name.
+func (s *NoteService) GetNoteTree(ctx context.Context, projectID, userID primitive.ObjectID) ([]dto.NoteTreeNode, error) {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewNote, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.FindByProjectID(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]*domain.Note, len(notes))
+	for _, n := range notes {
+		byID[n.ID] = n
+	}
+	children := make(map[primitive.ObjectID][]*domain.Note)
+	var roots []*domain.Note
+	for _, n := range notes {
+		if n.ParentID != nil {
+			if _, ok := byID[*n.ParentID]; ok {
+				children[*n.ParentID] = append(children[*n.ParentID], n)
+				continue
+			}
+		}
+		roots = append(roots, n)
+	}
+
+	placed := make(map[primitive.ObjectID]struct{}, len(notes))
+	var build func(level []*domain.Note, depth int) []dto.NoteTreeNode
+	build = func(level []*domain.Note, depth int) []dto.NoteTreeNode {
+		sortNoteTreeLevel(level)
+		nodes := make([]dto.NoteTreeNode, 0, len(level))
+		for _, n := range level {
+			if _, ok := placed[n.ID]; ok {
+				continue
+			}
+			placed[n.ID] = struct{}{}
+			node := dto.NoteTreeNode{
+				ID:   n.ID.Hex(),
+				Name: n.FileName,
+				Type: n.Type,
+				Icon: n.Icon,
+			}
+			if depth < maxNoteTreeDepth {
+				node.Children = build(children[n.ID], depth+1)
+			}
+			nodes = append(nodes, node)
+		}
+		return nodes
+	}
+	tree := build(roots, 1)
+
+	// Notes in a parent cycle are unreachable from the top level; list them
+	// there so they stay visible and can be moved out
+	var unplaced []*domain.Note
+	for _, n := range notes {
+		if _, ok := placed[n.ID]; !ok && !reachesRoot(n, byID) {
+			unplaced = append(unplaced, n)
+		}
+	}
+	if len(unplaced) > 0 {
+		tree = append(tree, build(unplaced, 1)...)
+	}
+
+	return tree, nil
+}
+
+// CountNotes returns how many notes and folders a project has
+func (s *NoteService) CountNotes(ctx context.Context, projectID, userID primitive.ObjectID) (int64, error) {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewNote, ErrProjectNotFound); err != nil {
+		return 0, err
+	}
+
+	return s.noteRepo.CountByProjectID(ctx, projectID)
+}
+
+// ListNotesAfter lists notes ordered by ID, starting after the given cursor ID
+func (s *NoteService) ListNotesAfter(
+	ctx context.Context,
+	projectID, userID, afterID primitive.ObjectID,
+	limit int,
+) ([]*domain.Note, error) {
+	// Check permission
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionViewNote, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+
+	return s.noteRepo.FindByProjectIDAfter(ctx, projectID, afterID, limit)
+}
+
 // UpdateNote updates an existing note
 func (s *NoteService) UpdateNote(
 	ctx context.Context,
@@ -119,6 +235,10 @@ func (s *NoteService) UpdateNote(
 	icon *string,
 	encryptedContent, signature *string,
 ) (*domain.Note, error) {
+	if exceedsLimit(encryptedContent, s.limits.NoteContent) {
+		return nil, ErrInvalidNoteData
+	}
+
 	note, err := s.noteRepo.FindByID(ctx, noteID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
@@ -126,44 +246,47 @@ func (s *NoteService) UpdateNote(
 		}
 		return nil, err
 	}
+	if note == nil {
+		return nil, ErrNoteNotFound
+	}
 
 	// Check permission
-	if err := s.hasPermission(ctx, note.ProjectID, userID, domain.PermissionEditNote); err != nil {
+	if err := s.hasPermission(ctx, note.ProjectID, userID, domain.PermissionEditNote, ErrNoteNotFound); err != nil {
 		return nil, err
 	}
 
-	// Update fields if provided
-	if fileName != nil {
-		note.FileName = *fileName
+	// Only the fields sent are written
+	update := domain.NoteUpdate{
+		FileName:                  fileName,
+		Icon:                      icon,
+		EncryptedContent:          encryptedContent,
+		EncryptedContentSignature: signature,
+		UpdatedBy:                 userID,
 	}
 	if parentID != nil {
-		if *parentID == "" {
-			note.ParentID = nil
-		} else {
-			pid, err := primitive.ObjectIDFromHex(*parentID)
-			if err == nil {
-				// Verify new parent
-				if err := s.verifyParent(ctx, pid, note.ProjectID); err != nil {
-					return nil, err
-				}
-				note.ParentID = &pid
+		pid := primitive.NilObjectID
+		if *parentID != "" {
+			pid, err = primitive.ObjectIDFromHex(*parentID)
+			if err != nil {
+				return nil, ErrInvalidNoteData
+			}
+			// Verify new parent
+			if err := s.verifyParent(ctx, pid, note.ProjectID); err != nil {
+				return nil, err
 			}
 		}
-	}
-	if icon != nil {
-		note.Icon = *icon
-	}
-	if encryptedContent != nil {
-		note.EncryptedContent = encryptedContent
-	}
-	if signature != nil {
-		note.EncryptedContentSignature = signature
+		update.ParentID = &pid
 	}
 
-	if err := s.noteRepo.Update(ctx, note); err != nil {
+	note, err = s.noteRepo.UpdateFields(ctx, noteID, update)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrNoteNotFound
+		}
 		return nil, err
 	}
 
+	s.publish(event.NoteUpdated, note, userID)
 	return note, nil
 }
 
@@ -179,13 +302,77 @@ func (s *NoteService) DeleteNote(
 		}
 		return err
 	}
+	if note == nil {
+		return ErrNoteNotFound
+	}
 
 	// Check permission
-	if err := s.hasPermission(ctx, note.ProjectID, userID, domain.PermissionEditNote); err != nil {
+	if err := s.hasPermission(ctx, note.ProjectID, userID, domain.PermissionEditNote, ErrNoteNotFound); err != nil {
+		return err
+	}
+
+	if err := s.noteRepo.Delete(ctx, noteID); err != nil {
 		return err
 	}
 
-	return s.noteRepo.Delete(ctx, noteID)
+	s.publish(event.NoteDeleted, note, userID)
+	return nil
+}
+
+// DeleteNotes deletes several notes of a project at once. Edit permission is
+// checked once; the returned errors hold each ID's outcome in request order,
+// so an ID that is missing or belongs to another project only fails itself.
+// As with DeleteNote, a folder's children are left in place.
+func (s *NoteService) DeleteNotes(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	noteIDs []primitive.ObjectID,
+) ([]error, error) {
+	if err := s.hasPermission(ctx, projectID, userID, domain.PermissionEditNote, ErrProjectNotFound); err != nil {
+		return nil, err
+	}
+
+	notes, err := s.noteRepo.FindByIDs(ctx, noteIDs)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[primitive.ObjectID]*domain.Note, len(notes))
+	for _, note := range notes {
+		if note.ProjectID == projectID {
+			found[note.ID] = note
+		}
+	}
+
+	errs := make([]error, len(noteIDs))
+	deleting := make([]*domain.Note, 0, len(found))
+	queued := make(map[primitive.ObjectID]bool, len(found))
+	for i, id := range noteIDs {
+		note, ok := found[id]
+		if !ok {
+			errs[i] = ErrNoteNotFound
+			continue
+		}
+		if !queued[id] {
+			queued[id] = true
+			deleting = append(deleting, note)
+		}
+	}
+	if len(deleting) == 0 {
+		return errs, nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(deleting))
+	for _, note := range deleting {
+		ids = append(ids, note.ID)
+	}
+	if err := s.noteRepo.DeleteMany(ctx, ids); err != nil {
+		return nil, err
+	}
+
+	for _, note := range deleting {
+		s.publish(event.NoteDeleted, note, userID)
+	}
+	return errs, nil
 }
 
 // verifyParent checks if the parent ID exists and is a folder
@@ -197,37 +384,70 @@ func (s *NoteService) verifyParent(ctx context.Context, parentID primitive.Objec
 		}
 		return err
 	}
+	if parent == nil {
+		return errors.New("parent folder not found")
+	}
 
 	if parent.ProjectID != projectID {
 		return errors.New("parent folder belongs to a different project")
 	}
 
-	if parent.Type != "folder" {
+	if parent.Type != domain.NoteTypeFolder {
 		return errors.New("parent is not a folder")
 	}
 
 	return nil
 }
 
-// hasPermission checks if user has a specific permission for the project
+// hasPermission checks if user has a specific permission for the project.
+// Non-members get notFound so they cannot probe which IDs exist.
 func (s *NoteService) hasPermission(
 	ctx context.Context,
 	projectID, userID primitive.ObjectID,
-	permission string,
+	permission domain.Permission,
+	notFound error,
 ) error {
-	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, userID)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return ErrNoteAccessDenied
-		}
-		return err
+	if _, err := s.authz.Authorize(ctx, projectID, userID, permission); err != nil {
+		return concealNonMember(err, notFound)
 	}
+	return nil
+}
+
+func (s *NoteService) publish(eventType event.Type, note *domain.Note, userID primitive.ObjectID) {
+	s.events.Publish(event.Event{
+		Type:       eventType,
+		ProjectID:  note.ProjectID,
+		ActorID:    userID,
+		ResourceID: note.ID,
+	})
+}
 
-	for _, p := range member.Permissions {
-		if p == permission {
-			return nil
+// sortNoteTreeLevel orders folders before notes, then by name
+func sortNoteTreeLevel(level []*domain.Note) {
+	sort.SliceStable(level, func(i, j int) bool {
+		iFolder := level[i].Type == domain.NoteTypeFolder
+		jFolder := level[j].Type == domain.NoteTypeFolder
+		if iFolder != jFolder {
+			return iFolder
 		}
-	}
+		return strings.ToLower(level[i].FileName) < strings.ToLower(level[j].FileName)
+	})
+}
 
-	return ErrInsufficientPermission
+// reachesRoot reports whether following note's parents ends at the top level
+// rather than looping
+func reachesRoot(note *domain.Note, byID map[primitive.ObjectID]*domain.Note) bool {
+	visited := make(map[primitive.ObjectID]struct{})
+	for current := note; current.ParentID != nil; {
+		if _, seen := visited[current.ID]; seen {
+			return false
+		}
+		visited[current.ID] = struct{}{}
+		parent, ok := byID[*current.ParentID]
+		if !ok {
+			return true
+		}
+		current = parent
+	}
+	return true
 }
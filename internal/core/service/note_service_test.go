@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// newTestNoteService returns a service over notes in which userID may view
+// and edit the notes of projectID
+func newTestNoteService(projectID, userID primitive.ObjectID, notes ...*domain.Note) (*NoteService, *fakeNoteRepo) {
+	repo := &fakeNoteRepo{notes: notes}
+	authz := NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: userID, Role: domain.RoleEditor, Permissions: domain.PermissionStrings([]domain.Permission{
+			domain.PermissionViewNote, domain.PermissionEditNote,
+		})},
+	}})
+	return NewNoteService(repo, authz, nil, PayloadLimits{}, &fakePublisher{}), repo
+}
+
+func TestCreateNoteTypes(t *testing.T) {
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	content, signature := "Y29udGVudA==", "c2ln"
+
+	t.Run("folder then leaf inside it", func(t *testing.T) {
+		svc, repo := newTestNoteService(projectID, userID)
+		folder, err := svc.CreateNote(context.Background(), projectID, userID, nil, domain.NoteTypeFolder, "Runbooks", "", nil, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaf, err := svc.CreateNote(context.Background(), projectID, userID, &folder.ID, domain.NoteTypeNote, "Failover", "", &content, &signature)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if leaf.ParentID == nil || *leaf.ParentID != folder.ID || len(repo.notes) != 2 {
+			t.Errorf("leaf parent = %v, stored %d notes", leaf.ParentID, len(repo.notes))
+		}
+	})
+
+	t.Run("leaf cannot hold notes", func(t *testing.T) {
+		leaf := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: domain.NoteTypeNote}
+		svc, repo := newTestNoteService(projectID, userID, leaf)
+		if _, err := svc.CreateNote(context.Background(), projectID, userID, &leaf.ID, domain.NoteTypeNote, "Child", "", &content, &signature); err == nil {
+			t.Error("created a note under a leaf")
+		}
+		if len(repo.notes) != 1 {
+			t.Errorf("stored %d notes, want 1", len(repo.notes))
+		}
+	})
+
+	for _, noteType := range []string{"Folder", "markdown", ""} {
+		t.Run("rejects "+noteType, func(t *testing.T) {
+			svc, repo := newTestNoteService(projectID, userID)
+			_, err := svc.CreateNote(context.Background(), projectID, userID, nil, noteType, "Typo", "", nil, nil)
+			if !errors.Is(err, ErrInvalidNoteData) {
+				t.Errorf("err = %v, want %v", err, ErrInvalidNoteData)
+			}
+			if len(repo.notes) != 0 {
+				t.Errorf("stored %d notes, want 0", len(repo.notes))
+			}
+		})
+	}
+}
+
+func TestGetNoteTree(t *testing.T) {
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	content := "Y29udGVudA=="
+	newNote := func(noteType, name string, parent *domain.Note) *domain.Note {
+		n := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, Type: noteType, FileName: name, EncryptedContent: &content}
+		if parent != nil {
+			n.ParentID = &parent.ID
+		}
+		return n
+	}
+
+	ops := newNote(domain.NoteTypeFolder, "Ops", nil)
+	runbooks := newNote(domain.NoteTypeFolder, "Runbooks", ops)
+	failover := newNote(domain.NoteTypeNote, "failover", runbooks)
+	backups := newNote(domain.NoteTypeNote, "Backups", runbooks)
+	escalation := newNote(domain.NoteTypeNote, "Escalation", ops)
+	readme := newNote(domain.NoteTypeNote, "Readme", nil)
+	archive := newNote(domain.NoteTypeFolder, "Archive", nil)
+	gone := primitive.NewObjectID()
+	orphan := newNote(domain.NoteTypeNote, "Orphan", nil)
+	orphan.ParentID = &gone
+	loopA, loopB := newNote(domain.NoteTypeFolder, "Loop A", nil), newNote(domain.NoteTypeFolder, "Loop B", nil)
+	loopA.ParentID, loopB.ParentID = &loopB.ID, &loopA.ID
+
+	svc, _ := newTestNoteService(projectID, userID,
+		readme, failover, escalation, ops, backups, archive, runbooks, orphan, loopA, loopB)
+	tree, err := svc.GetNoteTree(context.Background(), projectID, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// render flattens the tree to "depth:name" in order
+	var render func(nodes []dto.NoteTreeNode, depth int) []string
+	render = func(nodes []dto.NoteTreeNode, depth int) []string {
+		var out []string
+		for _, n := range nodes {
+			out = append(out, fmt.Sprintf("%d:%s", depth, n.Name))
+			out = append(out, render(n.Children, depth+1)...)
+		}
+		return out
+	}
+	want := []string{
+		"0:Archive", "0:Ops", "1:Runbooks", "2:Backups", "2:failover", "1:Escalation",
+		"0:Orphan", "0:Readme",
+		"0:Loop A", "1:Loop B",
+	}
+	if got := render(tree, 0); !slices.Equal(got, want) {
+		t.Errorf("tree = %v\nwant   %v", got, want)
+	}
+
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), content) || strings.Contains(string(raw), "encrypted") {
+		t.Errorf("tree carries note content: %s", raw)
+	}
+}
+
+func TestGetNoteTreeDepthGuard(t *testing.T) {
+	projectID, userID := primitive.NewObjectID(), primitive.NewObjectID()
+	var notes []*domain.Note
+	var parentID *primitive.ObjectID
+	for range maxNoteTreeDepth + 5 {
+		n := &domain.Note{ID: primitive.NewObjectID(), ProjectID: projectID, ParentID: parentID, Type: domain.NoteTypeFolder, FileName: "level"}
+		notes = append(notes, n)
+		parentID = &n.ID
+	}
+
+	svc, _ := newTestNoteService(projectID, userID, notes...)
+	tree, err := svc.GetNoteTree(context.Background(), projectID, userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	depth := 0
+	for level := tree; len(level) > 0; level = level[0].Children {
+		depth++
+	}
+	if depth != maxNoteTreeDepth {
+		t.Errorf("tree is %d levels deep, want %d", depth, maxNoteTreeDepth)
+	}
+}
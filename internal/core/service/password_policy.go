@@ -0,0 +1,123 @@
+package service
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/i18n"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
+)
+
+// Validation tags reported for password policy violations. They have
+// entries in the validation message catalog so clients get localized text.
+const (
+	PasswordTagUppercase = "password_upper"
+	PasswordTagLowercase = "password_lower"
+	PasswordTagDigit     = "password_digit"
+	PasswordTagSymbol    = "password_symbol"
+	PasswordTagCommon    = "password_common"
+)
+
+// PasswordPolicy describes the passwords accepted on registration and
+// password change. Lengths count characters, not bytes; a zero length
+// disables that bound. Existing passwords are never re-checked.
+type PasswordPolicy struct {
+	MinLength     int
+	MaxLength     int
+	RequireUpper  bool
+	RequireLower  bool
+	RequireDigit  bool
+	RequireSymbol bool
+	RejectCommon  bool
+}
+
+// PasswordPolicyError lists every rule a password broke, in the same shape
+// as request validation errors.
+type PasswordPolicyError struct {
+	Violations []validation.FieldError
+}
+
+func (e *PasswordPolicyError) Error() string {
+	tags := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		tags[i] = violation.Tag
+	}
+	return "password does not meet policy: " + strings.Join(tags, ", ")
+}
+
+// commonPasswords is a small denylist of passwords that show up at the top
+// of every breach corpus. Entries are lower case.
+var commonPasswords = map[string]struct{}{
+	"password": {}, "password1": {}, "password123": {}, "passw0rd": {},
+	"12345678": {}, "123456789": {}, "1234567890": {}, "87654321": {},
+	"qwerty123": {}, "qwertyuiop": {}, "1q2w3e4r": {}, "1qaz2wsx": {},
+	"abc12345": {}, "abcd1234": {}, "iloveyou": {}, "sunshine": {},
+	"princess": {}, "football": {}, "baseball": {}, "superman": {},
+	"trustno1": {}, "letmein1": {}, "welcome1": {}, "admin123": {},
+	"11111111": {}, "00000000": {}, "asdfghjkl": {}, "zaq12wsx": {},
+	"changeme": {}, "infrantery": {},
+}
+
+// Check validates password against the policy. field names the request
+// field in the returned violations. It returns nil when the password is
+// acceptable and a *PasswordPolicyError otherwise.
+func (p PasswordPolicy) Check(field, password string) error {
+	var violations []validation.FieldError
+	add := func(tag, param string) {
+		message, _ := validation.Message(i18n.DefaultLocale, tag, param)
+		violations = append(violations, validation.FieldError{
+			Field:   field,
+			Tag:     tag,
+			Param:   param,
+			Message: message,
+		})
+	}
+
+	length := utf8.RuneCountInString(password)
+	if p.MinLength > 0 && length < p.MinLength {
+		add("min", strconv.Itoa(p.MinLength))
+	}
+	if p.MaxLength > 0 && length > p.MaxLength {
+		add("max", strconv.Itoa(p.MaxLength))
+		// Skip the remaining checks on oversized input
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r) || unicode.IsSpace(r):
+			hasSymbol = true
+		}
+	}
+	if p.RequireUpper && !hasUpper {
+		add(PasswordTagUppercase, "")
+	}
+	if p.RequireLower && !hasLower {
+		add(PasswordTagLowercase, "")
+	}
+	if p.RequireDigit && !hasDigit {
+		add(PasswordTagDigit, "")
+	}
+	if p.RequireSymbol && !hasSymbol {
+		add(PasswordTagSymbol, "")
+	}
+	if p.RejectCommon {
+		if _, ok := commonPasswords[strings.ToLower(password)]; ok {
+			add(PasswordTagCommon, "")
+		}
+	}
+
+	if violations != nil {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
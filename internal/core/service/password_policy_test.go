@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+)
+
+func TestPasswordPolicyCheck(t *testing.T) {
+	policy := PasswordPolicy{
+		MinLength:     10,
+		MaxLength:     64,
+		RequireUpper:  true,
+		RequireLower:  true,
+		RequireDigit:  true,
+		RequireSymbol: true,
+		RejectCommon:  true,
+	}
+
+	tests := []struct {
+		name     string
+		password string
+		wantTags []string
+	}{
+		{name: "meets every rule", password: "Tr0ub4dor&3x"},
+		{name: "counts characters, not bytes", password: "Pä$$wört9", wantTags: []string{"min"}},
+		{name: "too short", password: "Sh0rt!", wantTags: []string{"min"}},
+		{name: "too long skips the other rules", password: strings.Repeat("a", 65), wantTags: []string{"max"}},
+		{name: "no upper case", password: "tr0ub4dor&3x", wantTags: []string{PasswordTagUppercase}},
+		{name: "no lower case", password: "TR0UB4DOR&3X", wantTags: []string{PasswordTagLowercase}},
+		{name: "no digit", password: "Troubador&xx", wantTags: []string{PasswordTagDigit}},
+		{name: "no symbol", password: "Tr0ub4dor33x", wantTags: []string{PasswordTagSymbol}},
+		{name: "several rules at once", password: "abc", wantTags: []string{"min", PasswordTagUppercase, PasswordTagDigit, PasswordTagSymbol}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check("password", tt.password)
+			if tt.wantTags == nil {
+				if err != nil {
+					t.Fatalf("rejected: %v", err)
+				}
+				return
+			}
+
+			var policyErr *PasswordPolicyError
+			if !errors.As(err, &policyErr) {
+				t.Fatalf("err = %v, want a *PasswordPolicyError", err)
+			}
+			var tags []string
+			for _, violation := range policyErr.Violations {
+				if violation.Field != "password" || violation.Message == "" {
+					t.Errorf("violation %+v lacks its field or message", violation)
+				}
+				tags = append(tags, violation.Tag)
+			}
+			if !slices.Equal(tags, tt.wantTags) {
+				t.Errorf("tags = %v, want %v", tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestPasswordPolicyRejectsCommonPasswords(t *testing.T) {
+	policy := PasswordPolicy{RejectCommon: true}
+	for _, password := range []string{"password123", "Password123", "INFRANTERY"} {
+		var policyErr *PasswordPolicyError
+		if err := policy.Check("password", password); !errors.As(err, &policyErr) || policyErr.Violations[0].Tag != PasswordTagCommon {
+			t.Errorf("%q: err = %v, want %s", password, err, PasswordTagCommon)
+		}
+	}
+	if err := (PasswordPolicy{}).Check("password", "password123"); err != nil {
+		t.Errorf("the zero policy rejected a password: %v", err)
+	}
+}
+
+func TestRegisterEnforcesPasswordPolicy(t *testing.T) {
+	svc, _ := newTestAuthService(t)
+	svc.passwordPolicy = PasswordPolicy{MinLength: 12, RequireDigit: true}
+
+	_, err := svc.Register(context.Background(), dto.RegisterRequest{
+		Email:    "grace@example.com",
+		Username: "grace",
+		Password: "too simple",
+	})
+	var policyErr *PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		t.Fatalf("err = %v, want a *PasswordPolicyError", err)
+	}
+	if len(policyErr.Violations) != 2 {
+		t.Errorf("violations = %+v, want min and %s", policyErr.Violations, PasswordTagDigit)
+	}
+}
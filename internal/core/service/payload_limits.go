@@ -0,0 +1,15 @@
+package service
+
+// PayloadLimits bounds the size (in bytes) of encrypted blobs accepted per
+// resource. A zero value disables the check for that resource.
+type PayloadLimits struct {
+	DiagramData int
+	NodeData    int
+	VaultValue  int
+	NoteContent int
+}
+
+// exceedsLimit reports whether an optional encrypted field is larger than limit.
+func exceedsLimit(value *string, limit int) bool {
+	return limit > 0 && value != nil && len(*value) > limit
+}
@@ -0,0 +1,28 @@
+package service
+
+import "testing"
+
+func TestExceedsLimit(t *testing.T) {
+	str := func(s string) *string { return &s }
+
+	tests := []struct {
+		name  string
+		value *string
+		limit int
+		want  bool
+	}{
+		{name: "below the limit", value: str("abc"), limit: 4, want: false},
+		{name: "exactly the limit", value: str("abcd"), limit: 4, want: false},
+		{name: "one byte over", value: str("abcde"), limit: 4, want: true},
+		{name: "field not sent", value: nil, limit: 4, want: false},
+		{name: "empty value", value: str(""), limit: 1, want: false},
+		{name: "limit disabled", value: str("abcde"), limit: 0, want: false},
+		{name: "negative limit disabled", value: str("abcde"), limit: -1, want: false},
+		{name: "counted in bytes", value: str("ééé"), limit: 5, want: true},
+	}
+	for _, tt := range tests {
+		if got := exceedsLimit(tt.value, tt.limit); got != tt.want {
+			t.Errorf("%s: exceedsLimit = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
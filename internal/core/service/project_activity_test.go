@@ -0,0 +1,57 @@
+package service
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// touchProjectRepo records the projects Touch was called for
+type touchProjectRepo struct {
+	port.ProjectRepository
+	mu      sync.Mutex
+	touched []primitive.ObjectID
+}
+
+func (r *touchProjectRepo) Touch(_ context.Context, projectID primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.touched = append(r.touched, projectID)
+	return nil
+}
+
+func TestNoteEditsTouchProject(t *testing.T) {
+	ctx := context.Background()
+	projectID, otherProjectID, userID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+
+	projectRepo := &touchProjectRepo{}
+	projects := NewProjectService(projectRepo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, &fakePublisher{}, 0, 0)
+	bus := event.NewBus()
+	bus.Register("project_activity", 16, projects.HandleEvent)
+
+	notes := NewNoteService(&fakeNoteRepo{}, NewAuthorizationService(&fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: userID, Role: domain.RoleOwner},
+	}}), nil, PayloadLimits{}, bus)
+
+	// A burst of edits touches the project once
+	for _, name := range []string{"Runbooks", "Postmortems", "Drafts"} {
+		if _, err := notes.CreateNote(ctx, projectID, userID, nil, domain.NoteTypeFolder, name, "", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Changes to the project itself are not activity
+	bus.Publish(event.Event{Type: event.ProjectUpdated, ProjectID: otherProjectID, ActorID: userID})
+	bus.Close()
+
+	projectRepo.mu.Lock()
+	defer projectRepo.mu.Unlock()
+	if len(projectRepo.touched) != 1 || projectRepo.touched[0] != projectID {
+		t.Errorf("touched %v, want only %s once", projectRepo.touched, projectID.Hex())
+	}
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// archiveProjectRepo archives and unarchives in place, matching like the
+// real filters
+type archiveProjectRepo struct {
+	fakeProjectRepo
+}
+
+func (r *archiveProjectRepo) Archive(_ context.Context, projectID primitive.ObjectID, archivedAt time.Time) (*domain.Project, error) {
+	for _, p := range r.projects {
+		if p.ID == projectID && p.DeletedAt == nil && p.ArchivedAt == nil {
+			p.ArchivedAt = &archivedAt
+			clone := *p
+			return &clone, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func (r *archiveProjectRepo) Unarchive(_ context.Context, projectID primitive.ObjectID) (*domain.Project, error) {
+	for _, p := range r.projects {
+		if p.ID == projectID && p.DeletedAt == nil {
+			p.ArchivedAt = nil
+			clone := *p
+			return &clone, nil
+		}
+	}
+	return nil, mongo.ErrNoDocuments
+}
+
+func TestArchiveProject(t *testing.T) {
+	ctx := context.Background()
+	projectID, ownerID, viewerID := primitive.NewObjectID(), primitive.NewObjectID(), primitive.NewObjectID()
+	repo := &archiveProjectRepo{fakeProjectRepo{projects: []*domain.Project{{ID: projectID, Name: "infra"}}}}
+	members := &fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: projectID, UserID: ownerID, Role: domain.RoleOwner},
+		{ProjectID: projectID, UserID: viewerID, Role: domain.RoleViewer, Permissions: []string{string(domain.PermissionViewDiagram)}},
+	}}
+	events := &fakePublisher{}
+	svc := NewProjectService(repo, members, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		NewAuthorizationService(members), nil, events, 0, 0)
+
+	if _, err := svc.ArchiveProject(ctx, projectID, viewerID); !errors.Is(err, ErrInsufficientPermission) {
+		t.Errorf("viewer: err = %v, want %v", err, ErrInsufficientPermission)
+	}
+
+	archived, err := svc.ArchiveProject(ctx, projectID, ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !archived.IsArchived() {
+		t.Fatal("project is not archived")
+	}
+
+	// Archiving again keeps the original time and announces nothing
+	again, err := svc.ArchiveProject(ctx, projectID, ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.ArchivedAt.Equal(*archived.ArchivedAt) {
+		t.Errorf("archived_at moved from %v to %v", archived.ArchivedAt, again.ArchivedAt)
+	}
+
+	// An archived project stays readable
+	if project, err := repo.FindByID(ctx, projectID); err != nil || project == nil {
+		t.Errorf("FindByID = %v, %v; want the archived project", project, err)
+	}
+
+	unarchived, err := svc.UnarchiveProject(ctx, projectID, ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unarchived.IsArchived() {
+		t.Error("project is still archived")
+	}
+
+	var types []event.Type
+	for _, e := range events.events {
+		types = append(types, e.Type)
+	}
+	if len(types) != 2 || types[0] != event.ProjectArchived || types[1] != event.ProjectUnarchived {
+		t.Errorf("events = %v, want one archive and one unarchive", types)
+	}
+
+	if _, err := svc.UnarchiveProject(ctx, primitive.NewObjectID(), ownerID); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("unknown project: err = %v, want %v", err, ErrProjectNotFound)
+	}
+}
@@ -0,0 +1,304 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// DefaultProjectDeletionGrace is how long a deletion request waits for
+	// another owner to cancel it when no grace period is configured.
+	DefaultProjectDeletionGrace = 72 * time.Hour
+
+	// DefaultProjectRetention is how long a deleted project stays in the
+	// recycle bin when no retention is configured.
+	DefaultProjectRetention = 7 * 24 * time.Hour
+
+	// projectPurgeLease is how long a claimed project is reserved for the
+	// claiming instance before another may retry the purge.
+	projectPurgeLease = time.Hour
+
+	// purgeArchiveBatch is how many backup archives a purge lists at a time
+	// while deleting their stored objects.
+	purgeArchiveBatch = 100
+)
+
+var (
+	ErrProjectDeletionPending      = errors.New("project deletion already requested")
+	ErrProjectDeletionNotRequested = errors.New("project deletion not requested")
+	ErrProjectDeletionNeedsConsent = errors.New("project has several owners, request deletion instead")
+)
+
+// ---------------------------------------------------------------------------
+// Deletion Requests
+// ---------------------------------------------------------------------------
+
+// RequestProjectDeletion marks the project for deletion after the grace
+// period. Other members are notified through the event stream and any owner
+// can cancel until the purge runs.
+func (s *ProjectService) RequestProjectDeletion(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+) (*domain.Project, error) {
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if project == nil {
+		return nil, ErrProjectNotFound
+	}
+	if project.DeletionPending() {
+		return nil, ErrProjectDeletionPending
+	}
+
+	now := time.Now().UTC()
+	project, err = s.projectRepo.MarkForDeletion(ctx, projectID, userID, now, now.Add(s.deletionGrace))
+	if err != nil {
+		// The project exists, so a miss means another owner got there first
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProjectDeletionPending
+		}
+		return nil, err
+	}
+
+	s.publish(event.ProjectDeletionRequested, projectID, userID, projectID)
+	return project, nil
+}
+
+// CancelProjectDeletion withdraws a pending deletion request. Any owner may
+// cancel, not only the one who asked.
+func (s *ProjectService) CancelProjectDeletion(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+) error {
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return err
+	}
+
+	cleared, err := s.projectRepo.ClearDeletion(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if !cleared {
+		return ErrProjectDeletionNotRequested
+	}
+
+	s.publish(event.ProjectDeletionCancelled, projectID, userID, projectID)
+	return nil
+}
+
+// GetDeletedProjects lists the user's projects that are in the recycle bin,
+// each paired with the user's role so clients know which ones they can restore
+func (s *ProjectService) GetDeletedProjects(ctx context.Context, userID primitive.ObjectID) ([]*domain.MemberProject, error) {
+	return s.projectRepo.FindDeletedByUserID(ctx, userID)
+}
+
+// RestoreProject takes a project out of the recycle bin. Only members who
+// could manage the project before it was deleted may restore it.
+func (s *ProjectService) RestoreProject(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+) (*domain.Project, error) {
+	member, err := s.memberRepo.FindInDeletedProject(ctx, projectID, userID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, err
+	}
+	if member == nil {
+		return nil, ErrProjectNotFound
+	}
+	if !s.authz.Can(member, domain.PermissionManageProject) {
+		return nil, ErrInsufficientPermission
+	}
+
+	project, err := s.projectRepo.Restore(ctx, projectID)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// An earlier restore may have stopped before the memberships below
+		project, err = s.projectRepo.FindByID(ctx, projectID)
+		if err == nil && project == nil {
+			return nil, ErrProjectNotFound
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.memberRepo.SetProjectDeleted(ctx, projectID, false); err != nil {
+		return nil, err
+	}
+
+	s.publish(event.ProjectRestored, projectID, userID, projectID)
+	return project, nil
+}
+
+// softDeleteProject moves the project to the recycle bin. Memberships are
+// flagged first so no member can act on the project once it is hidden.
+func (s *ProjectService) softDeleteProject(ctx context.Context, projectID primitive.ObjectID) error {
+	if err := s.memberRepo.SetProjectDeleted(ctx, projectID, true); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	if err := s.projectRepo.SoftDelete(ctx, projectID, now, now.Add(s.retention)); err != nil {
+		if revertErr := s.memberRepo.SetProjectDeleted(ctx, projectID, false); revertErr != nil {
+			logger.Error().Err(revertErr).Str("project_id", projectID.Hex()).Msg("Failed to unflag memberships after soft delete failed")
+		}
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// countOwners returns how many members of the project hold the owner role
+func (s *ProjectService) countOwners(ctx context.Context, projectID primitive.ObjectID) (int, error) {
+	members, _, err := s.memberRepo.FindByProjectID(ctx, projectID, 0, 10000) // Get all members
+	if err != nil {
+		return 0, err
+	}
+
+	owners := 0
+	for _, m := range members {
+		if m.IsOwner() {
+			owners++
+		}
+	}
+	return owners, nil
+}
+
+// ---------------------------------------------------------------------------
+// Scheduled Purge
+// ---------------------------------------------------------------------------
+
+// PurgeDueProjects moves projects whose deletion request outlived its grace
+// period to the recycle bin, then purges deleted projects whose retention
+// has run out. It returns how many projects were purged. A failed step stays
+// scheduled and is retried once its claim lease expires.
+func (s *ProjectService) PurgeDueProjects(ctx context.Context) (int, error) {
+	for ctx.Err() == nil {
+		project, err := s.projectRepo.ClaimDueDeletion(ctx, time.Now().UTC(), projectPurgeLease)
+		if err != nil {
+			return 0, err
+		}
+		if project == nil {
+			break
+		}
+
+		if err := s.softDeleteProject(ctx, project.ID); err != nil {
+			logger.Error().
+				Err(err).
+				Str("project_id", project.ID.Hex()).
+				Msg("Scheduled project deletion failed")
+			continue
+		}
+
+		s.publish(event.ProjectDeleted, project.ID, project.DeletionRequestedBy, project.ID)
+	}
+
+	purged := 0
+	for ctx.Err() == nil {
+		project, err := s.projectRepo.ClaimDuePurge(ctx, time.Now().UTC(), projectPurgeLease)
+		if err != nil {
+			return purged, err
+		}
+		if project == nil {
+			break
+		}
+
+		if err := s.purgeProject(ctx, project.ID); err != nil {
+			logger.Error().
+				Err(err).
+				Str("project_id", project.ID.Hex()).
+				Msg("Project purge failed")
+			continue
+		}
+
+		purged++
+		s.publish(event.ProjectPurged, project.ID, primitive.NilObjectID, project.ID)
+	}
+
+	return purged, nil
+}
+
+// ProjectDeletionScheduler periodically carries out expired deletion
+// requests and empties the recycle bin.
+type ProjectDeletionScheduler struct {
+	projectService *ProjectService
+	tick           time.Duration
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewProjectDeletionScheduler creates a scheduler that checks for due
+// deletions every tick.
+func NewProjectDeletionScheduler(projectService *ProjectService, tick time.Duration) *ProjectDeletionScheduler {
+	if tick <= 0 {
+		tick = time.Minute
+	}
+	return &ProjectDeletionScheduler{
+		projectService: projectService,
+		tick:           tick,
+	}
+}
+
+// Start launches the scheduler goroutine. It stops when Stop is called.
+func (s *ProjectDeletionScheduler) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.cancel = cancel
+	s.done = make(chan struct{})
+
+	go func() {
+		defer close(s.done)
+
+		ticker := time.NewTicker(s.tick)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				purged, err := s.projectService.PurgeDueProjects(ctx)
+				if err != nil && ctx.Err() == nil {
+					logger.Error().Err(err).Msg("Project deletion scheduler run failed")
+				}
+				if purged > 0 {
+					logger.Info().Int("purged", purged).Msg("Deleted projects purged")
+				}
+			}
+		}
+	}()
+
+	logger.Info().Dur("tick", s.tick).Msg("Project deletion scheduler started")
+}
+
+// Stop cancels the scheduler and waits for an in-flight run to finish.
+func (s *ProjectDeletionScheduler) Stop() {
+	s.once.Do(func() {
+		if s.cancel == nil {
+			return
+		}
+		s.cancel()
+		<-s.done
+	})
+}
@@ -0,0 +1,392 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// purgeLog records the collections a purge emptied, in order
+type purgeLog struct {
+	deleted []string
+}
+
+func (l *purgeLog) record(collection string) error {
+	l.deleted = append(l.deleted, collection)
+	return nil
+}
+
+type purgeMemberRepo struct {
+	port.ProjectMemberRepository
+	log *purgeLog
+}
+
+func (r purgeMemberRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	return r.log.record("members")
+}
+
+type purgeNoteRepo struct {
+	port.NoteRepository
+	log *purgeLog
+}
+
+func (r purgeNoteRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	return r.log.record("notes")
+}
+
+type purgeDiagramRepo struct {
+	port.DiagramRepository
+	log *purgeLog
+}
+
+func (r purgeDiagramRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	return r.log.record("diagrams")
+}
+
+type purgeKeyRotationRepo struct {
+	port.KeyRotationRepository
+	log *purgeLog
+}
+
+func (r purgeKeyRotationRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	return r.log.record("key_rotations")
+}
+
+type purgeShareLinkRepo struct {
+	port.ShareLinkRepository
+	log *purgeLog
+}
+
+func (r purgeShareLinkRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	return r.log.record("share_links")
+}
+
+type purgeCommentRepo struct {
+	port.CommentRepository
+	log *purgeLog
+}
+
+func (r purgeCommentRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	return r.log.record("comments")
+}
+
+type purgeRoleRepo struct {
+	port.ProjectRoleRepository
+	log *purgeLog
+}
+
+func (r purgeRoleRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	return r.log.record("roles")
+}
+
+type purgeScheduleRepo struct {
+	port.BackupScheduleRepository
+	log *purgeLog
+}
+
+func (r purgeScheduleRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	return r.log.record("backup_schedules")
+}
+
+type purgeProjectRepo struct {
+	port.ProjectRepository
+	log *purgeLog
+}
+
+func (r purgeProjectRepo) Delete(context.Context, primitive.ObjectID) error {
+	return r.log.record("projects")
+}
+
+type purgeArchiveRepo struct {
+	port.BackupArchiveRepository
+	log      *purgeLog
+	archives []*domain.BackupArchive
+}
+
+func (r *purgeArchiveRepo) FindByProjectID(_ context.Context, projectID primitive.ObjectID, offset, limit int) ([]*domain.BackupArchive, int64, error) {
+	var found []*domain.BackupArchive
+	for _, a := range r.archives {
+		if a.ProjectID == projectID {
+			found = append(found, a)
+		}
+	}
+	total := int64(len(found))
+	found = found[min(offset, len(found)):]
+	return found[:min(limit, len(found))], total, nil
+}
+
+func (r *purgeArchiveRepo) DeleteByProjectID(context.Context, primitive.ObjectID) error {
+	r.archives = nil
+	return r.log.record("backup_archives")
+}
+
+// memoryStorage holds archive objects by key; deleteErr fails every delete
+type memoryStorage struct {
+	port.BackupStorage
+	objects   map[string]bool
+	deleteErr error
+}
+
+func (s *memoryStorage) Delete(_ context.Context, key string) error {
+	if s.deleteErr != nil {
+		return s.deleteErr
+	}
+	delete(s.objects, key)
+	return nil
+}
+
+func newPurgeTestService(log *purgeLog, archives *purgeArchiveRepo, storage port.BackupStorage) *ProjectService {
+	return NewProjectService(purgeProjectRepo{log: log}, purgeMemberRepo{log: log}, nil, purgeNoteRepo{log: log},
+		purgeDiagramRepo{log: log}, nil, purgeKeyRotationRepo{log: log}, purgeShareLinkRepo{log: log},
+		purgeCommentRepo{log: log}, purgeRoleRepo{log: log}, archives, purgeScheduleRepo{log: log}, storage,
+		nil, nil, &fakePublisher{}, 0, 0)
+}
+
+// archivesFor stores n archives of the project, more than one listing batch
+func archivesFor(projectID primitive.ObjectID, n int, storage *memoryStorage) []*domain.BackupArchive {
+	archives := make([]*domain.BackupArchive, n)
+	for i := range archives {
+		key := fmt.Sprintf("backups/%s/%d.infbak", projectID.Hex(), i)
+		archives[i] = &domain.BackupArchive{ID: primitive.NewObjectID(), ProjectID: projectID, Key: key}
+		storage.objects[key] = true
+	}
+	return archives
+}
+
+func TestPurgeProjectDeletesBackups(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	storage := &memoryStorage{objects: make(map[string]bool)}
+	log := &purgeLog{}
+	archives := &purgeArchiveRepo{log: log, archives: archivesFor(projectID, purgeArchiveBatch+1, storage)}
+	svc := newPurgeTestService(log, archives, storage)
+
+	if err := svc.purgeProject(context.Background(), projectID); err != nil {
+		t.Fatal(err)
+	}
+	if len(storage.objects) != 0 {
+		t.Errorf("%d archive objects left in storage", len(storage.objects))
+	}
+	for _, collection := range []string{"backup_schedules", "backup_archives", "projects"} {
+		if !slices.Contains(log.deleted, collection) {
+			t.Errorf("purge did not delete %s; deleted %v", collection, log.deleted)
+		}
+	}
+}
+
+func TestPurgeProjectKeepsArchiveRecordsWhenStorageFails(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	storage := &memoryStorage{objects: make(map[string]bool), deleteErr: errors.New("bucket unavailable")}
+	log := &purgeLog{}
+	archives := &purgeArchiveRepo{log: log, archives: archivesFor(projectID, 2, storage)}
+	svc := newPurgeTestService(log, archives, storage)
+
+	if err := svc.purgeProject(context.Background(), projectID); !errors.Is(err, storage.deleteErr) {
+		t.Fatalf("err = %v, want %v", err, storage.deleteErr)
+	}
+	// The retried purge must still find the objects to delete
+	if len(archives.archives) != 2 {
+		t.Errorf("%d archive records left, want 2", len(archives.archives))
+	}
+	if slices.Contains(log.deleted, "projects") {
+		t.Error("project was deleted although its archives were not")
+	}
+}
+
+func TestPurgeProjectWithoutStorage(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	log := &purgeLog{}
+	archives := &purgeArchiveRepo{log: log, archives: []*domain.BackupArchive{{ID: primitive.NewObjectID(), ProjectID: projectID}}}
+	svc := newPurgeTestService(log, archives, nil)
+
+	if err := svc.purgeProject(context.Background(), projectID); err != nil {
+		t.Fatal(err)
+	}
+	if len(archives.archives) != 0 {
+		t.Error("archive records were kept")
+	}
+}
+
+// recycleBin keeps projects and memberships and applies the recycle bin
+// filters the real repositories use: memberships of a deleted project are
+// flagged and left out of every lookup but FindInDeletedProject
+type recycleBin struct {
+	projects map[primitive.ObjectID]*domain.Project
+	members  []*domain.ProjectMember
+}
+
+type binProjectRepo struct {
+	port.ProjectRepository
+	bin *recycleBin
+}
+
+func (r binProjectRepo) FindByID(_ context.Context, id primitive.ObjectID) (*domain.Project, error) {
+	return r.bin.projects[id], nil
+}
+
+func (r binProjectRepo) FindByUserID(_ context.Context, userID primitive.ObjectID, _ domain.ProjectListQuery, offset, limit int) ([]*domain.MemberProject, int64, error) {
+	var found []*domain.MemberProject
+	for _, m := range r.bin.members {
+		if m.UserID == userID && !m.ProjectDeleted {
+			found = append(found, &domain.MemberProject{Project: r.bin.projects[m.ProjectID], Role: m.Role})
+		}
+	}
+	total := int64(len(found))
+	found = found[min(offset, len(found)):]
+	return found[:min(limit, len(found))], total, nil
+}
+
+func (r binProjectRepo) FindDeletedByUserID(_ context.Context, userID primitive.ObjectID) ([]*domain.MemberProject, error) {
+	var found []*domain.MemberProject
+	for _, m := range r.bin.members {
+		if m.UserID == userID && m.ProjectDeleted {
+			found = append(found, &domain.MemberProject{Project: r.bin.projects[m.ProjectID], Role: m.Role})
+		}
+	}
+	return found, nil
+}
+
+func (r binProjectRepo) SoftDelete(_ context.Context, id primitive.ObjectID, deletedAt, purgeAt time.Time) error {
+	project := r.bin.projects[id]
+	if project == nil || project.DeletedAt != nil {
+		return mongo.ErrNoDocuments
+	}
+	project.DeletedAt, project.PurgeAt = &deletedAt, &purgeAt
+	return nil
+}
+
+func (r binProjectRepo) Restore(_ context.Context, id primitive.ObjectID) (*domain.Project, error) {
+	project := r.bin.projects[id]
+	if project == nil || project.DeletedAt == nil {
+		return nil, mongo.ErrNoDocuments
+	}
+	project.DeletedAt, project.PurgeAt = nil, nil
+	return project, nil
+}
+
+type binMemberRepo struct {
+	port.ProjectMemberRepository
+	bin *recycleBin
+}
+
+func (r binMemberRepo) find(projectID, userID primitive.ObjectID, deleted bool) *domain.ProjectMember {
+	for _, m := range r.bin.members {
+		if m.ProjectID == projectID && m.UserID == userID && m.ProjectDeleted == deleted {
+			return m
+		}
+	}
+	return nil
+}
+
+func (r binMemberRepo) FindByProjectAndUser(_ context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
+	return r.find(projectID, userID, false), nil
+}
+
+func (r binMemberRepo) FindInDeletedProject(_ context.Context, projectID, userID primitive.ObjectID) (*domain.ProjectMember, error) {
+	return r.find(projectID, userID, true), nil
+}
+
+func (r binMemberRepo) FindByProjectID(_ context.Context, projectID primitive.ObjectID, _, _ int) ([]*domain.ProjectMember, int64, error) {
+	var found []*domain.ProjectMember
+	for _, m := range r.bin.members {
+		if m.ProjectID == projectID {
+			found = append(found, m)
+		}
+	}
+	return found, int64(len(found)), nil
+}
+
+func (r binMemberRepo) SetProjectDeleted(_ context.Context, projectID primitive.ObjectID, deleted bool) error {
+	for _, m := range r.bin.members {
+		if m.ProjectID == projectID {
+			m.ProjectDeleted = deleted
+		}
+	}
+	return nil
+}
+
+func TestProjectRecycleBin(t *testing.T) {
+	projectID := primitive.NewObjectID()
+	owner, viewer := primitive.NewObjectID(), primitive.NewObjectID()
+	bin := &recycleBin{
+		projects: map[primitive.ObjectID]*domain.Project{projectID: {ID: projectID, Name: "infra"}},
+		members: []*domain.ProjectMember{
+			{ProjectID: projectID, UserID: owner, Role: domain.RoleOwner},
+			{ProjectID: projectID, UserID: viewer, Role: domain.RoleViewer},
+		},
+	}
+	members := binMemberRepo{bin: bin}
+	svc := NewProjectService(binProjectRepo{bin: bin}, members, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		NewAuthorizationService(members), nil, &fakePublisher{}, 0, time.Hour)
+	ctx := context.Background()
+
+	listed := func(userID primitive.ObjectID) int {
+		t.Helper()
+		projects, total, err := svc.GetUserProjects(ctx, userID, domain.ProjectListQuery{}, 0, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if int(total) != len(projects) {
+			t.Fatalf("total %d for %d projects", total, len(projects))
+		}
+		return len(projects)
+	}
+
+	if err := svc.DeleteProject(ctx, projectID, viewer); !errors.Is(err, ErrInsufficientPermission) {
+		t.Fatalf("viewer delete: err = %v, want %v", err, ErrInsufficientPermission)
+	}
+	if err := svc.DeleteProject(ctx, projectID, owner); err != nil {
+		t.Fatal(err)
+	}
+
+	project := bin.projects[projectID]
+	if project.DeletedAt == nil || project.PurgeAt == nil || project.PurgeAt.Sub(*project.DeletedAt) != time.Hour {
+		t.Errorf("deleted project = %+v, want it kept for the retention window", project)
+	}
+
+	// Hidden from listings and from every member action
+	if n := listed(owner); n != 0 {
+		t.Errorf("owner still lists %d projects", n)
+	}
+	if _, _, err := svc.GetProjectDetails(ctx, projectID, viewer); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("details of a deleted project: err = %v, want %v", err, ErrProjectNotFound)
+	}
+	if err := svc.HasPermission(ctx, projectID, owner, domain.PermissionManageProject); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("owner acting on a deleted project: err = %v, want %v", err, ErrProjectNotFound)
+	}
+
+	deleted, err := svc.GetDeletedProjects(ctx, viewer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deleted) != 1 || deleted[0].Role != domain.RoleViewer {
+		t.Errorf("recycle bin = %+v, want the project with the viewer role", deleted)
+	}
+
+	if _, err := svc.RestoreProject(ctx, projectID, viewer); !errors.Is(err, ErrInsufficientPermission) {
+		t.Fatalf("viewer restore: err = %v, want %v", err, ErrInsufficientPermission)
+	}
+	if _, err := svc.RestoreProject(ctx, projectID, primitive.NewObjectID()); !errors.Is(err, ErrProjectNotFound) {
+		t.Fatalf("non-member restore: err = %v, want %v", err, ErrProjectNotFound)
+	}
+	restored, err := svc.RestoreProject(ctx, projectID, owner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored.DeletedAt != nil || restored.PurgeAt != nil {
+		t.Errorf("restored project = %+v, want it out of the recycle bin", restored)
+	}
+	if n := listed(viewer); n != 1 {
+		t.Errorf("viewer lists %d projects after the restore, want 1", n)
+	}
+	if _, err := svc.RestoreProject(ctx, projectID, owner); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("second restore: err = %v, want %v", err, ErrProjectNotFound)
+	}
+}
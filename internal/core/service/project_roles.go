@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+var (
+	ErrProjectRoleNotFound = errors.New("project role not found")
+	ErrProjectRoleExists   = errors.New("project role already exists")
+	ErrProjectRoleReserved = errors.New("project role name is reserved")
+	ErrProjectRoleInUse    = errors.New("project role is assigned to members")
+	ErrInvalidPermission   = errors.New("invalid permission")
+)
+
+// ---------------------------------------------------------------------------
+// Custom Roles
+// ---------------------------------------------------------------------------
+
+// ListRoles lists the project's custom roles. Any member may see them.
+func (s *ProjectService) ListRoles(ctx context.Context, projectID, userID primitive.ObjectID) ([]*domain.ProjectRole, error) {
+	if _, err := s.authz.GetMember(ctx, projectID, userID); err != nil {
+		return nil, concealNonMember(err, ErrProjectNotFound)
+	}
+	return s.roleRepo.FindByProjectID(ctx, projectID)
+}
+
+// CreateRole defines a named custom role in the project (manage_project)
+func (s *ProjectService) CreateRole(ctx context.Context, projectID, userID primitive.ObjectID, name string, permissions []string) (*domain.ProjectRole, error) {
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, err
+	}
+
+	name, err := s.checkRoleName(ctx, projectID, name, primitive.NilObjectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	role := &domain.ProjectRole{
+		ProjectID:   projectID,
+		Name:        name,
+		Permissions: permissions,
+	}
+	if err := s.roleRepo.Create(ctx, role); err != nil {
+		return nil, err
+	}
+
+	s.publish(event.RoleCreated, projectID, userID, role.ID)
+	return role, nil
+}
+
+// UpdateRole renames a custom role or changes its permissions
+// (manage_project). Members holding the role are moved along with it.
+func (s *ProjectService) UpdateRole(ctx context.Context, projectID, userID, roleID primitive.ObjectID, name string, permissions []string) (*domain.ProjectRole, error) {
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, err
+	}
+
+	role, err := s.findRole(ctx, projectID, roleID)
+	if err != nil {
+		return nil, err
+	}
+	name, err = s.checkRoleName(ctx, projectID, name, role.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkPermissions(permissions); err != nil {
+		return nil, err
+	}
+
+	previousName := role.Name
+	role.Name = name
+	role.Permissions = permissions
+	if err := s.roleRepo.Update(ctx, role); err != nil {
+		return nil, err
+	}
+
+	memberIDs, err := s.memberRepo.ApplyCustomRole(ctx, projectID, previousName, role)
+	if err != nil {
+		return nil, err
+	}
+
+	s.publish(event.RoleUpdated, projectID, userID, role.ID)
+	// Members' permissions changed too; their streams pick that up
+	for _, memberID := range memberIDs {
+		s.publish(event.MemberUpdated, projectID, userID, memberID)
+	}
+	return role, nil
+}
+
+// DeleteRole removes a custom role (manage_project). A role still assigned
+// to members cannot be deleted; move them to another role first.
+func (s *ProjectService) DeleteRole(ctx context.Context, projectID, userID, roleID primitive.ObjectID) error {
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return err
+	}
+
+	role, err := s.findRole(ctx, projectID, roleID)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := s.memberRepo.CountByCustomRole(ctx, projectID, role.Name)
+	if err != nil {
+		return err
+	}
+	if inUse > 0 {
+		return ErrProjectRoleInUse
+	}
+
+	if err := s.roleRepo.Delete(ctx, role.ID); err != nil {
+		return err
+	}
+
+	s.publish(event.RoleDeleted, projectID, userID, role.ID)
+	return nil
+}
+
+// resolveMemberRole works out what a member is granted: role, permissions
+// and the custom role name. A customRole takes its permissions from the
+// project's role of that name; otherwise role and permissions are used as
+// given.
+func (s *ProjectService) resolveMemberRole(ctx context.Context, projectID primitive.ObjectID, role string, permissions []string, customRole string) (string, []string, string, error) {
+	customRole = strings.TrimSpace(customRole)
+	if customRole == "" {
+		if err := checkPermissions(permissions); err != nil {
+			return "", nil, "", err
+		}
+		return role, permissions, "", nil
+	}
+
+	projectRole, err := s.roleRepo.FindByProjectAndName(ctx, projectID, customRole)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return "", nil, "", ErrProjectRoleNotFound
+		}
+		return "", nil, "", err
+	}
+	if projectRole == nil {
+		return "", nil, "", ErrProjectRoleNotFound
+	}
+	return domain.RoleCustom, projectRole.Permissions, projectRole.Name, nil
+}
+
+// findRole loads a role of the project, hiding roles of other projects
+func (s *ProjectService) findRole(ctx context.Context, projectID, roleID primitive.ObjectID) (*domain.ProjectRole, error) {
+	role, err := s.roleRepo.FindByID(ctx, roleID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProjectRoleNotFound
+		}
+		return nil, err
+	}
+	if role == nil || role.ProjectID != projectID {
+		return nil, ErrProjectRoleNotFound
+	}
+	return role, nil
+}
+
+// checkRoleName trims name and makes sure no built-in role or other role of
+// the project, apart from self, already uses it
+func (s *ProjectService) checkRoleName(ctx context.Context, projectID primitive.ObjectID, name string, self primitive.ObjectID) (string, error) {
+	name = strings.TrimSpace(name)
+	if domain.IsReservedRoleName(name) {
+		return "", ErrProjectRoleReserved
+	}
+
+	existing, err := s.roleRepo.FindByProjectAndName(ctx, projectID, name)
+	if err == nil && existing != nil && existing.ID != self {
+		return "", ErrProjectRoleExists
+	}
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return "", err
+	}
+	return name, nil
+}
+
+// checkPermissions rejects permission names the system does not know
+func checkPermissions(permissions []string) error {
+	for _, permission := range permissions {
+		if !domain.IsValidPermission(permission) {
+			return ErrInvalidPermission
+		}
+	}
+	return nil
+}
@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -22,35 +24,50 @@ var (
 	ErrInvitationAlreadyAccepted = errors.New("invitation already accepted")
 	ErrInvitationExpired         = errors.New("invitation expired")
 	ErrInvitationInvalidPassword = errors.New("invalid invitation password")
+	ErrInvitationDeclined        = errors.New("invitation declined")
+	ErrCannotInviteSelf          = errors.New("cannot invite yourself")
+	ErrDuplicateInvitee          = errors.New("invitee listed more than once")
 )
 
 // RolePresets defines default permissions for each role
-var RolePresets = map[string][]string{
-	"owner": {
+var RolePresets = map[string][]domain.Permission{
+	domain.RoleOwner: {
 		domain.PermissionViewDiagram, domain.PermissionEditDiagram,
 		domain.PermissionViewNote, domain.PermissionEditNote,
 		domain.PermissionViewVault, domain.PermissionEditVault,
 		domain.PermissionManageProject,
 	},
-	"editor": {
+	domain.RoleEditor: {
 		domain.PermissionViewDiagram, domain.PermissionEditDiagram,
 		domain.PermissionViewNote, domain.PermissionEditNote,
 		domain.PermissionViewVault,
 	},
-	"viewer": {
+	domain.RoleViewer: {
 		domain.PermissionViewDiagram,
 		domain.PermissionViewNote,
 	},
 }
 
 type ProjectService struct {
-	projectRepo    port.ProjectRepository
-	memberRepo     port.ProjectMemberRepository
-	userRepo       port.UserRepository
-	noteRepo       port.NoteRepository
-	diagramRepo    port.DiagramRepository
-	invitationRepo port.InvitationRepository
-	argon2Params   *Argon2Params
+	projectRepo     port.ProjectRepository
+	memberRepo      port.ProjectMemberRepository
+	userRepo        port.UserRepository
+	noteRepo        port.NoteRepository
+	diagramRepo     port.DiagramRepository
+	invitationRepo  port.InvitationRepository
+	keyRotationRepo port.KeyRotationRepository
+	shareLinkRepo   port.ShareLinkRepository
+	commentRepo     port.CommentRepository
+	roleRepo        port.ProjectRoleRepository
+	archiveRepo     port.BackupArchiveRepository
+	scheduleRepo    port.BackupScheduleRepository
+	backupStorage   port.BackupStorage
+	authz           *AuthorizationService
+	argon2Params    *Argon2Params
+	events          event.Publisher
+	deletionGrace   time.Duration
+	retention       time.Duration
+	touches         *idThrottle
 }
 
 func NewProjectService(
@@ -60,16 +77,45 @@ func NewProjectService(
 	noteRepo port.NoteRepository,
 	diagramRepo port.DiagramRepository,
 	invitationRepo port.InvitationRepository,
+	keyRotationRepo port.KeyRotationRepository,
+	shareLinkRepo port.ShareLinkRepository,
+	commentRepo port.CommentRepository,
+	roleRepo port.ProjectRoleRepository,
+	archiveRepo port.BackupArchiveRepository,
+	scheduleRepo port.BackupScheduleRepository,
+	backupStorage port.BackupStorage,
+	authz *AuthorizationService,
 	argon2Params *Argon2Params,
+	events event.Publisher,
+	deletionGrace time.Duration,
+	retention time.Duration,
 ) *ProjectService {
+	if deletionGrace <= 0 {
+		deletionGrace = DefaultProjectDeletionGrace
+	}
+	if retention <= 0 {
+		retention = DefaultProjectRetention
+	}
 	return &ProjectService{
-		projectRepo:    projectRepo,
-		memberRepo:     memberRepo,
-		userRepo:       userRepo,
-		noteRepo:       noteRepo,
-		diagramRepo:    diagramRepo,
-		invitationRepo: invitationRepo,
-		argon2Params:   argon2Params,
+		projectRepo:     projectRepo,
+		memberRepo:      memberRepo,
+		userRepo:        userRepo,
+		noteRepo:        noteRepo,
+		diagramRepo:     diagramRepo,
+		invitationRepo:  invitationRepo,
+		keyRotationRepo: keyRotationRepo,
+		shareLinkRepo:   shareLinkRepo,
+		commentRepo:     commentRepo,
+		roleRepo:        roleRepo,
+		archiveRepo:     archiveRepo,
+		scheduleRepo:    scheduleRepo,
+		backupStorage:   backupStorage,
+		authz:           authz,
+		argon2Params:    argon2Params,
+		events:          events,
+		deletionGrace:   deletionGrace,
+		retention:       retention,
+		touches:         newIDThrottle(projectTouchInterval),
 	}
 }
 
@@ -78,6 +124,7 @@ func (s *ProjectService) CreateProject(
 	ctx context.Context,
 	userID primitive.ObjectID,
 	name, description string,
+	tags []string,
 	secretPassphrase string,
 	secretSigningPrivateKey, signingPublicKey string,
 	userPublicKey string, userEncryptedPrivateKey string,
@@ -86,6 +133,7 @@ func (s *ProjectService) CreateProject(
 		ID:          primitive.NewObjectID(),
 		Name:        name,
 		Description: description,
+		Tags:        domain.NormalizeProjectTags(tags),
 		KeyEpoch:    "0",
 	}
 
@@ -97,8 +145,8 @@ func (s *ProjectService) CreateProject(
 	member := &domain.ProjectMember{
 		ProjectID:           project.ID,
 		UserID:              userID,
-		Role:                "owner",
-		Permissions:         RolePresets["owner"],
+		Role:                domain.RoleOwner,
+		Permissions:         domain.PermissionStrings(RolePresets[domain.RoleOwner]),
 		PublicKey:           userPublicKey,
 		EncryptedPrivateKey: userEncryptedPrivateKey,
 		Keyrings: []domain.ProjectMemberKeyring{
@@ -115,12 +163,28 @@ func (s *ProjectService) CreateProject(
 		return nil, err
 	}
 
+	s.publish(event.ProjectCreated, project.ID, userID, project.ID)
 	return project, nil
 }
 
-// GetUserProjects gets all projects the user has access to with pagination
-func (s *ProjectService) GetUserProjects(ctx context.Context, userID primitive.ObjectID, offset, limit int) ([]*domain.Project, int64, error) {
-	return s.projectRepo.FindByUserID(ctx, userID, offset, limit)
+// GetUserProjects gets the projects the user has access to with pagination,
+// each paired with the user's role, favorites first
+func (s *ProjectService) GetUserProjects(ctx context.Context, userID primitive.ObjectID, query domain.ProjectListQuery, offset, limit int) ([]*domain.MemberProject, int64, error) {
+	query.Tag = domain.NormalizeProjectTag(query.Tag)
+	return s.projectRepo.FindByUserID(ctx, userID, query, offset, limit)
+}
+
+// SetFavorite pins the project to the top of the user's project list, or
+// unpins it. Favorites are per member and visible to nobody else.
+func (s *ProjectService) SetFavorite(ctx context.Context, projectID, userID primitive.ObjectID, favorite bool) error {
+	isMember, err := s.memberRepo.SetFavorite(ctx, projectID, userID, favorite)
+	if err != nil {
+		return err
+	}
+	if !isMember {
+		return ErrProjectNotFound
+	}
+	return nil
 }
 
 // GetProjectDetails gets project details with user permissions
@@ -129,12 +193,9 @@ func (s *ProjectService) GetProjectDetails(
 	projectID, userID primitive.ObjectID,
 ) (*domain.Project, *domain.ProjectMember, error) {
 	// Check if user has access
-	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, userID)
+	member, err := s.authz.GetMember(ctx, projectID, userID)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, nil, ErrProjectAccessDenied
-		}
-		return nil, nil, err
+		return nil, nil, concealNonMember(err, ErrProjectNotFound)
 	}
 
 	project, err := s.projectRepo.FindByID(ctx, projectID)
@@ -144,6 +205,9 @@ func (s *ProjectService) GetProjectDetails(
 		}
 		return nil, nil, err
 	}
+	if project == nil {
+		return nil, nil, ErrProjectNotFound
+	}
 
 	return project, member, nil
 }
@@ -153,13 +217,17 @@ func (s *ProjectService) UpdateProject(
 	ctx context.Context,
 	projectID, userID primitive.ObjectID,
 	name, description *string,
+	tags []string,
 ) (*domain.Project, error) {
 	// Check permission
 	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
 		return nil, err
 	}
 
-	project, err := s.projectRepo.FindByID(ctx, projectID)
+	// Only the provided fields are written; nil leaves a field unchanged and
+	// an empty description or tag list clears it. The key epoch belongs to
+	// RotateProjectKeys and is never touched here.
+	project, err := s.projectRepo.UpdateMetadata(ctx, projectID, name, description, domain.NormalizeProjectTags(tags))
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return nil, ErrProjectNotFound
@@ -167,22 +235,58 @@ func (s *ProjectService) UpdateProject(
 		return nil, err
 	}
 
-	// Update fields if provided
-	if name != nil {
-		project.Name = *name
+	s.publish(event.ProjectUpdated, projectID, userID, projectID)
+	return project, nil
+}
+
+// ArchiveProject moves the project out of the default project list. Nothing
+// else changes; archiving an archived project keeps its original time.
+func (s *ProjectService) ArchiveProject(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.Project, error) {
+	// Check permission
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.Archive(ctx, projectID, time.Now().UTC())
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		// Already archived, or gone since the permission check
+		project, err = s.projectRepo.FindByID(ctx, projectID)
+		if errors.Is(err, mongo.ErrNoDocuments) || (err == nil && project == nil) {
+			return nil, ErrProjectNotFound
+		}
+		return project, err
 	}
-	if description != nil {
-		project.Description = *description
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.projectRepo.Update(ctx, project); err != nil {
+	s.publish(event.ProjectArchived, projectID, userID, projectID)
+	return project, nil
+}
+
+// UnarchiveProject puts an archived project back in the default project list
+func (s *ProjectService) UnarchiveProject(ctx context.Context, projectID, userID primitive.ObjectID) (*domain.Project, error) {
+	// Check permission
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, err
+	}
+
+	project, err := s.projectRepo.Unarchive(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrProjectNotFound
+		}
 		return nil, err
 	}
 
+	s.publish(event.ProjectUnarchived, projectID, userID, projectID)
 	return project, nil
 }
 
-// DeleteProject deletes a project (owner only)
+// DeleteProject moves a project to the recycle bin right away (owner only).
+// Projects with more than one owner must go through RequestProjectDeletion
+// instead, so no single owner can destroy shared work without the others
+// noticing.
 func (s *ProjectService) DeleteProject(
 	ctx context.Context,
 	projectID, userID primitive.ObjectID,
@@ -192,6 +296,25 @@ func (s *ProjectService) DeleteProject(
 		return err
 	}
 
+	owners, err := s.countOwners(ctx, projectID)
+	if err != nil {
+		return err
+	}
+	if owners > 1 {
+		return ErrProjectDeletionNeedsConsent
+	}
+
+	if err := s.softDeleteProject(ctx, projectID); err != nil {
+		return err
+	}
+
+	s.publish(event.ProjectDeleted, projectID, userID, projectID)
+	return nil
+}
+
+// purgeProject removes the project and everything stored under it. Only the
+// scheduler calls it, once the recycle bin retention has run out.
+func (s *ProjectService) purgeProject(ctx context.Context, projectID primitive.ObjectID) error {
 	// Cascade delete: Delete all members first
 	if err := s.memberRepo.DeleteByProjectID(ctx, projectID); err != nil {
 		return err
@@ -207,24 +330,83 @@ func (s *ProjectService) DeleteProject(
 		return err
 	}
 
+	// Cascade delete: Delete key rotation history
+	if err := s.keyRotationRepo.DeleteByProjectID(ctx, projectID); err != nil {
+		return err
+	}
+
+	// Cascade delete: Delete diagram share links
+	if err := s.shareLinkRepo.DeleteByProjectID(ctx, projectID); err != nil {
+		return err
+	}
+
+	// Cascade delete: Delete comments
+	if err := s.commentRepo.DeleteByProjectID(ctx, projectID); err != nil {
+		return err
+	}
+
+	// Cascade delete: Delete custom roles
+	if err := s.roleRepo.DeleteByProjectID(ctx, projectID); err != nil {
+		return err
+	}
+
+	// Cascade delete: Delete the backup schedule and stored archives
+	if err := s.scheduleRepo.DeleteByProjectID(ctx, projectID); err != nil {
+		return err
+	}
+	if err := s.deleteBackupArchives(ctx, projectID); err != nil {
+		return err
+	}
+
 	// Delete the project
 	return s.projectRepo.Delete(ctx, projectID)
 }
 
-// AddMember adds a member to the project
+// deleteBackupArchives removes the project's archives from storage, then
+// their records. A failed object delete keeps the records so the retried
+// purge finds the object again. Without storage configured only the records
+// are removed.
+func (s *ProjectService) deleteBackupArchives(ctx context.Context, projectID primitive.ObjectID) error {
+	if s.backupStorage != nil {
+		for offset := 0; ; offset += purgeArchiveBatch {
+			archives, _, err := s.archiveRepo.FindByProjectID(ctx, projectID, offset, purgeArchiveBatch)
+			if err != nil {
+				return err
+			}
+			for _, archive := range archives {
+				if err := s.backupStorage.Delete(ctx, archive.Key); err != nil {
+					return err
+				}
+			}
+			if len(archives) < purgeArchiveBatch {
+				break
+			}
+		}
+	}
+	return s.archiveRepo.DeleteByProjectID(ctx, projectID)
+}
+
+// AddMember adds a member to the project. A non-empty customRole grants the
+// project's custom role of that name in place of role and permissions.
 func (s *ProjectService) AddMember(
 	ctx context.Context,
 	projectID, userID, targetUserID primitive.ObjectID,
 	role string,
 	permissions []string,
+	customRole string,
 ) error {
 	// Check permission
 	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
 		return err
 	}
 
+	role, permissions, customRole, err := s.resolveMemberRole(ctx, projectID, role, permissions, customRole)
+	if err != nil {
+		return err
+	}
+
 	// Check if target user exists
-	_, err := s.userRepo.FindByID(ctx, targetUserID)
+	_, err = s.userRepo.FindByID(ctx, targetUserID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
 			return ErrMemberNotFound
@@ -246,10 +428,16 @@ func (s *ProjectService) AddMember(
 		ProjectID:   projectID,
 		UserID:      targetUserID,
 		Role:        role,
+		CustomRole:  customRole,
 		Permissions: permissions,
 	}
 
-	return s.memberRepo.Create(ctx, member)
+	if err := s.memberRepo.Create(ctx, member); err != nil {
+		return err
+	}
+
+	s.publish(event.MemberAdded, projectID, userID, targetUserID)
+	return nil
 }
 
 // GetMembers gets all members of a project with pagination
@@ -259,29 +447,59 @@ func (s *ProjectService) GetMembers(
 	offset, limit int,
 ) ([]*domain.ProjectMember, int64, error) {
 	// Check if user has access (any member can view members)
-	_, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, userID)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, 0, ErrProjectAccessDenied
-		}
-		return nil, 0, err
+	if _, err := s.authz.GetMember(ctx, projectID, userID); err != nil {
+		return nil, 0, concealNonMember(err, ErrProjectNotFound)
 	}
 
 	return s.memberRepo.FindByProjectID(ctx, projectID, offset, limit)
 }
 
-// UpdateMember updates member permissions
+// GetMembersAfter gets project members ordered by ID, starting after the given cursor ID
+func (s *ProjectService) GetMembersAfter(
+	ctx context.Context,
+	projectID, userID, afterID primitive.ObjectID,
+	limit int,
+) ([]*domain.ProjectMember, error) {
+	// Check if user has access (any member can view members)
+	if _, err := s.authz.GetMember(ctx, projectID, userID); err != nil {
+		return nil, concealNonMember(err, ErrProjectNotFound)
+	}
+
+	return s.memberRepo.FindByProjectIDAfter(ctx, projectID, afterID, limit)
+}
+
+// GetMemberUserIDs returns the user IDs of the project's members. Only members
+// who can manage the project, and so invite others, may list them this way.
+func (s *ProjectService) GetMemberUserIDs(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+) ([]primitive.ObjectID, error) {
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, err
+	}
+
+	return s.memberRepo.FindUserIDsByProjectID(ctx, projectID)
+}
+
+// UpdateMember updates member permissions. A non-empty customRole grants the
+// project's custom role of that name in place of role and permissions.
 func (s *ProjectService) UpdateMember(
 	ctx context.Context,
 	projectID, userID, targetUserID primitive.ObjectID,
 	role string,
 	permissions []string,
+	customRole string,
 ) error {
 	// Check permission
 	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
 		return err
 	}
 
+	role, permissions, customRole, err := s.resolveMemberRole(ctx, projectID, role, permissions, customRole)
+	if err != nil {
+		return err
+	}
+
 	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, targetUserID)
 	if err != nil {
 		if errors.Is(err, mongo.ErrNoDocuments) {
@@ -291,9 +509,15 @@ func (s *ProjectService) UpdateMember(
 	}
 
 	member.Role = role
+	member.CustomRole = customRole
 	member.Permissions = permissions
 
-	return s.memberRepo.Update(ctx, member)
+	if err := s.memberRepo.Update(ctx, member); err != nil {
+		return err
+	}
+
+	s.publish(event.MemberUpdated, projectID, userID, targetUserID)
+	return nil
 }
 
 // RemoveMember removes a member from the project
@@ -315,7 +539,7 @@ func (s *ProjectService) RemoveMember(
 	ownerCount := 0
 	targetIsOwner := false
 	for _, m := range members {
-		if m.Role == "owner" {
+		if m.IsOwner() {
 			ownerCount++
 			if m.UserID == targetUserID {
 				targetIsOwner = true
@@ -327,46 +551,39 @@ func (s *ProjectService) RemoveMember(
 		return ErrCannotRemoveOwner
 	}
 
-	return s.memberRepo.Delete(ctx, projectID, targetUserID)
+	if err := s.memberRepo.Delete(ctx, projectID, targetUserID); err != nil {
+		return err
+	}
+
+	s.publish(event.MemberRemoved, projectID, userID, targetUserID)
+	return nil
 }
 
-// HasPermission checks if user has a specific permission
+// HasPermission checks if user has a specific permission.
+// Owners are granted every permission even if their stored list is out of sync.
+// Non-members get ErrProjectNotFound so project IDs cannot be probed.
 func (s *ProjectService) HasPermission(
 	ctx context.Context,
 	projectID, userID primitive.ObjectID,
-	permission string,
+	permission domain.Permission,
 ) error {
-	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, userID)
-	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return ErrProjectAccessDenied
-		}
-		return err
-	}
-
-	for _, p := range member.Permissions {
-		if p == permission {
-			return nil
-		}
+	if _, err := s.authz.Authorize(ctx, projectID, userID, permission); err != nil {
+		return concealNonMember(err, ErrProjectNotFound)
 	}
-
-	return ErrInsufficientPermission
+	return nil
 }
 
-// GetUserPermissions gets user's permissions for a project
+// GetUserPermissions gets user's role and effective permissions for a project
 func (s *ProjectService) GetUserPermissions(
 	ctx context.Context,
 	projectID, userID primitive.ObjectID,
-) ([]string, error) {
-	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, userID)
+) (string, []string, error) {
+	member, err := s.authz.GetMember(ctx, projectID, userID)
 	if err != nil {
-		if errors.Is(err, mongo.ErrNoDocuments) {
-			return nil, ErrProjectAccessDenied
-		}
-		return nil, err
+		return "", nil, concealNonMember(err, ErrProjectNotFound)
 	}
 
-	return member.Permissions, nil
+	return member.Role, member.EffectivePermissions(), nil
 }
 
 // CreateInvitation creates a new project invitation
@@ -391,16 +608,10 @@ func (s *ProjectService) CreateInvitation(
 		return nil, err
 	}
 
-	// Check for existing pending invitation for this user in this project
-	// and mark it as expired to prevent duplicates but keep history
-	if !inviteeUserID.IsZero() {
-		existingInv, err := s.invitationRepo.FindByProjectAndInvitee(ctx, projectID, inviteeUserID)
-		if err == nil && existingInv != nil {
-			// Found existing, mark as expired
-			existingInv.Status = domain.InvitationStatusExpired
-			_ = s.invitationRepo.Update(ctx, existingInv)
-		}
+	if err := s.checkInvitee(ctx, projectID, inviterUserID, inviteeUserID); err != nil {
+		return nil, err
 	}
+	previous := s.pendingInvitation(ctx, projectID, inviteeUserID)
 
 	invitation := &domain.Invitation{
 		ID:                primitive.NewObjectID(),
@@ -418,10 +629,134 @@ func (s *ProjectService) CreateInvitation(
 	if err != nil {
 		return nil, err
 	}
+	s.expireInvitation(ctx, previous)
 
+	s.publish(event.InvitationCreated, projectID, inviterUserID, result.ID)
 	return result, nil
 }
 
+// CreateInvitations creates several invitations with one permission check
+// and one insert. errs holds each draft's rejection, such as
+// ErrMemberAlreadyExists; invitations holds the created invitation of every
+// draft that was not rejected. The returned error fails the whole batch.
+func (s *ProjectService) CreateInvitations(
+	ctx context.Context,
+	projectID, inviterUserID primitive.ObjectID,
+	drafts []domain.InvitationDraft,
+) (invitations []*domain.Invitation, errs []error, err error) {
+	// Check permission
+	if err := s.HasPermission(ctx, projectID, inviterUserID, domain.PermissionManageProject); err != nil {
+		return nil, nil, err
+	}
+
+	// Fetch project to get current KeyEpoch
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil, ErrProjectNotFound
+		}
+		return nil, nil, err
+	}
+
+	invitations = make([]*domain.Invitation, len(drafts))
+	errs = make([]error, len(drafts))
+	seen := make(map[primitive.ObjectID]struct{}, len(drafts))
+	accepted := make([]*domain.Invitation, 0, len(drafts))
+	for i, draft := range drafts {
+		if _, dup := seen[draft.InviteeUserID]; dup {
+			errs[i] = ErrDuplicateInvitee
+			continue
+		}
+		seen[draft.InviteeUserID] = struct{}{}
+
+		if err := s.checkInvitee(ctx, projectID, inviterUserID, draft.InviteeUserID); err != nil {
+			if !errors.Is(err, ErrCannotInviteSelf) && !errors.Is(err, ErrMemberAlreadyExists) {
+				return nil, nil, err
+			}
+			errs[i] = err
+			continue
+		}
+
+		invitations[i] = &domain.Invitation{
+			ID:                primitive.NewObjectID(),
+			ProjectID:         projectID,
+			InviterUserID:     inviterUserID,
+			InviteeUserID:     draft.InviteeUserID,
+			Role:              draft.Role,
+			Permissions:       draft.Permissions,
+			EncryptedKeyrings: draft.EncryptedKeyrings,
+			KeyEpoch:          project.KeyEpoch,
+			Status:            domain.InvitationStatusPending,
+		}
+		accepted = append(accepted, invitations[i])
+	}
+
+	if len(accepted) == 0 {
+		return invitations, errs, nil
+	}
+
+	previous := make([]*domain.Invitation, 0, len(accepted))
+	for _, invitation := range accepted {
+		previous = append(previous, s.pendingInvitation(ctx, projectID, invitation.InviteeUserID))
+	}
+	if err := s.invitationRepo.CreateMany(ctx, accepted); err != nil {
+		return nil, nil, err
+	}
+	for _, invitation := range previous {
+		s.expireInvitation(ctx, invitation)
+	}
+
+	for _, invitation := range accepted {
+		s.publish(event.InvitationCreated, projectID, inviterUserID, invitation.ID)
+	}
+	return invitations, errs, nil
+}
+
+// checkInvitee rejects inviting yourself, since the inviter is a member
+// already, and inviting anyone who is a member
+func (s *ProjectService) checkInvitee(ctx context.Context, projectID, inviterUserID, inviteeUserID primitive.ObjectID) error {
+	if inviteeUserID == inviterUserID {
+		return ErrCannotInviteSelf
+	}
+	if inviteeUserID.IsZero() {
+		return nil
+	}
+
+	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, inviteeUserID)
+	if err != nil && !errors.Is(err, mongo.ErrNoDocuments) {
+		return err
+	}
+	if member != nil {
+		return ErrMemberAlreadyExists
+	}
+	return nil
+}
+
+// pendingInvitation returns the invitee's pending invitation to the project,
+// or nil if there is none. A new invitation replaces it once inserted.
+func (s *ProjectService) pendingInvitation(ctx context.Context, projectID, inviteeUserID primitive.ObjectID) *domain.Invitation {
+	if inviteeUserID.IsZero() {
+		return nil
+	}
+	existingInv, err := s.invitationRepo.FindByProjectAndInvitee(ctx, projectID, inviteeUserID)
+	if err != nil {
+		return nil
+	}
+	return existingInv
+}
+
+// expireInvitation marks a replaced invitation expired, so the new one does
+// not duplicate it but history is kept. It is only called after the
+// replacement was inserted, so a failed insert leaves the invitee's pending
+// invitation usable.
+func (s *ProjectService) expireInvitation(ctx context.Context, invitation *domain.Invitation) {
+	if invitation == nil || invitation.Status != domain.InvitationStatusPending {
+		return
+	}
+	invitation.Status = domain.InvitationStatusExpired
+	_ = s.invitationRepo.Update(ctx, invitation)
+}
+
 // GetInvitation fetches an invitation by ID
 func (s *ProjectService) GetInvitation(
 	ctx context.Context,
@@ -461,6 +796,9 @@ func (s *ProjectService) AcceptInvitation(
 	if invitation.Status == domain.InvitationStatusExpired {
 		return primitive.NilObjectID, ErrInvitationExpired
 	}
+	if invitation.Status == domain.InvitationStatusDeclined {
+		return primitive.NilObjectID, ErrInvitationDeclined
+	}
 
 	// Fetch project to check KeyEpoch
 	project, err := s.projectRepo.FindByID(ctx, invitation.ProjectID)
@@ -501,12 +839,14 @@ func (s *ProjectService) AcceptInvitation(
 		if err := s.memberRepo.Update(ctx, existingMember); err != nil {
 			return primitive.NilObjectID, err
 		}
+		s.publish(event.MemberUpdated, invitation.ProjectID, acceptingUserID, acceptingUserID)
 
 		// Mark invitation as accepted
 		invitation.Status = domain.InvitationStatusAccepted
 		if err := s.invitationRepo.Update(ctx, invitation); err != nil {
 			return invitation.ProjectID, nil
 		}
+		s.publish(event.InvitationAccepted, invitation.ProjectID, acceptingUserID, invitation.ID)
 
 		return invitation.ProjectID, nil
 	}
@@ -529,6 +869,7 @@ func (s *ProjectService) AcceptInvitation(
 	if err := s.memberRepo.Create(ctx, member); err != nil {
 		return primitive.NilObjectID, err
 	}
+	s.publish(event.MemberAdded, invitation.ProjectID, acceptingUserID, acceptingUserID)
 
 	// Mark invitation as accepted
 	invitation.Status = domain.InvitationStatusAccepted
@@ -536,6 +877,7 @@ func (s *ProjectService) AcceptInvitation(
 		// Non-critical: member was already created
 		return invitation.ProjectID, nil
 	}
+	s.publish(event.InvitationAccepted, invitation.ProjectID, acceptingUserID, invitation.ID)
 
 	// Cleanup: Mark any other pending invitations for this user in this project as expired
 	// This handles cases where multiple invitations might have been created (rare but possible safely)
@@ -551,6 +893,83 @@ func (s *ProjectService) AcceptInvitation(
 	return invitation.ProjectID, nil
 }
 
+// ResendInvitation replaces a pending or expired invitation with a fresh one
+// at the project's current key epoch, keeping its invitee, role and
+// permissions. The old invitation is marked expired once the new one exists.
+func (s *ProjectService) ResendInvitation(
+	ctx context.Context,
+	projectID, userID, invitationID primitive.ObjectID,
+	encryptedKeyrings string,
+) (*domain.Invitation, error) {
+	// Check permission
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, err
+	}
+
+	old, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if old == nil || old.ProjectID != projectID {
+		return nil, ErrInvitationNotFound
+	}
+
+	switch old.Status {
+	case domain.InvitationStatusAccepted:
+		return nil, ErrInvitationAlreadyAccepted
+	case domain.InvitationStatusDeclined:
+		return nil, ErrInvitationDeclined
+	}
+
+	invitation, err := s.CreateInvitation(ctx, projectID, userID, old.InviteeUserID, old.Role, old.Permissions, encryptedKeyrings)
+	if err != nil {
+		return nil, err
+	}
+	// CreateInvitation expires the invitee's pending invitation itself; one
+	// without an invitee is only expired here
+	s.expireInvitation(ctx, old)
+	return invitation, nil
+}
+
+// DeclineInvitation lets the invitee turn down a pending invitation. The
+// invitation stays in the project's list with its declined status.
+func (s *ProjectService) DeclineInvitation(
+	ctx context.Context,
+	invitationID, userID primitive.ObjectID,
+) error {
+	invitation, err := s.invitationRepo.FindByID(ctx, invitationID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrInvitationNotFound
+		}
+		return err
+	}
+	// Other users must not learn the invitation exists
+	if invitation == nil || invitation.InviteeUserID != userID {
+		return ErrInvitationNotFound
+	}
+
+	switch invitation.Status {
+	case domain.InvitationStatusAccepted:
+		return ErrInvitationAlreadyAccepted
+	case domain.InvitationStatusExpired:
+		return ErrInvitationExpired
+	case domain.InvitationStatusDeclined:
+		return ErrInvitationDeclined
+	}
+
+	invitation.Status = domain.InvitationStatusDeclined
+	if err := s.invitationRepo.Update(ctx, invitation); err != nil {
+		return err
+	}
+
+	s.publish(event.InvitationDeclined, invitation.ProjectID, userID, invitationID)
+	return nil
+}
+
 // GetProjectInvitations lists invitations for a project
 func (s *ProjectService) GetProjectInvitations(
 	ctx context.Context,
@@ -574,6 +993,14 @@ func (s *ProjectService) GetUserInvitations(
 	return s.invitationRepo.FindByInviteeID(ctx, userID, offset, limit)
 }
 
+// CountPendingInvitations counts the invitations awaiting the user's response
+func (s *ProjectService) CountPendingInvitations(
+	ctx context.Context,
+	userID primitive.ObjectID,
+) (int64, error) {
+	return s.invitationRepo.CountByInviteeAndStatus(ctx, userID, domain.InvitationStatusPending)
+}
+
 // RevokeInvitation revokes a pending invitation
 func (s *ProjectService) RevokeInvitation(
 	ctx context.Context,
@@ -601,7 +1028,65 @@ func (s *ProjectService) RevokeInvitation(
 		return ErrInvitationAlreadyAccepted
 	}
 
-	return s.invitationRepo.Delete(ctx, invitationID)
+	if err := s.invitationRepo.Delete(ctx, invitationID); err != nil {
+		return err
+	}
+
+	s.publish(event.InvitationRevoked, projectID, userID, invitationID)
+	return nil
+}
+
+// RekeyMember provisions a fresh keyring for a single member at the current
+// project epoch without rotating the epoch for everyone. Any keyring the
+// member already holds for that epoch is replaced.
+func (s *ProjectService) RekeyMember(
+	ctx context.Context,
+	projectID, userID, targetUserID primitive.ObjectID,
+	keyring domain.ProjectMemberKeyring,
+) error {
+	// Check permission
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return err
+	}
+
+	project, err := s.projectRepo.FindByID(ctx, projectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrProjectNotFound
+		}
+		return err
+	}
+	if project == nil {
+		return ErrProjectNotFound
+	}
+
+	member, err := s.memberRepo.FindByProjectAndUser(ctx, projectID, targetUserID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrMemberNotFound
+		}
+		return err
+	}
+	if member == nil {
+		return ErrMemberNotFound
+	}
+
+	keyring.Epoch = project.KeyEpoch
+
+	keyrings := make([]domain.ProjectMemberKeyring, 0, len(member.Keyrings)+1)
+	for _, k := range member.Keyrings {
+		if k.Epoch != project.KeyEpoch {
+			keyrings = append(keyrings, k)
+		}
+	}
+	member.Keyrings = append(keyrings, keyring)
+
+	if err := s.memberRepo.Update(ctx, member); err != nil {
+		return err
+	}
+
+	s.publish(event.MemberUpdated, projectID, userID, targetUserID)
+	return nil
 }
 
 // RotateProjectKeys updates the project key epoch and adds new keyrings for members
@@ -617,12 +1102,10 @@ func (s *ProjectService) RotateProjectKeys(
 	}
 
 	// 1. Update Project Epoch
-	project, err := s.projectRepo.FindByID(ctx, projectID)
-	if err != nil {
-		return err
-	}
-	project.KeyEpoch = newKeyEpoch
-	if err := s.projectRepo.Update(ctx, project); err != nil {
+	if err := s.projectRepo.UpdateKeyEpoch(ctx, projectID, newKeyEpoch); err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrProjectNotFound
+		}
 		return err
 	}
 
@@ -631,6 +1114,7 @@ func (s *ProjectService) RotateProjectKeys(
 	// as long as the project epoch is updated first.
 	// If a member update fails, they just won't be able to access new data until re-invited/fixed,
 	// but security is maintained because the project epoch has changed.
+	rekeyed := 0
 	for _, update := range updates {
 		memberUserID, err := primitive.ObjectIDFromHex(update.UserID)
 		if err != nil {
@@ -654,9 +1138,82 @@ func (s *ProjectService) RotateProjectKeys(
 		if err := s.memberRepo.Update(ctx, member); err != nil {
 			logger.Error().Err(err).Str("project_id", projectID.Hex()).Str("user_id", update.UserID).Msg("Failed to update member keyring")
 		} else {
+			rekeyed++
 			logger.Info().Str("project_id", projectID.Hex()).Str("user_id", update.UserID).Msg("Updated member keyring")
 		}
 	}
 
+	// 3. Record the rotation. The epoch has already changed, so a failed
+	// audit write is logged rather than failing the rotation.
+	rotation := &domain.KeyRotation{
+		ProjectID:   projectID,
+		Epoch:       newKeyEpoch,
+		RotatedBy:   userID,
+		RotatedAt:   time.Now().UTC(),
+		MemberCount: rekeyed,
+	}
+	if err := s.keyRotationRepo.Create(ctx, rotation); err != nil {
+		logger.Error().Err(err).Str("project_id", projectID.Hex()).Msg("Failed to record key rotation")
+	}
+
+	s.publish(event.KeyRotated, projectID, userID, projectID)
 	return nil
 }
+
+// GetKeyRotations lists the project's key rotation history, newest first
+func (s *ProjectService) GetKeyRotations(
+	ctx context.Context,
+	projectID, userID primitive.ObjectID,
+	offset, limit int,
+) ([]*domain.KeyRotation, int64, error) {
+	// Check permission
+	if err := s.HasPermission(ctx, projectID, userID, domain.PermissionManageProject); err != nil {
+		return nil, 0, err
+	}
+
+	return s.keyRotationRepo.FindByProjectID(ctx, projectID, offset, limit)
+}
+
+const (
+	// projectTouchInterval throttles activity writes so a burst of edits, such
+	// as an autosaving diagram, touches its project once
+	projectTouchInterval = time.Minute
+	// projectTouchTimeout bounds the write HandleEvent makes per event
+	projectTouchTimeout = 5 * time.Second
+)
+
+// HandleEvent bumps a project's UpdatedAt when its content changes, so the
+// recent order of the project list follows activity. It is registered on the
+// event bus at startup; a dropped event only leaves the order a little stale.
+func (s *ProjectService) HandleEvent(e event.Event) {
+	switch e.Type {
+	case event.DiagramCreated, event.DiagramUpdated, event.DiagramDeleted,
+		event.NodeUpdated, event.NodeDeleted,
+		event.NoteCreated, event.NoteUpdated, event.NoteDeleted,
+		event.VaultItemCreated, event.VaultItemUpdated, event.VaultItemDeleted,
+		event.BackupRestored:
+	default:
+		return
+	}
+	if !s.touches.allow(e.ProjectID, time.Now()) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), projectTouchTimeout)
+	defer cancel()
+
+	if err := s.projectRepo.Touch(ctx, e.ProjectID); err != nil {
+		logger.Error().Err(err).Str("project_id", e.ProjectID.Hex()).Msg("Failed to record project activity")
+	}
+}
+
+// publish announces a project-level change; resourceID is the project itself,
+// the affected user for member events or the invitation for invitation events
+func (s *ProjectService) publish(eventType event.Type, projectID, actorID, resourceID primitive.ObjectID) {
+	s.events.Publish(event.Event{
+		Type:       eventType,
+		ProjectID:  projectID,
+		ActorID:    actorID,
+		ResourceID: resourceID,
+	})
+}
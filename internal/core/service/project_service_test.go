@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// invitationTestEnv is a project with an owner and one other member
+type invitationTestEnv struct {
+	svc         *ProjectService
+	invitations *fakeInvitationRepo
+	members     *fakeMemberRepo
+	events      *fakePublisher
+	projectID   primitive.ObjectID
+	ownerID     primitive.ObjectID
+	memberID    primitive.ObjectID
+}
+
+func newInvitationTestEnv() *invitationTestEnv {
+	env := &invitationTestEnv{
+		invitations: &fakeInvitationRepo{},
+		events:      &fakePublisher{},
+		projectID:   primitive.NewObjectID(),
+		ownerID:     primitive.NewObjectID(),
+		memberID:    primitive.NewObjectID(),
+	}
+	env.members = &fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: env.projectID, UserID: env.ownerID, Role: domain.RoleOwner},
+		{ProjectID: env.projectID, UserID: env.memberID, Role: "viewer"},
+	}}
+	projects := &fakeProjectRepo{projects: []*domain.Project{
+		{ID: env.projectID, Name: "infra", KeyEpoch: "epoch-1"},
+	}}
+	env.svc = NewProjectService(projects, env.members, nil, nil, nil, env.invitations, nil, nil, nil, nil, nil, nil, nil,
+		NewAuthorizationService(env.members), nil, env.events, 0, 0)
+	return env
+}
+
+func (env *invitationTestEnv) invite(inviteeID primitive.ObjectID) (*domain.Invitation, error) {
+	return env.svc.CreateInvitation(context.Background(), env.projectID, env.ownerID, inviteeID, "viewer", nil, "keyrings")
+}
+
+func TestCreateInvitationChecksInvitee(t *testing.T) {
+	env := newInvitationTestEnv()
+
+	tests := []struct {
+		name    string
+		invitee primitive.ObjectID
+		wantErr error
+	}{
+		{name: "self", invitee: env.ownerID, wantErr: ErrCannotInviteSelf},
+		{name: "existing member", invitee: env.memberID, wantErr: ErrMemberAlreadyExists},
+		{name: "non-member", invitee: primitive.NewObjectID()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invitation, err := env.invite(tt.invitee)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+			if invitation.Status != domain.InvitationStatusPending || invitation.KeyEpoch != "epoch-1" {
+				t.Errorf("invitation = %+v, want pending at epoch-1", invitation)
+			}
+			if env.invitations.status(invitation.ID) != domain.InvitationStatusPending {
+				t.Error("invitation was not stored")
+			}
+		})
+	}
+}
+
+func TestDeclineInvitation(t *testing.T) {
+	env := newInvitationTestEnv()
+	inviteeID := primitive.NewObjectID()
+	invitation, err := env.invite(inviteeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	if err := env.svc.DeclineInvitation(ctx, invitation.ID, env.memberID); !errors.Is(err, ErrInvitationNotFound) {
+		t.Errorf("decline by another user: err = %v, want %v", err, ErrInvitationNotFound)
+	}
+
+	if err := env.svc.DeclineInvitation(ctx, invitation.ID, inviteeID); err != nil {
+		t.Fatalf("decline: %v", err)
+	}
+	if got := env.invitations.status(invitation.ID); got != domain.InvitationStatusDeclined {
+		t.Errorf("status = %q, want %q", got, domain.InvitationStatusDeclined)
+	}
+
+	if err := env.svc.DeclineInvitation(ctx, invitation.ID, inviteeID); !errors.Is(err, ErrInvitationDeclined) {
+		t.Errorf("second decline: err = %v, want %v", err, ErrInvitationDeclined)
+	}
+	if _, err := env.svc.AcceptInvitation(ctx, invitation.ID, inviteeID, nil, "", ""); !errors.Is(err, ErrInvitationDeclined) {
+		t.Errorf("accept after decline: err = %v, want %v", err, ErrInvitationDeclined)
+	}
+	if member, _ := env.members.FindByProjectAndUser(ctx, env.projectID, inviteeID); member != nil {
+		t.Error("declined invitee became a member")
+	}
+}
+
+func TestResendInvitationAfterKeyRotation(t *testing.T) {
+	env := newInvitationTestEnv()
+	inviteeID := primitive.NewObjectID()
+	old, err := env.invite(inviteeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	project, _ := env.svc.projectRepo.FindByID(ctx, env.projectID)
+	project.KeyEpoch = "epoch-2"
+	if _, err := env.svc.AcceptInvitation(ctx, old.ID, inviteeID, nil, "", ""); !errors.Is(err, ErrInvitationExpired) {
+		t.Fatalf("accept at the old epoch: err = %v, want %v", err, ErrInvitationExpired)
+	}
+
+	resent, err := env.svc.ResendInvitation(ctx, env.projectID, env.ownerID, old.ID, "rotated-keyrings")
+	if err != nil {
+		t.Fatalf("resend: %v", err)
+	}
+	if resent.ID == old.ID || resent.KeyEpoch != "epoch-2" || resent.EncryptedKeyrings != "rotated-keyrings" {
+		t.Errorf("resent = %+v, want a new invitation at epoch-2", resent)
+	}
+
+	projectID, err := env.svc.AcceptInvitation(ctx, resent.ID, inviteeID, nil, "", "")
+	if err != nil {
+		t.Fatalf("accept the resent invitation: %v", err)
+	}
+	if projectID != env.projectID {
+		t.Errorf("accepted project = %s, want %s", projectID.Hex(), env.projectID.Hex())
+	}
+	if got := env.invitations.status(old.ID); got != domain.InvitationStatusExpired {
+		t.Errorf("old status = %q, want %q", got, domain.InvitationStatusExpired)
+	}
+}
+
+func TestResendInvitationKeepsOldWhenCreateFails(t *testing.T) {
+	env := newInvitationTestEnv()
+	old, err := env.invite(primitive.NewObjectID())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	env.invitations.createErr = errors.New("insert failed")
+	if _, err := env.svc.ResendInvitation(context.Background(), env.projectID, env.ownerID, old.ID, "keyrings"); !errors.Is(err, env.invitations.createErr) {
+		t.Fatalf("err = %v, want %v", err, env.invitations.createErr)
+	}
+	if got := env.invitations.status(old.ID); got != domain.InvitationStatusPending {
+		t.Errorf("old status = %q, want it still %q", got, domain.InvitationStatusPending)
+	}
+}
+
+func TestCreateInvitationsMixedBatch(t *testing.T) {
+	env := newInvitationTestEnv()
+	newID := primitive.NewObjectID()
+	drafts := []domain.InvitationDraft{
+		{InviteeUserID: newID, Role: "viewer"},
+		{InviteeUserID: newID, Role: "editor"},
+		{InviteeUserID: env.ownerID, Role: "viewer"},
+		{InviteeUserID: env.memberID, Role: "viewer"},
+	}
+	wantErrs := []error{nil, ErrDuplicateInvitee, ErrCannotInviteSelf, ErrMemberAlreadyExists}
+
+	invitations, errs, err := env.svc.CreateInvitations(context.Background(), env.projectID, env.ownerID, drafts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, want := range wantErrs {
+		if !errors.Is(errs[i], want) {
+			t.Errorf("draft %d: err = %v, want %v", i, errs[i], want)
+		}
+		if (invitations[i] != nil) != (want == nil) {
+			t.Errorf("draft %d: invitation = %v, want one only without an error", i, invitations[i])
+		}
+	}
+	if len(env.invitations.invitations) != 1 || env.invitations.invitations[0].InviteeUserID != newID {
+		t.Errorf("stored %d invitations, want only the new invitee's", len(env.invitations.invitations))
+	}
+}
+
+func TestCreateInvitationPublishesEvent(t *testing.T) {
+	env := newInvitationTestEnv()
+
+	if _, err := env.invite(env.ownerID); !errors.Is(err, ErrCannotInviteSelf) {
+		t.Fatalf("err = %v, want %v", err, ErrCannotInviteSelf)
+	}
+	if len(env.events.events) != 0 {
+		t.Fatalf("a failed invitation published %+v", env.events.events)
+	}
+
+	invitation, err := env.invite(primitive.NewObjectID())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := event.Event{
+		Type:       event.InvitationCreated,
+		ProjectID:  env.projectID,
+		ActorID:    env.ownerID,
+		ResourceID: invitation.ID,
+	}
+	if len(env.events.events) != 1 || env.events.events[0] != want {
+		t.Errorf("events = %+v, want only %+v", env.events.events, want)
+	}
+}
+
+func TestRolePresetsUseValidPermissions(t *testing.T) {
+	for role, permissions := range RolePresets {
+		for _, permission := range permissions {
+			if !domain.IsValidPermission(string(permission)) {
+				t.Errorf("role %s grants unknown permission %q", role, permission)
+			}
+		}
+	}
+	if len(RolePresets[domain.RoleOwner]) != len(domain.AllPermissions) {
+		t.Errorf("owner preset has %d permissions, want all %d", len(RolePresets[domain.RoleOwner]), len(domain.AllPermissions))
+	}
+}
+
+func TestHasPermissionOwnerWithEmptyPermissions(t *testing.T) {
+	env := newInvitationTestEnv()
+	ctx := context.Background()
+
+	for _, permission := range domain.AllPermissions {
+		if err := env.svc.HasPermission(ctx, env.projectID, env.ownerID, permission); err != nil {
+			t.Errorf("owner %s: %v", permission, err)
+		}
+	}
+	if err := env.svc.HasPermission(ctx, env.projectID, env.memberID, domain.PermissionManageProject); !errors.Is(err, ErrInsufficientPermission) {
+		t.Errorf("viewer manage_project: err = %v, want %v", err, ErrInsufficientPermission)
+	}
+
+	role, permissions, err := env.svc.GetUserPermissions(ctx, env.projectID, env.ownerID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if role != domain.RoleOwner || len(permissions) != len(domain.AllPermissions) {
+		t.Errorf("owner has role %q and permissions %v, want owner with all", role, permissions)
+	}
+}
+
+// listingProjectRepo records the query of the last project listing
+type listingProjectRepo struct {
+	fakeProjectRepo
+	query domain.ProjectListQuery
+}
+
+func (r *listingProjectRepo) FindByUserID(_ context.Context, _ primitive.ObjectID, query domain.ProjectListQuery, _, _ int) ([]*domain.MemberProject, int64, error) {
+	r.query = query
+	return []*domain.MemberProject{}, 0, nil
+}
+
+func TestGetUserProjectsNormalizesTag(t *testing.T) {
+	repo := &listingProjectRepo{}
+	svc := NewProjectService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, &fakePublisher{}, 0, 0)
+
+	if _, _, err := svc.GetUserProjects(context.Background(), primitive.NewObjectID(), domain.ProjectListQuery{Tag: "  Prod "}, 0, 10); err != nil {
+		t.Fatal(err)
+	}
+	if repo.query.Tag != "prod" {
+		t.Errorf("tag = %q, want prod", repo.query.Tag)
+	}
+}
+
+func TestSetFavoriteIsPerMember(t *testing.T) {
+	env := newInvitationTestEnv()
+	ctx := context.Background()
+
+	if err := env.svc.SetFavorite(ctx, env.projectID, env.memberID, true); err != nil {
+		t.Fatal(err)
+	}
+	for _, m := range env.members.members {
+		if want := m.UserID == env.memberID; m.Favorite != want {
+			t.Errorf("member %s favorite = %v, want %v", m.UserID.Hex(), m.Favorite, want)
+		}
+	}
+
+	if err := env.svc.SetFavorite(ctx, env.projectID, env.memberID, false); err != nil {
+		t.Fatal(err)
+	}
+	if m, _ := env.members.FindByProjectAndUser(ctx, env.projectID, env.memberID); m.Favorite {
+		t.Error("project is still a favorite after unfavoriting")
+	}
+
+	if err := env.svc.SetFavorite(ctx, env.projectID, primitive.NewObjectID(), true); !errors.Is(err, ErrProjectNotFound) {
+		t.Errorf("non-member: err = %v, want %v", err, ErrProjectNotFound)
+	}
+}
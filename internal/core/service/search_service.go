@@ -0,0 +1,295 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// SearchService searches names across every project a user belongs to
+type SearchService struct {
+	memberRepo  port.ProjectMemberRepository
+	projectRepo port.ProjectRepository
+	diagramRepo port.DiagramRepository
+	noteRepo    port.NoteRepository
+	authz       *AuthorizationService
+}
+
+func NewSearchService(
+	memberRepo port.ProjectMemberRepository,
+	projectRepo port.ProjectRepository,
+	diagramRepo port.DiagramRepository,
+	noteRepo port.NoteRepository,
+	authz *AuthorizationService,
+) *SearchService {
+	return &SearchService{
+		memberRepo:  memberRepo,
+		projectRepo: projectRepo,
+		diagramRepo: diagramRepo,
+		noteRepo:    noteRepo,
+		authz:       authz,
+	}
+}
+
+// Search finds the projects, diagrams and notes whose name contains query in
+// every project the user is a member of. Diagrams and notes are only searched
+// in projects where the member may view them. Each kind of result is capped
+// at limit; the matches are grouped by project, ordered by project name.
+func (s *SearchService) Search(ctx context.Context, userID primitive.ObjectID, query string, limit int) (*dto.SearchResponse, error) {
+	response := &dto.SearchResponse{
+		Query:  query,
+		Groups: []dto.SearchProjectGroup{},
+	}
+
+	// The caller's memberships are the whole search scope
+	members, err := s.memberRepo.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(members) == 0 {
+		return response, nil
+	}
+
+	memberIDs := make([]primitive.ObjectID, 0, len(members))
+	for _, member := range members {
+		memberIDs = append(memberIDs, member.ProjectID)
+	}
+	projects, err := s.projectRepo.FindActiveByIDs(ctx, memberIDs)
+	if err != nil {
+		return nil, err
+	}
+	active := make(map[primitive.ObjectID]*domain.Project, len(projects))
+	for _, project := range projects {
+		active[project.ID] = project
+	}
+
+	var diagramScope, noteScope []primitive.ObjectID
+	for _, member := range members {
+		if _, ok := active[member.ProjectID]; !ok {
+			continue
+		}
+		if s.authz.Can(member, domain.PermissionViewDiagram) {
+			diagramScope = append(diagramScope, member.ProjectID)
+		}
+		if s.authz.Can(member, domain.PermissionViewNote) {
+			noteScope = append(noteScope, member.ProjectID)
+		}
+	}
+
+	// One more than the limit tells whether the results were cut off
+	matchedProjects := matchProjectNames(projects, query)
+	diagrams, err := s.diagramRepo.SearchByName(ctx, diagramScope, query, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	notes, err := s.noteRepo.SearchByName(ctx, noteScope, query, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	if len(matchedProjects) > limit || len(diagrams) > limit || len(notes) > limit {
+		response.Truncated = true
+	}
+	matchedProjects = matchedProjects[:min(len(matchedProjects), limit)]
+	diagrams = diagrams[:min(len(diagrams), limit)]
+	notes = notes[:min(len(notes), limit)]
+
+	diagramsByID, err := loadAncestors(ctx, diagrams,
+		func(d *domain.Diagram) primitive.ObjectID { return d.ID },
+		func(d *domain.Diagram) *primitive.ObjectID { return d.ParentDiagramID },
+		s.diagramRepo.FindByIDs, maxDiagramTreeDepth)
+	if err != nil {
+		return nil, err
+	}
+	notesByID, err := loadAncestors(ctx, notes,
+		func(n *domain.Note) primitive.ObjectID { return n.ID },
+		func(n *domain.Note) *primitive.ObjectID { return n.ParentID },
+		s.noteRepo.FindByIDs, maxNoteBreadcrumbDepth)
+	if err != nil {
+		return nil, err
+	}
+
+	groups := make(map[primitive.ObjectID]*dto.SearchProjectGroup)
+	groupFor := func(projectID primitive.ObjectID) *dto.SearchProjectGroup {
+		group, ok := groups[projectID]
+		if !ok {
+			project := active[projectID]
+			group = &dto.SearchProjectGroup{
+				ProjectID:   project.ID.Hex(),
+				ProjectName: project.Name,
+				Results:     []dto.SearchResult{},
+			}
+			groups[projectID] = group
+		}
+		return group
+	}
+
+	for _, project := range matchedProjects {
+		group := groupFor(project.ID)
+		path := []dto.BreadcrumbItem{projectCrumb(project)}
+		path[0].Active = true
+		group.Results = append(group.Results, dto.SearchResult{
+			Type: "project",
+			ID:   project.ID.Hex(),
+			Name: project.Name,
+			Path: path,
+		})
+	}
+	for _, diagram := range diagrams {
+		group := groupFor(diagram.ProjectID)
+		chain := ancestorChain(diagram, diagramsByID,
+			func(d *domain.Diagram) primitive.ObjectID { return d.ID },
+			func(d *domain.Diagram) *primitive.ObjectID { return d.ParentDiagramID },
+			func(d *domain.Diagram) bool { return d.ProjectID == diagram.ProjectID },
+			maxDiagramTreeDepth)
+		path := []dto.BreadcrumbItem{projectCrumb(active[diagram.ProjectID])}
+		for _, d := range chain {
+			path = append(path, dto.BreadcrumbItem{Type: "diagram", ID: d.ID.Hex(), Label: d.DiagramName})
+		}
+		path[len(path)-1].Active = true
+		group.Results = append(group.Results, dto.SearchResult{
+			Type: "diagram",
+			ID:   diagram.ID.Hex(),
+			Name: diagram.DiagramName,
+			Path: path,
+		})
+	}
+	for _, note := range notes {
+		group := groupFor(note.ProjectID)
+		chain := ancestorChain(note, notesByID,
+			func(n *domain.Note) primitive.ObjectID { return n.ID },
+			func(n *domain.Note) *primitive.ObjectID { return n.ParentID },
+			func(n *domain.Note) bool { return n.ProjectID == note.ProjectID },
+			maxNoteBreadcrumbDepth)
+		path := []dto.BreadcrumbItem{projectCrumb(active[note.ProjectID])}
+		for _, n := range chain {
+			path = append(path, dto.BreadcrumbItem{Type: "note", ID: n.ID.Hex(), Label: n.FileName})
+		}
+		path[len(path)-1].Active = true
+		group.Results = append(group.Results, dto.SearchResult{
+			Type: "note",
+			ID:   note.ID.Hex(),
+			Name: note.FileName,
+			Path: path,
+		})
+	}
+
+	for _, group := range groups {
+		response.Groups = append(response.Groups, *group)
+	}
+	sort.Slice(response.Groups, func(i, j int) bool {
+		a, b := strings.ToLower(response.Groups[i].ProjectName), strings.ToLower(response.Groups[j].ProjectName)
+		if a != b {
+			return a < b
+		}
+		return response.Groups[i].ProjectID < response.Groups[j].ProjectID
+	})
+
+	return response, nil
+}
+
+// matchProjectNames returns the projects whose name contains query, ignoring
+// case, sorted by name. The caller's projects are already loaded, so they are
+// matched in memory.
+func matchProjectNames(projects []*domain.Project, query string) []*domain.Project {
+	query = strings.ToLower(query)
+	var matched []*domain.Project
+	for _, project := range projects {
+		if strings.Contains(strings.ToLower(project.Name), query) {
+			matched = append(matched, project)
+		}
+	}
+	sort.Slice(matched, func(i, j int) bool {
+		return strings.ToLower(matched[i].Name) < strings.ToLower(matched[j].Name)
+	})
+	return matched
+}
+
+func projectCrumb(project *domain.Project) dto.BreadcrumbItem {
+	return dto.BreadcrumbItem{
+		Type:  "project",
+		ID:    project.ID.Hex(),
+		Label: project.Name,
+	}
+}
+
+// loadAncestors fetches the parents of items one level at a time, up to
+// maxDepth levels, so a page of results costs one query per level rather
+// than one per result. The returned map holds the items and every ancestor
+// found.
+func loadAncestors[T any](
+	ctx context.Context,
+	items []*T,
+	idOf func(*T) primitive.ObjectID,
+	parentOf func(*T) *primitive.ObjectID,
+	find func(context.Context, []primitive.ObjectID) ([]*T, error),
+	maxDepth int,
+) (map[primitive.ObjectID]*T, error) {
+	known := make(map[primitive.ObjectID]*T, len(items))
+	for _, item := range items {
+		known[idOf(item)] = item
+	}
+
+	level := items
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		var missing []primitive.ObjectID
+		queued := make(map[primitive.ObjectID]bool)
+		for _, item := range level {
+			parentID := parentOf(item)
+			if parentID == nil || queued[*parentID] {
+				continue
+			}
+			if _, ok := known[*parentID]; ok {
+				continue
+			}
+			queued[*parentID] = true
+			missing = append(missing, *parentID)
+		}
+		if len(missing) == 0 {
+			break
+		}
+
+		parents, err := find(ctx, missing)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			known[idOf(parent)] = parent
+		}
+		level = parents
+	}
+	return known, nil
+}
+
+// ancestorChain walks from item up through its parents in known and returns
+// the chain root first. A parent that is missing, in another project or part
+// of a cycle ends the chain there.
+func ancestorChain[T any](
+	item *T,
+	known map[primitive.ObjectID]*T,
+	idOf func(*T) primitive.ObjectID,
+	parentOf func(*T) *primitive.ObjectID,
+	sameProject func(*T) bool,
+	maxDepth int,
+) []*T {
+	chain := []*T{item}
+	visited := map[primitive.ObjectID]bool{idOf(item): true}
+	for current := item; parentOf(current) != nil && len(chain) < maxDepth; {
+		parent, ok := known[*parentOf(current)]
+		if !ok || !sameProject(parent) || visited[idOf(parent)] {
+			break
+		}
+		visited[idOf(parent)] = true
+		chain = append(chain, parent)
+		current = parent
+	}
+
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
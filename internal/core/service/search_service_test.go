@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestSearchScopesResultsToMemberProjects(t *testing.T) {
+	userID := primitive.NewObjectID()
+	otherUserID := primitive.NewObjectID()
+	deletedAt := time.Now()
+
+	owned := &domain.Project{ID: primitive.NewObjectID(), Name: "Alpha"}
+	viewerOnly := &domain.Project{ID: primitive.NewObjectID(), Name: "Beta"}
+	trashed := &domain.Project{ID: primitive.NewObjectID(), Name: "Gamma", DeletedAt: &deletedAt}
+	foreign := &domain.Project{ID: primitive.NewObjectID(), Name: "Delta"}
+
+	members := &fakeMemberRepo{members: []*domain.ProjectMember{
+		{ProjectID: owned.ID, UserID: userID, Role: domain.RoleOwner},
+		{ProjectID: viewerOnly.ID, UserID: userID, Role: domain.RoleCustom,
+			Permissions: []string{string(domain.PermissionViewDiagram)}},
+		{ProjectID: trashed.ID, UserID: userID, Role: domain.RoleOwner},
+		{ProjectID: foreign.ID, UserID: otherUserID, Role: domain.RoleOwner},
+	}}
+	projects := &fakeProjectRepo{projects: []*domain.Project{owned, viewerOnly, trashed, foreign}}
+
+	parent := &domain.Diagram{ID: primitive.NewObjectID(), ProjectID: owned.ID, DiagramName: "Datacenter"}
+	diagrams := &fakeDiagramRepo{diagrams: []*domain.Diagram{
+		parent,
+		{ID: primitive.NewObjectID(), ProjectID: owned.ID, ParentDiagramID: &parent.ID, DiagramName: "Network core"},
+		{ID: primitive.NewObjectID(), ProjectID: viewerOnly.ID, DiagramName: "network edge"},
+		{ID: primitive.NewObjectID(), ProjectID: trashed.ID, DiagramName: "Network trash"},
+		{ID: primitive.NewObjectID(), ProjectID: foreign.ID, DiagramName: "Network foreign"},
+	}}
+	notes := &fakeNoteRepo{notes: []*domain.Note{
+		{ID: primitive.NewObjectID(), ProjectID: owned.ID, FileName: "Network runbook"},
+		{ID: primitive.NewObjectID(), ProjectID: viewerOnly.ID, FileName: "Network secrets"},
+		{ID: primitive.NewObjectID(), ProjectID: foreign.ID, FileName: "Network foreign"},
+	}}
+
+	svc := NewSearchService(members, projects, diagrams, notes, &AuthorizationService{})
+	response, err := svc.Search(context.Background(), userID, "network", 10)
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+
+	if !slices.Equal(diagrams.scope, []primitive.ObjectID{owned.ID, viewerOnly.ID}) {
+		t.Errorf("diagram scope = %v, want only the active member projects", diagrams.scope)
+	}
+	if !slices.Equal(notes.scope, []primitive.ObjectID{owned.ID}) {
+		t.Errorf("note scope = %v, want only projects with view_note", notes.scope)
+	}
+
+	got := map[string][]string{}
+	for _, group := range response.Groups {
+		for _, result := range group.Results {
+			got[group.ProjectName] = append(got[group.ProjectName], result.Type+":"+result.Name)
+		}
+	}
+	want := map[string][]string{
+		"Alpha": {"diagram:Network core", "note:Network runbook"},
+		"Beta":  {"diagram:network edge"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("groups = %v, want %v", got, want)
+	}
+	for name, results := range want {
+		if !slices.Equal(got[name], results) {
+			t.Errorf("group %s = %v, want %v", name, got[name], results)
+		}
+	}
+	if response.Groups[0].ProjectName != "Alpha" || response.Truncated {
+		t.Errorf("groups not ordered by name or wrongly truncated: %+v", response)
+	}
+
+	path := response.Groups[0].Results[0].Path
+	if len(path) != 3 || path[1].Label != "Datacenter" || !path[2].Active {
+		t.Errorf("diagram path = %+v, want project > Datacenter > Network core", path)
+	}
+}
@@ -0,0 +1,233 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/domain"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const (
+	// ShareLinkPrefix marks share link tokens so they are recognisable in URLs
+	// and logs.
+	ShareLinkPrefix = "inf_shr_"
+
+	// DefaultShareLinkExpiry applies when a link is created without an
+	// explicit lifetime.
+	DefaultShareLinkExpiry = 7 * 24 * time.Hour
+
+	// MaxShareLinkExpiry bounds how long a link may stay valid.
+	MaxShareLinkExpiry = 90 * 24 * time.Hour
+
+	// MaxShareLinksPerDiagram bounds how many links one diagram can hold.
+	MaxShareLinksPerDiagram = 20
+
+	// shareLinkDisplayLength is how much of the token is kept in the clear so
+	// members can recognise it in listings.
+	shareLinkDisplayLength = len(ShareLinkPrefix) + 4
+)
+
+var (
+	ErrShareLinkNotFound         = errors.New("share link not found")
+	ErrShareLinkPasswordRequired = errors.New("share link password required")
+	ErrShareLinkInvalidPassword  = errors.New("share link password is incorrect")
+	ErrTooManyShareLinks         = errors.New("too many share links")
+)
+
+// ShareLinkService manages read-only links that expose a single diagram to
+// people outside the project
+type ShareLinkService struct {
+	shareLinkRepo port.ShareLinkRepository
+	diagramRepo   port.DiagramRepository
+	projectRepo   port.ProjectRepository
+	authz         *AuthorizationService
+	argon2Params  *Argon2Params
+}
+
+func NewShareLinkService(
+	shareLinkRepo port.ShareLinkRepository,
+	diagramRepo port.DiagramRepository,
+	projectRepo port.ProjectRepository,
+	authz *AuthorizationService,
+	argon2Params *Argon2Params,
+) *ShareLinkService {
+	return &ShareLinkService{
+		shareLinkRepo: shareLinkRepo,
+		diagramRepo:   diagramRepo,
+		projectRepo:   projectRepo,
+		authz:         authz,
+		argon2Params:  argon2Params,
+	}
+}
+
+// CreateShareLink issues a link to the diagram and returns it together with
+// the plaintext token, which is never stored and cannot be retrieved again.
+// Sharing outside the project needs edit access to the diagram.
+func (s *ShareLinkService) CreateShareLink(
+	ctx context.Context,
+	projectID, diagramID, userID primitive.ObjectID,
+	password string,
+	expiresIn time.Duration,
+) (*domain.ShareLink, string, error) {
+	if err := s.checkDiagram(ctx, projectID, diagramID, userID, domain.PermissionEditDiagram); err != nil {
+		return nil, "", err
+	}
+
+	existing, err := s.shareLinkRepo.FindByDiagramID(ctx, diagramID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(existing) >= MaxShareLinksPerDiagram {
+		return nil, "", ErrTooManyShareLinks
+	}
+
+	if expiresIn <= 0 {
+		expiresIn = DefaultShareLinkExpiry
+	}
+	if expiresIn > MaxShareLinkExpiry {
+		expiresIn = MaxShareLinkExpiry
+	}
+
+	var passwordHash string
+	if password != "" {
+		passwordHash, err = HashPassword(password, s.argon2Params)
+		if err != nil {
+			return nil, "", err
+		}
+	}
+
+	plaintext, err := generateToken(ShareLinkPrefix)
+	if err != nil {
+		return nil, "", err
+	}
+
+	link := &domain.ShareLink{
+		ProjectID:    projectID,
+		DiagramID:    diagramID,
+		TokenHash:    hashToken(plaintext),
+		Prefix:       plaintext[:shareLinkDisplayLength],
+		PasswordHash: passwordHash,
+		ExpiresAt:    time.Now().UTC().Add(expiresIn),
+		CreatedBy:    userID,
+	}
+	if err := s.shareLinkRepo.Create(ctx, link); err != nil {
+		return nil, "", err
+	}
+
+	return link, plaintext, nil
+}
+
+// ListShareLinks returns the diagram's links without their tokens
+func (s *ShareLinkService) ListShareLinks(
+	ctx context.Context,
+	projectID, diagramID, userID primitive.ObjectID,
+) ([]*domain.ShareLink, error) {
+	if err := s.checkDiagram(ctx, projectID, diagramID, userID, domain.PermissionViewDiagram); err != nil {
+		return nil, err
+	}
+
+	return s.shareLinkRepo.FindByDiagramID(ctx, diagramID)
+}
+
+// RevokeShareLink deletes one of the diagram's links
+func (s *ShareLinkService) RevokeShareLink(
+	ctx context.Context,
+	projectID, diagramID, linkID, userID primitive.ObjectID,
+) error {
+	if err := s.checkDiagram(ctx, projectID, diagramID, userID, domain.PermissionEditDiagram); err != nil {
+		return err
+	}
+
+	deleted, err := s.shareLinkRepo.Delete(ctx, linkID, diagramID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrShareLinkNotFound
+	}
+	return nil
+}
+
+// OpenShareLink resolves a plaintext token to the shared diagram. Unknown
+// and expired links, and links whose diagram or project is gone, all return
+// ErrShareLinkNotFound so callers learn nothing about which tokens existed.
+func (s *ShareLinkService) OpenShareLink(ctx context.Context, plaintext, password string) (*domain.Diagram, error) {
+	if !strings.HasPrefix(plaintext, ShareLinkPrefix) {
+		return nil, ErrShareLinkNotFound
+	}
+
+	link, err := s.shareLinkRepo.FindByHash(ctx, hashToken(plaintext))
+	if err != nil {
+		return nil, err
+	}
+	if link == nil || link.IsExpired(time.Now().UTC()) {
+		return nil, ErrShareLinkNotFound
+	}
+
+	if link.HasPassword() {
+		if password == "" {
+			return nil, ErrShareLinkPasswordRequired
+		}
+		match, err := ComparePassword(password, link.PasswordHash)
+		if err != nil && !errors.Is(err, ErrPasswordTooLong) {
+			return nil, err
+		}
+		if !match {
+			return nil, ErrShareLinkInvalidPassword
+		}
+	}
+
+	// Projects in the recycle bin are hidden from FindByID
+	project, err := s.projectRepo.FindByID(ctx, link.ProjectID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+	if project == nil {
+		return nil, ErrShareLinkNotFound
+	}
+
+	diagram, err := s.diagramRepo.FindByID(ctx, link.DiagramID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, ErrShareLinkNotFound
+		}
+		return nil, err
+	}
+	if diagram == nil || diagram.ProjectID != link.ProjectID {
+		return nil, ErrShareLinkNotFound
+	}
+
+	return diagram, nil
+}
+
+// checkDiagram verifies the diagram belongs to the project and the user holds
+// permission on it. Non-members get ErrDiagramNotFound.
+func (s *ShareLinkService) checkDiagram(
+	ctx context.Context,
+	projectID, diagramID, userID primitive.ObjectID,
+	permission domain.Permission,
+) error {
+	if _, err := s.authz.Authorize(ctx, projectID, userID, permission); err != nil {
+		return concealNonMember(err, ErrDiagramNotFound)
+	}
+
+	diagram, err := s.diagramRepo.FindByID(ctx, diagramID)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return ErrDiagramNotFound
+		}
+		return err
+	}
+	if diagram == nil || diagram.ProjectID != projectID {
+		return ErrDiagramNotFound
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// maxThrottleEntries bounds a throttle's map; past it entries older than the
+// interval are swept
+const maxThrottleEntries = 10000
+
+// idThrottle lets an action through at most once per interval for each ID
+type idThrottle struct {
+	interval time.Duration
+	mu       sync.Mutex
+	last     map[primitive.ObjectID]time.Time
+}
+
+// newIDThrottle returns a throttle for interval; an interval of zero lets
+// everything through
+func newIDThrottle(interval time.Duration) *idThrottle {
+	return &idThrottle{
+		interval: interval,
+		last:     make(map[primitive.ObjectID]time.Time),
+	}
+}
+
+// allow reports whether id is due, recording now as its last action if so
+func (t *idThrottle) allow(id primitive.ObjectID, now time.Time) bool {
+	if t.interval <= 0 {
+		return true
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if last, ok := t.last[id]; ok && now.Sub(last) < t.interval {
+		return false
+	}
+	if len(t.last) >= maxThrottleEntries {
+		for key, last := range t.last {
+			if now.Sub(last) >= t.interval {
+				delete(t.last, key)
+			}
+		}
+	}
+	t.last[id] = now
+	return true
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestIDThrottleInterval(t *testing.T) {
+	throttle := newIDThrottle(time.Second)
+	id, other := primitive.NewObjectID(), primitive.NewObjectID()
+	start := time.Now()
+
+	steps := []struct {
+		name string
+		id   primitive.ObjectID
+		at   time.Duration
+		want bool
+	}{
+		{name: "first action", id: id, at: 0, want: true},
+		{name: "inside the interval", id: id, at: 999 * time.Millisecond, want: false},
+		{name: "another ID is independent", id: other, at: 999 * time.Millisecond, want: true},
+		{name: "exactly one interval later", id: id, at: time.Second, want: true},
+		{name: "rejections do not extend the window", id: id, at: 1500 * time.Millisecond, want: false},
+		{name: "one interval after the last allowed", id: id, at: 2 * time.Second, want: true},
+	}
+	for _, step := range steps {
+		if got := throttle.allow(step.id, start.Add(step.at)); got != step.want {
+			t.Errorf("%s: allow = %v, want %v", step.name, got, step.want)
+		}
+	}
+}
+
+func TestIDThrottleZeroIntervalAllowsEverything(t *testing.T) {
+	throttle := newIDThrottle(0)
+	id, now := primitive.NewObjectID(), time.Now()
+	for i := 0; i < 3; i++ {
+		if !throttle.allow(id, now) {
+			t.Fatalf("call %d was throttled", i)
+		}
+	}
+	if len(throttle.last) != 0 {
+		t.Errorf("a disabled throttle tracked %d IDs", len(throttle.last))
+	}
+}
+
+func TestIDThrottleEvictsExpiredEntries(t *testing.T) {
+	throttle := newIDThrottle(time.Minute)
+	start := time.Now()
+
+	// Fill the map: half the entries expire before the sweep, half do not
+	recent := make([]primitive.ObjectID, 0, maxThrottleEntries/2)
+	for i := 0; i < maxThrottleEntries; i++ {
+		id := primitive.NewObjectID()
+		at := start
+		if i%2 == 1 {
+			at = start.Add(30 * time.Second)
+			recent = append(recent, id)
+		}
+		throttle.allow(id, at)
+	}
+
+	now := start.Add(time.Minute)
+	if !throttle.allow(primitive.NewObjectID(), now) {
+		t.Fatal("a new ID was throttled")
+	}
+	if want := len(recent) + 1; len(throttle.last) != want {
+		t.Errorf("tracked %d IDs after the sweep, want %d", len(throttle.last), want)
+	}
+	// Entries still inside their interval survive the sweep
+	for _, id := range recent[:10] {
+		if throttle.allow(id, now) {
+			t.Fatalf("%s was let through inside its interval", id.Hex())
+		}
+	}
+}
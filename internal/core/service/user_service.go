@@ -18,21 +18,38 @@ var (
 	ErrSamePassword          = errors.New("new password must be different")
 )
 
+// dashboardRoles are the member roles counted on the user dashboard
+var dashboardRoles = []string{
+	domain.RoleOwner,
+	domain.RoleEditor,
+	domain.RoleViewer,
+	domain.RoleCustom,
+}
+
 type UserService struct {
 	userRepo         port.UserRepository
 	refreshTokenRepo port.RefreshTokenRepository
+	memberRepo       port.ProjectMemberRepository
+	invitationRepo   port.InvitationRepository
 	argon2Params     *Argon2Params
+	passwordPolicy   PasswordPolicy
 }
 
 func NewUserService(
 	userRepo port.UserRepository,
 	refreshTokenRepo port.RefreshTokenRepository,
+	memberRepo port.ProjectMemberRepository,
+	invitationRepo port.InvitationRepository,
 	argon2Params *Argon2Params,
+	passwordPolicy PasswordPolicy,
 ) *UserService {
 	return &UserService{
 		userRepo:         userRepo,
 		refreshTokenRepo: refreshTokenRepo,
+		memberRepo:       memberRepo,
+		invitationRepo:   invitationRepo,
 		argon2Params:     argon2Params,
+		passwordPolicy:   passwordPolicy,
 	}
 }
 
@@ -48,6 +65,36 @@ func (s *UserService) GetUserProfile(ctx context.Context, userID primitive.Objec
 	return user, nil
 }
 
+// GetUserDashboard returns the user's profile with project counts per role
+// and the number of pending invitations. Everything is counted in the
+// database; no memberships are loaded.
+func (s *UserService) GetUserDashboard(ctx context.Context, userID primitive.ObjectID) (*domain.UserDashboard, error) {
+	user, err := s.GetUserProfile(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byRole := make(map[string]int64, len(dashboardRoles))
+	for _, role := range dashboardRoles {
+		count, err := s.memberRepo.CountByUserAndRole(ctx, userID, role)
+		if err != nil {
+			return nil, err
+		}
+		byRole[role] = count
+	}
+
+	pending, err := s.invitationRepo.CountByInviteeAndStatus(ctx, userID, domain.InvitationStatusPending)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.UserDashboard{
+		User:               user,
+		ProjectsByRole:     byRole,
+		PendingInvitations: pending,
+	}, nil
+}
+
 // UpdateProfile updates user profile information
 func (s *UserService) UpdateProfile(ctx context.Context, userID primitive.ObjectID, req dto.UpdateProfileRequest) (*domain.User, error) {
 	// Get current user
@@ -116,6 +163,10 @@ func (s *UserService) ChangePassword(ctx context.Context, userID primitive.Objec
 		return ErrCurrentPasswordWrong
 	}
 
+	if err := s.passwordPolicy.Check("new_password", newPassword); err != nil {
+		return err
+	}
+
 	// Check if new password is different
 	sameAsOld, err := ComparePassword(newPassword, user.Password)
 	if err != nil {
@@ -155,5 +206,5 @@ func (s *UserService) SearchUsers(ctx context.Context, query string, limit int)
 	if limit <= 0 || limit > 20 {
 		limit = 10
 	}
-	return s.userRepo.SearchUsers(ctx, query, limit)
+	return s.userRepo.SearchUsers(ctx, query, nil, limit)
 }
@@ -0,0 +1,277 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/handler"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/middleware"
+	"github.com/dhanuprys/infrantery-backend-go/internal/version"
+	"github.com/gin-gonic/gin"
+)
+
+// routeHandlers carries the handlers and route middleware every API version
+// registers its routes with
+type routeHandlers struct {
+	authMiddleware        *middleware.AuthMiddleware
+	idempotencyMiddleware *middleware.IdempotencyMiddleware
+	maintenance           *middleware.Maintenance
+	adminHandler          *handler.AdminHandler
+	authHandler           *handler.AuthHandler
+	profileHandler        *handler.ProfileHandler
+	accessTokenHandler    *handler.AccessTokenHandler
+	projectHandler        *handler.ProjectHandler
+	projectRoleHandler    *handler.ProjectRoleHandler
+	invitationHandler     *handler.InvitationHandler
+	noteHandler           *handler.NoteHandler
+	diagramHandler        *handler.DiagramHandler
+	shareLinkHandler      *handler.ShareLinkHandler
+	commentHandler        *handler.CommentHandler
+	nodeHandler           *handler.NodeHandler
+	nodeVaultHandler      *handler.NodeVaultHandler
+	breadcrumbHandler     *handler.BreadcrumbHandler
+	activityHandler       *handler.ActivityHandler
+	exportHandler         *handler.ExportHandler
+	presenceHandler       *handler.PresenceHandler
+	eventStreamHandler    *handler.EventStreamHandler
+	backupHandler         *handler.BackupHandler
+	metaHandler           *handler.MetaHandler
+	searchHandler         *handler.SearchHandler
+}
+
+// apiVersion is one mounted version of the API. Handlers always write the v1
+// response shape; shaper turns it into the version's own shape.
+type apiVersion struct {
+	name     string
+	register func(s *Server, group *gin.RouterGroup, h *routeHandlers)
+	shaper   middleware.ResponseShaper
+}
+
+// apiVersions are mounted oldest first, v1 at API_BASE_PATH and the others
+// next to it. A breaking change goes into a new version, leaving existing
+// clients on the old one.
+var apiVersions = []apiVersion{
+	{name: "v1", register: (*Server).registerV1},
+	{name: "v2", register: (*Server).registerV2, shaper: middleware.ShapeV2Response},
+}
+
+// versionedRoutes expands route patterns relative to a version into the full
+// patterns under every mounted version, for middleware matched by route
+func (s *Server) versionedRoutes(routes ...string) []string {
+	full := make([]string, 0, len(routes)*len(apiVersions))
+	for _, v := range apiVersions {
+		for _, route := range routes {
+			full = append(full, s.versionPath(v.name)+route)
+		}
+	}
+	return full
+}
+
+// versionPath is the path prefix a version is mounted at. API_BASE_PATH is
+// the v1 prefix; other versions replace its trailing /v1 segment, or are
+// appended to it when it has none.
+func (s *Server) versionPath(name string) string {
+	if name == "v1" {
+		return s.cfg.APIBasePath
+	}
+	return strings.TrimSuffix(s.cfg.APIBasePath, "/v1") + "/" + name
+}
+
+// registerV2 registers the v2 routes. v2 serves the same routes and handlers
+// as v1 and only differs in its response shape so far.
+func (s *Server) registerV2(group *gin.RouterGroup, h *routeHandlers) {
+	s.registerV1(group, h)
+}
+
+// registerV1 registers the v1 routes
+func (s *Server) registerV1(group *gin.RouterGroup, h *routeHandlers) {
+	// Public routes
+	public := group.Group("")
+	{
+		public.POST("/auth/register", h.authHandler.Register)
+		public.POST("/auth/login", h.authHandler.Login)
+		public.POST("/auth/refresh", h.authHandler.RefreshToken)
+		public.POST("/auth/logout", h.authHandler.Logout)
+		public.GET("/share/:token", h.shareLinkHandler.OpenShareLink)
+
+		// Build info for correlating incidents with deploys
+		public.GET("/version", func(c *gin.Context) {
+			c.JSON(http.StatusOK, dto.NewAPIResponse(version.Get(), nil))
+		})
+
+		// Permission and role definitions for role pickers
+		public.GET("/meta/roles", h.metaHandler.GetRoles)
+	}
+
+	// Operator routes (require the admin token)
+	admin := group.Group("/admin")
+	admin.Use(middleware.RequireAdminToken(s.cfg.AdminToken))
+	{
+		admin.GET("/maintenance", h.adminHandler.GetMaintenance)
+		admin.POST("/maintenance", h.adminHandler.SetMaintenance)
+	}
+
+	// Realtime routes; browsers cannot set headers on a WebSocket
+	// handshake, so these also accept the token as a query parameter or
+	// subprotocol
+	sockets := group.Group("")
+	sockets.Use(h.authMiddleware.RequireSocketAuth())
+	{
+		sockets.GET("/projects/:project_id/diagrams/:diagram_id/ws", h.presenceHandler.DiagramPresence)
+	}
+
+	// Protected routes (require authentication)
+	protected := group.Group("")
+	protected.Use(h.authMiddleware.RequireAuth())
+
+	// Create endpoints replay the original response for a repeated Idempotency-Key
+	idempotent := h.idempotencyMiddleware.Handle()
+	{
+		// Profile routes
+		protected.GET("/profile", h.profileHandler.GetProfile)
+		protected.GET("/profile/dashboard", h.profileHandler.GetDashboard)
+		protected.PUT("/profile", h.profileHandler.UpdateProfile)
+
+		// Credential management needs a real session, never an access token
+		session := h.authMiddleware.RequireSession()
+		protected.PUT("/profile/password", session, h.profileHandler.ChangePassword)
+
+		// Personal access tokens
+		protected.POST("/profile/tokens", session, h.accessTokenHandler.CreateToken)
+		protected.GET("/profile/tokens", session, h.accessTokenHandler.ListTokens)
+		protected.DELETE("/profile/tokens/:token_id", session, h.accessTokenHandler.RevokeToken)
+
+		// Project routes
+		projects := protected.Group("/projects")
+		{
+			projects.POST("", idempotent, h.projectHandler.CreateProject)
+			projects.GET("", h.projectHandler.GetUserProjects)
+			projects.GET("/deleted", h.projectHandler.GetDeletedProjects)
+			projects.GET("/:project_id", h.projectHandler.GetProjectDetails)
+			projects.GET("/:project_id/permissions", h.projectHandler.GetPermissions)
+			projects.PUT("/:project_id", h.projectHandler.UpdateProject)
+			projects.DELETE("/:project_id", h.projectHandler.DeleteProject)
+
+			// Delayed deletion for projects with several owners
+			projects.POST("/:project_id/deletion-request", h.projectHandler.RequestProjectDeletion)
+			projects.DELETE("/:project_id/deletion-request", h.projectHandler.CancelProjectDeletion)
+			projects.POST("/:project_id/restore", h.projectHandler.RestoreProject)
+			projects.POST("/:project_id/archive", h.projectHandler.ArchiveProject)
+			projects.POST("/:project_id/unarchive", h.projectHandler.UnarchiveProject)
+			projects.POST("/:project_id/favorite", h.projectHandler.FavoriteProject)
+			projects.POST("/:project_id/unfavorite", h.projectHandler.UnfavoriteProject)
+
+			// Breadcrumbs
+			projects.GET("/:project_id/breadcrumbs", h.breadcrumbHandler.GetBreadcrumbs)
+
+			// Activity feed
+			projects.GET("/:project_id/activity", h.activityHandler.ListProjectActivity)
+
+			// Plain JSON export for client-side decryption
+			projects.GET("/:project_id/export", h.exportHandler.ExportProject)
+
+			// Change notifications (Server-Sent Events)
+			projects.GET("/:project_id/events", h.eventStreamHandler.StreamProjectEvents)
+
+			// Project member management
+			projects.POST("/:project_id/members", h.projectHandler.AddMember)
+			projects.GET("/:project_id/members", h.projectHandler.GetMembers)
+			projects.PUT("/:project_id/members/:user_id", h.projectHandler.UpdateMember)
+			projects.DELETE("/:project_id/members/:user_id", h.projectHandler.RemoveMember)
+			projects.POST("/:project_id/members/:user_id/rekey", h.projectHandler.RekeyMember)
+
+			// Custom role routes
+			projects.GET("/:project_id/roles", h.projectRoleHandler.ListRoles)
+			projects.POST("/:project_id/roles", idempotent, h.projectRoleHandler.CreateRole)
+			projects.PUT("/:project_id/roles/:role_id", h.projectRoleHandler.UpdateRole)
+			projects.DELETE("/:project_id/roles/:role_id", h.projectRoleHandler.DeleteRole)
+
+			// Key Rotation
+			projects.POST("/:project_id/keys/rotate", h.projectHandler.RotateProjectKeys)
+			projects.GET("/:project_id/key-rotations", h.projectHandler.GetKeyRotations)
+
+			// Invitation management (project-scoped)
+			projects.POST("/:project_id/invitations", idempotent, h.projectHandler.CreateInvitation)
+			projects.POST("/:project_id/invitations/bulk", idempotent, h.projectHandler.BulkCreateInvitations)
+			projects.GET("/:project_id/invitations", h.projectHandler.GetProjectInvitations)
+			projects.DELETE("/:project_id/invitations/:invitation_id", h.projectHandler.RevokeInvitation)
+			projects.POST("/:project_id/invitations/:invitation_id/resend", idempotent, h.projectHandler.ResendInvitation)
+
+			// Note management
+			projects.POST("/:project_id/notes", idempotent, h.noteHandler.CreateNote)
+			projects.GET("/:project_id/notes", h.noteHandler.ListNotes)
+			projects.GET("/:project_id/notes/count", h.noteHandler.CountNotes)
+			projects.GET("/:project_id/notes/tree", h.noteHandler.GetNoteTree)
+			projects.POST("/:project_id/notes/batch-delete", h.noteHandler.BatchDeleteNotes)
+			projects.GET("/:project_id/notes/:note_id", h.noteHandler.GetNote)
+			projects.PUT("/:project_id/notes/:note_id", h.noteHandler.UpdateNote)
+			projects.DELETE("/:project_id/notes/:note_id", h.noteHandler.DeleteNote)
+
+			// Diagram management
+			projects.POST("/:project_id/diagrams", idempotent, h.diagramHandler.CreateDiagram)
+			projects.GET("/:project_id/diagrams", h.diagramHandler.ListDiagrams)
+			projects.GET("/:project_id/diagrams/tree", h.diagramHandler.GetDiagramTree)
+			projects.POST("/:project_id/diagrams/batch-delete", h.diagramHandler.BatchDeleteDiagrams)
+			projects.GET("/:project_id/diagrams/:diagram_id", h.diagramHandler.GetDiagram)
+			projects.PUT("/:project_id/diagrams/:diagram_id", h.diagramHandler.UpdateDiagram)
+			projects.DELETE("/:project_id/diagrams/:diagram_id", h.diagramHandler.DeleteDiagram)
+			projects.POST("/:project_id/diagrams/:diagram_id/duplicate", idempotent, h.diagramHandler.DuplicateDiagram)
+			projects.POST("/:project_id/diagrams/:diagram_id/lock", h.diagramHandler.LockDiagram)
+			projects.POST("/:project_id/diagrams/:diagram_id/unlock", h.diagramHandler.UnlockDiagram)
+
+			// Diagram share links
+			projects.POST("/:project_id/diagrams/:diagram_id/share-links", idempotent, h.shareLinkHandler.CreateShareLink)
+			projects.GET("/:project_id/diagrams/:diagram_id/share-links", h.shareLinkHandler.ListShareLinks)
+			projects.DELETE("/:project_id/diagrams/:diagram_id/share-links/:link_id", h.shareLinkHandler.RevokeShareLink)
+
+			// Comments on the project, its diagrams and nodes
+			projects.GET("/:project_id/comments", h.commentHandler.ListComments)
+			projects.POST("/:project_id/comments", idempotent, h.commentHandler.CreateComment)
+			projects.PUT("/:project_id/comments/:comment_id", h.commentHandler.UpdateComment)
+			projects.DELETE("/:project_id/comments/:comment_id", h.commentHandler.DeleteComment)
+
+			// Node management
+			projects.GET("/:project_id/diagrams/:diagram_id/nodes/:node_id", h.nodeHandler.GetOrCreateNode)
+			projects.PUT("/:project_id/diagrams/:diagram_id/nodes/:node_id", h.nodeHandler.UpdateNode)
+			projects.DELETE("/:project_id/diagrams/:diagram_id/nodes/:node_id", h.nodeHandler.DeleteNode)
+
+			// Node Vault management
+			projects.GET("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault", h.nodeVaultHandler.ListVaultItems)
+			projects.GET("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault/count", h.nodeVaultHandler.CountVaultItems)
+			projects.GET("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault/:vault_id", h.nodeVaultHandler.GetVaultItem)
+			projects.POST("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault", idempotent, h.nodeVaultHandler.CreateVaultItem)
+			projects.POST("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault/bulk", idempotent, h.nodeVaultHandler.CreateVaultItems)
+			projects.PUT("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault/:vault_id", h.nodeVaultHandler.UpdateVaultItem)
+			projects.DELETE("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault/:vault_id", h.nodeVaultHandler.DeleteVaultItem)
+
+			// Backup & Restore
+			projects.POST("/:project_id/backup", h.backupHandler.CreateBackup)
+			projects.GET("/:project_id/backups", h.backupHandler.ListStoredBackups)
+			projects.GET("/:project_id/backup-schedule", h.backupHandler.GetBackupSchedule)
+			projects.PUT("/:project_id/backup-schedule", h.backupHandler.UpdateBackupSchedule)
+			projects.GET("/:project_id/backups/:backup_id/download", h.backupHandler.DownloadStoredBackup)
+			projects.POST("/restore", h.backupHandler.RestoreBackup)
+			projects.POST("/restore/inspect", h.backupHandler.InspectBackup)
+			projects.POST("/:project_id/backup/merge", h.backupHandler.MergeBackup)
+			projects.POST("/:project_id/clone", idempotent, h.backupHandler.CloneProject)
+			projects.POST("/:project_id/diagrams/:diagram_id/export", h.backupHandler.ExportDiagram)
+		}
+
+		// Invitation routes (non-project-scoped, for invitee)
+		protected.GET("/invitations", h.invitationHandler.ListUserInvitations)
+		protected.GET("/invitations/count", h.invitationHandler.CountPendingInvitations)
+		protected.GET("/invitations/:invitation_id", h.invitationHandler.GetInvitation)
+		protected.POST("/invitations/:invitation_id/accept", h.invitationHandler.AcceptInvitation)
+		protected.POST("/invitations/:invitation_id/decline", h.invitationHandler.DeclineInvitation)
+
+		// User search
+		protected.GET("/users/search", h.invitationHandler.SearchUsers)
+
+		// Name search across the caller's projects
+		protected.GET("/search", h.searchHandler.Search)
+
+		// Backup integrity check
+		protected.POST("/backups/verify", h.backupHandler.VerifyBackup)
+	}
+}
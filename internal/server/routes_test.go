@@ -0,0 +1,151 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/handler"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/middleware"
+	"github.com/dhanuprys/infrantery-backend-go/internal/config"
+	"github.com/gin-gonic/gin"
+)
+
+func TestVersionPath(t *testing.T) {
+	tests := []struct {
+		basePath string
+		v1, v2   string
+	}{
+		{basePath: "/api/v1", v1: "/api/v1", v2: "/api/v2"},
+		{basePath: "/infrantery/api/v1", v1: "/infrantery/api/v1", v2: "/infrantery/api/v2"},
+		{basePath: "/api", v1: "/api", v2: "/api/v2"},
+		{basePath: "", v1: "", v2: "/v2"},
+	}
+	for _, tt := range tests {
+		s := &Server{cfg: &config.Config{APIBasePath: tt.basePath}}
+		if got := s.versionPath("v1"); got != tt.v1 {
+			t.Errorf("versionPath(v1) with %q = %q, want %q", tt.basePath, got, tt.v1)
+		}
+		if got := s.versionPath("v2"); got != tt.v2 {
+			t.Errorf("versionPath(v2) with %q = %q, want %q", tt.basePath, got, tt.v2)
+		}
+	}
+}
+
+// newRoutesTestServer mounts every API version on a bare router. Handlers
+// needing a database are left nil; the routes exercised here do not use them.
+func newRoutesTestServer() *Server {
+	gin.SetMode(gin.TestMode)
+	s := &Server{
+		cfg:    &config.Config{APIBasePath: "/api/v1"},
+		router: gin.New(),
+	}
+	s.setupRoutes(&routeHandlers{
+		authMiddleware:        middleware.NewAuthMiddleware(nil, nil),
+		idempotencyMiddleware: middleware.NewIdempotencyMiddleware(nil, 0),
+		maintenance:           middleware.NewMaintenance(middleware.MaintenanceOff, 0),
+		metaHandler:           handler.NewMetaHandler(),
+	})
+	return s
+}
+
+func TestAPIVersionsMountTheSameRoutes(t *testing.T) {
+	s := newRoutesTestServer()
+
+	routes := make(map[string]map[string]bool)
+	for _, route := range s.router.Routes() {
+		for _, prefix := range []string{"/api/v1/", "/api/v2/"} {
+			if rest, ok := strings.CutPrefix(route.Path, prefix); ok {
+				key := route.Method + " " + rest
+				if routes[key] == nil {
+					routes[key] = make(map[string]bool)
+				}
+				routes[key][prefix] = true
+			}
+		}
+	}
+	if len(routes) == 0 {
+		t.Fatal("no versioned routes were mounted")
+	}
+	for route, prefixes := range routes {
+		if len(prefixes) != 2 {
+			t.Errorf("%s is only mounted under %v", route, prefixes)
+		}
+	}
+}
+
+func TestAPIVersionsShapeResponses(t *testing.T) {
+	s := newRoutesTestServer()
+
+	tests := []struct {
+		path           string
+		wantAPIVersion string
+	}{
+		{path: "/api/v1/meta/roles", wantAPIVersion: ""},
+		{path: "/api/v2/meta/roles", wantAPIVersion: "v2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if w.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+			}
+
+			var body dto.APIResponse[dto.RoleMetadataResponse]
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if len(body.Data.Permissions) == 0 {
+				t.Error("the handler's data did not survive shaping")
+			}
+			if body.Meta == nil {
+				t.Fatal("response has no meta")
+			}
+			if body.Meta.APIVersion != tt.wantAPIVersion {
+				t.Errorf("meta.api_version = %q, want %q", body.Meta.APIVersion, tt.wantAPIVersion)
+			}
+			// The logger middleware assigns the request ID; only v2 reports it
+			if gotID := body.Meta.RequestId != ""; gotID != (tt.wantAPIVersion == "v2") {
+				t.Errorf("meta.request_id = %q", body.Meta.RequestId)
+			}
+		})
+	}
+}
+
+func TestUnmatchedRequests(t *testing.T) {
+	s := newRoutesTestServer()
+
+	tests := []struct {
+		method, path string
+		wantStatus   int
+		wantCode     string
+	}{
+		{method: http.MethodPost, path: "/health", wantStatus: http.StatusMethodNotAllowed, wantCode: dto.ErrCodeMethodNotAllowed},
+		{method: http.MethodDelete, path: "/api/v1/meta/roles", wantStatus: http.StatusMethodNotAllowed, wantCode: dto.ErrCodeMethodNotAllowed},
+		{method: http.MethodGet, path: "/api/v1/no-such-page", wantStatus: http.StatusNotFound, wantCode: dto.ErrCodePageNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			s.router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body)
+			}
+
+			var body dto.APIResponse[any]
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Error == nil || body.Error.Code != tt.wantCode {
+				t.Errorf("error = %+v, want %s", body.Error, tt.wantCode)
+			}
+			if tt.wantStatus == http.StatusMethodNotAllowed && w.Header().Get("Allow") == "" {
+				t.Error("405 response has no Allow header")
+			}
+		})
+	}
+}
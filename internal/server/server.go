@@ -2,7 +2,9 @@ package server
 
 import (
 	"context"
+	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/Lyearn/mgod"
@@ -10,9 +12,15 @@ import (
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/dto"
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/handler"
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/middleware"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/notifier"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/realtime"
 	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/repository"
+	"github.com/dhanuprys/infrantery-backend-go/internal/adapter/storage"
 	"github.com/dhanuprys/infrantery-backend-go/internal/config"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/event"
+	"github.com/dhanuprys/infrantery-backend-go/internal/core/port"
 	"github.com/dhanuprys/infrantery-backend-go/internal/core/service"
+	"github.com/dhanuprys/infrantery-backend-go/pkg/compression"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/logger"
 	"github.com/dhanuprys/infrantery-backend-go/pkg/validation"
 	"github.com/gin-contrib/cors"
@@ -22,17 +30,35 @@ import (
 )
 
 type Server struct {
-	cfg         *config.Config
-	mongoClient *mongo.Client
-	router      *gin.Engine
+	cfg             *config.Config
+	mongoClient     *mongo.Client
+	router          *gin.Engine
+	backupScheduler *service.BackupScheduler
+	purgeScheduler  *service.ProjectDeletionScheduler
+	eventBus        *event.Bus
 }
 
 func NewServer(cfg *config.Config) (*Server, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
 	// Setup MongoDB connection
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
-	client, err := mongo.Connect(ctx, options.Client().ApplyURI(cfg.MongoDBURI))
+	// Retry reads and single-document writes once across a failover or
+	// network blip; the driver never retries writes it cannot make safe. Set
+	// before the URI so retryWrites=false in MONGODB_URI still wins.
+	clientOpts := options.Client().
+		SetRetryWrites(true).
+		SetRetryReads(true).
+		ApplyURI(cfg.MongoDBURI)
+	if logger.DebugEnabled() {
+		clientOpts.SetMonitor(repository.NewQueryMonitor())
+	}
+
+	client, err := mongo.Connect(ctx, clientOpts)
 	if err != nil {
 		return nil, err
 	}
@@ -50,6 +76,16 @@ func NewServer(cfg *config.Config) (*Server, error) {
 	mgod.SetDefaultConnection(db)
 
 	router := gin.New()
+
+	// Keep small multipart parts in memory and spool larger ones to disk;
+	// restore uploads are capped separately by the backup handler
+	router.MaxMultipartMemory = 8 << 20
+
+	// Only honour X-Forwarded-For from these hops, so clients cannot spoof
+	// the IP seen by logs and rate limits
+	if err := router.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
 	server := &Server{
 		cfg:         cfg,
 		mongoClient: client,
@@ -110,6 +146,56 @@ func (s *Server) setupDependencies() error {
 		return err
 	}
 
+	keyRotationRepo, err := repository.NewKeyRotationRepository("key_rotations")
+	if err != nil {
+		return err
+	}
+
+	shareLinkRepo, err := repository.NewShareLinkRepository("share_links")
+	if err != nil {
+		return err
+	}
+
+	commentRepo, err := repository.NewCommentRepository("comments")
+	if err != nil {
+		return err
+	}
+
+	projectRoleRepo, err := repository.NewProjectRoleRepository("project_roles")
+	if err != nil {
+		return err
+	}
+
+	backupArchiveRepo, err := repository.NewBackupArchiveRepository("backup_archives")
+	if err != nil {
+		return err
+	}
+
+	backupScheduleRepo, err := repository.NewBackupScheduleRepository("backup_schedules")
+	if err != nil {
+		return err
+	}
+
+	accessTokenRepo, err := repository.NewPersonalAccessTokenRepository("personal_access_tokens")
+	if err != nil {
+		return err
+	}
+
+	idempotencyRepo, err := repository.NewIdempotencyRepository("idempotency_keys")
+	if err != nil {
+		return err
+	}
+
+	backupStorage, err := s.newBackupStorage()
+	if err != nil {
+		return err
+	}
+
+	notifications, err := s.newNotifier()
+	if err != nil {
+		return err
+	}
+
 	// Initialize services
 	jwtService := service.NewJWTService(
 		s.cfg.JWTSecret,
@@ -125,19 +211,43 @@ func (s *Server) setupDependencies() error {
 		KeyLength:   s.cfg.Argon2KeyLength,
 	}
 
+	passwordPolicy := service.PasswordPolicy{
+		MinLength:     s.cfg.PasswordMinLength,
+		MaxLength:     s.cfg.PasswordMaxLength,
+		RequireUpper:  s.cfg.PasswordRequireUpper,
+		RequireLower:  s.cfg.PasswordRequireLower,
+		RequireDigit:  s.cfg.PasswordRequireDigit,
+		RequireSymbol: s.cfg.PasswordRequireSymbol,
+		RejectCommon:  s.cfg.PasswordRejectCommon,
+	}
+
 	authService := service.NewAuthService(
 		userRepo,
 		refreshTokenRepo,
 		jwtService,
 		argon2Params,
+		passwordPolicy,
 	)
 
 	userService := service.NewUserService(
 		userRepo,
 		refreshTokenRepo,
+		projectMemberRepo,
+		invitationRepo,
 		argon2Params,
+		passwordPolicy,
 	)
 
+	accessTokenService := service.NewAccessTokenService(accessTokenRepo)
+
+	authzService := service.NewAuthorizationService(projectMemberRepo)
+
+	// Services publish domain changes here; the event stream subscribes per
+	// request and long-lived subscribers are registered below
+	eventBus := event.NewBus()
+	eventBus.Register("audit", 256, event.AuditLog)
+	s.eventBus = eventBus
+
 	projectService := service.NewProjectService(
 		projectRepo,
 		projectMemberRepo,
@@ -145,34 +255,95 @@ func (s *Server) setupDependencies() error {
 		noteRepo,
 		diagramRepo,
 		invitationRepo,
+		keyRotationRepo,
+		shareLinkRepo,
+		commentRepo,
+		projectRoleRepo,
+		backupArchiveRepo,
+		backupScheduleRepo,
+		backupStorage,
+		authzService,
 		argon2Params,
+		eventBus,
+		s.cfg.ProjectDeletionGrace,
+		s.cfg.ProjectRetention,
 	)
 
+	// Carries out expired deletion requests and empties the recycle bin
+	s.purgeScheduler = service.NewProjectDeletionScheduler(projectService, s.cfg.ProjectDeletionTick)
+	s.purgeScheduler.Start()
+
+	payloadLimits := service.PayloadLimits{
+		DiagramData: s.cfg.MaxDiagramData,
+		NodeData:    s.cfg.MaxNodeData,
+		VaultValue:  s.cfg.MaxVaultValue,
+		NoteContent: s.cfg.MaxNoteContent,
+	}
+
 	noteService := service.NewNoteService(
 		noteRepo,
-		projectMemberRepo,
+		authzService,
 		projectRepo,
+		payloadLimits,
+		eventBus,
 	)
 
+	// Edit locks cover a diagram and its nodes
+	diagramLocks := service.NewDiagramLocks(s.cfg.DiagramLockTTL)
+	diagramPaths := service.NewDiagramPathCache(s.cfg.BreadcrumbCacheTTL)
+
 	diagramService := service.NewDiagramService(
 		diagramRepo,
-		projectMemberRepo,
+		authzService,
+		projectRepo,
+		nodeRepo,
+		nodeVaultRepo,
+		shareLinkRepo,
+		commentRepo,
+		diagramLocks,
+		diagramPaths,
+		payloadLimits,
+		eventBus,
+	)
+
+	shareLinkService := service.NewShareLinkService(
+		shareLinkRepo,
+		diagramRepo,
 		projectRepo,
+		authzService,
+		argon2Params,
+	)
+
+	commentService := service.NewCommentService(
+		commentRepo,
+		diagramRepo,
 		nodeRepo,
+		authzService,
+		eventBus,
 	)
 
 	nodeService := service.NewNodeService(
 		nodeRepo,
 		diagramRepo,
-		projectMemberRepo,
+		authzService,
+		payloadLimits,
+		eventBus,
+		s.cfg.NodeUpdateMinInterval,
+		diagramLocks,
 	)
 
 	nodeVaultService := service.NewNodeVaultService(
 		nodeVaultRepo,
 		nodeRepo,
 		diagramRepo,
-		projectMemberRepo,
+		authzService,
+		payloadLimits,
+		eventBus,
 	)
+	eventBus.Register("project_activity", 256, projectService.HandleEvent)
+
+	invitationNotifier := service.NewInvitationNotifier(invitationRepo, userRepo, projectRepo, notifications)
+	eventBus.Register("invitation_notifications", 256, invitationNotifier.HandleEvent)
 
 	breadcrumbService := service.NewBreadcrumbService(
 		projectRepo,
@@ -180,65 +351,245 @@ func (s *Server) setupDependencies() error {
 		diagramRepo,
 		nodeRepo,
 		nodeVaultRepo,
+		authzService,
+		diagramPaths,
+	)
+	eventBus.Register("breadcrumbs", 256, breadcrumbService.HandleEvent)
+
+	searchService := service.NewSearchService(
+		projectMemberRepo,
+		projectRepo,
+		diagramRepo,
+		noteRepo,
+		authzService,
+	)
+
+	activityService := service.NewActivityService(
+		diagramRepo,
+		noteRepo,
+		nodeVaultRepo,
+		authzService,
+	)
+
+	exportService := service.NewExportService(
+		authzService,
+		projectRepo,
+		diagramRepo,
+		nodeRepo,
+		nodeVaultRepo,
+		noteRepo,
 	)
 
+	backupAlgorithm, err := compression.ParseAlgorithm(s.cfg.BackupCompression)
+	if err != nil {
+		return err
+	}
+	if err := compression.ValidateLevel(backupAlgorithm, s.cfg.BackupCompressionLevel); err != nil {
+		return err
+	}
+
+	backupPeppers, err := service.ParseBackupPeppers(s.cfg.BackupPepperID, s.cfg.BackupPeppers)
+	if err != nil {
+		return err
+	}
+
 	backupService := service.NewBackupService(
 		projectService,
+		authzService,
 		projectRepo,
 		projectMemberRepo,
 		noteRepo,
 		diagramRepo,
 		nodeRepo,
 		nodeVaultRepo,
+		backupArchiveRepo,
+		backupScheduleRepo,
+		backupStorage,
 		argon2Params,
+		service.BackupCompression{
+			Algorithm: backupAlgorithm,
+			Level:     s.cfg.BackupCompressionLevel,
+		},
+		backupPeppers,
+		eventBus,
 	)
 
+	// Scheduled backups need somewhere to store archives
+	if backupStorage != nil && s.cfg.BackupSchedulerEnabled {
+		s.backupScheduler = service.NewBackupScheduler(backupService, s.cfg.BackupSchedulerTick)
+		s.backupScheduler.Start()
+	}
+
 	// Initialize validator
 	validator := validation.NewValidationEngine()
 
+	// Apply pagination limits
+	if s.cfg.MaxPageSize > 0 {
+		dto.MaxPageSize = s.cfg.MaxPageSize
+	}
+
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService, validator, s.cfg)
 	profileHandler := handler.NewProfileHandler(userService, validator)
+	accessTokenHandler := handler.NewAccessTokenHandler(accessTokenService, validator)
 	projectHandler := handler.NewProjectHandler(projectService, userRepo, validator)
+	projectRoleHandler := handler.NewProjectRoleHandler(projectService, validator)
 	invitationHandler := handler.NewInvitationHandler(projectService, userRepo, projectRepo, validator)
 	noteHandler := handler.NewNoteHandler(noteService, validator)
 	diagramHandler := handler.NewDiagramHandler(diagramService, validator)
+	shareLinkHandler := handler.NewShareLinkHandler(shareLinkService, validator)
+	commentHandler := handler.NewCommentHandler(commentService, validator)
 	nodeHandler := handler.NewNodeHandler(nodeService, validator)
 	nodeVaultHandler := handler.NewNodeVaultHandler(nodeVaultService, validator)
 	breadcrumbHandler := handler.NewBreadcrumbHandler(breadcrumbService)
+	activityHandler := handler.NewActivityHandler(activityService)
+	exportHandler := handler.NewExportHandler(exportService)
+	presenceHandler := handler.NewPresenceHandler(diagramService, userService, realtime.NewHub())
+	eventStreamHandler := handler.NewEventStreamHandler(projectService, authzService, eventBus, s.cfg.MaxEventStreamsPerUser)
 	backupHandler := handler.NewBackupHandler(backupService, validator)
+	metaHandler := handler.NewMetaHandler()
+	searchHandler := handler.NewSearchHandler(searchService)
 
 	// Initialize middleware
-	authMiddleware := middleware.NewAuthMiddleware(jwtService)
+	authMiddleware := middleware.NewAuthMiddleware(jwtService, accessTokenService)
+	idempotencyMiddleware := middleware.NewIdempotencyMiddleware(idempotencyRepo, s.cfg.IdempotencyTTL)
 
-	s.setupRoutes(authMiddleware, authHandler, profileHandler, projectHandler, invitationHandler, noteHandler, diagramHandler, nodeHandler, nodeVaultHandler, breadcrumbHandler, backupHandler)
+	maintenanceMode, err := middleware.ParseMaintenanceMode(s.cfg.MaintenanceMode)
+	if err != nil {
+		return err
+	}
+	// Health checks, build info and the admin toggle itself must keep working
+	maintenance := middleware.NewMaintenance(maintenanceMode, s.cfg.MaintenanceRetryAfter,
+		append([]string{"/health"}, s.versionedRoutes("/version", "/admin/maintenance")...)...,
+	)
+	adminHandler := handler.NewAdminHandler(maintenance, validator)
+
+	s.setupRoutes(&routeHandlers{
+		authMiddleware:        authMiddleware,
+		idempotencyMiddleware: idempotencyMiddleware,
+		maintenance:           maintenance,
+		adminHandler:          adminHandler,
+		authHandler:           authHandler,
+		profileHandler:        profileHandler,
+		accessTokenHandler:    accessTokenHandler,
+		projectHandler:        projectHandler,
+		projectRoleHandler:    projectRoleHandler,
+		invitationHandler:     invitationHandler,
+		noteHandler:           noteHandler,
+		diagramHandler:        diagramHandler,
+		shareLinkHandler:      shareLinkHandler,
+		commentHandler:        commentHandler,
+		nodeHandler:           nodeHandler,
+		nodeVaultHandler:      nodeVaultHandler,
+		breadcrumbHandler:     breadcrumbHandler,
+		activityHandler:       activityHandler,
+		exportHandler:         exportHandler,
+		presenceHandler:       presenceHandler,
+		eventStreamHandler:    eventStreamHandler,
+		backupHandler:         backupHandler,
+		metaHandler:           metaHandler,
+		searchHandler:         searchHandler,
+	})
 
 	return nil
 }
 
-func (s *Server) setupRoutes(
-	authMiddleware *middleware.AuthMiddleware,
-	authHandler *handler.AuthHandler,
-	profileHandler *handler.ProfileHandler,
-	projectHandler *handler.ProjectHandler,
-	invitationHandler *handler.InvitationHandler,
-	noteHandler *handler.NoteHandler,
-	diagramHandler *handler.DiagramHandler,
-	nodeHandler *handler.NodeHandler,
-	nodeVaultHandler *handler.NodeVaultHandler,
-	breadcrumbHandler *handler.BreadcrumbHandler,
-	backupHandler *handler.BackupHandler,
-) {
+// newBackupStorage builds the configured backup storage backend. It returns
+// nil when server-side storage is disabled.
+func (s *Server) newBackupStorage() (port.BackupStorage, error) {
+	switch strings.ToLower(s.cfg.BackupStorage) {
+	case "", "none":
+		return nil, nil
+	case "local":
+		return storage.NewLocalStorage(s.cfg.BackupStoragePath)
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:        s.cfg.S3Endpoint,
+			Region:          s.cfg.S3Region,
+			Bucket:          s.cfg.S3Bucket,
+			AccessKeyID:     s.cfg.S3AccessKeyID,
+			SecretAccessKey: s.cfg.S3SecretAccessKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown backup storage %q", s.cfg.BackupStorage)
+	}
+}
+
+// newNotifier builds the configured notification backend. Without one,
+// notifications are dropped.
+func (s *Server) newNotifier() (port.Notifier, error) {
+	switch strings.ToLower(s.cfg.Notifier) {
+	case "", "none":
+		return notifier.NewNoopNotifier(), nil
+	case "smtp":
+		return notifier.NewSMTPNotifier(notifier.SMTPConfig{
+			Host:     s.cfg.SMTPHost,
+			Port:     s.cfg.SMTPPort,
+			Username: s.cfg.SMTPUsername,
+			Password: s.cfg.SMTPPassword,
+			From:     s.cfg.SMTPFrom,
+		})
+	default:
+		return nil, fmt.Errorf("unknown notifier %q", s.cfg.Notifier)
+	}
+}
+
+func (s *Server) setupRoutes(h *routeHandlers) {
+	// Brotli buffers its output, so keep it off streams that flush per event
+	compress := middleware.SkipRoutes(brotli.Brotli(brotli.DefaultCompression),
+		s.versionedRoutes("/projects/:project_id/events")...,
+	)
+
 	// Add middlewares
-	s.router.Use(gin.Recovery())                           // Recovery middleware
-	s.router.Use(middleware.LoggerMiddleware())            // Our custom logger middleware
-	s.router.Use(brotli.Brotli(brotli.DefaultCompression)) // Use brotli for better compression
+	// Recovery comes first so panics anywhere answer with the error envelope
+	s.router.Use(middleware.RecoveryMiddleware())
+	s.router.Use(middleware.LoggerMiddleware()) // Our custom logger middleware
+	s.router.Use(compress)                      // Use brotli for better compression
+	s.router.Use(middleware.LocaleMiddleware()) // Translate error messages per Accept-Language
+
+	// Multipart backup uploads
+	uploads := s.versionedRoutes(
+		"/projects/restore",
+		"/projects/restore/inspect",
+		"/projects/:project_id/backup/merge",
+		"/backups/verify",
+	)
+
+	// Limit JSON request bodies; restore uploads are bounded by MaxBackupSize instead
+	s.router.Use(middleware.BodyLimitMiddleware(s.cfg.MaxRequestBody, uploads...))
+
+	// Everything else with a body must send JSON
+	s.router.Use(middleware.RequireJSON(uploads...))
+
+	// Bound how long a request may hold a connection; whole-project
+	// operations get longer and streams are left open
+	timeouts := make(map[string]time.Duration)
+	for _, route := range s.versionedRoutes(
+		"/projects/:project_id/events",
+		"/projects/:project_id/diagrams/:diagram_id/ws",
+	) {
+		timeouts[route] = 0
+	}
+	for _, route := range s.versionedRoutes(
+		"/projects/:project_id/export",
+		"/projects/:project_id/backup",
+		"/projects/:project_id/backups/:backup_id/download",
+		"/projects/:project_id/clone",
+		"/projects/:project_id/diagrams/:diagram_id/export",
+		"/projects/restore",
+		"/projects/restore/inspect",
+		"/projects/:project_id/backup/merge",
+		"/backups/verify",
+	) {
+		timeouts[route] = s.cfg.LongRequestTimeout
+	}
+	s.router.Use(middleware.RequestTimeout(s.cfg.RequestTimeout, timeouts))
 
 	// CORS configuration
 	s.router.Use(cors.New(cors.Config{
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Disposition"},
+		AllowHeaders:     []string{"Origin", "Content-Length", "Content-Type", "Authorization", "Accept-Language", middleware.IdempotencyKeyHeader, middleware.AdminTokenHeader, handler.ShareLinkPasswordHeader, "If-None-Match"},
+		ExposeHeaders:    []string{"Content-Length", "Content-Disposition", "X-Backup-Archive-Id", "Retry-After", "ETag", middleware.IdempotentReplayHeader},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 		AllowOriginFunc: func(origin string) bool {
@@ -246,6 +597,16 @@ func (s *Server) setupRoutes(
 		},
 	}))
 
+	// Reject requests with 503 while in maintenance; runs after CORS so
+	// preflights are still answered
+	s.router.Use(h.maintenance.Handle())
+
+	s.router.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, dto.NewAPIResponse(map[string]string{
+			"status": "ok",
+		}, nil))
+	})
+
 	s.router.NoRoute(func(c *gin.Context) {
 		c.JSON(
 			http.StatusNotFound,
@@ -253,92 +614,21 @@ func (s *Server) setupRoutes(
 		)
 	})
 
-	// API v1 routes
-	v1 := s.router.Group("/api/v1")
-	{
-		// Public routes
-		public := v1.Group("")
-		{
-			public.POST("/auth/register", authHandler.Register)
-			public.POST("/auth/login", authHandler.Login)
-			public.POST("/auth/refresh", authHandler.RefreshToken)
-			public.POST("/auth/logout", authHandler.Logout)
-		}
-
-		// Protected routes (require authentication)
-		protected := v1.Group("")
-		protected.Use(authMiddleware.RequireAuth())
-		{
-			// Profile routes
-			protected.GET("/profile", profileHandler.GetProfile)
-			protected.PUT("/profile", profileHandler.UpdateProfile)
-			protected.PUT("/profile/password", profileHandler.ChangePassword)
-
-			// Project routes
-			projects := protected.Group("/projects")
-			{
-				projects.POST("", projectHandler.CreateProject)
-				projects.GET("", projectHandler.GetUserProjects)
-				projects.GET("/:project_id", projectHandler.GetProjectDetails)
-				projects.PUT("/:project_id", projectHandler.UpdateProject)
-				projects.DELETE("/:project_id", projectHandler.DeleteProject)
-
-				// Breadcrumbs
-				projects.GET("/:project_id/breadcrumbs", breadcrumbHandler.GetBreadcrumbs)
-
-				// Project member management
-				projects.POST("/:project_id/members", projectHandler.AddMember)
-				projects.GET("/:project_id/members", projectHandler.GetMembers)
-				projects.PUT("/:project_id/members/:user_id", projectHandler.UpdateMember)
-				projects.DELETE("/:project_id/members/:user_id", projectHandler.RemoveMember)
-
-				// Key Rotation
-				projects.POST("/:project_id/keys/rotate", projectHandler.RotateProjectKeys)
-
-				// Invitation management (project-scoped)
-				projects.POST("/:project_id/invitations", projectHandler.CreateInvitation)
-				projects.GET("/:project_id/invitations", projectHandler.GetProjectInvitations)
-				projects.DELETE("/:project_id/invitations/:invitation_id", projectHandler.RevokeInvitation)
-
-				// Note management
-				projects.POST("/:project_id/notes", noteHandler.CreateNote)
-				projects.GET("/:project_id/notes", noteHandler.ListNotes)
-				projects.GET("/:project_id/notes/:note_id", noteHandler.GetNote)
-				projects.PUT("/:project_id/notes/:note_id", noteHandler.UpdateNote)
-				projects.DELETE("/:project_id/notes/:note_id", noteHandler.DeleteNote)
-
-				// Diagram management
-				projects.POST("/:project_id/diagrams", diagramHandler.CreateDiagram)
-				projects.GET("/:project_id/diagrams", diagramHandler.ListDiagrams)
-				projects.GET("/:project_id/diagrams/:diagram_id", diagramHandler.GetDiagram)
-				projects.PUT("/:project_id/diagrams/:diagram_id", diagramHandler.UpdateDiagram)
-				projects.DELETE("/:project_id/diagrams/:diagram_id", diagramHandler.DeleteDiagram)
-
-				// Node management
-				projects.GET("/:project_id/diagrams/:diagram_id/nodes/:node_id", nodeHandler.GetOrCreateNode)
-				projects.PUT("/:project_id/diagrams/:diagram_id/nodes/:node_id", nodeHandler.UpdateNode)
-				projects.DELETE("/:project_id/diagrams/:diagram_id/nodes/:node_id", nodeHandler.DeleteNode)
-
-				// Node Vault management
-				projects.GET("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault", nodeVaultHandler.ListVaultItems)
-				projects.GET("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault/:vault_id", nodeVaultHandler.GetVaultItem)
-				projects.POST("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault", nodeVaultHandler.CreateVaultItem)
-				projects.PUT("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault/:vault_id", nodeVaultHandler.UpdateVaultItem)
-				projects.DELETE("/:project_id/diagrams/:diagram_id/nodes/:node_id/vault/:vault_id", nodeVaultHandler.DeleteVaultItem)
-
-				// Backup & Restore
-				projects.POST("/:project_id/backup", backupHandler.CreateBackup)
-				projects.POST("/restore", backupHandler.RestoreBackup)
-			}
-
-			// Invitation routes (non-project-scoped, for invitee)
-			protected.GET("/invitations", invitationHandler.ListUserInvitations)
-			protected.GET("/invitations/:invitation_id", invitationHandler.GetInvitation)
-			protected.POST("/invitations/:invitation_id/accept", invitationHandler.AcceptInvitation)
-
-			// User search
-			protected.GET("/users/search", invitationHandler.SearchUsers)
-		}
+	// A known path with the wrong method is a 405, not a missing page
+	s.router.HandleMethodNotAllowed = true
+	s.router.NoMethod(func(c *gin.Context) {
+		c.JSON(
+			http.StatusMethodNotAllowed,
+			dto.NewAPIResponse[any](nil, dto.NewErrorResponse(dto.ErrCodeMethodNotAllowed)),
+		)
+	})
+
+	// Every API version is mounted next to API_BASE_PATH with its own
+	// response shape
+	for _, version := range apiVersions {
+		group := s.router.Group(s.versionPath(version.name),
+			middleware.APIVersion(version.name, version.shaper))
+		version.register(s, group, h)
 	}
 }
 
@@ -349,6 +639,15 @@ func (s *Server) Run() error {
 
 func (s *Server) Shutdown(ctx context.Context) error {
 	logger.Info().Msg("Server shutting down...")
+	if s.backupScheduler != nil {
+		s.backupScheduler.Stop()
+	}
+	if s.purgeScheduler != nil {
+		s.purgeScheduler.Stop()
+	}
+	if s.eventBus != nil {
+		s.eventBus.Close()
+	}
 	if err := s.mongoClient.Disconnect(ctx); err != nil {
 		return err
 	}
@@ -0,0 +1,54 @@
+// Package version reports which build of the server is running. The
+// variables are set at build time through -ldflags, for example:
+//
+//	go build -ldflags "-X github.com/dhanuprys/infrantery-backend-go/internal/version.Version=v1.2.0" ./cmd/server
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Populated via -ldflags "-X ..."; the defaults mark a development build.
+var (
+	Version   = "dev"
+	GitCommit = "unknown"
+	BuildTime = "unknown"
+)
+
+// Info describes the running build
+type Info struct {
+	Version   string `json:"version"`
+	GitCommit string `json:"git_commit"`
+	BuildTime string `json:"build_time"`
+	GoVersion string `json:"go_version"`
+}
+
+var info = load()
+
+// Get returns the build info. It is computed once at startup, so calling it
+// per request costs nothing.
+func Get() Info {
+	return info
+}
+
+func load() Info {
+	i := Info{
+		Version:   Version,
+		GitCommit: GitCommit,
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+
+	// Builds without ldflags still carry a VCS stamp when built inside a
+	// checkout with the go tool
+	if buildInfo, ok := debug.ReadBuildInfo(); ok && i.GitCommit == "unknown" {
+		for _, setting := range buildInfo.Settings {
+			if setting.Key == "vcs.revision" {
+				i.GitCommit = setting.Value
+			}
+		}
+	}
+
+	return i
+}
@@ -1,37 +1,125 @@
-// Package compression provides zstd compression utilities.
-// The encoder and decoder are initialized once and reused across calls,
+// Package compression provides zstd and gzip compression utilities.
+// Encoders and decoders are initialized once and reused across calls,
 // as they are safe for concurrent use with EncodeAll/DecodeAll.
 package compression
 
 import (
+	"bytes"
+	"compress/gzip"
 	"fmt"
+	"io"
+	"strings"
 	"sync"
 
 	"github.com/klauspost/compress/zstd"
 )
 
+// Algorithm identifies a compression algorithm. The numeric values are
+// stored in archives, so they must never be reassigned.
+type Algorithm byte
+
+const (
+	AlgorithmNone Algorithm = 0
+	AlgorithmZstd Algorithm = 1
+	AlgorithmGzip Algorithm = 2
+)
+
+// DefaultLevel selects the algorithm's default compression level.
+const DefaultLevel = 0
+
+// maxDecodedSize caps decompressed output to guard against compression bombs.
+const maxDecodedSize = 256 * 1024 * 1024 // 256 MB limit
+
+// String returns the configuration name of the algorithm.
+func (a Algorithm) String() string {
+	switch a {
+	case AlgorithmNone:
+		return "none"
+	case AlgorithmZstd:
+		return "zstd"
+	case AlgorithmGzip:
+		return "gzip"
+	default:
+		return fmt.Sprintf("unknown(%d)", byte(a))
+	}
+}
+
+// IsValid reports whether a is a known algorithm.
+func (a Algorithm) IsValid() bool {
+	return a == AlgorithmNone || a == AlgorithmZstd || a == AlgorithmGzip
+}
+
+// ParseAlgorithm converts a configuration name ("zstd", "gzip" or "none")
+// to an Algorithm.
+func ParseAlgorithm(name string) (Algorithm, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "zstd":
+		return AlgorithmZstd, nil
+	case "gzip":
+		return AlgorithmGzip, nil
+	case "none":
+		return AlgorithmNone, nil
+	default:
+		return 0, fmt.Errorf("unknown compression algorithm %q", name)
+	}
+}
+
+// ValidateLevel reports whether level is usable with the algorithm.
+// zstd accepts 1-22, gzip 1-9; DefaultLevel is always valid.
+func ValidateLevel(algorithm Algorithm, level int) error {
+	if level == DefaultLevel {
+		return nil
+	}
+	switch algorithm {
+	case AlgorithmZstd:
+		if level < 1 || level > 22 {
+			return fmt.Errorf("zstd level must be between 1 and 22, got %d", level)
+		}
+	case AlgorithmGzip:
+		if level < gzip.BestSpeed || level > gzip.BestCompression {
+			return fmt.Errorf("gzip level must be between %d and %d, got %d", gzip.BestSpeed, gzip.BestCompression, level)
+		}
+	case AlgorithmNone:
+		return fmt.Errorf("compression level is not supported with algorithm none")
+	default:
+		return fmt.Errorf("unknown compression algorithm %d", byte(algorithm))
+	}
+	return nil
+}
+
 var (
-	encoder     *zstd.Encoder
+	encodersMu sync.Mutex
+	encoders   = make(map[zstd.EncoderLevel]*zstd.Encoder)
+
 	decoder     *zstd.Decoder
-	encoderOnce sync.Once
 	decoderOnce sync.Once
-	initEncErr  error
 	initDecErr  error
 )
 
-func getEncoder() (*zstd.Encoder, error) {
-	encoderOnce.Do(func() {
-		encoder, initEncErr = zstd.NewWriter(nil,
-			zstd.WithEncoderLevel(zstd.SpeedDefault),
-		)
-	})
-	return encoder, initEncErr
+func getEncoder(level int) (*zstd.Encoder, error) {
+	encoderLevel := zstd.SpeedDefault
+	if level != DefaultLevel {
+		encoderLevel = zstd.EncoderLevelFromZstd(level)
+	}
+
+	encodersMu.Lock()
+	defer encodersMu.Unlock()
+
+	if enc, ok := encoders[encoderLevel]; ok {
+		return enc, nil
+	}
+	enc, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(encoderLevel))
+	if err != nil {
+		return nil, err
+	}
+	encoders[encoderLevel] = enc
+	return enc, nil
 }
 
 func getDecoder() (*zstd.Decoder, error) {
 	decoderOnce.Do(func() {
 		decoder, initDecErr = zstd.NewReader(nil,
-			zstd.WithDecoderMaxMemory(256*1024*1024), // 256 MB limit
+			zstd.WithDecoderMaxMemory(maxDecodedSize),
 		)
 	})
 	return decoder, initDecErr
@@ -39,22 +127,82 @@ func getDecoder() (*zstd.Decoder, error) {
 
 // Compress compresses data using zstd with the default compression level.
 func Compress(data []byte) ([]byte, error) {
-	enc, err := getEncoder()
-	if err != nil {
-		return nil, fmt.Errorf("initializing zstd encoder: %w", err)
-	}
-	return enc.EncodeAll(data, make([]byte, 0, len(data)/2)), nil
+	return CompressWith(data, AlgorithmZstd, DefaultLevel)
 }
 
 // Decompress decompresses zstd-compressed data.
 func Decompress(data []byte) ([]byte, error) {
-	dec, err := getDecoder()
-	if err != nil {
-		return nil, fmt.Errorf("initializing zstd decoder: %w", err)
+	return DecompressWith(data, AlgorithmZstd)
+}
+
+// CompressWith compresses data using the given algorithm and level.
+func CompressWith(data []byte, algorithm Algorithm, level int) ([]byte, error) {
+	if err := ValidateLevel(algorithm, level); err != nil {
+		return nil, err
 	}
-	result, err := dec.DecodeAll(data, nil)
-	if err != nil {
-		return nil, fmt.Errorf("decompressing data: %w", err)
+
+	switch algorithm {
+	case AlgorithmNone:
+		return data, nil
+	case AlgorithmZstd:
+		enc, err := getEncoder(level)
+		if err != nil {
+			return nil, fmt.Errorf("initializing zstd encoder: %w", err)
+		}
+		return enc.EncodeAll(data, make([]byte, 0, len(data)/2)), nil
+	case AlgorithmGzip:
+		if level == DefaultLevel {
+			level = gzip.DefaultCompression
+		}
+		var buf bytes.Buffer
+		buf.Grow(len(data) / 2)
+		w, err := gzip.NewWriterLevel(&buf, level)
+		if err != nil {
+			return nil, fmt.Errorf("initializing gzip writer: %w", err)
+		}
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("compressing data: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("compressing data: %w", err)
+		}
+		return buf.Bytes(), nil
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm %d", byte(algorithm))
+	}
+}
+
+// DecompressWith decompresses data produced by CompressWith with the same
+// algorithm.
+func DecompressWith(data []byte, algorithm Algorithm) ([]byte, error) {
+	switch algorithm {
+	case AlgorithmNone:
+		return data, nil
+	case AlgorithmZstd:
+		dec, err := getDecoder()
+		if err != nil {
+			return nil, fmt.Errorf("initializing zstd decoder: %w", err)
+		}
+		result, err := dec.DecodeAll(data, nil)
+		if err != nil {
+			return nil, fmt.Errorf("decompressing data: %w", err)
+		}
+		return result, nil
+	case AlgorithmGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("decompressing data: %w", err)
+		}
+		defer r.Close()
+		result, err := io.ReadAll(io.LimitReader(r, maxDecodedSize+1))
+		if err != nil {
+			return nil, fmt.Errorf("decompressing data: %w", err)
+		}
+		if len(result) > maxDecodedSize {
+			return nil, fmt.Errorf("decompressing data: output exceeds %d bytes", maxDecodedSize)
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("unknown compression algorithm %d", byte(algorithm))
 	}
-	return result, nil
 }
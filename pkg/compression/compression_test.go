@@ -0,0 +1,106 @@
+package compression
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// backupLikePayload builds JSON shaped like a backup payload: repeated field
+// names around base64 ciphertext, which is what the backup path compresses
+func backupLikePayload(items int) []byte {
+	type item struct {
+		ID             string `json:"id"`
+		Label          string `json:"label"`
+		EncryptedValue string `json:"encrypted_value"`
+		Signature      string `json:"encrypted_value_signature"`
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	random := func(n int) string {
+		b := make([]byte, n)
+		rng.Read(b)
+		return base64.StdEncoding.EncodeToString(b)
+	}
+
+	payload := make([]item, items)
+	for i := range payload {
+		payload[i] = item{
+			ID:             fmt.Sprintf("%024x", i),
+			Label:          fmt.Sprintf("node %d", i),
+			EncryptedValue: random(512),
+			Signature:      random(64),
+		}
+	}
+	data, _ := json.Marshal(payload)
+	return data
+}
+
+var benchmarkCases = []struct {
+	algorithm Algorithm
+	level     int
+}{
+	{AlgorithmNone, DefaultLevel},
+	{AlgorithmZstd, DefaultLevel},
+	{AlgorithmZstd, 1},
+	{AlgorithmZstd, 19},
+	{AlgorithmGzip, DefaultLevel},
+	{AlgorithmGzip, 1},
+	{AlgorithmGzip, 9},
+}
+
+func TestCompressWithRoundTrip(t *testing.T) {
+	data := backupLikePayload(50)
+	for _, bc := range benchmarkCases {
+		compressed, err := CompressWith(data, bc.algorithm, bc.level)
+		if err != nil {
+			t.Fatalf("CompressWith(%s, %d): %v", bc.algorithm, bc.level, err)
+		}
+		decompressed, err := DecompressWith(compressed, bc.algorithm)
+		if err != nil {
+			t.Fatalf("DecompressWith(%s): %v", bc.algorithm, err)
+		}
+		if !bytes.Equal(decompressed, data) {
+			t.Errorf("%s level %d did not round-trip", bc.algorithm, bc.level)
+		}
+	}
+}
+
+func BenchmarkCompressWith(b *testing.B) {
+	data := backupLikePayload(2000)
+	for _, bc := range benchmarkCases {
+		b.Run(fmt.Sprintf("%s/level=%d", bc.algorithm, bc.level), func(b *testing.B) {
+			var compressed []byte
+			b.SetBytes(int64(len(data)))
+			b.ResetTimer()
+			for range b.N {
+				var err error
+				if compressed, err = CompressWith(data, bc.algorithm, bc.level); err != nil {
+					b.Fatal(err)
+				}
+			}
+			b.ReportMetric(float64(len(compressed))/float64(len(data)), "ratio")
+		})
+	}
+}
+
+func BenchmarkDecompressWith(b *testing.B) {
+	data := backupLikePayload(2000)
+	for _, bc := range benchmarkCases {
+		compressed, err := CompressWith(data, bc.algorithm, bc.level)
+		if err != nil {
+			b.Fatal(err)
+		}
+		b.Run(fmt.Sprintf("%s/level=%d", bc.algorithm, bc.level), func(b *testing.B) {
+			b.SetBytes(int64(len(data)))
+			for range b.N {
+				if _, err := DecompressWith(compressed, bc.algorithm); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Package i18n provides locale negotiation and simple message catalogs.
+// Messages are looked up by a stable key (such as an error code) and fall
+// back to the default locale when a translation is missing.
+package i18n
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	LocaleEnglish    = "en"
+	LocaleIndonesian = "id"
+
+	// DefaultLocale is used when the client expresses no supported preference.
+	DefaultLocale = LocaleEnglish
+)
+
+var supportedLocales = map[string]struct{}{
+	LocaleEnglish:    {},
+	LocaleIndonesian: {},
+}
+
+// IsSupported reports whether the locale has a catalog.
+func IsSupported(locale string) bool {
+	_, ok := supportedLocales[locale]
+	return ok
+}
+
+// Negotiate picks the best supported locale from an Accept-Language header,
+// honouring q-values and matching on the primary language subtag
+// ("id-ID" selects "id"). It returns DefaultLocale when nothing matches.
+func Negotiate(acceptLanguage string) string {
+	type candidate struct {
+		locale string
+		q      float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if tag == "" {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		if q <= 0 {
+			continue
+		}
+
+		primary, _, _ := strings.Cut(strings.ToLower(tag), "-")
+		if IsSupported(primary) {
+			candidates = append(candidates, candidate{locale: primary, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return DefaultLocale
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+	return candidates[0].locale
+}
+
+// Catalog maps locale -> message key -> message.
+type Catalog map[string]map[string]string
+
+// Lookup returns the message for key in locale, falling back to
+// DefaultLocale. ok is false when no locale has the key.
+func (c Catalog) Lookup(locale, key string) (string, bool) {
+	if message, ok := c[locale][key]; ok {
+		return message, true
+	}
+	message, ok := c[DefaultLocale][key]
+	return message, ok
+}
@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"io"
 	"os"
 	"strings"
 	"time"
@@ -11,26 +12,67 @@ import (
 
 var Logger zerolog.Logger
 
-// Init initializes the global logger with the specified level and environment
-func Init(logLevel, environment string) {
+// logFile is the file opened by Init, if any
+var logFile *rotatingFile
+
+// Init initializes the global logger with the specified level and environment.
+// With sampleEvery above 1 only one in sampleEvery debug and info events is
+// written; warnings and errors are always written. A file.Path adds JSON
+// output to that file; stdout is written as well unless toStdout is false.
+func Init(logLevel, environment string, sampleEvery uint32, file FileOptions, toStdout bool) error {
 	// Set log level
 	level := parseLogLevel(logLevel)
 	zerolog.SetGlobalLevel(level)
 
-	// Configure based on environment
-	if environment == "development" {
-		// Pretty console output for development
-		Logger = zerolog.New(zerolog.ConsoleWriter{
-			Out:        os.Stdout,
-			TimeFormat: time.RFC3339,
-		}).With().Timestamp().Caller().Logger()
-	} else {
-		// JSON output for production
-		Logger = zerolog.New(os.Stdout).With().Timestamp().Caller().Logger()
+	var writers []io.Writer
+	if toStdout || file.Path == "" {
+		// Configure based on environment
+		if environment == "development" {
+			// Pretty console output for development
+			writers = append(writers, zerolog.ConsoleWriter{
+				Out:        os.Stdout,
+				TimeFormat: time.RFC3339,
+			})
+		} else {
+			// JSON output for production
+			writers = append(writers, os.Stdout)
+		}
+	}
+	if file.Path != "" {
+		f, err := openRotatingFile(file)
+		if err != nil {
+			return err
+		}
+		logFile = f
+		writers = append(writers, f)
+	}
+
+	var out io.Writer = writers[0]
+	if len(writers) > 1 {
+		out = zerolog.MultiLevelWriter(writers...)
+	}
+	Logger = zerolog.New(out).With().Timestamp().Caller().Logger()
+
+	if sampleEvery > 1 {
+		// Each level counts on its own; a shared counter would let busy
+		// info logging starve debug events, or the other way round
+		Logger = Logger.Sample(zerolog.LevelSampler{
+			DebugSampler: &zerolog.BasicSampler{N: sampleEvery},
+			InfoSampler:  &zerolog.BasicSampler{N: sampleEvery},
+		})
 	}
 
 	// Set global logger
 	log.Logger = Logger
+	return nil
+}
+
+// Close closes the log file opened by Init, if any
+func Close() error {
+	if logFile == nil {
+		return nil
+	}
+	return logFile.Close()
 }
 
 // parseLogLevel converts string log level to zerolog.Level
@@ -51,6 +93,12 @@ func parseLogLevel(level string) zerolog.Level {
 	}
 }
 
+// DebugEnabled reports whether debug events are written, so costly debug-only
+// instrumentation can be skipped entirely otherwise
+func DebugEnabled() bool {
+	return zerolog.GlobalLevel() <= zerolog.DebugLevel
+}
+
 // Info returns a logger for info level
 func Info() *zerolog.Event {
 	return Logger.Info()
@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+// initToFile points the global logger at a fresh file in a temporary
+// directory and restores the previous logger when the test ends
+func initToFile(t *testing.T, level string, sampleEvery uint32, file FileOptions) string {
+	t.Helper()
+	previous, previousLevel := Logger, zerolog.GlobalLevel()
+	t.Cleanup(func() {
+		Close()
+		logFile = nil
+		Logger = previous
+		zerolog.SetGlobalLevel(previousLevel)
+	})
+
+	if file.Path == "" {
+		file.Path = filepath.Join(t.TempDir(), "app.log")
+	}
+	if err := Init(level, "production", sampleEvery, file, false); err != nil {
+		t.Fatal(err)
+	}
+	return file.Path
+}
+
+// logLines returns the lines written to path
+func logLines(t *testing.T, path string) []string {
+	t.Helper()
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSpace(string(raw)), "\n")
+}
+
+func TestInitSamplesDebugAndInfoOnly(t *testing.T) {
+	path := initToFile(t, "debug", 4, FileOptions{})
+
+	for range 100 {
+		Info().Msg("request")
+		Debug().Msg("query")
+	}
+	for range 3 {
+		Error().Msg("failure")
+	}
+
+	counts := map[string]int{}
+	for _, line := range logLines(t, path) {
+		for _, msg := range []string{"request", "query", "failure"} {
+			if strings.Contains(line, `"message":"`+msg+`"`) {
+				counts[msg]++
+			}
+		}
+	}
+	if counts["request"] != 25 || counts["query"] != 25 {
+		t.Errorf("sampled %d info and %d debug events of 100 each, want 25", counts["request"], counts["query"])
+	}
+	if counts["failure"] != 3 {
+		t.Errorf("wrote %d of 3 errors, want all", counts["failure"])
+	}
+}
+
+func TestInitWithoutSamplingWritesEverything(t *testing.T) {
+	path := initToFile(t, "info", 1, FileOptions{})
+
+	for range 10 {
+		Info().Msg("request")
+	}
+	Debug().Msg("hidden")
+
+	lines := logLines(t, path)
+	if len(lines) != 10 {
+		t.Errorf("wrote %d lines, want 10", len(lines))
+	}
+	if DebugEnabled() {
+		t.Error("debug is enabled at info level")
+	}
+}
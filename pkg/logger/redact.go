@@ -0,0 +1,61 @@
+package logger
+
+import (
+	"strconv"
+	"strings"
+)
+
+// RedactString hides a value that must not reach the logs, such as an
+// encrypted blob or a token, keeping only its length
+func RedactString(value string) string {
+	if value == "" {
+		return ""
+	}
+	return "[redacted " + strconv.Itoa(len(value)) + " bytes]"
+}
+
+// Redact masks value according to the field it is logged under: emails keep
+// their first character and domain, user IDs are shortened, and secrets,
+// tokens and encrypted data are redacted entirely. Other fields are returned
+// unchanged.
+func Redact(field, value string) string {
+	field = strings.ToLower(field)
+	switch {
+	case strings.Contains(field, "email"), field == "identifier":
+		return MaskEmail(value)
+	case field == "user_id" || strings.HasSuffix(field, "_user_id"):
+		return SanitizeUserID(value)
+	case isSecretField(field):
+		return RedactString(value)
+	}
+	return value
+}
+
+// secretFieldMarkers name the fields Redact never logs in any form
+var secretFieldMarkers = []string{
+	"password", "secret", "token", "encrypted", "signature", "keyring", "body",
+}
+
+func isSecretField(field string) bool {
+	// Token IDs identify a token without revealing it
+	if strings.HasSuffix(field, "_id") {
+		return false
+	}
+	for _, marker := range secretFieldMarkers {
+		if strings.Contains(field, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactPath replaces each secret in a request path, such as a share link
+// token, with its redacted form
+func RedactPath(path string, secrets ...string) string {
+	for _, secret := range secrets {
+		if secret != "" {
+			path = strings.ReplaceAll(path, secret, RedactString(secret))
+		}
+	}
+	return path
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	blob := strings.Repeat("QUJD", 64)
+
+	tests := []struct {
+		field, value, want string
+	}{
+		{field: "email", value: "alice@example.com", want: "a***@example.com"},
+		{field: "invitee_email", value: "bob@example.com", want: "b***@example.com"},
+		{field: "identifier", value: "alice@example.com", want: "a***@example.com"},
+		{field: "user_id", value: "65f0c0ffee0123456789abcd", want: "65f0c0ff..."},
+		{field: "inviter_user_id", value: "65f0c0ffee0123456789abcd", want: "65f0c0ff..."},
+		{field: "encrypted_data", value: blob, want: "[redacted 256 bytes]"},
+		{field: "Password", value: "hunter2", want: "[redacted 7 bytes]"},
+		{field: "refresh_token", value: "tok", want: "[redacted 3 bytes]"},
+		{field: "request_body", value: `{"a":1}`, want: "[redacted 7 bytes]"},
+		{field: "token_id", value: "65f0c0ffee0123456789abcd", want: "65f0c0ffee0123456789abcd"},
+		{field: "project_id", value: "65f0c0ffee0123456789abcd", want: "65f0c0ffee0123456789abcd"},
+		{field: "password", value: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := Redact(tt.field, tt.value); got != tt.want {
+			t.Errorf("Redact(%q, %.20q) = %q, want %q", tt.field, tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestRedactPath(t *testing.T) {
+	got := RedactPath("/api/v1/share/abc123token/diagram", "abc123token", "")
+	if strings.Contains(got, "abc123token") || got != "/api/v1/share/[redacted 11 bytes]/diagram" {
+		t.Errorf("RedactPath = %q", got)
+	}
+}
+
+func TestMaskEmail(t *testing.T) {
+	for email, want := range map[string]string{
+		"alice@example.com": "a***@example.com",
+		"@example.com":      "***@example.com",
+		"not-an-email":      "***",
+		"":                  "***",
+	} {
+		if got := MaskEmail(email); got != want {
+			t.Errorf("MaskEmail(%q) = %q, want %q", email, got, want)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat stamps rotated files; it sorts in time order
+const backupTimeFormat = "20060102T150405.000"
+
+// FileOptions configures writing logs to a file. The file is rotated once it
+// would grow past MaxSizeMB; rotated files beyond MaxBackups or older than
+// MaxAge are removed. Zero disables the respective limit.
+type FileOptions struct {
+	Path       string
+	MaxSizeMB  int
+	MaxBackups int
+	MaxAge     time.Duration
+}
+
+// rotatingFile is an io.Writer appending to a log file and rotating it by
+// size. Rotated files sit next to it as name-<timestamp>.ext.
+type rotatingFile struct {
+	opts FileOptions
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func openRotatingFile(opts FileOptions) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(opts.Path), 0o755); err != nil {
+		return nil, err
+	}
+	f := &rotatingFile{opts: opts}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.opts.Path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o640)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	maxSize := int64(f.opts.MaxSizeMB) * 1024 * 1024
+	if maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file aside and starts a new one
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	ext := filepath.Ext(f.opts.Path)
+	base := strings.TrimSuffix(f.opts.Path, ext)
+	backup := base + "-" + time.Now().UTC().Format(backupTimeFormat) + ext
+	if err := os.Rename(f.opts.Path, backup); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.removeOldBackups()
+	return nil
+}
+
+// removeOldBackups applies MaxBackups and MaxAge. Failures are ignored; a
+// leftover file must not stop logging.
+func (f *rotatingFile) removeOldBackups() {
+	if f.opts.MaxBackups <= 0 && f.opts.MaxAge <= 0 {
+		return
+	}
+	ext := filepath.Ext(f.opts.Path)
+	backups, err := filepath.Glob(strings.TrimSuffix(f.opts.Path, ext) + "-*" + ext)
+	if err != nil {
+		return
+	}
+
+	// Newest first
+	slices.Sort(backups)
+	slices.Reverse(backups)
+	cutoff := time.Now().Add(-f.opts.MaxAge)
+	for i, backup := range backups {
+		if f.opts.MaxBackups > 0 && i >= f.opts.MaxBackups {
+			os.Remove(backup)
+			continue
+		}
+		if f.opts.MaxAge > 0 {
+			if info, err := os.Stat(backup); err == nil && info.ModTime().Before(cutoff) {
+				os.Remove(backup)
+			}
+		}
+	}
+}
+
+// Close closes the current file
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
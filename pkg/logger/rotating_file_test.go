@@ -0,0 +1,84 @@
+package logger
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestInitWritesJSONToFile(t *testing.T) {
+	path := initToFile(t, "info", 1, FileOptions{Path: filepath.Join(t.TempDir(), "logs", "app.log")})
+
+	Info().Str("project_id", "p1").Msg("project created")
+	Warn().Msg("slow query")
+
+	lines := logLines(t, path)
+	if len(lines) != 2 {
+		t.Fatalf("file holds %d lines, want 2", len(lines))
+	}
+	var entry map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &entry); err != nil {
+		t.Fatalf("line is not JSON: %s", lines[0])
+	}
+	if entry["message"] != "project created" || entry["project_id"] != "p1" || entry["level"] != "info" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestRotatingFileRotatesBySize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+	f, err := openRotatingFile(FileOptions{Path: path, MaxSizeMB: 1, MaxBackups: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	chunk := bytes.Repeat([]byte("x"), 600*1024)
+	for range 5 {
+		if _, err := f.Write(chunk); err != nil {
+			t.Fatal(err)
+		}
+		// Backups are named to the millisecond
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	backups, err := filepath.Glob(filepath.Join(dir, "app-*.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 {
+		t.Errorf("kept %d backups, want 2: %v", len(backups), backups)
+	}
+	for _, name := range append(backups, path) {
+		info, err := os.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() > 1024*1024 {
+			t.Errorf("%s is %d bytes, over the 1 MB limit", name, info.Size())
+		}
+	}
+}
+
+func TestRotatingFileAppendsToExisting(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+	if err := os.WriteFile(path, []byte("earlier\n"), 0o640); err != nil {
+		t.Fatal(err)
+	}
+	f, err := openRotatingFile(FileOptions{Path: path})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("later\n")); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	if raw, _ := os.ReadFile(path); string(raw) != "earlier\nlater\n" {
+		t.Errorf("file = %q, want the new line appended", raw)
+	}
+}
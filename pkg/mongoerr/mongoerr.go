@@ -0,0 +1,90 @@
+// Package mongoerr sorts MongoDB driver errors into the few kinds callers
+// can act on, so a duplicate key or a failover is not reported the same way
+// as a bug.
+package mongoerr
+
+import (
+	"errors"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+var (
+	// ErrDuplicateKey means a write violated a unique index.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrUnavailable means the database could not be reached or was changing
+	// primaries. Retrying the request later is expected to succeed.
+	ErrUnavailable = errors.New("database unavailable")
+
+	// ErrTimeout means the operation ran out of time, either through the
+	// request deadline or a server-side time limit.
+	ErrTimeout = errors.New("database operation timed out")
+)
+
+// Server error codes seen while a replica set elects a new primary or a
+// concurrent transaction touched the same document.
+var transientCodes = []int{
+	91,    // ShutdownInProgress
+	112,   // WriteConflict
+	189,   // PrimarySteppedDown
+	10107, // NotWritablePrimary
+	11600, // InterruptedAtShutdown
+	11602, // InterruptedDueToReplStateChange
+	13435, // NotPrimaryNoSecondaryOk
+	13436, // NotPrimaryOrSecondary
+}
+
+// Error labels the driver and server attach to errors worth retrying.
+var transientLabels = []string{
+	"NetworkError",
+	"TransientTransactionError",
+	"RetryableWriteError",
+}
+
+// Classify returns ErrDuplicateKey, ErrUnavailable or ErrTimeout for errors
+// of those kinds, and nil for anything else
+func Classify(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicateKey
+	}
+
+	// No server could be selected; checked before timeouts because the
+	// driver reports it as one
+	if errors.Is(err, topology.ErrServerSelectionTimeout) || errors.Is(err, mongo.ErrClientDisconnected) {
+		return ErrUnavailable
+	}
+
+	var labeled mongo.LabeledError
+	if errors.As(err, &labeled) {
+		for _, label := range transientLabels {
+			if labeled.HasErrorLabel(label) {
+				// A network timeout carries both labels; it is still a timeout
+				if mongo.IsTimeout(err) {
+					return ErrTimeout
+				}
+				return ErrUnavailable
+			}
+		}
+	}
+
+	var server mongo.ServerError
+	if errors.As(err, &server) {
+		for _, code := range transientCodes {
+			if server.HasErrorCode(code) {
+				return ErrUnavailable
+			}
+		}
+	}
+
+	if mongo.IsTimeout(err) {
+		return ErrTimeout
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package mongoerr
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/topology"
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{name: "nil", err: nil, want: nil},
+		{name: "unique index on insert", err: mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}, want: ErrDuplicateKey},
+		{name: "unique index on update", err: mongo.CommandError{Code: 11001}, want: ErrDuplicateKey},
+		{name: "wrapped duplicate", err: fmt.Errorf("creating user: %w", mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 11000}}}), want: ErrDuplicateKey},
+		{name: "no server selected", err: fmt.Errorf("finding: %w", topology.ErrServerSelectionTimeout), want: ErrUnavailable},
+		{name: "client disconnected", err: mongo.ErrClientDisconnected, want: ErrUnavailable},
+		{name: "primary stepped down", err: mongo.CommandError{Code: 189, Name: "PrimarySteppedDown"}, want: ErrUnavailable},
+		{name: "not writable primary", err: mongo.CommandError{Code: 10107, Name: "NotWritablePrimary"}, want: ErrUnavailable},
+		{name: "write conflict", err: mongo.CommandError{Code: 112, Name: "WriteConflict"}, want: ErrUnavailable},
+		{name: "transient transaction", err: mongo.CommandError{Code: 251, Labels: []string{"TransientTransactionError"}}, want: ErrUnavailable},
+		{name: "network error", err: mongo.CommandError{Labels: []string{"NetworkError"}}, want: ErrUnavailable},
+		{name: "network timeout", err: mongo.CommandError{Labels: []string{"NetworkError"}, Wrapped: context.DeadlineExceeded}, want: ErrTimeout},
+		{name: "request deadline", err: fmt.Errorf("query: %w", context.DeadlineExceeded), want: ErrTimeout},
+		{name: "server time limit", err: mongo.CommandError{Code: 50, Name: "MaxTimeMSExpired"}, want: ErrTimeout},
+		{name: "no documents", err: mongo.ErrNoDocuments, want: nil},
+		{name: "validation failure", err: mongo.WriteException{WriteErrors: []mongo.WriteError{{Code: 121}}}, want: nil},
+		{name: "plain error", err: errors.New("boom"), want: nil},
+	}
+	for _, tt := range tests {
+		if got := Classify(tt.err); got != tt.want {
+			t.Errorf("%s: Classify = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
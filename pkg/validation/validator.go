@@ -1,12 +1,15 @@
 package validation
 
 import (
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"reflect"
 	"strings"
 
+	"github.com/dhanuprys/infrantery-backend-go/pkg/i18n"
 	"github.com/go-playground/validator/v10"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // ValidationEngine handles struct validation and error formatting
@@ -27,13 +30,33 @@ func NewValidationEngine() *ValidationEngine {
 		return name
 	})
 
+	// Register custom tag for encrypted blobs and signatures
+	_ = v.RegisterValidation("base64std", validateBase64Std)
+
+	// Register custom tag for 24-char hex ObjectIDs
+	_ = v.RegisterValidation("objectid", validateObjectID)
+
+	// Register custom tag for strings that must contain more than whitespace
+	_ = v.RegisterValidation("notblank", validateNotBlank)
+
 	return &ValidationEngine{
 		validate: v,
 	}
 }
 
-// ValidateStruct validates a struct and returns formatted error fields if validation fails
-func (ve *ValidationEngine) ValidateStruct(s interface{}) *[]map[string]string {
+// FieldError describes one failed constraint. Tag and Param are the raw
+// validator tag and its argument (e.g. "min" and "8") so clients can build
+// their own, localized messages; Message is a ready-made English fallback.
+type FieldError struct {
+	Field   string `json:"field"`
+	Tag     string `json:"tag"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidateStruct validates a struct and returns every failed constraint,
+// or nil if validation passes
+func (ve *ValidationEngine) ValidateStruct(s interface{}) []FieldError {
 	err := ve.validate.Struct(s)
 	if err == nil {
 		return nil
@@ -41,40 +64,108 @@ func (ve *ValidationEngine) ValidateStruct(s interface{}) *[]map[string]string {
 
 	var veErrors validator.ValidationErrors
 	if errors.As(err, &veErrors) {
-		out := make([]map[string]string, len(veErrors))
+		out := make([]FieldError, len(veErrors))
 		for i, fe := range veErrors {
-			out[i] = map[string]string{
+			out[i] = FieldError{
 				// Field returns the value from the registered TagNameFunc (json tag)
-				fe.Field(): msgForTag(fe),
+				Field:   fe.Field(),
+				Tag:     fe.Tag(),
+				Param:   fe.Param(),
+				Message: msgForTag(fe),
 			}
 		}
-		return &out
+		return out
 	}
 
 	return nil
 }
 
+// tagMessages holds the message templates per locale; %s is replaced with
+// the tag parameter
+var tagMessages = i18n.Catalog{
+	i18n.LocaleEnglish: {
+		"required":  "This field is required",
+		"email":     "Invalid email format",
+		"min":       "Minimum length is %s",
+		"max":       "Maximum length is %s",
+		"uuid":      "Invalid UUID format",
+		"alpha":     "Must contain only letters",
+		"alphanum":  "Must contain only letters and numbers",
+		"numeric":   "Must be valid numeric value",
+		"len":       "Length must be exactly %s",
+		"base64std": "Must be valid base64",
+		"objectid":  "Invalid ID format",
+		"notblank":  "Must not be blank",
+
+		"required_without": "Required unless %s is given",
+
+		"password_upper":  "Must contain an uppercase letter",
+		"password_lower":  "Must contain a lowercase letter",
+		"password_digit":  "Must contain a digit",
+		"password_symbol": "Must contain a symbol",
+		"password_common": "This password is too common",
+	},
+	i18n.LocaleIndonesian: {
+		"required":  "Kolom ini wajib diisi",
+		"email":     "Format email tidak valid",
+		"min":       "Panjang minimal %s",
+		"max":       "Panjang maksimal %s",
+		"uuid":      "Format UUID tidak valid",
+		"alpha":     "Hanya boleh berisi huruf",
+		"alphanum":  "Hanya boleh berisi huruf dan angka",
+		"numeric":   "Harus berupa angka yang valid",
+		"len":       "Panjang harus tepat %s",
+		"base64std": "Harus berupa base64 yang valid",
+		"objectid":  "Format ID tidak valid",
+		"notblank":  "Tidak boleh kosong",
+
+		"required_without": "Wajib diisi kecuali %s diberikan",
+
+		"password_upper":  "Harus berisi huruf besar",
+		"password_lower":  "Harus berisi huruf kecil",
+		"password_digit":  "Harus berisi angka",
+		"password_symbol": "Harus berisi simbol",
+		"password_common": "Kata sandi ini terlalu umum",
+	},
+}
+
+// Message renders the message for a validation tag in the given locale.
+// ok is false for tags without a catalog entry.
+func Message(locale, tag, param string) (string, bool) {
+	template, ok := tagMessages.Lookup(locale, tag)
+	if !ok {
+		return "", false
+	}
+	if strings.Contains(template, "%s") {
+		return fmt.Sprintf(template, param), true
+	}
+	return template, true
+}
+
 // msgForTag returns a friendly error message
 func msgForTag(fe validator.FieldError) string {
-	switch fe.Tag() {
-	case "required":
-		return "This field is required"
-	case "email":
-		return "Invalid email format"
-	case "min":
-		return fmt.Sprintf("Minimum length is %s", fe.Param())
-	case "max":
-		return fmt.Sprintf("Maximum length is %s", fe.Param())
-	case "uuid":
-		return "Invalid UUID format"
-	case "alpha":
-		return "Must contain only letters"
-	case "alphanum":
-		return "Must contain only letters and numbers"
-	case "numeric":
-		return "Must be valid numeric value"
-	case "len":
-		return fmt.Sprintf("Length must be exactly %s", fe.Param())
+	if message, ok := Message(i18n.DefaultLocale, fe.Tag(), fe.Param()); ok {
+		return message
 	}
 	return fe.Error() // Default error message
 }
+
+// validateBase64Std checks that a non-empty string decodes as standard base64
+func validateBase64Std(fl validator.FieldLevel) bool {
+	value := fl.Field().String()
+	if value == "" {
+		return true
+	}
+	_, err := base64.StdEncoding.DecodeString(value)
+	return err == nil
+}
+
+// validateObjectID checks that a string is a valid hex-encoded ObjectID
+func validateObjectID(fl validator.FieldLevel) bool {
+	return primitive.IsValidObjectID(fl.Field().String())
+}
+
+// validateNotBlank checks that a string is not empty or whitespace only
+func validateNotBlank(fl validator.FieldLevel) bool {
+	return strings.TrimSpace(fl.Field().String()) != ""
+}
@@ -0,0 +1,133 @@
+package validation
+
+import (
+	"testing"
+
+	"github.com/dhanuprys/infrantery-backend-go/pkg/i18n"
+)
+
+// tags reports the failed tag per field
+func tags(errs []FieldError) map[string]string {
+	out := make(map[string]string, len(errs))
+	for _, fe := range errs {
+		out[fe.Field] = fe.Tag
+	}
+	return out
+}
+
+func TestBase64StdTag(t *testing.T) {
+	type payload struct {
+		Value string `json:"value" validate:"base64std"`
+	}
+	engine := NewValidationEngine()
+
+	tests := []struct {
+		value string
+		valid bool
+	}{
+		{value: "aGVsbG8=", valid: true},
+		{value: "aGVsbG8gd29ybGQ+Lw==", valid: true},
+		{value: "", valid: true},                      // left to "required"
+		{value: "aGVsbG8", valid: false},              // missing padding
+		{value: "aGVsbG8gd29ybGQ-_w==", valid: false}, // URL alphabet
+		{value: "not base64!", valid: false},
+	}
+	for _, tt := range tests {
+		errs := engine.ValidateStruct(payload{Value: tt.value})
+		if valid := errs == nil; valid != tt.valid {
+			t.Errorf("%q: valid = %v, want %v (%v)", tt.value, valid, tt.valid, errs)
+			continue
+		}
+		if !tt.valid && tags(errs)["value"] != "base64std" {
+			t.Errorf("%q: errors = %+v, want a base64std error on value", tt.value, errs)
+		}
+	}
+}
+
+func TestObjectIDTag(t *testing.T) {
+	type payload struct {
+		UserID   string  `json:"user_id" validate:"required,objectid"`
+		ParentID *string `json:"parent_id,omitempty" validate:"omitempty,objectid"`
+	}
+	engine := NewValidationEngine()
+	str := func(s string) *string { return &s }
+
+	const valid = "65a1f0c2e4b0a1b2c3d4e5f6"
+	tests := []struct {
+		name    string
+		payload payload
+		want    map[string]string
+	}{
+		{name: "valid IDs", payload: payload{UserID: valid, ParentID: str(valid)}, want: map[string]string{}},
+		{name: "optional ID omitted", payload: payload{UserID: valid}, want: map[string]string{}},
+		{name: "too short", payload: payload{UserID: valid[:23]}, want: map[string]string{"user_id": "objectid"}},
+		{name: "not hex", payload: payload{UserID: "zza1f0c2e4b0a1b2c3d4e5f6"}, want: map[string]string{"user_id": "objectid"}},
+		{name: "missing", payload: payload{}, want: map[string]string{"user_id": "required"}},
+		{name: "bad optional ID", payload: payload{UserID: valid, ParentID: str("parent")}, want: map[string]string{"parent_id": "objectid"}},
+	}
+	for _, tt := range tests {
+		errs := engine.ValidateStruct(tt.payload)
+		got := tags(errs)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: errors = %+v, want %v", tt.name, errs, tt.want)
+			continue
+		}
+		for field, tag := range tt.want {
+			if got[field] != tag {
+				t.Errorf("%s: %s failed %q, want %q", tt.name, field, got[field], tag)
+			}
+		}
+		for _, fe := range errs {
+			if fe.Tag == "objectid" && fe.Message != "Invalid ID format" {
+				t.Errorf("%s: message = %q, want the friendly ID message", tt.name, fe.Message)
+			}
+		}
+	}
+}
+
+func TestValidateStructReportsEveryConstraint(t *testing.T) {
+	type payload struct {
+		Username string `json:"username" validate:"required"`
+		Password string `json:"password" validate:"required,min=8"`
+		Email    string `json:"email" validate:"required,email"`
+	}
+	engine := NewValidationEngine()
+
+	errs := engine.ValidateStruct(payload{Password: "short", Email: "not-an-email"})
+	want := []FieldError{
+		{Field: "username", Tag: "required", Message: "This field is required"},
+		{Field: "password", Tag: "min", Param: "8", Message: "Minimum length is 8"},
+		{Field: "email", Tag: "email", Message: "Invalid email format"},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("errors = %+v, want %+v", errs, want)
+	}
+	for i := range want {
+		if errs[i] != want[i] {
+			t.Errorf("error %d = %+v, want %+v", i, errs[i], want[i])
+		}
+	}
+
+	if errs := engine.ValidateStruct(payload{Username: "ada", Password: "long enough", Email: "ada@example.com"}); errs != nil {
+		t.Errorf("valid payload: errors = %+v", errs)
+	}
+}
+
+func TestMessage(t *testing.T) {
+	tests := []struct {
+		locale, tag, param string
+		want               string
+		ok                 bool
+	}{
+		{locale: i18n.LocaleEnglish, tag: "min", param: "8", want: "Minimum length is 8", ok: true},
+		{locale: i18n.LocaleIndonesian, tag: "min", param: "8", want: "Panjang minimal 8", ok: true},
+		{locale: i18n.LocaleEnglish, tag: "objectid", want: "Invalid ID format", ok: true},
+		{locale: i18n.LocaleEnglish, tag: "no_such_tag", ok: false},
+	}
+	for _, tt := range tests {
+		got, ok := Message(tt.locale, tt.tag, tt.param)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Message(%s, %s, %s) = %q, %v, want %q, %v", tt.locale, tt.tag, tt.param, got, ok, tt.want, tt.ok)
+		}
+	}
+}